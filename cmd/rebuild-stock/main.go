@@ -0,0 +1,43 @@
+// Command rebuild-stock menghitung ulang seluruh ProductStock.Quantity dari ledger stock_movements.
+//
+// Jalankan dari folder ini agar config.json terbaca:
+//
+//	cd cmd/rebuild-stock
+//	go run . -dry-run
+package main
+
+import (
+	"context"
+	"flag"
+
+	"auth-service/internal/configs"
+	"auth-service/internal/repositorys"
+	usecase "auth-service/internal/usecases"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "only report balances that differ from the ledger")
+	flag.Parse()
+
+	viperConfig := configs.NewViper()
+	log := configs.NewLogger(viperConfig)
+	db := configs.NewDatabase(viperConfig, log)
+	validate := configs.NewValidator(viperConfig)
+
+	costingMethod, err := usecase.ParseCostingMethod(viperConfig.GetString("valuation.default_method"))
+	if err != nil {
+		log.Fatalf("invalid valuation config: %v", err)
+	}
+
+	productRepo := repositorys.NewProductRepository(db)
+	productUseCase := usecase.NewProductUseCase(productRepo, nil, costingMethod, log, validate)
+
+	results, err := productUseCase.RebuildProductStocks(context.Background(), *dryRun)
+	if err != nil {
+		log.Fatalf("failed to rebuild product stocks: %v", err)
+	}
+	for _, r := range results {
+		log.Infof("product %s at location %s: %d -> %d", r.ProductID, r.WarehouseLocationID, r.PreviousQuantity, r.LedgerQuantity)
+	}
+	log.Infof("%d product stock(s) differ from the ledger (dry run: %t)", len(results), *dryRun)
+}
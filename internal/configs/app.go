@@ -2,12 +2,15 @@ package configs
 
 import (
 	controller "auth-service/internal/controllers"
+	"auth-service/internal/jobs"
 	middleware "auth-service/internal/middlewares"
 	"auth-service/internal/repositorys"
 	route "auth-service/internal/routes"
 	usecase "auth-service/internal/usecases"
 	"auth-service/internal/utils"
+	"context"
 	"log"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
@@ -38,10 +41,78 @@ func NewAppConfig(config *AppConfig) {
 	authMiddleware := middleware.NewAuth(authUseCase, config.Log, config.Viper, jwtUtils, rateLimiterUtils)
 
 	productRepo := repositorys.NewProductRepository(config.DB)
-	productUseCase := usecase.NewProductUseCase(productRepo, config.Log, config.Validate)
+	costingMethod, err := usecase.ParseCostingMethod(config.Viper.GetString("valuation.default_method"))
+	if err != nil {
+		log.Fatalf("Invalid valuation config: %v", err)
+	}
+	var approvalPolicy usecase.MovementApprovalPolicy
+	if err := config.Viper.UnmarshalKey("stock_movements.approval_thresholds", &approvalPolicy); err != nil {
+		log.Fatalf("Invalid stock movement approval policy: %v", err)
+	}
+	productUseCase := usecase.NewProductUseCase(productRepo, approvalPolicy, costingMethod, config.Log, config.Validate)
 	productController := controller.NewProductController(productUseCase, config.Log, config.Validate)
 	productMiddleware := middleware.NewProductMiddleware(productUseCase, config.Log)
 
+	stockTransferRepo := repositorys.NewStockTransferRepository(config.DB)
+	stockTransferUseCase := usecase.NewStockTransferUseCase(stockTransferRepo, productRepo, costingMethod, config.Log, config.Validate)
+	stockTransferController := controller.NewStockTransferController(stockTransferUseCase, config.Log, config.Validate)
+
+	stockReservationRepo := repositorys.NewStockReservationRepository(config.DB)
+	stockReservationUseCase := usecase.NewStockReservationUseCase(stockReservationRepo, config.Log, config.Validate)
+	stockReservationController := controller.NewStockReservationController(stockReservationUseCase, config.Log, config.Validate)
+
+	stockThresholdRepo := repositorys.NewStockThresholdRepository(config.DB)
+	stockThresholdUseCase := usecase.NewStockThresholdUseCase(stockThresholdRepo, productRepo, config.Log, config.Validate)
+	stockThresholdController := controller.NewStockThresholdController(stockThresholdUseCase, config.Log, config.Validate)
+
+	supplierRepo := repositorys.NewSupplierRepository(config.DB)
+	supplierUseCase := usecase.NewSupplierUseCase(supplierRepo, productRepo, config.Log, config.Validate)
+	supplierController := controller.NewSupplierController(supplierUseCase, config.Log, config.Validate)
+
+	purchaseOrderRepo := repositorys.NewPurchaseOrderRepository(config.DB)
+	purchaseOrderUseCase := usecase.NewPurchaseOrderUseCase(purchaseOrderRepo, supplierRepo, productRepo,
+		config.Viper.GetInt("purchase_orders.over_receipt_tolerance_percent"), costingMethod, config.Log, config.Validate)
+	purchaseOrderController := controller.NewPurchaseOrderController(purchaseOrderUseCase, config.Log, config.Validate)
+
+	outboundOrderRepo := repositorys.NewOutboundOrderRepository(config.DB)
+	outboundOrderUseCase := usecase.NewOutboundOrderUseCase(outboundOrderRepo, productRepo, costingMethod, config.Log, config.Validate)
+	outboundOrderController := controller.NewOutboundOrderController(outboundOrderUseCase, config.Log, config.Validate)
+
+	pickWaveRepo := repositorys.NewPickWaveRepository(config.DB)
+	pickWaveUseCase := usecase.NewPickWaveUseCase(pickWaveRepo, config.Viper.GetInt("pick_waves.default_max_lines"), config.Log, config.Validate)
+	pickWaveController := controller.NewPickWaveController(pickWaveUseCase, config.Log, config.Validate)
+
+	returnAuthorizationRepo := repositorys.NewReturnAuthorizationRepository(config.DB)
+	returnAuthorizationUseCase := usecase.NewReturnAuthorizationUseCase(returnAuthorizationRepo, costingMethod, config.Log, config.Validate)
+	returnAuthorizationController := controller.NewReturnAuthorizationController(returnAuthorizationUseCase, config.Log, config.Validate)
+
+	kitRepo := repositorys.NewKitRepository(config.DB)
+	kitUseCase := usecase.NewKitUseCase(kitRepo, productRepo, costingMethod, config.Log, config.Validate)
+	kitController := controller.NewKitController(kitUseCase, config.Log, config.Validate)
+
+	valuationRepo := repositorys.NewValuationRepository(config.DB)
+	valuationUseCase := usecase.NewValuationUseCase(valuationRepo, productRepo, costingMethod, config.Log, config.Validate)
+	valuationController := controller.NewValuationController(valuationUseCase, config.Log, config.Validate)
+
+	priceListRepo := repositorys.NewPriceListRepository(config.DB)
+	priceListUseCase := usecase.NewPriceListUseCase(priceListRepo, productRepo, config.Log, config.Validate)
+	priceListController := controller.NewPriceListController(priceListUseCase, config.Log, config.Validate)
+
+	barcodeRepo := repositorys.NewBarcodeRepository(config.DB)
+	barcodeUseCase := usecase.NewBarcodeUseCase(barcodeRepo, productRepo, config.Log, config.Validate)
+	barcodeController := controller.NewBarcodeController(barcodeUseCase, config.Log, config.Validate)
+
+	labelUseCase := usecase.NewLabelUseCase(productRepo, config.Log, config.Validate)
+	labelController := controller.NewLabelController(labelUseCase, config.Log, config.Validate)
+
+	replenishmentRepo := repositorys.NewReplenishmentRepository(config.DB)
+	replenishmentUseCase := usecase.NewReplenishmentUseCase(replenishmentRepo, supplierRepo, config.Log, config.Validate)
+	replenishmentController := controller.NewReplenishmentController(replenishmentUseCase, config.Log, config.Validate)
+
+	cycleCountRepo := repositorys.NewCycleCountRepository(config.DB)
+	cycleCountUseCase := usecase.NewCycleCountUseCase(cycleCountRepo, productRepo, costingMethod, config.Log, config.Validate)
+	cycleCountController := controller.NewCycleCountController(cycleCountUseCase, config.Log, config.Validate)
+
 	authRoutesConfig := route.RouteConfig{
 		App:            config.App,
 		AuthController: authController,
@@ -55,7 +126,141 @@ func NewAppConfig(config *AppConfig) {
 		AuthMiddleware:    authMiddleware,
 	}
 
+	stockTransferRouteConfig := route.StockTransferRouteConfig{
+		App:                     config.App,
+		StockTransferController: stockTransferController,
+		ProductMiddleware:       productMiddleware,
+		AuthMiddleware:          authMiddleware,
+	}
+
+	stockReservationRouteConfig := route.StockReservationRouteConfig{
+		App:                        config.App,
+		StockReservationController: stockReservationController,
+		ProductMiddleware:          productMiddleware,
+		AuthMiddleware:             authMiddleware,
+	}
+
+	stockThresholdRouteConfig := route.StockThresholdRouteConfig{
+		App:                      config.App,
+		StockThresholdController: stockThresholdController,
+		ProductMiddleware:        productMiddleware,
+		AuthMiddleware:           authMiddleware,
+	}
+
+	replenishmentRouteConfig := route.ReplenishmentRouteConfig{
+		App:                     config.App,
+		ReplenishmentController: replenishmentController,
+		ProductMiddleware:       productMiddleware,
+		AuthMiddleware:          authMiddleware,
+	}
+
+	cycleCountRouteConfig := route.CycleCountRouteConfig{
+		App:                  config.App,
+		CycleCountController: cycleCountController,
+		ProductMiddleware:    productMiddleware,
+		AuthMiddleware:       authMiddleware,
+	}
+
+	supplierRouteConfig := route.SupplierRouteConfig{
+		App:                config.App,
+		SupplierController: supplierController,
+		ProductMiddleware:  productMiddleware,
+		AuthMiddleware:     authMiddleware,
+	}
+
+	purchaseOrderRouteConfig := route.PurchaseOrderRouteConfig{
+		App:                     config.App,
+		PurchaseOrderController: purchaseOrderController,
+		ProductMiddleware:       productMiddleware,
+		AuthMiddleware:          authMiddleware,
+	}
+
+	outboundOrderRouteConfig := route.OutboundOrderRouteConfig{
+		App:                     config.App,
+		OutboundOrderController: outboundOrderController,
+		ProductMiddleware:       productMiddleware,
+		AuthMiddleware:          authMiddleware,
+	}
+
+	pickWaveRouteConfig := route.PickWaveRouteConfig{
+		App:                config.App,
+		PickWaveController: pickWaveController,
+		ProductMiddleware:  productMiddleware,
+		AuthMiddleware:     authMiddleware,
+	}
+
+	returnAuthorizationRouteConfig := route.ReturnAuthorizationRouteConfig{
+		App:                           config.App,
+		ReturnAuthorizationController: returnAuthorizationController,
+		ProductMiddleware:             productMiddleware,
+		AuthMiddleware:                authMiddleware,
+	}
+
+	kitRouteConfig := route.KitRouteConfig{
+		App:               config.App,
+		KitController:     kitController,
+		ProductMiddleware: productMiddleware,
+		AuthMiddleware:    authMiddleware,
+	}
+
+	valuationRouteConfig := route.ValuationRouteConfig{
+		App:                 config.App,
+		ValuationController: valuationController,
+		ProductMiddleware:   productMiddleware,
+		AuthMiddleware:      authMiddleware,
+	}
+
+	priceListRouteConfig := route.PriceListRouteConfig{
+		App:                 config.App,
+		PriceListController: priceListController,
+		ProductMiddleware:   productMiddleware,
+		AuthMiddleware:      authMiddleware,
+	}
+
+	barcodeRouteConfig := route.BarcodeRouteConfig{
+		App:               config.App,
+		BarcodeController: barcodeController,
+		ProductMiddleware: productMiddleware,
+		AuthMiddleware:    authMiddleware,
+	}
+
+	labelRouteConfig := route.LabelRouteConfig{
+		App:               config.App,
+		LabelController:   labelController,
+		ProductMiddleware: productMiddleware,
+		AuthMiddleware:    authMiddleware,
+	}
+
 	productRouteConfig.Setup()
+	stockTransferRouteConfig.Setup()
+	stockReservationRouteConfig.Setup()
+	stockThresholdRouteConfig.Setup()
+	replenishmentRouteConfig.Setup()
+	cycleCountRouteConfig.Setup()
+	supplierRouteConfig.Setup()
+	purchaseOrderRouteConfig.Setup()
+	outboundOrderRouteConfig.Setup()
+	pickWaveRouteConfig.Setup()
+	returnAuthorizationRouteConfig.Setup()
+	kitRouteConfig.Setup()
+	valuationRouteConfig.Setup()
+	priceListRouteConfig.Setup()
+	barcodeRouteConfig.Setup()
+	labelRouteConfig.Setup()
+
+	// Background jobs
+	jobs.RunEvery(context.Background(), config.Log, "reservation-sweeper",
+		time.Duration(config.Viper.GetInt("jobs.reservation_sweeper_interval"))*time.Second,
+		func(ctx context.Context) error {
+			_, err := stockReservationUseCase.ReleaseExpiredReservations(ctx)
+			return err
+		})
+	jobs.RunEvery(context.Background(), config.Log, "replenishment-planner",
+		time.Duration(config.Viper.GetInt("jobs.replenishment_interval"))*time.Second,
+		func(ctx context.Context) error {
+			_, err := replenishmentUseCase.GenerateReplenishmentProposals(ctx)
+			return err
+		})
 	authRoutesConfig.Setup()
 
 	config.Log.Info("Server starting on :8080")
@@ -67,10 +67,109 @@ func NewDatabase(viper *viper.Viper, log *logrus.Logger) *gorm.DB {
 			"available",
 			"low-stock",
 			"out-of-stock",
+			"quarantined",
 		},
 		"movement_type": {
+			// inbound/outbound hanya dipakai entri ledger lama
 			"inbound",
 			"outbound",
+			"count_correction",
+			"receipt",
+			"shipment",
+			"transfer_in",
+			"transfer_out",
+			"adjustment",
+			"damage",
+			"return",
+			"assembly_in",
+			"assembly_out",
+		},
+		"reservation_status": {
+			"active",
+			"released",
+			"consumed",
+			"expired",
+		},
+		"transfer_status": {
+			"draft",
+			"in_transit",
+			"received",
+			"cancelled",
+		},
+		"location_type": {
+			"site",
+			"zone",
+			"aisle",
+			"rack",
+			"bin",
+		},
+		"serial_status": {
+			"in_stock",
+			"issued",
+		},
+		"replenishment_source": {
+			"transfer",
+			"purchase",
+		},
+		"proposal_status": {
+			"open",
+			"converted",
+			"dismissed",
+			"obsolete",
+		},
+		"count_status": {
+			"open",
+			"approved",
+			"cancelled",
+		},
+		"approval_status": {
+			"pending",
+			"approved",
+			"rejected",
+		},
+		"po_status": {
+			"draft",
+			"approved",
+			"partially_received",
+			"closed",
+			"cancelled",
+		},
+		"outbound_order_status": {
+			"draft",
+			"partially_allocated",
+			"allocated",
+			"packed",
+			"shipped",
+			"cancelled",
+		},
+		"outbound_line_status": {
+			"pending",
+			"partially_allocated",
+			"allocated",
+			"packed",
+			"shipped",
+			"short_shipped",
+			"cancelled",
+		},
+		"pick_wave_status": {
+			"open",
+			"completed",
+			"cancelled",
+		},
+		"rma_status": {
+			"authorized",
+			"received",
+			"completed",
+			"cancelled",
+		},
+		"return_disposition": {
+			"restock",
+			"quarantine",
+			"scrap",
+		},
+		"kit_operation": {
+			"assemble",
+			"disassemble",
 		},
 	}
 	for typeName, values := range enumTypes {
@@ -85,6 +184,31 @@ func NewDatabase(viper *viper.Viper, log *logrus.Logger) *gorm.DB {
 			if err != nil {
 				log.Printf("Gagal membuat tipe %s: %v", typeName, err)
 			}
+			continue
+		}
+		// Tipe sudah ada, tambahkan nilai baru yang belum terdaftar
+		for _, value := range values {
+			err = db.Exec(fmt.Sprintf("ALTER TYPE %s ADD VALUE IF NOT EXISTS '%s'", typeName, value)).Error
+			if err != nil {
+				log.Printf("Gagal menambah nilai %s ke tipe %s: %v", value, typeName, err)
+			}
+		}
+	}
+
+	// Unique index idx_product_stock_location tidak bisa dibuat selama masih ada dua stok aktif untuk
+	// produk dan lokasi yang sama; baris ganda harus digabung manual lebih dulu
+	if db.Migrator().HasTable(&models.ProductStock{}) {
+		var duplicates int64
+		err = db.Raw(`
+			SELECT COUNT(*) FROM (
+				SELECT 1 FROM product_stocks WHERE deleted_at IS NULL
+				GROUP BY source_product_id, warehouse_location_id HAVING COUNT(*) > 1
+			) d`).Scan(&duplicates).Error
+		if err != nil {
+			log.Fatalf("failed to check duplicate product stocks: %v", err)
+		}
+		if duplicates > 0 {
+			log.Fatalf("%d product/location pairs have more than one active product_stocks row; merge them before migrating", duplicates)
 		}
 	}
 
@@ -100,12 +224,98 @@ func NewDatabase(viper *viper.Viper, log *logrus.Logger) *gorm.DB {
 		&models.WarehouseLocation{},
 		&models.ProductStock{},
 		&models.StockMovement{},
+		&models.StockTransfer{},
+		&models.StockTransferItem{},
+		&models.StockReservation{},
+		&models.StockLot{},
+		&models.SerialNumber{},
+		&models.StockMovementSerial{},
+		&models.StockThreshold{},
+		&models.ReplenishmentProposal{},
+		&models.CycleCount{},
+		&models.CycleCountLine{},
+		&models.CycleCountEntry{},
+		&models.StockMovementApproval{},
+		&models.Supplier{},
+		&models.SupplierContact{},
+		&models.SupplierProduct{},
+		&models.PurchaseOrder{},
+		&models.PurchaseOrderLine{},
+		&models.GoodsReceipt{},
+		&models.GoodsReceiptLine{},
+		&models.OutboundOrder{},
+		&models.OutboundOrderLine{},
+		&models.OutboundAllocation{},
+		&models.PickWave{},
+		&models.ReturnAuthorization{},
+		&models.ReturnAuthorizationLine{},
+		&models.ReturnInspection{},
+		&models.ProductComponent{},
+		&models.KitAssembly{},
+		&models.ProductUnit{},
+		&models.CostLayer{},
+		&models.PriceList{},
+		&models.ProductPrice{},
+		&models.ProductPriceHistory{},
+		&models.ProductBarcode{},
 	)
 	if err != nil {
 		log.Fatalf("failed to auto migrate: %v", err)
 	}
+
+	// stock_movements adalah ledger append-only, tolak UPDATE dan DELETE di level database
+	err = db.Exec(`
+		CREATE OR REPLACE FUNCTION reject_stock_movement_change() RETURNS trigger AS $$
+		BEGIN
+			RAISE EXCEPTION 'stock_movements is append-only';
+		END;
+		$$ LANGUAGE plpgsql;
+
+		CREATE OR REPLACE TRIGGER stock_movements_append_only
+			BEFORE UPDATE OR DELETE ON stock_movements
+			FOR EACH ROW EXECUTE FUNCTION reject_stock_movement_change();
+	`).Error
 	if err != nil {
-		log.Fatalf("failed to auto migrate: %v", err)
+		log.Fatalf("failed to create stock ledger trigger: %v", err)
+	}
+
+	// Saldo awal: product_stocks yang sudah ada sebelum ledger dan belum punya entri ledger sama sekali
+	// dicatat sebagai 'adjustment' initial_stock (satu baris per lot ditambah sisa tanpa lot), sehingga
+	// rebuild, stok as-of dan running balance tidak menganggap saldonya nol. Idempoten: stok yang sudah
+	// punya entri ledger dilewati.
+	err = db.Exec(`
+		WITH legacy AS (
+			SELECT ps.* FROM product_stocks ps
+			WHERE ps.deleted_at IS NULL AND ps.quantity <> 0
+				AND NOT EXISTS (
+					SELECT 1 FROM stock_movements sm
+					WHERE sm.source_product_id = ps.source_product_id
+						AND sm.warehouse_location_id = ps.warehouse_location_id
+				)
+		), opening AS (
+			SELECT l.id AS stock_id, l.source_product_id, l.warehouse_location_id, l.updated_by, l.created_at,
+				l.quantity AS stock_quantity, l.stock_value, sl.id AS lot_id, sl.quantity
+			FROM legacy l JOIN stock_lots sl ON sl.product_stock_id = l.id AND sl.quantity <> 0
+			UNION ALL
+			SELECT l.id, l.source_product_id, l.warehouse_location_id, l.updated_by, l.created_at,
+				l.quantity, l.stock_value, NULL,
+				l.quantity - COALESCE((SELECT SUM(sl.quantity) FROM stock_lots sl WHERE sl.product_stock_id = l.id), 0)
+			FROM legacy l
+		)
+		INSERT INTO stock_movements (source_product_id, warehouse_location_id, movement_type, quantity, delta,
+			balance_after, unit_cost, total_cost, reason, reference_type, reference_id, reference_note,
+			lot_id, created_by, created_at)
+		SELECT source_product_id, warehouse_location_id, 'adjustment', ABS(quantity), quantity,
+			SUM(quantity) OVER (PARTITION BY stock_id ORDER BY lot_id NULLS LAST),
+			ROUND(ABS(stock_value / stock_quantity), 4),
+			ROUND(ABS(stock_value * quantity / stock_quantity), 4),
+			'initial_stock', 'product_stock', stock_id, 'Opening balance before stock ledger',
+			lot_id, updated_by, created_at
+		FROM opening
+		WHERE quantity <> 0
+	`).Error
+	if err != nil {
+		log.Fatalf("failed to backfill opening stock balances: %v", err)
 	}
 
 	return db
@@ -0,0 +1,136 @@
+package controllers
+
+import (
+	"net/url"
+
+	"auth-service/internal/dtos"
+	middleware "auth-service/internal/middlewares"
+	"auth-service/internal/usecases"
+	"auth-service/internal/utils"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type BarcodeController interface {
+	AddProductBarcode(ctx *fiber.Ctx) error
+	GetProductBarcodes(ctx *fiber.Ctx) error
+	SetPrimaryProductBarcode(ctx *fiber.Ctx) error
+	DeleteProductBarcode(ctx *fiber.Ctx) error
+	Scan(ctx *fiber.Ctx) error
+}
+
+type barcodeController struct {
+	usecase  usecases.BarcodeUseCase
+	log      *logrus.Logger
+	validate *validator.Validate
+}
+
+func NewBarcodeController(usecase usecases.BarcodeUseCase, log *logrus.Logger, validate *validator.Validate) BarcodeController {
+	return &barcodeController{usecase: usecase, log: log, validate: validate}
+}
+
+func (c *barcodeController) AddProductBarcode(ctx *fiber.Ctx) error {
+	productID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, "Invalid ID format", nil))
+	}
+
+	var req dtos.ProductBarcodeRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error(), nil))
+	}
+	if err := c.validate.Struct(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error(), nil))
+	}
+
+	localKeys := middleware.GetLocalKeys(ctx)
+	if localKeys == nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(utils.ErrorResponse(fiber.StatusInternalServerError, "Internal Server error", nil))
+	}
+	barcode, err := c.usecase.AddProductBarcode(ctx.Context(), productID, req, localKeys.UserID)
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(utils.SuccessResponse(fiber.StatusCreated, "Barcode added successfully", barcode, nil))
+}
+
+func (c *barcodeController) GetProductBarcodes(ctx *fiber.Ctx) error {
+	productID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, "Invalid ID format", nil))
+	}
+
+	barcodes, err := c.usecase.GetProductBarcodes(ctx.Context(), productID)
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(utils.SuccessResponse(fiber.StatusOK, "Barcodes retrieved successfully", barcodes, nil))
+}
+
+func (c *barcodeController) SetPrimaryProductBarcode(ctx *fiber.Ctx) error {
+	productID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, "Invalid ID format", nil))
+	}
+	barcodeID, err := uuid.Parse(ctx.Params("barcodeId"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, "Invalid barcode ID format", nil))
+	}
+
+	localKeys := middleware.GetLocalKeys(ctx)
+	if localKeys == nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(utils.ErrorResponse(fiber.StatusInternalServerError, "Internal Server error", nil))
+	}
+	barcode, err := c.usecase.SetPrimaryProductBarcode(ctx.Context(), productID, barcodeID, localKeys.UserID)
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(utils.SuccessResponse(fiber.StatusOK, "Primary barcode set successfully", barcode, nil))
+}
+
+func (c *barcodeController) DeleteProductBarcode(ctx *fiber.Ctx) error {
+	productID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, "Invalid ID format", nil))
+	}
+	barcodeID, err := uuid.Parse(ctx.Params("barcodeId"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, "Invalid barcode ID format", nil))
+	}
+
+	localKeys := middleware.GetLocalKeys(ctx)
+	if localKeys == nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(utils.ErrorResponse(fiber.StatusInternalServerError, "Internal Server error", nil))
+	}
+	if err := c.usecase.DeleteProductBarcode(ctx.Context(), productID, barcodeID, localKeys.UserID); err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(utils.SuccessResponse(fiber.StatusOK, "Barcode deleted successfully", nil, nil))
+}
+
+// Scan; kode GS1 dikirim URL-encoded, separator FNC1 sebagai %1D
+func (c *barcodeController) Scan(ctx *fiber.Ctx) error {
+	code, err := url.PathUnescape(ctx.Params("code"))
+	if err != nil || code == "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, "Invalid code", nil))
+	}
+
+	result, err := c.usecase.Scan(ctx.Context(), code)
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(utils.SuccessResponse(fiber.StatusOK, "Code resolved successfully", result, nil))
+}
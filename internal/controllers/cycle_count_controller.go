@@ -0,0 +1,140 @@
+package controllers
+
+import (
+	"context"
+
+	"auth-service/internal/dtos"
+	middleware "auth-service/internal/middlewares"
+	"auth-service/internal/usecases"
+	"auth-service/internal/utils"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type CycleCountController interface {
+	CreateCycleCount(ctx *fiber.Ctx) error
+	GetCycleCountByID(ctx *fiber.Ctx) error
+	GetCycleCountsList(ctx *fiber.Ctx) error
+	SubmitCycleCount(ctx *fiber.Ctx) error
+	ApproveCycleCount(ctx *fiber.Ctx) error
+	CancelCycleCount(ctx *fiber.Ctx) error
+}
+
+type cycleCountController struct {
+	usecase  usecases.CycleCountUseCase
+	log      *logrus.Logger
+	validate *validator.Validate
+}
+
+func NewCycleCountController(usecase usecases.CycleCountUseCase, log *logrus.Logger, validate *validator.Validate) CycleCountController {
+	return &cycleCountController{usecase: usecase, log: log, validate: validate}
+}
+
+func (c *cycleCountController) CreateCycleCount(ctx *fiber.Ctx) error {
+	var req dtos.CreateCycleCountRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error(), nil))
+	}
+	if err := c.validate.Struct(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error(), nil))
+	}
+
+	localKeys := middleware.GetLocalKeys(ctx)
+	if localKeys == nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(utils.ErrorResponse(fiber.StatusInternalServerError, "Internal Server error", nil))
+	}
+	count, err := c.usecase.CreateCycleCount(ctx.Context(), req, localKeys.UserID)
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(utils.SuccessResponse(fiber.StatusCreated, "Cycle count opened successfully", count, nil))
+}
+
+func (c *cycleCountController) GetCycleCountByID(ctx *fiber.Ctx) error {
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, "Invalid ID format", nil))
+	}
+
+	count, err := c.usecase.GetCycleCountByID(ctx.Context(), id)
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(utils.SuccessResponse(fiber.StatusOK, "Cycle count retrieved successfully", count, nil))
+}
+
+func (c *cycleCountController) GetCycleCountsList(ctx *fiber.Ctx) error {
+	var req dtos.CycleCountListRequest
+	if err := ctx.QueryParser(&req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error(), nil))
+	}
+
+	list, pagination, err := c.usecase.GetCycleCountsList(ctx.Context(), req)
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(utils.SuccessResponse(fiber.StatusOK, "Cycle counts retrieved successfully", list, pagination))
+}
+
+func (c *cycleCountController) SubmitCycleCount(ctx *fiber.Ctx) error {
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, "Invalid ID format", nil))
+	}
+	var req dtos.SubmitCycleCountRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error(), nil))
+	}
+	if err := c.validate.Struct(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error(), nil))
+	}
+
+	localKeys := middleware.GetLocalKeys(ctx)
+	if localKeys == nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(utils.ErrorResponse(fiber.StatusInternalServerError, "Internal Server error", nil))
+	}
+	count, err := c.usecase.SubmitCycleCount(ctx.Context(), id, req, localKeys.UserID)
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(utils.SuccessResponse(fiber.StatusOK, "Counts recorded successfully", count, nil))
+}
+
+func (c *cycleCountController) ApproveCycleCount(ctx *fiber.Ctx) error {
+	return c.changeStatus(ctx, c.usecase.ApproveCycleCount, "Cycle count approved successfully")
+}
+
+func (c *cycleCountController) CancelCycleCount(ctx *fiber.Ctx) error {
+	return c.changeStatus(ctx, c.usecase.CancelCycleCount, "Cycle count cancelled successfully")
+}
+
+// changeStatus menangani endpoint aksi (approve/cancel) yang bentuknya sama
+func (c *cycleCountController) changeStatus(ctx *fiber.Ctx, action func(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*dtos.CycleCountResponse, error), message string) error {
+	countID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, "Invalid ID format", nil))
+	}
+
+	localKeys := middleware.GetLocalKeys(ctx)
+	if localKeys == nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(utils.ErrorResponse(fiber.StatusInternalServerError, "Internal Server error", nil))
+	}
+	count, err := action(ctx.Context(), countID, localKeys.UserID)
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(utils.SuccessResponse(fiber.StatusOK, message, count, nil))
+}
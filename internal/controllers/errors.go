@@ -0,0 +1,132 @@
+package controllers
+
+import (
+	"errors"
+
+	"auth-service/internal/usecases"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// businessRuleErrors adalah error usecase yang disebabkan oleh aturan bisnis, bukan kegagalan server
+var businessRuleErrors = []error{
+	usecases.ErrInsufficientStock,
+	usecases.ErrStockReserved,
+	usecases.ErrInvalidMovementType,
+	usecases.ErrInvalidQuantity,
+	usecases.ErrInvalidTransferState,
+	usecases.ErrStockAlreadyExists,
+	usecases.ErrStockNotEmpty,
+	usecases.ErrInvalidAsOfTime,
+	usecases.ErrInsufficientAvailableStock,
+	usecases.ErrReservationNotActive,
+	usecases.ErrReservationExpiryInPast,
+	usecases.ErrLotNumberRequired,
+	usecases.ErrProductNotLotTracked,
+	usecases.ErrLotNotFound,
+	usecases.ErrInsufficientLotStock,
+	usecases.ErrLotExpiryMismatch,
+	usecases.ErrLotTrackingLocked,
+	usecases.ErrSerialCountMismatch,
+	usecases.ErrProductNotSerialized,
+	usecases.ErrDuplicateSerial,
+	usecases.ErrSerialAlreadyInStock,
+	usecases.ErrSerialNotAvailable,
+	usecases.ErrSerializedLocked,
+	usecases.ErrSerializedLotMix,
+	usecases.ErrInvalidLocationLevel,
+	usecases.ErrLocationCodeRequired,
+	usecases.ErrLocationPathTaken,
+	usecases.ErrLocationHasChildren,
+	usecases.ErrInvalidThresholdLevels,
+	usecases.ErrProposalNotOpen,
+	usecases.ErrCycleCountNotOpen,
+	usecases.ErrCycleCountEmpty,
+	usecases.ErrStockAlreadyCounting,
+	usecases.ErrCycleCountUnresolved,
+	usecases.ErrInvalidReasonCode,
+	usecases.ErrReferenceRequired,
+	usecases.ErrInvalidMovementDirection,
+	usecases.ErrApprovalNotPending,
+	usecases.ErrInvalidDateFilter,
+	usecases.ErrInvalidDateRange,
+	usecases.ErrSupplierInactive,
+	usecases.ErrSupplierHasOpenOrders,
+	usecases.ErrMultiplePrimaryContacts,
+	usecases.ErrInvalidPurchaseOrderState,
+	usecases.ErrProductNotSupplied,
+	usecases.ErrBelowMinOrderQuantity,
+	usecases.ErrNoSupplierForProduct,
+	usecases.ErrReceiptLineNotOnOrder,
+	usecases.ErrOverReceiptLimit,
+	usecases.ErrInvalidOutboundOrderState,
+	usecases.ErrNoStockToAllocate,
+	usecases.ErrOrderNotFullyAllocated,
+	usecases.ErrOutboundLineNotOnOrder,
+	usecases.ErrInvalidPickWaveState,
+	usecases.ErrNoOrdersForWave,
+	usecases.ErrStockQuarantined,
+	usecases.ErrQuarantineMixesStock,
+	usecases.ErrQuarantineLocation,
+	usecases.ErrRestockIntoQuarantine,
+	usecases.ErrInvalidReturnState,
+	usecases.ErrReturnProductNotOnOrder,
+	usecases.ErrReturnExceedsShipped,
+	usecases.ErrReturnLineNotOnRMA,
+	usecases.ErrReceiveExceedsAuthorized,
+	usecases.ErrDispositionExceedsReceived,
+	usecases.ErrKitContainsItself,
+	usecases.ErrNestedKit,
+	usecases.ErrProductNotKit,
+	usecases.ErrComponentNotInKitBOM,
+	usecases.ErrNotVariantParent,
+	usecases.ErrNestedVariant,
+	usecases.ErrVariantAttributesMismatch,
+	usecases.ErrDuplicateVariant,
+	usecases.ErrVariantParentStock,
+	usecases.ErrProductHasVariants,
+	usecases.ErrInvalidAttributeSchema,
+	usecases.ErrInvalidProductAttribute,
+	usecases.ErrUnknownUnit,
+	usecases.ErrInvalidUnit,
+	usecases.ErrBaseUnitLocked,
+	usecases.ErrInvalidCostingMethod,
+	usecases.ErrCostingMethodLocked,
+	usecases.ErrUnitCostNotAllowed,
+	usecases.ErrInvalidCustomerGroup,
+	usecases.ErrDefaultPriceListType,
+	usecases.ErrPriceListIsDefault,
+	usecases.ErrPriceListInactive,
+	usecases.ErrNoDefaultPriceList,
+	usecases.ErrInvalidEffectiveDate,
+	usecases.ErrInvalidEffectivePeriod,
+	usecases.ErrPriceOverlap,
+	usecases.ErrInvalidGTIN,
+	usecases.ErrInvalidGS1,
+	usecases.ErrDuplicateBarcode,
+	usecases.ErrSymbologyLength,
+	usecases.ErrUnsupportedBarcodeData,
+	usecases.ErrUnknownLabelTemplate,
+	usecases.ErrLabelTemplateKind,
+	usecases.ErrTooManyLabels,
+	usecases.ErrInvalidLabelStartPosition,
+}
+
+// errorStatusCode memetakan error dari usecase ke HTTP status code
+func errorStatusCode(err error) int {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		return fiber.StatusBadRequest
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fiber.StatusNotFound
+	}
+	for _, target := range businessRuleErrors {
+		if errors.Is(err, target) {
+			return fiber.StatusUnprocessableEntity
+		}
+	}
+	return fiber.StatusInternalServerError
+}
@@ -0,0 +1,138 @@
+package controllers
+
+import (
+	"context"
+
+	"auth-service/internal/dtos"
+	middleware "auth-service/internal/middlewares"
+	"auth-service/internal/usecases"
+	"auth-service/internal/utils"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type KitController interface {
+	GetBillOfMaterials(ctx *fiber.Ctx) error
+	SetBillOfMaterials(ctx *fiber.Ctx) error
+	AssembleKit(ctx *fiber.Ctx) error
+	DisassembleKit(ctx *fiber.Ctx) error
+	GetKitAssemblyByID(ctx *fiber.Ctx) error
+	GetKitAssembliesList(ctx *fiber.Ctx) error
+}
+
+type kitController struct {
+	usecase  usecases.KitUseCase
+	log      *logrus.Logger
+	validate *validator.Validate
+}
+
+func NewKitController(usecase usecases.KitUseCase, log *logrus.Logger, validate *validator.Validate) KitController {
+	return &kitController{usecase: usecase, log: log, validate: validate}
+}
+
+func (c *kitController) GetBillOfMaterials(ctx *fiber.Ctx) error {
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, "Invalid ID format", nil))
+	}
+
+	bom, err := c.usecase.GetBillOfMaterials(ctx.Context(), id)
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(utils.SuccessResponse(fiber.StatusOK, "Bill of materials retrieved successfully", bom, nil))
+}
+
+func (c *kitController) SetBillOfMaterials(ctx *fiber.Ctx) error {
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, "Invalid ID format", nil))
+	}
+
+	var req dtos.SetBillOfMaterialsRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error(), nil))
+	}
+	if err := c.validate.Struct(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error(), nil))
+	}
+
+	bom, err := c.usecase.SetBillOfMaterials(ctx.Context(), id, req)
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(utils.SuccessResponse(fiber.StatusOK, "Bill of materials updated successfully", bom, nil))
+}
+
+func (c *kitController) AssembleKit(ctx *fiber.Ctx) error {
+	return c.runAssembly(ctx, c.usecase.AssembleKit, "Kit assembled successfully")
+}
+
+func (c *kitController) DisassembleKit(ctx *fiber.Ctx) error {
+	return c.runAssembly(ctx, c.usecase.DisassembleKit, "Kit disassembled successfully")
+}
+
+// runAssembly menangani endpoint assemble/disassemble yang bentuknya sama
+func (c *kitController) runAssembly(ctx *fiber.Ctx, action func(ctx context.Context, kitID uuid.UUID, req dtos.KitAssemblyRequest, userID uuid.UUID) (*dtos.KitAssemblyResponse, error), message string) error {
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, "Invalid ID format", nil))
+	}
+
+	var req dtos.KitAssemblyRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error(), nil))
+	}
+	if err := c.validate.Struct(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error(), nil))
+	}
+
+	localKeys := middleware.GetLocalKeys(ctx)
+	if localKeys == nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(utils.ErrorResponse(fiber.StatusInternalServerError, "Internal Server error", nil))
+	}
+	assembly, err := action(ctx.Context(), id, req, localKeys.UserID)
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(utils.SuccessResponse(fiber.StatusCreated, message, assembly, nil))
+}
+
+func (c *kitController) GetKitAssemblyByID(ctx *fiber.Ctx) error {
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, "Invalid ID format", nil))
+	}
+
+	assembly, err := c.usecase.GetKitAssemblyByID(ctx.Context(), id)
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(utils.SuccessResponse(fiber.StatusOK, "Kit assembly retrieved successfully", assembly, nil))
+}
+
+func (c *kitController) GetKitAssembliesList(ctx *fiber.Ctx) error {
+	var req dtos.KitAssemblyListRequest
+	if err := ctx.QueryParser(&req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error(), nil))
+	}
+
+	list, pagination, err := c.usecase.GetKitAssembliesList(ctx.Context(), req)
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(utils.SuccessResponse(fiber.StatusOK, "Kit assemblies list retrieved", list, pagination))
+}
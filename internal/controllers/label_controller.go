@@ -0,0 +1,92 @@
+package controllers
+
+import (
+	"fmt"
+	"strconv"
+
+	"auth-service/internal/dtos"
+	"auth-service/internal/usecases"
+	"auth-service/internal/utils"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type LabelController interface {
+	GetLabelTemplates(ctx *fiber.Ctx) error
+	RenderProductLabels(ctx *fiber.Ctx) error
+	RenderLocationLabels(ctx *fiber.Ctx) error
+}
+
+type labelController struct {
+	usecase  usecases.LabelUseCase
+	log      *logrus.Logger
+	validate *validator.Validate
+}
+
+func NewLabelController(usecase usecases.LabelUseCase, log *logrus.Logger, validate *validator.Validate) LabelController {
+	return &labelController{usecase: usecase, log: log, validate: validate}
+}
+
+func (c *labelController) GetLabelTemplates(ctx *fiber.Ctx) error {
+	var req dtos.LabelTemplateListRequest
+	if err := ctx.QueryParser(&req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error(), nil))
+	}
+	if err := c.validate.Struct(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error(), nil))
+	}
+
+	templates, err := c.usecase.GetLabelTemplates(ctx.Context(), req)
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(utils.SuccessResponse(fiber.StatusOK, "Label templates retrieved successfully", templates, nil))
+}
+
+func (c *labelController) RenderProductLabels(ctx *fiber.Ctx) error {
+	var req dtos.ProductLabelRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error(), nil))
+	}
+	if err := c.validate.Struct(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error(), nil))
+	}
+
+	document, err := c.usecase.RenderProductLabels(ctx.Context(), req)
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return sendLabelDocument(ctx, document)
+}
+
+func (c *labelController) RenderLocationLabels(ctx *fiber.Ctx) error {
+	var req dtos.LocationLabelRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error(), nil))
+	}
+	if err := c.validate.Struct(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error(), nil))
+	}
+
+	document, err := c.usecase.RenderLocationLabels(ctx.Context(), req)
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return sendLabelDocument(ctx, document)
+}
+
+// sendLabelDocument mengirim dokumen label apa adanya (bukan JSON) agar bisa langsung diteruskan ke printer
+func sendLabelDocument(ctx *fiber.Ctx, document *dtos.LabelDocument) error {
+	ctx.Set(fiber.HeaderContentType, document.ContentType)
+	ctx.Set(fiber.HeaderContentDisposition, fmt.Sprintf("inline; filename=%q", document.FileName))
+	ctx.Set("X-Label-Count", strconv.Itoa(document.Labels))
+	return ctx.Status(fiber.StatusOK).Send(document.Content)
+}
@@ -0,0 +1,217 @@
+package controllers
+
+import (
+	"context"
+
+	"auth-service/internal/dtos"
+	middleware "auth-service/internal/middlewares"
+	"auth-service/internal/usecases"
+	"auth-service/internal/utils"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type OutboundOrderController interface {
+	CreateOutboundOrder(ctx *fiber.Ctx) error
+	UpdateOutboundOrder(ctx *fiber.Ctx) error
+	GetOutboundOrderByID(ctx *fiber.Ctx) error
+	GetOutboundOrdersList(ctx *fiber.Ctx) error
+	AllocateOutboundOrder(ctx *fiber.Ctx) error
+	GetPickList(ctx *fiber.Ctx) error
+	PackOutboundOrder(ctx *fiber.Ctx) error
+	ShipOutboundOrder(ctx *fiber.Ctx) error
+	CancelOutboundOrder(ctx *fiber.Ctx) error
+}
+
+type outboundOrderController struct {
+	usecase  usecases.OutboundOrderUseCase
+	log      *logrus.Logger
+	validate *validator.Validate
+}
+
+func NewOutboundOrderController(usecase usecases.OutboundOrderUseCase, log *logrus.Logger, validate *validator.Validate) OutboundOrderController {
+	return &outboundOrderController{usecase: usecase, log: log, validate: validate}
+}
+
+func (c *outboundOrderController) CreateOutboundOrder(ctx *fiber.Ctx) error {
+	var req dtos.OutboundOrderRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error(), nil))
+	}
+	if err := c.validate.Struct(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error(), nil))
+	}
+
+	localKeys := middleware.GetLocalKeys(ctx)
+	if localKeys == nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(utils.ErrorResponse(fiber.StatusInternalServerError, "Internal Server error", nil))
+	}
+	order, err := c.usecase.CreateOutboundOrder(ctx.Context(), req, localKeys.UserID)
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(utils.SuccessResponse(fiber.StatusCreated, "Outbound order created successfully", order, nil))
+}
+
+func (c *outboundOrderController) UpdateOutboundOrder(ctx *fiber.Ctx) error {
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, "Invalid ID format", nil))
+	}
+
+	var req dtos.OutboundOrderRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error(), nil))
+	}
+	if err := c.validate.Struct(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error(), nil))
+	}
+
+	localKeys := middleware.GetLocalKeys(ctx)
+	if localKeys == nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(utils.ErrorResponse(fiber.StatusInternalServerError, "Internal Server error", nil))
+	}
+	order, err := c.usecase.UpdateOutboundOrder(ctx.Context(), id, req, localKeys.UserID)
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(utils.SuccessResponse(fiber.StatusOK, "Outbound order updated successfully", order, nil))
+}
+
+func (c *outboundOrderController) GetOutboundOrderByID(ctx *fiber.Ctx) error {
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, "Invalid ID format", nil))
+	}
+
+	order, err := c.usecase.GetOutboundOrderByID(ctx.Context(), id)
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(utils.SuccessResponse(fiber.StatusOK, "Outbound order retrieved successfully", order, nil))
+}
+
+func (c *outboundOrderController) GetOutboundOrdersList(ctx *fiber.Ctx) error {
+	var req dtos.OutboundOrderListRequest
+	if err := ctx.QueryParser(&req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error(), nil))
+	}
+
+	list, pagination, err := c.usecase.GetOutboundOrdersList(ctx.Context(), req)
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(utils.SuccessResponse(fiber.StatusOK, "Outbound orders list retrieved", list, pagination))
+}
+
+func (c *outboundOrderController) AllocateOutboundOrder(ctx *fiber.Ctx) error {
+	return c.changeStatus(ctx, c.usecase.AllocateOutboundOrder, "Outbound order allocated successfully")
+}
+
+func (c *outboundOrderController) GetPickList(ctx *fiber.Ctx) error {
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, "Invalid ID format", nil))
+	}
+
+	pickList, err := c.usecase.GetPickList(ctx.Context(), id)
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(utils.SuccessResponse(fiber.StatusOK, "Pick list retrieved successfully", pickList, nil))
+}
+
+func (c *outboundOrderController) PackOutboundOrder(ctx *fiber.Ctx) error {
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, "Invalid ID format", nil))
+	}
+
+	var req dtos.PackOutboundOrderRequest
+	if len(ctx.Body()) > 0 {
+		if err := ctx.BodyParser(&req); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error(), nil))
+		}
+	}
+	if err := c.validate.Struct(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error(), nil))
+	}
+
+	localKeys := middleware.GetLocalKeys(ctx)
+	if localKeys == nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(utils.ErrorResponse(fiber.StatusInternalServerError, "Internal Server error", nil))
+	}
+	order, err := c.usecase.PackOutboundOrder(ctx.Context(), id, req, localKeys.UserID)
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(utils.SuccessResponse(fiber.StatusOK, "Outbound order packed successfully", order, nil))
+}
+
+func (c *outboundOrderController) ShipOutboundOrder(ctx *fiber.Ctx) error {
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, "Invalid ID format", nil))
+	}
+
+	var req dtos.ShipOutboundOrderRequest
+	if len(ctx.Body()) > 0 {
+		if err := ctx.BodyParser(&req); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error(), nil))
+		}
+	}
+	if err := c.validate.Struct(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error(), nil))
+	}
+
+	localKeys := middleware.GetLocalKeys(ctx)
+	if localKeys == nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(utils.ErrorResponse(fiber.StatusInternalServerError, "Internal Server error", nil))
+	}
+	order, err := c.usecase.ShipOutboundOrder(ctx.Context(), id, req, localKeys.UserID)
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(utils.SuccessResponse(fiber.StatusOK, "Outbound order shipped successfully", order, nil))
+}
+
+func (c *outboundOrderController) CancelOutboundOrder(ctx *fiber.Ctx) error {
+	return c.changeStatus(ctx, c.usecase.CancelOutboundOrder, "Outbound order cancelled successfully")
+}
+
+// changeStatus menangani endpoint aksi tanpa body (allocate/cancel) yang bentuknya sama
+func (c *outboundOrderController) changeStatus(ctx *fiber.Ctx, action func(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*dtos.OutboundOrderResponse, error), message string) error {
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, "Invalid ID format", nil))
+	}
+
+	localKeys := middleware.GetLocalKeys(ctx)
+	if localKeys == nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(utils.ErrorResponse(fiber.StatusInternalServerError, "Internal Server error", nil))
+	}
+	order, err := action(ctx.Context(), id, localKeys.UserID)
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(utils.SuccessResponse(fiber.StatusOK, message, order, nil))
+}
@@ -0,0 +1,131 @@
+package controllers
+
+import (
+	"context"
+
+	"auth-service/internal/dtos"
+	middleware "auth-service/internal/middlewares"
+	"auth-service/internal/usecases"
+	"auth-service/internal/utils"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type PickWaveController interface {
+	CreatePickWave(ctx *fiber.Ctx) error
+	GetPickWaveByID(ctx *fiber.Ctx) error
+	GetPickWavesList(ctx *fiber.Ctx) error
+	GetPickRoute(ctx *fiber.Ctx) error
+	CompletePickWave(ctx *fiber.Ctx) error
+	CancelPickWave(ctx *fiber.Ctx) error
+}
+
+type pickWaveController struct {
+	usecase  usecases.PickWaveUseCase
+	log      *logrus.Logger
+	validate *validator.Validate
+}
+
+func NewPickWaveController(usecase usecases.PickWaveUseCase, log *logrus.Logger, validate *validator.Validate) PickWaveController {
+	return &pickWaveController{usecase: usecase, log: log, validate: validate}
+}
+
+func (c *pickWaveController) CreatePickWave(ctx *fiber.Ctx) error {
+	var req dtos.CreatePickWaveRequest
+	if len(ctx.Body()) > 0 {
+		if err := ctx.BodyParser(&req); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error(), nil))
+		}
+	}
+	if err := c.validate.Struct(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error(), nil))
+	}
+
+	localKeys := middleware.GetLocalKeys(ctx)
+	if localKeys == nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(utils.ErrorResponse(fiber.StatusInternalServerError, "Internal Server error", nil))
+	}
+	wave, err := c.usecase.CreatePickWave(ctx.Context(), req, localKeys.UserID)
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(utils.SuccessResponse(fiber.StatusCreated, "Pick wave created successfully", wave, nil))
+}
+
+func (c *pickWaveController) GetPickWaveByID(ctx *fiber.Ctx) error {
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, "Invalid ID format", nil))
+	}
+
+	wave, err := c.usecase.GetPickWaveByID(ctx.Context(), id)
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(utils.SuccessResponse(fiber.StatusOK, "Pick wave retrieved successfully", wave, nil))
+}
+
+func (c *pickWaveController) GetPickWavesList(ctx *fiber.Ctx) error {
+	var req dtos.PickWaveListRequest
+	if err := ctx.QueryParser(&req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error(), nil))
+	}
+
+	list, pagination, err := c.usecase.GetPickWavesList(ctx.Context(), req)
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(utils.SuccessResponse(fiber.StatusOK, "Pick waves list retrieved", list, pagination))
+}
+
+func (c *pickWaveController) GetPickRoute(ctx *fiber.Ctx) error {
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, "Invalid ID format", nil))
+	}
+
+	route, err := c.usecase.GetPickRoute(ctx.Context(), id)
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(utils.SuccessResponse(fiber.StatusOK, "Pick route retrieved successfully", route, nil))
+}
+
+func (c *pickWaveController) CompletePickWave(ctx *fiber.Ctx) error {
+	return c.changeStatus(ctx, c.usecase.CompletePickWave, "Pick wave completed successfully")
+}
+
+func (c *pickWaveController) CancelPickWave(ctx *fiber.Ctx) error {
+	return c.changeStatus(ctx, c.usecase.CancelPickWave, "Pick wave cancelled successfully")
+}
+
+// changeStatus menangani endpoint aksi (complete/cancel) yang bentuknya sama
+func (c *pickWaveController) changeStatus(ctx *fiber.Ctx, action func(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*dtos.PickWaveResponse, error), message string) error {
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, "Invalid ID format", nil))
+	}
+
+	localKeys := middleware.GetLocalKeys(ctx)
+	if localKeys == nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(utils.ErrorResponse(fiber.StatusInternalServerError, "Internal Server error", nil))
+	}
+	wave, err := action(ctx.Context(), id, localKeys.UserID)
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(utils.SuccessResponse(fiber.StatusOK, message, wave, nil))
+}
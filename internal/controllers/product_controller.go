@@ -1,6 +1,8 @@
 package controllers
 
 import (
+	"context"
+
 	"auth-service/internal/dtos"
 	middleware "auth-service/internal/middlewares"
 	"auth-service/internal/usecases"
@@ -25,16 +27,29 @@ type ProductController interface {
 	GetProductStockByID(c *fiber.Ctx) error
 	UpdateProductStock(c *fiber.Ctx) error
 	DeleteProductStock(c *fiber.Ctx) error
+	TrackStockMovement(c *fiber.Ctx) error
+	GetMovementReasonCodes(c *fiber.Ctx) error
+	GetStockMovementApprovalsList(c *fiber.Ctx) error
+	ApproveStockMovement(c *fiber.Ctx) error
+	RejectStockMovement(c *fiber.Ctx) error
+	GetStockLotsByStockID(c *fiber.Ctx) error
+	GetSerialNumbersList(c *fiber.Ctx) error
+	GetSerialNumber(c *fiber.Ctx) error
 
 	// Product Stock
 	CreateWarehouseLocation(c *fiber.Ctx) error
 	GetWarehouseLocationByID(c *fiber.Ctx) error
 	UpdateWarehouseLocation(c *fiber.Ctx) error
 	DeleteWarehouseLocation(c *fiber.Ctx) error
+	GetWarehouseLocationTree(c *fiber.Ctx) error
+	GetLocationStockRollup(c *fiber.Ctx) error
 
 	GetProductsList(ctx *fiber.Ctx) error
 	GetWarehouseLocationsList(ctx *fiber.Ctx) error
 	GetProductStocksList(ctx *fiber.Ctx) error
+	GetProductStocksAsOf(ctx *fiber.Ctx) error
+	GetStockMovementHistory(ctx *fiber.Ctx) error
+	GetStockCard(ctx *fiber.Ctx) error
 	GetDashboardSummary(ctx *fiber.Ctx) error
 
 	GetProductCategoriesList(ctx *fiber.Ctx) error
@@ -75,9 +90,10 @@ func (c *productController) CreateProductCategory(ctx *fiber.Ctx) error {
 	}
 	category, err := c.usecase.CreateProductCategory(ctx.Context(), req, localKeys.UserID)
 	if err != nil {
-		return ctx.Status(fiber.StatusInternalServerError).JSON(dtos.ApiResponse{
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(dtos.ApiResponse{
 			Status:     "error",
-			StatusCode: fiber.StatusInternalServerError,
+			StatusCode: code,
 			Message:    err.Error(),
 			Payload:    nil,
 		})
@@ -154,9 +170,10 @@ func (c *productController) UpdateProductCategory(ctx *fiber.Ctx) error {
 	userID := ctx.Locals("userID").(uuid.UUID)
 	category, err := c.usecase.UpdateProductCategory(ctx.Context(), categoryID, req, userID)
 	if err != nil {
-		return ctx.Status(fiber.StatusInternalServerError).JSON(dtos.ApiResponse{
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(dtos.ApiResponse{
 			Status:     "error",
-			StatusCode: fiber.StatusInternalServerError,
+			StatusCode: code,
 			Message:    err.Error(),
 			Payload:    nil,
 		})
@@ -228,9 +245,10 @@ func (c *productController) CreateProductStock(ctx *fiber.Ctx) error {
 	}
 	stock, err := c.usecase.CreateProductStock(ctx.Context(), req, localKeys.UserID)
 	if err != nil {
-		return ctx.Status(fiber.StatusInternalServerError).JSON(dtos.ApiResponse{
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(dtos.ApiResponse{
 			Status:     "error",
-			StatusCode: fiber.StatusInternalServerError,
+			StatusCode: code,
 			Message:    err.Error(),
 			Payload:    nil,
 		})
@@ -256,11 +274,12 @@ func (c *productController) GetProductStockByID(ctx *fiber.Ctx) error {
 		})
 	}
 
-	stock, err := c.usecase.GetProductStockByID(ctx.Context(), stockID)
+	stock, err := c.usecase.GetProductStockByID(ctx.Context(), stockID, ctx.Query("unit"))
 	if err != nil {
-		return ctx.Status(fiber.StatusNotFound).JSON(dtos.ApiResponse{
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(dtos.ApiResponse{
 			Status:     "error",
-			StatusCode: fiber.StatusNotFound,
+			StatusCode: code,
 			Message:    err.Error(),
 			Payload:    nil,
 		})
@@ -310,9 +329,10 @@ func (c *productController) UpdateProductStock(ctx *fiber.Ctx) error {
 	}
 	stock, err := c.usecase.UpdateProductStock(ctx.Context(), stockID, req, localKeys.UserID)
 	if err != nil {
-		return ctx.Status(fiber.StatusInternalServerError).JSON(dtos.ApiResponse{
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(dtos.ApiResponse{
 			Status:     "error",
-			StatusCode: fiber.StatusInternalServerError,
+			StatusCode: code,
 			Message:    err.Error(),
 			Payload:    nil,
 		})
@@ -343,9 +363,10 @@ func (c *productController) DeleteProductStock(ctx *fiber.Ctx) error {
 		return ctx.Status(fiber.StatusInternalServerError).JSON(utils.ErrorResponse(fiber.StatusInternalServerError, "Internal Server error", nil))
 	}
 	if err := c.usecase.DeleteProductStock(ctx.Context(), stockID, localKeys.UserID); err != nil {
-		return ctx.Status(fiber.StatusInternalServerError).JSON(dtos.ApiResponse{
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(dtos.ApiResponse{
 			Status:     "error",
-			StatusCode: fiber.StatusInternalServerError,
+			StatusCode: code,
 			Message:    err.Error(),
 			Payload:    nil,
 		})
@@ -359,6 +380,134 @@ func (c *productController) DeleteProductStock(ctx *fiber.Ctx) error {
 	})
 }
 
+func (c *productController) TrackStockMovement(ctx *fiber.Ctx) error {
+	var req dtos.CreateStockMovementRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error(), nil))
+	}
+	if err := c.validate.Struct(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error(), nil))
+	}
+
+	localKeys := middleware.GetLocalKeys(ctx)
+	if localKeys == nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(utils.ErrorResponse(fiber.StatusInternalServerError, "Internal Server error", nil))
+	}
+	result, err := c.usecase.TrackStockMovement(ctx.Context(), req, localKeys.UserID, localKeys.Role)
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+	if result.Approval != nil {
+		return ctx.Status(fiber.StatusAccepted).JSON(utils.SuccessResponse(fiber.StatusAccepted, "Stock movement is awaiting super_admin approval", result, nil))
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(utils.SuccessResponse(fiber.StatusCreated, "Stock movement recorded successfully", result, nil))
+}
+
+func (c *productController) GetMovementReasonCodes(ctx *fiber.Ctx) error {
+	codes := c.usecase.GetMovementReasonCodes(ctx.Context())
+	return ctx.Status(fiber.StatusOK).JSON(utils.SuccessResponse(fiber.StatusOK, "Movement reason codes retrieved successfully", codes, nil))
+}
+
+func (c *productController) GetStockMovementApprovalsList(ctx *fiber.Ctx) error {
+	var req dtos.StockMovementApprovalListRequest
+	if err := ctx.QueryParser(&req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error(), nil))
+	}
+
+	list, pagination, err := c.usecase.GetStockMovementApprovalsList(ctx.Context(), req)
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(utils.SuccessResponse(fiber.StatusOK, "Stock movement approvals retrieved successfully", list, pagination))
+}
+
+func (c *productController) ApproveStockMovement(ctx *fiber.Ctx) error {
+	return c.decideStockMovement(ctx, c.usecase.ApproveStockMovement, "Stock movement approved and recorded successfully")
+}
+
+func (c *productController) RejectStockMovement(ctx *fiber.Ctx) error {
+	return c.decideStockMovement(ctx, c.usecase.RejectStockMovement, "Stock movement rejected successfully")
+}
+
+// decideStockMovement menangani endpoint approve/reject yang bentuknya sama
+func (c *productController) decideStockMovement(ctx *fiber.Ctx, action func(ctx context.Context, id uuid.UUID, req dtos.DecideStockMovementApprovalRequest, userID uuid.UUID) (*dtos.StockMovementApprovalResponse, error), message string) error {
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, "Invalid ID format", nil))
+	}
+	var req dtos.DecideStockMovementApprovalRequest
+	if len(ctx.Body()) > 0 {
+		if err := ctx.BodyParser(&req); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error(), nil))
+		}
+	}
+
+	localKeys := middleware.GetLocalKeys(ctx)
+	if localKeys == nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(utils.ErrorResponse(fiber.StatusInternalServerError, "Internal Server error", nil))
+	}
+	approval, err := action(ctx.Context(), id, req, localKeys.UserID)
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(utils.SuccessResponse(fiber.StatusOK, message, approval, nil))
+}
+
+func (c *productController) GetStockLotsByStockID(ctx *fiber.Ctx) error {
+	stockID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, "Invalid ID format", nil))
+	}
+
+	lots, err := c.usecase.GetStockLotsByStockID(ctx.Context(), stockID)
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(utils.SuccessResponse(fiber.StatusOK, "Stock lots retrieved successfully", lots, nil))
+}
+
+func (c *productController) GetSerialNumbersList(ctx *fiber.Ctx) error {
+	productID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, "Invalid ID format", nil))
+	}
+	var req dtos.SerialNumberListRequest
+	if err := ctx.QueryParser(&req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error(), nil))
+	}
+
+	list, pagination, err := c.usecase.GetSerialNumbersList(ctx.Context(), productID, req)
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(utils.SuccessResponse(fiber.StatusOK, "Serial numbers retrieved successfully", list, pagination))
+}
+
+func (c *productController) GetSerialNumber(ctx *fiber.Ctx) error {
+	productID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, "Invalid ID format", nil))
+	}
+
+	serial, err := c.usecase.GetSerialNumber(ctx.Context(), productID, ctx.Params("serial"))
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(utils.SuccessResponse(fiber.StatusOK, "Serial number retrieved successfully", serial, nil))
+}
+
 func (c *productController) CreateProduct(ctx *fiber.Ctx) error {
 	var req dtos.CreateProductRequest
 	if err := ctx.BodyParser(&req); err != nil {
@@ -384,9 +533,10 @@ func (c *productController) CreateProduct(ctx *fiber.Ctx) error {
 	}
 	product, err := c.usecase.CreateProduct(ctx.Context(), req, localKeys.UserID)
 	if err != nil {
-		return ctx.Status(fiber.StatusInternalServerError).JSON(dtos.ApiResponse{
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(dtos.ApiResponse{
 			Status:     "error",
-			StatusCode: fiber.StatusInternalServerError,
+			StatusCode: code,
 			Message:    err.Error(),
 			Payload:    nil,
 		})
@@ -458,9 +608,10 @@ func (c *productController) UpdateProduct(ctx *fiber.Ctx) error {
 	}
 	product, err := c.usecase.UpdateProduct(ctx.Context(), productID, req, localKeys.UserID)
 	if err != nil {
-		return ctx.Status(fiber.StatusInternalServerError).JSON(dtos.ApiResponse{
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(dtos.ApiResponse{
 			Status:     "error",
-			StatusCode: fiber.StatusInternalServerError,
+			StatusCode: code,
 			Message:    err.Error(),
 			Payload:    nil,
 		})
@@ -491,9 +642,10 @@ func (c *productController) DeleteProduct(ctx *fiber.Ctx) error {
 		return ctx.Status(fiber.StatusInternalServerError).JSON(utils.ErrorResponse(fiber.StatusInternalServerError, "Internal Server error", nil))
 	}
 	if err := c.usecase.DeleteProduct(ctx.Context(), productID, localKeys.UserID); err != nil {
-		return ctx.Status(fiber.StatusInternalServerError).JSON(dtos.ApiResponse{
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(dtos.ApiResponse{
 			Status:     "error",
-			StatusCode: fiber.StatusInternalServerError,
+			StatusCode: code,
 			Message:    err.Error(),
 			Payload:    nil,
 		})
@@ -604,9 +756,10 @@ func (c *productController) CreateWarehouseLocation(ctx *fiber.Ctx) error {
 	}
 	location, err := c.usecase.CreateWarehouseLocation(ctx.Context(), req, localKeys.UserID)
 	if err != nil {
-		return ctx.Status(fiber.StatusInternalServerError).JSON(dtos.ApiResponse{
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(dtos.ApiResponse{
 			Status:     "error",
-			StatusCode: fiber.StatusInternalServerError,
+			StatusCode: code,
 			Message:    err.Error(),
 			Payload:    nil,
 		})
@@ -687,9 +840,10 @@ func (c *productController) UpdateWarehouseLocation(ctx *fiber.Ctx) error {
 	userID := ctx.Locals("userID").(uuid.UUID)
 	location, err := c.usecase.UpdateWarehouseLocation(ctx.Context(), locationID, req, userID)
 	if err != nil {
-		return ctx.Status(fiber.StatusInternalServerError).JSON(dtos.ApiResponse{
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(dtos.ApiResponse{
 			Status:     "error",
-			StatusCode: fiber.StatusInternalServerError,
+			StatusCode: code,
 			Message:    err.Error(),
 			Payload:    nil,
 		})
@@ -721,9 +875,10 @@ func (c *productController) DeleteWarehouseLocation(ctx *fiber.Ctx) error {
 		return ctx.Status(fiber.StatusInternalServerError).JSON(utils.ErrorResponse(fiber.StatusInternalServerError, "Internal Server error", nil))
 	}
 	if err := c.usecase.DeleteWarehouseLocation(ctx.Context(), locationID, localKeys.UserID); err != nil {
-		return ctx.Status(fiber.StatusInternalServerError).JSON(dtos.ApiResponse{
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(dtos.ApiResponse{
 			Status:     "error",
-			StatusCode: fiber.StatusInternalServerError,
+			StatusCode: code,
 			Message:    err.Error(),
 			Payload:    nil,
 		})
@@ -756,8 +911,98 @@ func (c *productController) GetProductStocksList(ctx *fiber.Ctx) error {
 	return ctx.Status(fiber.StatusOK).JSON(utils.SuccessResponse(fiber.StatusOK, "Product stocks list retrieved", list, pagination))
 }
 
+func (c *productController) GetProductStocksAsOf(ctx *fiber.Ctx) error {
+	var req dtos.StockAsOfRequest
+	if err := ctx.QueryParser(&req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error(), nil))
+	}
+
+	list, pagination, err := c.usecase.GetProductStocksAsOf(ctx.Context(), req)
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(utils.SuccessResponse(fiber.StatusOK, "Product stocks as of "+req.At+" retrieved", list, pagination))
+}
+
+func (c *productController) GetStockMovementHistory(ctx *fiber.Ctx) error {
+	var req dtos.StockMovementHistoryRequest
+	if err := ctx.QueryParser(&req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error(), nil))
+	}
+
+	list, pagination, err := c.usecase.GetStockMovementHistory(ctx.Context(), req)
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(utils.SuccessResponse(fiber.StatusOK, "Stock movement history retrieved", list, pagination))
+}
+
+func (c *productController) GetStockCard(ctx *fiber.Ctx) error {
+	productID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, "Invalid ID format", nil))
+	}
+
+	var req dtos.StockCardRequest
+	if err := ctx.QueryParser(&req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error(), nil))
+	}
+
+	card, pagination, err := c.usecase.GetStockCard(ctx.Context(), productID, req)
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(utils.SuccessResponse(fiber.StatusOK, "Stock card retrieved", card, pagination))
+}
+
+func (c *productController) GetWarehouseLocationTree(ctx *fiber.Ctx) error {
+	var rootID *uuid.UUID
+	if raw := ctx.Query("root_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, "Invalid root_id format", nil))
+		}
+		rootID = &id
+	}
+
+	tree, err := c.usecase.GetWarehouseLocationTree(ctx.Context(), rootID)
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(utils.SuccessResponse(fiber.StatusOK, "Warehouse location tree retrieved successfully", tree, nil))
+}
+
+func (c *productController) GetLocationStockRollup(ctx *fiber.Ctx) error {
+	locationID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, "Invalid ID format", nil))
+	}
+
+	rollup, err := c.usecase.GetLocationStockRollup(ctx.Context(), locationID)
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(utils.SuccessResponse(fiber.StatusOK, "Location stock rollup retrieved successfully", rollup, nil))
+}
+
 func (c *productController) GetDashboardSummary(ctx *fiber.Ctx) error {
-	summary, err := c.usecase.GetDashboardSummary(ctx.Context())
+	// Jendela laporan lot kedaluwarsa, default 30 hari
+	expiringWithinDays := ctx.QueryInt("expiring_within_days", 30)
+	if expiringWithinDays < 0 {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, "expiring_within_days must not be negative", nil))
+	}
+
+	summary, err := c.usecase.GetDashboardSummary(ctx.Context(), expiringWithinDays)
 	if err != nil {
 		return ctx.Status(fiber.StatusInternalServerError).JSON(utils.ErrorResponse(fiber.StatusInternalServerError, err.Error(), nil))
 	}
@@ -0,0 +1,190 @@
+package controllers
+
+import (
+	"context"
+
+	"auth-service/internal/dtos"
+	middleware "auth-service/internal/middlewares"
+	"auth-service/internal/usecases"
+	"auth-service/internal/utils"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type PurchaseOrderController interface {
+	CreatePurchaseOrder(ctx *fiber.Ctx) error
+	UpdatePurchaseOrder(ctx *fiber.Ctx) error
+	GetPurchaseOrderByID(ctx *fiber.Ctx) error
+	GetPurchaseOrdersList(ctx *fiber.Ctx) error
+	ApprovePurchaseOrder(ctx *fiber.Ctx) error
+	ClosePurchaseOrder(ctx *fiber.Ctx) error
+	CancelPurchaseOrder(ctx *fiber.Ctx) error
+	ReceivePurchaseOrder(ctx *fiber.Ctx) error
+	GetGoodsReceipts(ctx *fiber.Ctx) error
+}
+
+type purchaseOrderController struct {
+	usecase  usecases.PurchaseOrderUseCase
+	log      *logrus.Logger
+	validate *validator.Validate
+}
+
+func NewPurchaseOrderController(usecase usecases.PurchaseOrderUseCase, log *logrus.Logger, validate *validator.Validate) PurchaseOrderController {
+	return &purchaseOrderController{usecase: usecase, log: log, validate: validate}
+}
+
+func (c *purchaseOrderController) CreatePurchaseOrder(ctx *fiber.Ctx) error {
+	var req dtos.PurchaseOrderRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error(), nil))
+	}
+	if err := c.validate.Struct(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error(), nil))
+	}
+
+	localKeys := middleware.GetLocalKeys(ctx)
+	if localKeys == nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(utils.ErrorResponse(fiber.StatusInternalServerError, "Internal Server error", nil))
+	}
+	order, err := c.usecase.CreatePurchaseOrder(ctx.Context(), req, localKeys.UserID)
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(utils.SuccessResponse(fiber.StatusCreated, "Purchase order created successfully", order, nil))
+}
+
+func (c *purchaseOrderController) UpdatePurchaseOrder(ctx *fiber.Ctx) error {
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, "Invalid ID format", nil))
+	}
+
+	var req dtos.PurchaseOrderRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error(), nil))
+	}
+	if err := c.validate.Struct(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error(), nil))
+	}
+
+	localKeys := middleware.GetLocalKeys(ctx)
+	if localKeys == nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(utils.ErrorResponse(fiber.StatusInternalServerError, "Internal Server error", nil))
+	}
+	order, err := c.usecase.UpdatePurchaseOrder(ctx.Context(), id, req, localKeys.UserID)
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(utils.SuccessResponse(fiber.StatusOK, "Purchase order updated successfully", order, nil))
+}
+
+func (c *purchaseOrderController) GetPurchaseOrderByID(ctx *fiber.Ctx) error {
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, "Invalid ID format", nil))
+	}
+
+	order, err := c.usecase.GetPurchaseOrderByID(ctx.Context(), id)
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(utils.SuccessResponse(fiber.StatusOK, "Purchase order retrieved successfully", order, nil))
+}
+
+func (c *purchaseOrderController) GetPurchaseOrdersList(ctx *fiber.Ctx) error {
+	var req dtos.PurchaseOrderListRequest
+	if err := ctx.QueryParser(&req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error(), nil))
+	}
+
+	list, pagination, err := c.usecase.GetPurchaseOrdersList(ctx.Context(), req)
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(utils.SuccessResponse(fiber.StatusOK, "Purchase orders list retrieved", list, pagination))
+}
+
+func (c *purchaseOrderController) ApprovePurchaseOrder(ctx *fiber.Ctx) error {
+	return c.changeStatus(ctx, c.usecase.ApprovePurchaseOrder, "Purchase order approved successfully")
+}
+
+func (c *purchaseOrderController) ClosePurchaseOrder(ctx *fiber.Ctx) error {
+	return c.changeStatus(ctx, c.usecase.ClosePurchaseOrder, "Purchase order closed successfully")
+}
+
+func (c *purchaseOrderController) CancelPurchaseOrder(ctx *fiber.Ctx) error {
+	return c.changeStatus(ctx, c.usecase.CancelPurchaseOrder, "Purchase order cancelled successfully")
+}
+
+func (c *purchaseOrderController) ReceivePurchaseOrder(ctx *fiber.Ctx) error {
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, "Invalid ID format", nil))
+	}
+
+	var req dtos.CreateGoodsReceiptRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error(), nil))
+	}
+	if err := c.validate.Struct(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error(), nil))
+	}
+
+	localKeys := middleware.GetLocalKeys(ctx)
+	if localKeys == nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(utils.ErrorResponse(fiber.StatusInternalServerError, "Internal Server error", nil))
+	}
+	receipt, err := c.usecase.ReceivePurchaseOrder(ctx.Context(), id, req, localKeys.UserID)
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(utils.SuccessResponse(fiber.StatusCreated, "Goods receipt posted successfully", receipt, nil))
+}
+
+func (c *purchaseOrderController) GetGoodsReceipts(ctx *fiber.Ctx) error {
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, "Invalid ID format", nil))
+	}
+
+	receipts, err := c.usecase.GetGoodsReceipts(ctx.Context(), id)
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(utils.SuccessResponse(fiber.StatusOK, "Goods receipts retrieved successfully", receipts, nil))
+}
+
+// changeStatus menangani endpoint aksi (approve/close/cancel) yang bentuknya sama
+func (c *purchaseOrderController) changeStatus(ctx *fiber.Ctx, action func(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*dtos.PurchaseOrderResponse, error), message string) error {
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, "Invalid ID format", nil))
+	}
+
+	localKeys := middleware.GetLocalKeys(ctx)
+	if localKeys == nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(utils.ErrorResponse(fiber.StatusInternalServerError, "Internal Server error", nil))
+	}
+	order, err := action(ctx.Context(), id, localKeys.UserID)
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(utils.SuccessResponse(fiber.StatusOK, message, order, nil))
+}
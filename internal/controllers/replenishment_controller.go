@@ -0,0 +1,93 @@
+package controllers
+
+import (
+	"auth-service/internal/dtos"
+	middleware "auth-service/internal/middlewares"
+	"auth-service/internal/usecases"
+	"auth-service/internal/utils"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type ReplenishmentController interface {
+	GenerateReplenishmentProposals(ctx *fiber.Ctx) error
+	GetReplenishmentProposalsList(ctx *fiber.Ctx) error
+	ConvertReplenishmentProposal(ctx *fiber.Ctx) error
+	DismissReplenishmentProposal(ctx *fiber.Ctx) error
+}
+
+type replenishmentController struct {
+	usecase  usecases.ReplenishmentUseCase
+	log      *logrus.Logger
+	validate *validator.Validate
+}
+
+func NewReplenishmentController(usecase usecases.ReplenishmentUseCase, log *logrus.Logger, validate *validator.Validate) ReplenishmentController {
+	return &replenishmentController{usecase: usecase, log: log, validate: validate}
+}
+
+func (c *replenishmentController) GenerateReplenishmentProposals(ctx *fiber.Ctx) error {
+	result, err := c.usecase.GenerateReplenishmentProposals(ctx.Context())
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(utils.SuccessResponse(fiber.StatusOK, "Replenishment proposals generated successfully", result, nil))
+}
+
+func (c *replenishmentController) GetReplenishmentProposalsList(ctx *fiber.Ctx) error {
+	var req dtos.ReplenishmentProposalListRequest
+	if err := ctx.QueryParser(&req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error(), nil))
+	}
+
+	list, pagination, err := c.usecase.GetReplenishmentProposalsList(ctx.Context(), req)
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(utils.SuccessResponse(fiber.StatusOK, "Replenishment proposals retrieved successfully", list, pagination))
+}
+
+func (c *replenishmentController) ConvertReplenishmentProposal(ctx *fiber.Ctx) error {
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, "Invalid ID format", nil))
+	}
+
+	localKeys := middleware.GetLocalKeys(ctx)
+	if localKeys == nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(utils.ErrorResponse(fiber.StatusInternalServerError, "Internal Server error", nil))
+	}
+	proposal, err := c.usecase.ConvertReplenishmentProposal(ctx.Context(), id, localKeys.UserID)
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(utils.SuccessResponse(fiber.StatusOK, "Replenishment proposal converted successfully", proposal, nil))
+}
+
+func (c *replenishmentController) DismissReplenishmentProposal(ctx *fiber.Ctx) error {
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, "Invalid ID format", nil))
+	}
+
+	localKeys := middleware.GetLocalKeys(ctx)
+	if localKeys == nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(utils.ErrorResponse(fiber.StatusInternalServerError, "Internal Server error", nil))
+	}
+	proposal, err := c.usecase.DismissReplenishmentProposal(ctx.Context(), id, localKeys.UserID)
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(utils.SuccessResponse(fiber.StatusOK, "Replenishment proposal dismissed successfully", proposal, nil))
+}
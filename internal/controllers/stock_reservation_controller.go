@@ -0,0 +1,94 @@
+package controllers
+
+import (
+	"auth-service/internal/dtos"
+	middleware "auth-service/internal/middlewares"
+	"auth-service/internal/usecases"
+	"auth-service/internal/utils"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type StockReservationController interface {
+	CreateStockReservation(ctx *fiber.Ctx) error
+	GetStockReservationsByStockID(ctx *fiber.Ctx) error
+	ReleaseStockReservation(ctx *fiber.Ctx) error
+}
+
+type stockReservationController struct {
+	usecase  usecases.StockReservationUseCase
+	log      *logrus.Logger
+	validate *validator.Validate
+}
+
+func NewStockReservationController(usecase usecases.StockReservationUseCase, log *logrus.Logger, validate *validator.Validate) StockReservationController {
+	return &stockReservationController{usecase: usecase, log: log, validate: validate}
+}
+
+func (c *stockReservationController) CreateStockReservation(ctx *fiber.Ctx) error {
+	stockID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, "Invalid ID format", nil))
+	}
+
+	var req dtos.CreateStockReservationRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error(), nil))
+	}
+	if err := c.validate.Struct(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error(), nil))
+	}
+
+	localKeys := middleware.GetLocalKeys(ctx)
+	if localKeys == nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(utils.ErrorResponse(fiber.StatusInternalServerError, "Internal Server error", nil))
+	}
+	reservation, err := c.usecase.CreateStockReservation(ctx.Context(), stockID, req, localKeys.UserID)
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(utils.SuccessResponse(fiber.StatusCreated, "Stock reservation created successfully", reservation, nil))
+}
+
+func (c *stockReservationController) GetStockReservationsByStockID(ctx *fiber.Ctx) error {
+	stockID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, "Invalid ID format", nil))
+	}
+	status := ctx.Query("status")
+	if err := c.validate.Var(status, "omitempty,oneof=active released consumed expired"); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, "Invalid status filter", nil))
+	}
+
+	list, err := c.usecase.GetStockReservationsByStockID(ctx.Context(), stockID, status)
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(utils.SuccessResponse(fiber.StatusOK, "Stock reservations retrieved", list, nil))
+}
+
+func (c *stockReservationController) ReleaseStockReservation(ctx *fiber.Ctx) error {
+	reservationID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, "Invalid ID format", nil))
+	}
+
+	localKeys := middleware.GetLocalKeys(ctx)
+	if localKeys == nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(utils.ErrorResponse(fiber.StatusInternalServerError, "Internal Server error", nil))
+	}
+	reservation, err := c.usecase.ReleaseStockReservation(ctx.Context(), reservationID, localKeys.UserID)
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(utils.SuccessResponse(fiber.StatusOK, "Stock reservation released successfully", reservation, nil))
+}
@@ -0,0 +1,96 @@
+package controllers
+
+import (
+	"auth-service/internal/dtos"
+	middleware "auth-service/internal/middlewares"
+	"auth-service/internal/usecases"
+	"auth-service/internal/utils"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type StockThresholdController interface {
+	UpsertStockThreshold(ctx *fiber.Ctx) error
+	GetStockThresholdsList(ctx *fiber.Ctx) error
+	DeleteStockThreshold(ctx *fiber.Ctx) error
+	GetEffectiveStockThreshold(ctx *fiber.Ctx) error
+}
+
+type stockThresholdController struct {
+	usecase  usecases.StockThresholdUseCase
+	log      *logrus.Logger
+	validate *validator.Validate
+}
+
+func NewStockThresholdController(usecase usecases.StockThresholdUseCase, log *logrus.Logger, validate *validator.Validate) StockThresholdController {
+	return &stockThresholdController{usecase: usecase, log: log, validate: validate}
+}
+
+func (c *stockThresholdController) UpsertStockThreshold(ctx *fiber.Ctx) error {
+	var req dtos.UpsertStockThresholdRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error(), nil))
+	}
+	if err := c.validate.Struct(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error(), nil))
+	}
+
+	localKeys := middleware.GetLocalKeys(ctx)
+	if localKeys == nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(utils.ErrorResponse(fiber.StatusInternalServerError, "Internal Server error", nil))
+	}
+	threshold, err := c.usecase.UpsertStockThreshold(ctx.Context(), req, localKeys.UserID)
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(utils.SuccessResponse(fiber.StatusOK, "Stock threshold saved successfully", threshold, nil))
+}
+
+func (c *stockThresholdController) GetStockThresholdsList(ctx *fiber.Ctx) error {
+	var req dtos.StockThresholdListRequest
+	if err := ctx.QueryParser(&req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error(), nil))
+	}
+
+	list, pagination, err := c.usecase.GetStockThresholdsList(ctx.Context(), req)
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(utils.SuccessResponse(fiber.StatusOK, "Stock thresholds retrieved successfully", list, pagination))
+}
+
+func (c *stockThresholdController) DeleteStockThreshold(ctx *fiber.Ctx) error {
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, "Invalid ID format", nil))
+	}
+
+	if err := c.usecase.DeleteStockThreshold(ctx.Context(), id); err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(utils.SuccessResponse(fiber.StatusOK, "Stock threshold deleted successfully", nil, nil))
+}
+
+func (c *stockThresholdController) GetEffectiveStockThreshold(ctx *fiber.Ctx) error {
+	stockID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, "Invalid ID format", nil))
+	}
+
+	threshold, err := c.usecase.GetEffectiveStockThreshold(ctx.Context(), stockID)
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(utils.SuccessResponse(fiber.StatusOK, "Effective stock threshold retrieved successfully", threshold, nil))
+}
@@ -0,0 +1,117 @@
+package controllers
+
+import (
+	"context"
+
+	"auth-service/internal/dtos"
+	middleware "auth-service/internal/middlewares"
+	"auth-service/internal/usecases"
+	"auth-service/internal/utils"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type StockTransferController interface {
+	CreateStockTransfer(ctx *fiber.Ctx) error
+	GetStockTransferByID(ctx *fiber.Ctx) error
+	GetStockTransfersList(ctx *fiber.Ctx) error
+	DispatchStockTransfer(ctx *fiber.Ctx) error
+	ReceiveStockTransfer(ctx *fiber.Ctx) error
+	CancelStockTransfer(ctx *fiber.Ctx) error
+}
+
+type stockTransferController struct {
+	usecase  usecases.StockTransferUseCase
+	log      *logrus.Logger
+	validate *validator.Validate
+}
+
+func NewStockTransferController(usecase usecases.StockTransferUseCase, log *logrus.Logger, validate *validator.Validate) StockTransferController {
+	return &stockTransferController{usecase: usecase, log: log, validate: validate}
+}
+
+func (c *stockTransferController) CreateStockTransfer(ctx *fiber.Ctx) error {
+	var req dtos.CreateStockTransferRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error(), nil))
+	}
+	if err := c.validate.Struct(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error(), nil))
+	}
+
+	localKeys := middleware.GetLocalKeys(ctx)
+	if localKeys == nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(utils.ErrorResponse(fiber.StatusInternalServerError, "Internal Server error", nil))
+	}
+	transfer, err := c.usecase.CreateStockTransfer(ctx.Context(), req, localKeys.UserID)
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(utils.SuccessResponse(fiber.StatusCreated, "Stock transfer created successfully", transfer, nil))
+}
+
+func (c *stockTransferController) GetStockTransferByID(ctx *fiber.Ctx) error {
+	transferID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, "Invalid ID format", nil))
+	}
+
+	transfer, err := c.usecase.GetStockTransferByID(ctx.Context(), transferID)
+	if err != nil {
+		return ctx.Status(fiber.StatusNotFound).JSON(utils.ErrorResponse(fiber.StatusNotFound, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(utils.SuccessResponse(fiber.StatusOK, "Stock transfer retrieved successfully", transfer, nil))
+}
+
+func (c *stockTransferController) GetStockTransfersList(ctx *fiber.Ctx) error {
+	var req dtos.StockTransferListRequest
+	if err := ctx.QueryParser(&req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error(), nil))
+	}
+
+	list, pagination, err := c.usecase.GetStockTransfersList(ctx.Context(), req)
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(utils.SuccessResponse(fiber.StatusOK, "Stock transfers list retrieved", list, pagination))
+}
+
+func (c *stockTransferController) DispatchStockTransfer(ctx *fiber.Ctx) error {
+	return c.changeStatus(ctx, c.usecase.DispatchStockTransfer, "Stock transfer dispatched successfully")
+}
+
+func (c *stockTransferController) ReceiveStockTransfer(ctx *fiber.Ctx) error {
+	return c.changeStatus(ctx, c.usecase.ReceiveStockTransfer, "Stock transfer received successfully")
+}
+
+func (c *stockTransferController) CancelStockTransfer(ctx *fiber.Ctx) error {
+	return c.changeStatus(ctx, c.usecase.CancelStockTransfer, "Stock transfer cancelled successfully")
+}
+
+// changeStatus menangani endpoint aksi (dispatch/receive/cancel) yang bentuknya sama
+func (c *stockTransferController) changeStatus(ctx *fiber.Ctx, action func(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*dtos.StockTransferResponse, error), message string) error {
+	transferID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, "Invalid ID format", nil))
+	}
+
+	localKeys := middleware.GetLocalKeys(ctx)
+	if localKeys == nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(utils.ErrorResponse(fiber.StatusInternalServerError, "Internal Server error", nil))
+	}
+	transfer, err := action(ctx.Context(), transferID, localKeys.UserID)
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(utils.SuccessResponse(fiber.StatusOK, message, transfer, nil))
+}
@@ -0,0 +1,56 @@
+package controllers
+
+import (
+	"auth-service/internal/dtos"
+	"auth-service/internal/usecases"
+	"auth-service/internal/utils"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type ValuationController interface {
+	GetInventoryValuation(ctx *fiber.Ctx) error
+	GetCostOfGoods(ctx *fiber.Ctx) error
+}
+
+type valuationController struct {
+	usecase  usecases.ValuationUseCase
+	log      *logrus.Logger
+	validate *validator.Validate
+}
+
+func NewValuationController(usecase usecases.ValuationUseCase, log *logrus.Logger, validate *validator.Validate) ValuationController {
+	return &valuationController{usecase: usecase, log: log, validate: validate}
+}
+
+func (c *valuationController) GetInventoryValuation(ctx *fiber.Ctx) error {
+	var req dtos.InventoryValuationRequest
+	if err := ctx.QueryParser(&req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error(), nil))
+	}
+
+	valuation, err := c.usecase.GetInventoryValuation(ctx.Context(), req)
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(utils.SuccessResponse(fiber.StatusOK, "Inventory valuation retrieved", valuation, nil))
+}
+
+func (c *valuationController) GetCostOfGoods(ctx *fiber.Ctx) error {
+	var req dtos.CostOfGoodsRequest
+	if err := ctx.QueryParser(&req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error(), nil))
+	}
+
+	report, err := c.usecase.GetCostOfGoods(ctx.Context(), req)
+	if err != nil {
+		code := errorStatusCode(err)
+		return ctx.Status(code).JSON(utils.ErrorResponse(code, err.Error(), nil))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(utils.SuccessResponse(fiber.StatusOK, "Cost of goods retrieved", report, nil))
+}
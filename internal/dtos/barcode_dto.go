@@ -0,0 +1,80 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProductBarcodeRequest menambah barcode produk. Tanpa symbology, kode 8/12/13/14 digit dianggap GTIN
+// dan check digit-nya divalidasi; kode lain disimpan sebagai code128. Unit kosong berarti satuan dasar.
+type ProductBarcodeRequest struct {
+	Code      string `json:"code" validate:"required,max=50"`
+	Symbology string `json:"symbology" validate:"omitempty,oneof=ean8 upca ean13 gtin14 code128"`
+	Unit      string `json:"unit" validate:"max=20"`
+	IsPrimary bool   `json:"is_primary"`
+}
+
+type ProductBarcodeResponse struct {
+	ID        uuid.UUID `json:"id"`
+	ProductID uuid.UUID `json:"product_id"`
+	Code      string    `json:"code"`
+	Symbology string    `json:"symbology"`
+	GTIN      string    `json:"gtin,omitempty"`
+	Unit      string    `json:"unit,omitempty"`
+	IsPrimary bool      `json:"is_primary"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ScanProductResponse struct {
+	ID         uuid.UUID `json:"id"`
+	Name       string    `json:"name"`
+	SKU        string    `json:"sku"`
+	BaseUnit   string    `json:"base_unit"`
+	LotTracked bool      `json:"lot_tracked"`
+	Serialized bool      `json:"serialized"`
+}
+
+type ScanLocationResponse struct {
+	ID   uuid.UUID `json:"id"`
+	Type string    `json:"type"`
+	Code string    `json:"code"`
+	Path string    `json:"path"`
+	Name string    `json:"name"`
+}
+
+// ScanLotResponse adalah stok lot hasil scan per lokasi
+type ScanLotResponse struct {
+	LotID               uuid.UUID  `json:"lot_id"`
+	LotNumber           string     `json:"lot_number"`
+	ExpiryDate          *time.Time `json:"expiry_date"`
+	WarehouseLocationID uuid.UUID  `json:"warehouse_location_id"`
+	LocationPath        string     `json:"location_path"`
+	Quantity            int        `json:"quantity"`
+}
+
+type ScanSerialResponse struct {
+	SerialNumber        string     `json:"serial_number"`
+	Status              string     `json:"status"`
+	WarehouseLocationID *uuid.UUID `json:"warehouse_location_id"`
+	LocationPath        string     `json:"location_path,omitempty"`
+}
+
+// ScanResponse adalah hasil resolusi kode hasil scan. MatchedBy: barcode, sku, location atau gs1.
+// Unit dan Factor adalah satuan kemasan barcode dan konversinya ke satuan dasar.
+type ScanResponse struct {
+	Code         string                  `json:"code"`
+	MatchedBy    string                  `json:"matched_by"`
+	Product      *ScanProductResponse    `json:"product,omitempty"`
+	Barcode      *ProductBarcodeResponse `json:"barcode,omitempty"`
+	Unit         string                  `json:"unit,omitempty"`
+	Factor       int                     `json:"factor,omitempty"`
+	GTIN         string                  `json:"gtin,omitempty"`
+	LotNumber    string                  `json:"lot_number,omitempty"`
+	ExpiryDate   *time.Time              `json:"expiry_date,omitempty"`
+	SerialNumber string                  `json:"serial_number,omitempty"`
+	Serial       *ScanSerialResponse     `json:"serial,omitempty"`
+	Location     *ScanLocationResponse   `json:"location,omitempty"`
+	Lots         []ScanLotResponse       `json:"lots,omitempty"`
+	Elements     map[string]string       `json:"elements,omitempty"` // element GS1 per application identifier
+}
@@ -0,0 +1,92 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreateCycleCountRequest: isi warehouse_location_id (termasuk turunannya), product_id, atau keduanya
+type CreateCycleCountRequest struct {
+	WarehouseLocationID *uuid.UUID `json:"warehouse_location_id" validate:"required_without=ProductID"`
+	ProductID           *uuid.UUID `json:"product_id" validate:"required_without=WarehouseLocationID"`
+	Note                string     `json:"note"`
+}
+
+type CycleCountEntryRequest struct {
+	LineID          uuid.UUID `json:"line_id" validate:"required"`
+	CountedQuantity int       `json:"counted_quantity" validate:"min=0"`
+	Unit            string    `json:"unit" validate:"max=20"` // kosong berarti satuan dasar
+	SerialNumbers   []string  `json:"serial_numbers" validate:"omitempty,dive,required,max=100"`
+}
+
+// SubmitCycleCountRequest berisi hasil hitung satu penghitung untuk beberapa baris
+type SubmitCycleCountRequest struct {
+	Counts []CycleCountEntryRequest `json:"counts" validate:"required,min=1,dive"`
+}
+
+// CycleCountListRequest untuk query param list sesi hitung
+type CycleCountListRequest struct {
+	Page                int       `query:"page" validate:"min=1"`
+	Limit               int       `query:"limit" validate:"min=1,max=100"`
+	Search              string    `query:"search"`
+	Status              string    `query:"status" validate:"omitempty,oneof=open approved cancelled"`
+	WarehouseLocationID uuid.UUID `query:"warehouse_location_id"`
+	ProductID           uuid.UUID `query:"product_id"`
+}
+
+type CycleCountEntryResponse struct {
+	CountedBy       uuid.UUID `json:"counted_by"`
+	CountedQuantity int       `json:"counted_quantity"`
+	SerialNumbers   []string  `json:"serial_numbers,omitempty"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// CycleCountLineResponse: status pending (belum dihitung), disputed (penghitung berbeda), atau counted
+type CycleCountLineResponse struct {
+	ID                    uuid.UUID                 `json:"id"`
+	ProductStockID        uuid.UUID                 `json:"product_stock_id"`
+	ProductID             uuid.UUID                 `json:"product_id"`
+	ProductName           string                    `json:"product_name"`
+	SKU                   string                    `json:"sku"`
+	WarehouseLocationID   uuid.UUID                 `json:"warehouse_location_id"`
+	WarehouseLocationName string                    `json:"warehouse_location_name"`
+	WarehouseLocationPath string                    `json:"warehouse_location_path"`
+	LotNumber             string                    `json:"lot_number,omitempty"`
+	ExpectedQuantity      int                       `json:"expected_quantity"`
+	CountedQuantity       *int                      `json:"counted_quantity"`
+	Variance              *int                      `json:"variance"`
+	MissingSerials        []string                  `json:"missing_serials,omitempty"`
+	UnexpectedSerials     []string                  `json:"unexpected_serials,omitempty"`
+	ReservedShortfall     int                       `json:"reserved_shortfall,omitempty"`
+	Status                string                    `json:"status"`
+	Entries               []CycleCountEntryResponse `json:"entries"`
+}
+
+type CycleCountSummary struct {
+	TotalLines    int `json:"total_lines"`
+	PendingLines  int `json:"pending_lines"`
+	DisputedLines int `json:"disputed_lines"`
+	VarianceLines int `json:"variance_lines"`
+	NetVariance   int `json:"net_variance"`
+}
+
+// CycleCountResponse; Lines dan Summary hanya diisi pada detail
+type CycleCountResponse struct {
+	ID                    uuid.UUID                `json:"id"`
+	CountNumber           string                   `json:"count_number"`
+	WarehouseLocationID   *uuid.UUID               `json:"warehouse_location_id"`
+	WarehouseLocationName string                   `json:"warehouse_location_name,omitempty"`
+	ProductID             *uuid.UUID               `json:"product_id"`
+	ProductName           string                   `json:"product_name,omitempty"`
+	Status                string                   `json:"status"`
+	Note                  string                   `json:"note"`
+	CreatedBy             uuid.UUID                `json:"created_by"`
+	ApprovedBy            *uuid.UUID               `json:"approved_by"`
+	ApprovedAt            *time.Time               `json:"approved_at"`
+	CancelledBy           *uuid.UUID               `json:"cancelled_by"`
+	CancelledAt           *time.Time               `json:"cancelled_at"`
+	CreatedAt             time.Time                `json:"created_at"`
+	Summary               *CycleCountSummary       `json:"summary,omitempty"`
+	Lines                 []CycleCountLineResponse `json:"lines,omitempty"`
+}
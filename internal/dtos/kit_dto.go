@@ -0,0 +1,91 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ProductComponentRequest struct {
+	ProductID uuid.UUID `json:"product_id" validate:"required"`
+	Quantity  int       `json:"quantity" validate:"required,min=1"`
+}
+
+// SetBillOfMaterialsRequest mengganti seluruh BOM kit; components kosong menghapus BOM
+type SetBillOfMaterialsRequest struct {
+	Components []ProductComponentRequest `json:"components" validate:"omitempty,dive"`
+}
+
+// ProductComponentResponse; Available adalah stok tersedia komponen di seluruh lokasi
+type ProductComponentResponse struct {
+	ProductID   uuid.UUID `json:"product_id"`
+	ProductName string    `json:"product_name"`
+	SKU         string    `json:"sku"`
+	Quantity    int       `json:"quantity"`
+	Available   int       `json:"available"`
+}
+
+// KitBuildableLocationResponse adalah jumlah kit yang bisa dirakit dari stok komponen di satu lokasi
+type KitBuildableLocationResponse struct {
+	WarehouseLocationID   uuid.UUID `json:"warehouse_location_id"`
+	WarehouseLocationName string    `json:"warehouse_location_name"`
+	WarehouseLocationPath string    `json:"warehouse_location_path"`
+	Quantity              int       `json:"quantity"`
+}
+
+// BillOfMaterialsResponse; BuildableQuantity adalah jumlah BuildableByLocation karena perakitan
+// mengambil seluruh komponen dari satu lokasi
+type BillOfMaterialsResponse struct {
+	ProductID           uuid.UUID                      `json:"product_id"`
+	ProductName         string                         `json:"product_name"`
+	SKU                 string                         `json:"sku"`
+	Components          []ProductComponentResponse     `json:"components"`
+	BuildableQuantity   int                            `json:"buildable_quantity"`
+	BuildableByLocation []KitBuildableLocationResponse `json:"buildable_by_location"`
+}
+
+// KitComponentSelection menentukan lot/serial komponen; tanpa lot, komponen lot-tracked diambil FEFO saat assemble
+type KitComponentSelection struct {
+	ProductID     uuid.UUID `json:"product_id" validate:"required"`
+	LotNumber     string    `json:"lot_number" validate:"max=100"`
+	ExpiryDate    string    `json:"expiry_date" validate:"omitempty,datetime=2006-01-02"`
+	SerialNumbers []string  `json:"serial_numbers" validate:"omitempty,dive,required,max=100"`
+}
+
+// KitAssemblyRequest dipakai untuk assemble dan disassemble. lot_number, expiry_date, dan serial_numbers
+// berlaku untuk kit; lot/serial komponen diisi lewat components.
+type KitAssemblyRequest struct {
+	WarehouseLocationID uuid.UUID               `json:"warehouse_location_id" validate:"required"`
+	Quantity            int                     `json:"quantity" validate:"required,min=1"`
+	Unit                string                  `json:"unit" validate:"max=20"` // kosong berarti satuan dasar
+	LotNumber           string                  `json:"lot_number" validate:"max=100"`
+	ExpiryDate          string                  `json:"expiry_date" validate:"omitempty,datetime=2006-01-02"`
+	SerialNumbers       []string                `json:"serial_numbers" validate:"omitempty,dive,required,max=100"`
+	Components          []KitComponentSelection `json:"components" validate:"omitempty,dive"`
+	Note                string                  `json:"note"`
+}
+
+// KitAssemblyListRequest untuk query param list assembly
+type KitAssemblyListRequest struct {
+	Page                int       `query:"page" validate:"min=1"`
+	Limit               int       `query:"limit" validate:"min=1,max=100"`
+	Operation           string    `query:"operation" validate:"omitempty,oneof=assemble disassemble"`
+	ProductID           uuid.UUID `query:"product_id"`
+	WarehouseLocationID uuid.UUID `query:"warehouse_location_id"`
+}
+
+type KitAssemblyResponse struct {
+	ID                    uuid.UUID               `json:"id"`
+	AssemblyNumber        string                  `json:"assembly_number"`
+	Operation             string                  `json:"operation"`
+	ProductID             uuid.UUID               `json:"product_id"`
+	ProductName           string                  `json:"product_name"`
+	SKU                   string                  `json:"sku"`
+	WarehouseLocationID   uuid.UUID               `json:"warehouse_location_id"`
+	WarehouseLocationName string                  `json:"warehouse_location_name"`
+	Quantity              int                     `json:"quantity"`
+	Note                  string                  `json:"note"`
+	CreatedBy             uuid.UUID               `json:"created_by"`
+	CreatedAt             time.Time               `json:"created_at"`
+	Movements             []StockMovementResponse `json:"movements,omitempty"`
+}
@@ -0,0 +1,57 @@
+package dtos
+
+import "github.com/google/uuid"
+
+// LabelTemplateListRequest; kind kosong berarti seluruh template
+type LabelTemplateListRequest struct {
+	Kind string `query:"kind" validate:"omitempty,oneof=product location"`
+}
+
+type ProductLabelItem struct {
+	ProductID uuid.UUID `json:"product_id" validate:"required"`
+	BarcodeID uuid.UUID `json:"barcode_id"`                      // kosong berarti barcode utama, atau SKU bila produk belum punya barcode
+	Copies    int       `json:"copies" validate:"min=0,max=500"` // 0 berarti 1
+}
+
+// ProductLabelRequest mencetak label beberapa produk sekaligus. Template kosong berarti template
+// produk default. StartPosition (khusus pdf) adalah slot pertama yang masih kosong pada lembar pertama.
+type ProductLabelRequest struct {
+	Template      string             `json:"template" validate:"max=50"`
+	Format        string             `json:"format" validate:"required,oneof=zpl pdf"`
+	StartPosition int                `json:"start_position" validate:"min=0"`
+	Items         []ProductLabelItem `json:"items" validate:"required,min=1,max=200,dive"`
+}
+
+// LocationLabelItem; include_children ikut mencetak label seluruh lokasi turunan (misal semua bin dalam satu rack)
+type LocationLabelItem struct {
+	LocationID      uuid.UUID `json:"location_id" validate:"required"`
+	IncludeChildren bool      `json:"include_children"`
+	Copies          int       `json:"copies" validate:"min=0,max=500"` // 0 berarti 1
+}
+
+type LocationLabelRequest struct {
+	Template      string              `json:"template" validate:"max=50"`
+	Format        string              `json:"format" validate:"required,oneof=zpl pdf"`
+	StartPosition int                 `json:"start_position" validate:"min=0"`
+	Items         []LocationLabelItem `json:"items" validate:"required,min=1,max=200,dive"`
+}
+
+// LabelTemplateResponse; ukuran label dalam milimeter, sheet adalah susunan label per lembar A4 untuk pdf
+type LabelTemplateResponse struct {
+	Name         string  `json:"name"`
+	Kind         string  `json:"kind"`
+	Description  string  `json:"description"`
+	WidthMM      float64 `json:"width_mm"`
+	HeightMM     float64 `json:"height_mm"`
+	SheetColumns int     `json:"sheet_columns"`
+	SheetRows    int     `json:"sheet_rows"`
+	IsDefault    bool    `json:"is_default"`
+}
+
+// LabelDocument adalah hasil render label yang dikirim apa adanya ke client
+type LabelDocument struct {
+	ContentType string
+	FileName    string
+	Content     []byte
+	Labels      int
+}
@@ -0,0 +1,146 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OutboundOrderLineRequest; quantity dan unit_price dalam unit (kosong berarti satuan dasar)
+// dan disimpan setelah dikonversi ke satuan dasar
+type OutboundOrderLineRequest struct {
+	ProductID uuid.UUID `json:"product_id" validate:"required"`
+	Quantity  int       `json:"quantity" validate:"required,min=1"`
+	Unit      string    `json:"unit" validate:"max=20"`
+	UnitPrice float64   `json:"unit_price" validate:"min=0"`
+}
+
+// OutboundOrderRequest dipakai untuk membuat pesanan draft maupun mengganti isinya selama masih draft.
+// warehouse_location_id kosong berarti stok dari lokasi mana pun boleh dialokasikan.
+type OutboundOrderRequest struct {
+	CustomerName        string                     `json:"customer_name" validate:"required,max=150"`
+	CustomerReference   string                     `json:"customer_reference" validate:"max=100"`
+	ShippingAddress     string                     `json:"shipping_address"`
+	WarehouseLocationID *uuid.UUID                 `json:"warehouse_location_id"`
+	RequestedShipDate   string                     `json:"requested_ship_date" validate:"omitempty,datetime=2006-01-02"`
+	Carrier             string                     `json:"carrier" validate:"max=100"`
+	Note                string                     `json:"note"`
+	Lines               []OutboundOrderLineRequest `json:"lines" validate:"required,min=1,dive"`
+}
+
+// OutboundOrderListRequest untuk query param list pesanan
+type OutboundOrderListRequest struct {
+	Page                int       `query:"page" validate:"min=1"`
+	Limit               int       `query:"limit" validate:"min=1,max=100"`
+	Search              string    `query:"search"`
+	Status              string    `query:"status" validate:"omitempty,oneof=draft partially_allocated allocated packed shipped cancelled"`
+	WarehouseLocationID uuid.UUID `query:"warehouse_location_id"`
+	ProductID           uuid.UUID `query:"product_id"`
+	PickWaveID          uuid.UUID `query:"pick_wave_id"`
+}
+
+// PackOutboundOrderRequest; allow_partial mengizinkan pesanan yang baru teralokasi sebagian dikemas
+type PackOutboundOrderRequest struct {
+	PackageCount int  `json:"package_count" validate:"min=0"`
+	AllowPartial bool `json:"allow_partial"`
+}
+
+// ShipOutboundLineRequest berisi nomor serial yang dikirim untuk baris produk serialized
+type ShipOutboundLineRequest struct {
+	OrderLineID   uuid.UUID `json:"order_line_id" validate:"required"`
+	SerialNumbers []string  `json:"serial_numbers" validate:"required,min=1,dive,required,max=100"`
+}
+
+// ShipOutboundOrderRequest; carrier kosong mempertahankan carrier yang direncanakan di pesanan
+type ShipOutboundOrderRequest struct {
+	Carrier        string                    `json:"carrier" validate:"max=100"`
+	TrackingNumber string                    `json:"tracking_number" validate:"max=100"`
+	Lines          []ShipOutboundLineRequest `json:"lines" validate:"omitempty,dive"`
+}
+
+// OutboundAllocationResponse; Status adalah status reservasi (active, consumed, released)
+type OutboundAllocationResponse struct {
+	ID                    uuid.UUID `json:"id"`
+	StockReservationID    uuid.UUID `json:"stock_reservation_id"`
+	WarehouseLocationID   uuid.UUID `json:"warehouse_location_id"`
+	WarehouseLocationName string    `json:"warehouse_location_name"`
+	WarehouseLocationPath string    `json:"warehouse_location_path"`
+	Quantity              int       `json:"quantity"`
+	Status                string    `json:"status"`
+}
+
+// OutboundOrderLineResponse; BackorderQuantity = quantity - allocated_quantity
+type OutboundOrderLineResponse struct {
+	ID                uuid.UUID                    `json:"id"`
+	ProductID         uuid.UUID                    `json:"product_id"`
+	ProductName       string                       `json:"product_name"`
+	SKU               string                       `json:"sku"`
+	Quantity          int                          `json:"quantity"`
+	AllocatedQuantity int                          `json:"allocated_quantity"`
+	BackorderQuantity int                          `json:"backorder_quantity"`
+	PackedQuantity    int                          `json:"packed_quantity"`
+	ShippedQuantity   int                          `json:"shipped_quantity"`
+	UnitPrice         float64                      `json:"unit_price"`
+	LineTotal         float64                      `json:"line_total"`
+	Status            string                       `json:"status"`
+	Allocations       []OutboundAllocationResponse `json:"allocations"`
+}
+
+type OutboundOrderResponse struct {
+	ID                    uuid.UUID                   `json:"id"`
+	OrderNumber           string                      `json:"order_number"`
+	CustomerName          string                      `json:"customer_name"`
+	CustomerReference     string                      `json:"customer_reference"`
+	ShippingAddress       string                      `json:"shipping_address"`
+	WarehouseLocationID   *uuid.UUID                  `json:"warehouse_location_id"`
+	WarehouseLocationName string                      `json:"warehouse_location_name,omitempty"`
+	PickWaveID            *uuid.UUID                  `json:"pick_wave_id"`
+	Status                string                      `json:"status"`
+	RequestedShipDate     *time.Time                  `json:"requested_ship_date"`
+	Note                  string                      `json:"note"`
+	PackageCount          int                         `json:"package_count"`
+	Carrier               string                      `json:"carrier"`
+	TrackingNumber        string                      `json:"tracking_number"`
+	TotalQuantity         int                         `json:"total_quantity"`
+	AllocatedQuantity     int                         `json:"allocated_quantity"`
+	ShippedQuantity       int                         `json:"shipped_quantity"`
+	TotalAmount           float64                     `json:"total_amount"`
+	Lines                 []OutboundOrderLineResponse `json:"lines"`
+	CreatedBy             uuid.UUID                   `json:"created_by"`
+	PackedBy              *uuid.UUID                  `json:"packed_by"`
+	PackedAt              *time.Time                  `json:"packed_at"`
+	ShippedBy             *uuid.UUID                  `json:"shipped_by"`
+	ShippedAt             *time.Time                  `json:"shipped_at"`
+	CancelledBy           *uuid.UUID                  `json:"cancelled_by"`
+	CancelledAt           *time.Time                  `json:"cancelled_at"`
+	CreatedAt             time.Time                   `json:"created_at"`
+	UpdatedAt             time.Time                   `json:"updated_at"`
+}
+
+type PickListItemResponse struct {
+	AllocationID uuid.UUID `json:"allocation_id"`
+	OrderLineID  uuid.UUID `json:"order_line_id"`
+	ProductID    uuid.UUID `json:"product_id"`
+	ProductName  string    `json:"product_name"`
+	SKU          string    `json:"sku"`
+	Quantity     int       `json:"quantity"`
+	LotTracked   bool      `json:"lot_tracked"`
+	Serialized   bool      `json:"serialized"`
+}
+
+// PickListLocationResponse adalah satu titik ambil pada pick list
+type PickListLocationResponse struct {
+	WarehouseLocationID   uuid.UUID              `json:"warehouse_location_id"`
+	WarehouseLocationName string                 `json:"warehouse_location_name"`
+	WarehouseLocationPath string                 `json:"warehouse_location_path"`
+	Items                 []PickListItemResponse `json:"items"`
+}
+
+// PickListResponse mengelompokkan alokasi yang belum dikirim per lokasi, urut path lokasi
+type PickListResponse struct {
+	OrderID       uuid.UUID                  `json:"order_id"`
+	OrderNumber   string                     `json:"order_number"`
+	Status        string                     `json:"status"`
+	TotalQuantity int                        `json:"total_quantity"`
+	Locations     []PickListLocationResponse `json:"locations"`
+}
@@ -0,0 +1,93 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreatePickWaveRequest berisi aturan pemilihan pesanan untuk wave baru.
+// carrier: hanya pesanan dengan carrier tersebut; cutoff (RFC3339 atau YYYY-MM-DD): hanya pesanan
+// yang masuk sebelum cutoff carrier; zone_id: hanya pesanan yang seluruh alokasinya berada di subtree zone;
+// max_lines: batas jumlah baris pesanan dalam satu wave (kosong memakai default konfigurasi).
+type CreatePickWaveRequest struct {
+	Carrier  string     `json:"carrier" validate:"max=100"`
+	Cutoff   string     `json:"cutoff"`
+	ZoneID   *uuid.UUID `json:"zone_id"`
+	MaxLines int        `json:"max_lines" validate:"omitempty,min=1,max=1000"`
+	Note     string     `json:"note"`
+}
+
+// PickWaveListRequest untuk query param list wave
+type PickWaveListRequest struct {
+	Page   int    `query:"page" validate:"min=1"`
+	Limit  int    `query:"limit" validate:"min=1,max=100"`
+	Status string `query:"status" validate:"omitempty,oneof=open completed cancelled"`
+}
+
+type PickWaveOrderResponse struct {
+	ID           uuid.UUID `json:"id"`
+	OrderNumber  string    `json:"order_number"`
+	CustomerName string    `json:"customer_name"`
+	Carrier      string    `json:"carrier"`
+	Status       string    `json:"status"`
+	LineCount    int       `json:"line_count"`
+}
+
+type PickWaveResponse struct {
+	ID          uuid.UUID               `json:"id"`
+	WaveNumber  string                  `json:"wave_number"`
+	Status      string                  `json:"status"`
+	Carrier     string                  `json:"carrier"`
+	Cutoff      *time.Time              `json:"cutoff"`
+	ZoneID      *uuid.UUID              `json:"zone_id"`
+	ZoneName    string                  `json:"zone_name,omitempty"`
+	MaxLines    int                     `json:"max_lines"`
+	OrderCount  int                     `json:"order_count"`
+	LineCount   int                     `json:"line_count"`
+	Note        string                  `json:"note"`
+	Orders      []PickWaveOrderResponse `json:"orders,omitempty"`
+	CreatedBy   uuid.UUID               `json:"created_by"`
+	CompletedBy *uuid.UUID              `json:"completed_by"`
+	CompletedAt *time.Time              `json:"completed_at"`
+	CancelledBy *uuid.UUID              `json:"cancelled_by"`
+	CancelledAt *time.Time              `json:"cancelled_at"`
+	CreatedAt   time.Time               `json:"created_at"`
+	UpdatedAt   time.Time               `json:"updated_at"`
+}
+
+// PickStopOrderResponse adalah porsi satu pesanan dari item yang diambil di sebuah stop (untuk sortir ke tote)
+type PickStopOrderResponse struct {
+	OrderID      uuid.UUID `json:"order_id"`
+	OrderNumber  string    `json:"order_number"`
+	AllocationID uuid.UUID `json:"allocation_id"`
+	Quantity     int       `json:"quantity"`
+}
+
+type PickStopItemResponse struct {
+	ProductID   uuid.UUID               `json:"product_id"`
+	ProductName string                  `json:"product_name"`
+	SKU         string                  `json:"sku"`
+	Quantity    int                     `json:"quantity"`
+	LotTracked  bool                    `json:"lot_tracked"`
+	Serialized  bool                    `json:"serialized"`
+	Orders      []PickStopOrderResponse `json:"orders"`
+}
+
+// PickStopResponse adalah satu lokasi yang dikunjungi picker, Sequence dimulai dari 1
+type PickStopResponse struct {
+	Sequence              int                    `json:"sequence"`
+	WarehouseLocationID   uuid.UUID              `json:"warehouse_location_id"`
+	WarehouseLocationName string                 `json:"warehouse_location_name"`
+	WarehouseLocationPath string                 `json:"warehouse_location_path"`
+	Items                 []PickStopItemResponse `json:"items"`
+}
+
+// PickRouteResponse adalah pick list gabungan seluruh pesanan wave dalam urutan kunjungan
+type PickRouteResponse struct {
+	WaveID        uuid.UUID          `json:"wave_id"`
+	WaveNumber    string             `json:"wave_number"`
+	Status        string             `json:"status"`
+	TotalQuantity int                `json:"total_quantity"`
+	Stops         []PickStopResponse `json:"stops"`
+}
@@ -0,0 +1,143 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreatePriceListRequest struct {
+	Code          string `json:"code" validate:"required,max=50"`
+	Name          string `json:"name" validate:"required,max=150"`
+	Type          string `json:"type" validate:"required,oneof=list cost customer_group"`
+	CustomerGroup string `json:"customer_group" validate:"max=100"`   // wajib untuk type customer_group
+	Currency      string `json:"currency" validate:"omitempty,len=3"` // default IDR
+	IsDefault     bool   `json:"is_default"`
+	Description   string `json:"description"`
+}
+
+// UpdatePriceListRequest: field kosong tidak diubah; type tidak bisa diubah
+type UpdatePriceListRequest struct {
+	Code          string `json:"code" validate:"max=50"`
+	Name          string `json:"name" validate:"max=150"`
+	CustomerGroup string `json:"customer_group" validate:"max=100"`
+	Currency      string `json:"currency" validate:"omitempty,len=3"`
+	IsDefault     *bool  `json:"is_default"`
+	IsActive      *bool  `json:"is_active"`
+	Description   string `json:"description"`
+}
+
+// PriceListListRequest untuk query param list daftar harga
+type PriceListListRequest struct {
+	Page          int    `query:"page" validate:"min=1"`
+	Limit         int    `query:"limit" validate:"min=1,max=100"`
+	Search        string `query:"search"`
+	Type          string `query:"type" validate:"omitempty,oneof=list cost customer_group"`
+	CustomerGroup string `query:"customer_group"`
+	IsActive      *bool  `query:"is_active"`
+}
+
+type PriceListResponse struct {
+	ID            uuid.UUID `json:"id"`
+	Code          string    `json:"code"`
+	Name          string    `json:"name"`
+	Type          string    `json:"type"`
+	CustomerGroup string    `json:"customer_group,omitempty"`
+	Currency      string    `json:"currency"`
+	IsDefault     bool      `json:"is_default"`
+	IsActive      bool      `json:"is_active"`
+	Description   string    `json:"description"`
+	CreatedBy     uuid.UUID `json:"created_by"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// ProductPriceRequest menambah harga produk. Tanggal berformat RFC3339 atau YYYY-MM-DD (awal hari);
+// effective_from kosong berarti sekarang, effective_to kosong berarti berlaku tanpa batas (eksklusif).
+type ProductPriceRequest struct {
+	ProductID     uuid.UUID `json:"product_id" validate:"required"`
+	Price         float64   `json:"price" validate:"min=0"`
+	EffectiveFrom string    `json:"effective_from"`
+	EffectiveTo   string    `json:"effective_to"`
+	Note          string    `json:"note"`
+}
+
+// UpdateProductPriceRequest: field nil tidak diubah; effective_to "" membuat harga berlaku tanpa batas
+type UpdateProductPriceRequest struct {
+	Price         *float64 `json:"price" validate:"omitempty,min=0"`
+	EffectiveFrom *string  `json:"effective_from"`
+	EffectiveTo   *string  `json:"effective_to"`
+	Note          string   `json:"note"`
+}
+
+// ProductPriceListRequest; at menampilkan hanya harga yang berlaku pada waktu tersebut
+type ProductPriceListRequest struct {
+	Page      int       `query:"page" validate:"min=1"`
+	Limit     int       `query:"limit" validate:"min=1,max=100"`
+	Search    string    `query:"search"` // nama produk atau SKU
+	ProductID uuid.UUID `query:"product_id"`
+	At        string    `query:"at"` // RFC3339 atau YYYY-MM-DD (akhir hari)
+}
+
+// ProductPriceHistoryRequest untuk riwayat perubahan harga pada satu daftar harga
+type ProductPriceHistoryRequest struct {
+	Page      int       `query:"page" validate:"min=1"`
+	Limit     int       `query:"limit" validate:"min=1,max=100"`
+	ProductID uuid.UUID `query:"product_id"`
+	From      string    `query:"from"` // RFC3339 atau YYYY-MM-DD (awal hari)
+	To        string    `query:"to"`   // RFC3339 atau YYYY-MM-DD (akhir hari)
+}
+
+// PriceLookupRequest; at kosong berarti sekarang
+type PriceLookupRequest struct {
+	At string `query:"at"` // RFC3339 atau YYYY-MM-DD (akhir hari)
+}
+
+type ProductPriceResponse struct {
+	ID            uuid.UUID  `json:"id"`
+	PriceListID   uuid.UUID  `json:"price_list_id"`
+	ProductID     uuid.UUID  `json:"product_id"`
+	ProductName   string     `json:"product_name"`
+	SKU           string     `json:"sku"`
+	Price         float64    `json:"price"`
+	EffectiveFrom time.Time  `json:"effective_from"`
+	EffectiveTo   *time.Time `json:"effective_to"`
+	CreatedBy     uuid.UUID  `json:"created_by"`
+	UpdatedBy     uuid.UUID  `json:"updated_by"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+type ProductPriceHistoryResponse struct {
+	ID               uuid.UUID  `json:"id"`
+	ProductPriceID   uuid.UUID  `json:"product_price_id"`
+	PriceListID      uuid.UUID  `json:"price_list_id"`
+	ProductID        uuid.UUID  `json:"product_id"`
+	ProductName      string     `json:"product_name"`
+	SKU              string     `json:"sku"`
+	Action           string     `json:"action"`
+	OldPrice         *float64   `json:"old_price"`
+	NewPrice         *float64   `json:"new_price"`
+	OldEffectiveFrom *time.Time `json:"old_effective_from"`
+	OldEffectiveTo   *time.Time `json:"old_effective_to"`
+	NewEffectiveFrom *time.Time `json:"new_effective_from"`
+	NewEffectiveTo   *time.Time `json:"new_effective_to"`
+	Note             string     `json:"note"`
+	ChangedBy        uuid.UUID  `json:"changed_by"`
+	ChangedAt        time.Time  `json:"changed_at"`
+}
+
+// PriceLookupResponse adalah harga yang berlaku untuk produk pada waktu At. Varian tanpa harga
+// sendiri memakai harga parent; PricedProductID menunjukkan produk asal harga.
+type PriceLookupResponse struct {
+	PriceListID     uuid.UUID  `json:"price_list_id"`
+	PriceListCode   string     `json:"price_list_code"`
+	Currency        string     `json:"currency"`
+	ProductID       uuid.UUID  `json:"product_id"`
+	PricedProductID uuid.UUID  `json:"priced_product_id"`
+	ProductPriceID  uuid.UUID  `json:"product_price_id"`
+	Price           float64    `json:"price"`
+	EffectiveFrom   time.Time  `json:"effective_from"`
+	EffectiveTo     *time.Time `json:"effective_to"`
+	At              time.Time  `json:"at"`
+}
@@ -6,11 +6,31 @@ import (
 	"github.com/google/uuid"
 )
 
+// CreateProductRequest; variant_attributes menjadikan produk parent varian, sedangkan parent_id
+// beserta attributes membuat varian (category mengikuti parent)
 type CreateProductRequest struct {
-	Name        string    `json:"name" validate:"required"`
-	SKU         string    `json:"sku" validate:"required"`
-	CategoryID  uuid.UUID `json:"category_id" validate:"required"`
-	Description string    `json:"description"`
+	Name              string            `json:"name" validate:"required"`
+	SKU               string            `json:"sku" validate:"required"`
+	CategoryID        uuid.UUID         `json:"category_id" validate:"required_without=ParentID"`
+	Description       string            `json:"description"`
+	LotTracked        bool              `json:"lot_tracked"`
+	Serialized        bool              `json:"serialized"`
+	ParentID          *uuid.UUID        `json:"parent_id"`
+	VariantAttributes []string          `json:"variant_attributes" validate:"omitempty,unique,dive,required,max=50"`
+	Attributes        map[string]string `json:"attributes" validate:"omitempty,dive,keys,required,max=50,endkeys,required,max=100"`
+	// Nilai atribut khusus sesuai skema category
+	CustomAttributes map[string]interface{} `json:"custom_attributes"`
+	// Satuan dasar (default each) dan satuan alternatif dengan faktor konversinya
+	BaseUnit string        `json:"base_unit" validate:"max=20"`
+	Units    []ProductUnit `json:"units" validate:"omitempty,unique=Unit,dive"`
+	// Metode valuasi; kosong berarti mengikuti default global
+	CostingMethod string `json:"costing_method" validate:"omitempty,oneof=fifo moving_average"`
+}
+
+// ProductUnit: satu Unit setara Factor satuan dasar produk
+type ProductUnit struct {
+	Unit   string `json:"unit" validate:"required,max=20"`
+	Factor int    `json:"factor" validate:"required,min=2"`
 }
 
 type UpdateProductRequest struct {
@@ -18,6 +38,17 @@ type UpdateProductRequest struct {
 	SKU         string    `json:"sku"`
 	CategoryID  uuid.UUID `json:"category_id"`
 	Description string    `json:"description"`
+	LotTracked  *bool     `json:"lot_tracked"`
+	Serialized  *bool     `json:"serialized"`
+	// Nilai atribut varian; nil berarti tidak diubah
+	Attributes map[string]string `json:"attributes" validate:"omitempty,dive,keys,required,max=50,endkeys,required,max=100"`
+	// Atribut khusus; nil berarti tidak diubah, tetapi tetap divalidasi ulang bila category berganti
+	CustomAttributes map[string]interface{} `json:"custom_attributes"`
+	// base_unit kosong dan units nil berarti tidak diubah
+	BaseUnit string        `json:"base_unit" validate:"max=20"`
+	Units    []ProductUnit `json:"units" validate:"omitempty,unique=Unit,dive"`
+	// Kosong berarti tidak diubah; terkunci selama ada stok on hand
+	CostingMethod string `json:"costing_method" validate:"omitempty,oneof=fifo moving_average"`
 }
 
 type ProductResponse struct {
@@ -26,35 +57,75 @@ type ProductResponse struct {
 	SKU         string    `json:"sku"`
 	CategoryID  uuid.UUID `json:"category_id"`
 	Description string    `json:"description"`
-	CreatedAt   string    `json:"created_at"`
-	UpdatedAt   string    `json:"updated_at"`
+	LotTracked  bool      `json:"lot_tracked"`
+	Serialized  bool      `json:"serialized"`
+	// Hanya diisi untuk varian (parent_id, attributes) atau parent (variant_attributes, variants)
+	ParentID          *uuid.UUID               `json:"parent_id,omitempty"`
+	VariantAttributes []string                 `json:"variant_attributes,omitempty"`
+	Attributes        map[string]string        `json:"attributes,omitempty"`
+	Variants          []ProductVariantResponse `json:"variants,omitempty"`
+	CustomAttributes  map[string]interface{}   `json:"custom_attributes,omitempty"`
+	BaseUnit          string                   `json:"base_unit"`
+	Units             []ProductUnit            `json:"units,omitempty"`
+	Barcodes          []ProductBarcodeResponse `json:"barcodes,omitempty"`
+	CostingMethod     string                   `json:"costing_method"` // metode efektif (produk atau default global)
+	CreatedAt         string                   `json:"created_at"`
+	UpdatedAt         string                   `json:"updated_at"`
+	// Hanya diisi untuk kit (produk yang punya bill of materials) pada detail produk
+	Components          []ProductComponentResponse     `json:"components,omitempty"`
+	BuildableQuantity   *int                           `json:"buildable_quantity,omitempty"`
+	BuildableByLocation []KitBuildableLocationResponse `json:"buildable_by_location,omitempty"`
+}
+
+// AttributeDefinition adalah satu atribut skema category; allowed_values hanya untuk string dan number
+type AttributeDefinition struct {
+	Name          string   `json:"name" validate:"required,max=50"`
+	Type          string   `json:"type" validate:"required,oneof=string number boolean date"`
+	Required      bool     `json:"required"`
+	AllowedValues []string `json:"allowed_values" validate:"omitempty,unique,dive,required,max=100"`
 }
 
 type CreateProductCategoryRequest struct {
-	Name        string `json:"name" validate:"required"`
-	Description string `json:"description"`
+	Name            string                `json:"name" validate:"required"`
+	Description     string                `json:"description"`
+	AttributeSchema []AttributeDefinition `json:"attribute_schema" validate:"omitempty,unique=Name,dive"`
 }
 
 type UpdateProductCategoryRequest struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
+	// nil berarti skema tidak diubah, array kosong menghapus skema
+	AttributeSchema []AttributeDefinition `json:"attribute_schema" validate:"omitempty,unique=Name,dive"`
 }
 
 type ProductCategoryResponse struct {
-	ID          uuid.UUID `json:"id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	CreatedAt   string    `json:"created_at"`
+	ID              uuid.UUID             `json:"id"`
+	Name            string                `json:"name"`
+	Description     string                `json:"description"`
+	LotTracked      bool                  `json:"lot_tracked"`
+	Serialized      bool                  `json:"serialized"`
+	AttributeSchema []AttributeDefinition `json:"attribute_schema"`
+	CreatedAt       string                `json:"created_at"`
 }
 
 type CreateProductStockRequest struct {
 	ProductID           uuid.UUID `json:"product_id" validate:"required"`
 	WarehouseLocationID uuid.UUID `json:"warehouse_location_id" validate:"required"`
 	Quantity            int       `json:"quantity" validate:"required,min=0"`
+	Unit                string    `json:"unit" validate:"max=20"` // kosong berarti satuan dasar
+	LotNumber           string    `json:"lot_number" validate:"max=100"`
+	ExpiryDate          string    `json:"expiry_date" validate:"omitempty,datetime=2006-01-02"`
+	SerialNumbers       []string  `json:"serial_numbers" validate:"omitempty,dive,required,max=100"`
+	UnitCost            *float64  `json:"unit_cost" validate:"omitempty,min=0"` // biaya per unit; kosong berarti biaya rata-rata saat ini
 }
 
 type UpdateProductStockRequest struct {
-	Quantity int `json:"quantity" validate:"min=0"`
+	Quantity      int      `json:"quantity" validate:"min=0"`
+	Unit          string   `json:"unit" validate:"max=20"` // kosong berarti satuan dasar
+	LotNumber     string   `json:"lot_number" validate:"max=100"`
+	ExpiryDate    string   `json:"expiry_date" validate:"omitempty,datetime=2006-01-02"`
+	SerialNumbers []string `json:"serial_numbers" validate:"omitempty,dive,required,max=100"`
+	UnitCost      *float64 `json:"unit_cost" validate:"omitempty,min=0"` // hanya bila quantity bertambah
 }
 
 type ProductStockResponse struct {
@@ -62,8 +133,43 @@ type ProductStockResponse struct {
 	ProductID           uuid.UUID `json:"product_id"`
 	WarehouseLocationID uuid.UUID `json:"warehouse_location_id"`
 	Quantity            int       `json:"quantity"`
+	OnHand              int       `json:"on_hand"`
+	Reserved            int       `json:"reserved"`
+	Available           int       `json:"available"`
+	StockValue          float64   `json:"stock_value"`
 	Status              string    `json:"status"`
 	UpdatedAt           string    `json:"updated_at"`
+	// Diisi bila quantity diminta dalam satuan tertentu
+	InUnit *StockQuantityInUnit `json:"in_unit,omitempty"`
+}
+
+// StockQuantityInUnit menampilkan quantity stok dalam satuan yang diminta (quantity satuan dasar / factor)
+type StockQuantityInUnit struct {
+	Unit      string  `json:"unit"`
+	Factor    int     `json:"factor"`
+	OnHand    float64 `json:"on_hand"`
+	Reserved  float64 `json:"reserved"`
+	Available float64 `json:"available"`
+}
+
+// MovementQuantityInUnit menampilkan quantity entri ledger dalam satuan yang diminta
+type MovementQuantityInUnit struct {
+	Unit           string  `json:"unit"`
+	Factor         int     `json:"factor"`
+	Quantity       float64 `json:"quantity"`
+	Delta          float64 `json:"delta"`
+	BalanceAfter   float64 `json:"balance_after"`
+	RunningBalance float64 `json:"running_balance"`
+}
+
+// StockCardInUnit menampilkan saldo kartu stok dalam satuan yang diminta
+type StockCardInUnit struct {
+	Unit           string  `json:"unit"`
+	Factor         int     `json:"factor"`
+	OpeningBalance float64 `json:"opening_balance"`
+	TotalIn        float64 `json:"total_in"`
+	TotalOut       float64 `json:"total_out"`
+	ClosingBalance float64 `json:"closing_balance"`
 }
 
 type ApiResponse struct {
@@ -81,36 +187,122 @@ type ProductListResponse struct {
 	CategoryID   uuid.UUID `json:"category_id"`
 	CategoryName string    `json:"category_name"`
 	Description  string    `json:"description"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	LotTracked   bool      `json:"lot_tracked"`
+	Serialized   bool      `json:"serialized"`
+	// Untuk parent, stok adalah gabungan stok varian (hanya varian yang cocok bila difilter atribut)
+	ParentID          *uuid.UUID             `json:"parent_id,omitempty"`
+	VariantAttributes []string               `json:"variant_attributes,omitempty"`
+	Attributes        map[string]string      `json:"attributes,omitempty"`
+	CustomAttributes  map[string]interface{} `json:"custom_attributes,omitempty"`
+	BaseUnit          string                 `json:"base_unit"`
+	VariantCount      int                    `json:"variant_count"`
+	OnHand            int                    `json:"on_hand"`
+	Reserved          int                    `json:"reserved"`
+	Quarantined       int                    `json:"quarantined"`
+	Available         int                    `json:"available"`
+	Price             *ProductListPrice      `json:"price,omitempty"` // hanya bila with_price
+	CreatedAt         time.Time              `json:"created_at"`
+	UpdatedAt         time.Time              `json:"updated_at"`
+}
+
+// ProductListPrice adalah harga produk yang berlaku saat ini; Price nil bila produk belum punya harga
+type ProductListPrice struct {
+	PriceListID   uuid.UUID  `json:"price_list_id"`
+	PriceListCode string     `json:"price_list_code"`
+	Currency      string     `json:"currency"`
+	Price         *float64   `json:"price"`
+	EffectiveFrom *time.Time `json:"effective_from,omitempty"`
+	EffectiveTo   *time.Time `json:"effective_to,omitempty"`
+}
+
+// ProductVariantResponse adalah satu varian pada detail parent beserta stoknya
+type ProductVariantResponse struct {
+	ID          uuid.UUID         `json:"id"`
+	Name        string            `json:"name"`
+	SKU         string            `json:"sku"`
+	Attributes  map[string]string `json:"attributes"`
+	OnHand      int               `json:"on_hand"`
+	Reserved    int               `json:"reserved"`
+	Quarantined int               `json:"quarantined"`
+	Available   int               `json:"available"`
 }
 
 // WarehouseLocationListResponse
 type WarehouseLocationListResponse struct {
-	ID          uuid.UUID `json:"id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	CreatedAt   time.Time `json:"created_at"`
+	ID          uuid.UUID  `json:"id"`
+	ParentID    *uuid.UUID `json:"parent_id"`
+	Type        string     `json:"type"`
+	Code        string     `json:"code"`
+	Path        string     `json:"path"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Quarantine  bool       `json:"quarantine"`
+	CreatedAt   time.Time  `json:"created_at"`
 }
 
+// CreateWarehouseLocationRequest: Code wajib untuk lokasi yang punya parent. Quarantine hanya bisa
+// disetel saat dibuat dan otomatis berlaku untuk lokasi di bawah lokasi karantina.
 type CreateWarehouseLocationRequest struct {
-	Name        string `json:"name" validate:"required"`
-	Description string `json:"description"`
+	ParentID    *uuid.UUID `json:"parent_id"`
+	Type        string     `json:"type" validate:"omitempty,oneof=site zone aisle rack bin"`
+	Code        string     `json:"code" validate:"omitempty,alphanum,max=20"`
+	Name        string     `json:"name" validate:"required"`
+	Description string     `json:"description"`
+	Quarantine  bool       `json:"quarantine"`
 }
 
-// UpdateWarehouseLocationRequest
+// UpdateWarehouseLocationRequest: mengubah Code ikut memperbarui path seluruh subtree
 type UpdateWarehouseLocationRequest struct {
+	Code        string `json:"code" validate:"omitempty,alphanum,max=20"`
 	Name        string `json:"name"`
 	Description string `json:"description"`
 }
 
 // WarehouseLocationResponse
 type WarehouseLocationResponse struct {
-	ID          uuid.UUID `json:"id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          uuid.UUID  `json:"id"`
+	ParentID    *uuid.UUID `json:"parent_id"`
+	Type        string     `json:"type"`
+	Code        string     `json:"code"`
+	Path        string     `json:"path"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Quarantine  bool       `json:"quarantine"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// WarehouseLocationTreeNode untuk tampilan pohon lokasi
+type WarehouseLocationTreeNode struct {
+	ID         uuid.UUID                    `json:"id"`
+	Type       string                       `json:"type"`
+	Code       string                       `json:"code"`
+	Path       string                       `json:"path"`
+	Name       string                       `json:"name"`
+	Quarantine bool                         `json:"quarantine"`
+	Children   []*WarehouseLocationTreeNode `json:"children"`
+}
+
+// LocationStockRollupResponse berisi total stok per produk pada satu subtree lokasi
+type LocationStockRollupResponse struct {
+	LocationID    uuid.UUID                 `json:"location_id"`
+	Path          string                    `json:"path"`
+	LocationCount int                       `json:"location_count"`
+	OnHand        int                       `json:"on_hand"`
+	Reserved      int                       `json:"reserved"`
+	Quarantined   int                       `json:"quarantined"`
+	Available     int                       `json:"available"`
+	Products      []LocationStockRollupItem `json:"products"`
+}
+
+type LocationStockRollupItem struct {
+	ProductID   uuid.UUID `json:"product_id"`
+	ProductName string    `json:"product_name"`
+	SKU         string    `json:"sku"`
+	OnHand      int       `json:"on_hand"`
+	Reserved    int       `json:"reserved"`
+	Quarantined int       `json:"quarantined"`
+	Available   int       `json:"available"`
 }
 
 // ProductStockListResponse: Enriched dengan ProductName dan WarehouseName
@@ -121,8 +313,14 @@ type ProductStockListResponse struct {
 	WarehouseLocationID uuid.UUID `json:"warehouse_location_id"`
 	WarehouseName       string    `json:"warehouse_name"`
 	Quantity            int       `json:"quantity"`
+	OnHand              int       `json:"on_hand"`
+	Reserved            int       `json:"reserved"`
+	Available           int       `json:"available"`
+	StockValue          float64   `json:"stock_value"`
 	Status              string    `json:"status"`
 	UpdatedAt           time.Time `json:"updated_at"`
+	// Hanya untuk produk yang mengenal satuan yang diminta
+	InUnit *StockQuantityInUnit `json:"in_unit,omitempty"`
 }
 
 // LowStockDetail untuk item low-stock dengan detail
@@ -133,6 +331,9 @@ type LowStockDetail struct {
 	WarehouseName  string    `json:"warehouse_name"`
 	Quantity       int       `json:"quantity"`
 	Status         string    `json:"status"`
+	MinQuantity    int       `json:"min_quantity"`
+	ReorderPoint   int       `json:"reorder_point"`
+	MaxQuantity    int       `json:"max_quantity"`
 	UpdatedByEmail string    `json:"updated_by_email"`
 	UpdatedByName  string    `json:"updated_by_name"`
 	UpdatedAt      time.Time `json:"updated_at"`
@@ -162,11 +363,25 @@ type RecentAddition struct {
 
 // DashboardResponse yang lebih modular dan kompleks
 type DashboardResponse struct {
-	TotalStock       int64              `json:"total_stock"`
-	NumberOfProducts int64              `json:"number_of_products"`
-	LowStockItems    []LowStockDetail   `json:"low_stock_items"`    // List detail low-stock
-	OutOfStockItems  []OutOfStockDetail `json:"out_of_stock_items"` // List detail out-of-stock
-	RecentAdditions  []RecentAddition   `json:"recent_additions"`   // List produk baru (misal last 5)
+	TotalStock       int64               `json:"total_stock"`
+	NumberOfProducts int64               `json:"number_of_products"`
+	LowStockItems    []LowStockDetail    `json:"low_stock_items"`    // List detail low-stock
+	OutOfStockItems  []OutOfStockDetail  `json:"out_of_stock_items"` // List detail out-of-stock
+	RecentAdditions  []RecentAddition    `json:"recent_additions"`   // List produk baru (misal last 5)
+	ExpiringLots     []ExpiringLotDetail `json:"expiring_lots"`      // Lot yang kedaluwarsa dalam N hari
+}
+
+// ExpiringLotDetail untuk lot yang mendekati (atau sudah lewat) tanggal kedaluwarsa
+type ExpiringLotDetail struct {
+	LotID         uuid.UUID `json:"lot_id"`
+	LotNumber     string    `json:"lot_number"`
+	ProductID     uuid.UUID `json:"product_id"`
+	ProductName   string    `json:"product_name"`
+	WarehouseID   uuid.UUID `json:"warehouse_id"`
+	WarehouseName string    `json:"warehouse_name"`
+	Quantity      int       `json:"quantity"`
+	ExpiryDate    time.Time `json:"expiry_date"`
+	DaysToExpiry  int       `json:"days_to_expiry"`
 }
 
 // PaginationRequest untuk query param
@@ -178,7 +393,59 @@ type PaginationRequest struct {
 	Order  string `query:"order" validate:"oneof=asc desc"`
 	// Filter spesifik
 	CategoryID uuid.UUID `query:"category_id"`
-	Status     string    `query:"status" validate:"oneof=available low-stock out-of-stock"`
+	Status     string    `query:"status" validate:"omitempty,oneof=available low-stock out-of-stock quarantined"`
+	// Filter stok pada lokasi beserta seluruh turunannya
+	WarehouseLocationID uuid.UUID `query:"warehouse_location_id"`
+	// Filter produk: parent_id menampilkan varian sebuah parent (tanpa itu hanya produk level atas),
+	// attribute berformat key:value dan boleh diulang
+	ParentID  uuid.UUID `query:"parent_id"`
+	Attribute []string  `query:"attribute" validate:"omitempty,dive,contains=:"`
+	// Filter atribut khusus category, format key:value dan boleh diulang
+	CustomAttribute []string `query:"custom_attribute" validate:"omitempty,dive,contains=:"`
+	// Tampilkan quantity stok juga dalam satuan ini
+	Unit string `query:"unit" validate:"max=20"`
+	// Sertakan harga yang berlaku saat ini dari price_list_id, atau dari daftar harga default
+	WithPrice   bool      `query:"with_price"`
+	PriceListID uuid.UUID `query:"price_list_id"`
+}
+
+// StockAsOfRequest untuk query posisi stok pada waktu tertentu
+type StockAsOfRequest struct {
+	At                  string    `query:"at" validate:"required"` // RFC3339 atau YYYY-MM-DD (akhir hari)
+	Page                int       `query:"page" validate:"min=1"`
+	Limit               int       `query:"limit" validate:"min=1,max=100"`
+	Search              string    `query:"search"`
+	ProductID           uuid.UUID `query:"product_id"`
+	WarehouseLocationID uuid.UUID `query:"warehouse_location_id"`
+}
+
+// StockMovementHistoryRequest untuk query riwayat ledger; page/limit/search/order mengikuti PaginationRequest
+type StockMovementHistoryRequest struct {
+	Page   int    `query:"page" validate:"min=1"`
+	Limit  int    `query:"limit" validate:"min=1,max=100"`
+	Search string `query:"search"` // nama produk atau SKU
+	Order  string `query:"order" validate:"omitempty,oneof=asc desc"`
+	// Filter spesifik
+	ProductID           uuid.UUID `query:"product_id"`
+	WarehouseLocationID uuid.UUID `query:"warehouse_location_id"` // lokasi beserta seluruh turunannya
+	MovementType        string    `query:"movement_type" validate:"omitempty,oneof=receipt shipment transfer_in transfer_out adjustment damage return count_correction assembly_in assembly_out inbound outbound"`
+	UserID              uuid.UUID `query:"user_id"`
+	From                string    `query:"from"` // RFC3339 atau YYYY-MM-DD (awal hari)
+	To                  string    `query:"to"`   // RFC3339 atau YYYY-MM-DD (akhir hari)
+	ReferenceType       string    `query:"reference_type"`
+	ReferenceID         uuid.UUID `query:"reference_id"`
+	Reference           string    `query:"reference"`              // cari di reference_note
+	Unit                string    `query:"unit" validate:"max=20"` // tampilkan quantity juga dalam satuan ini
+}
+
+// StockCardRequest untuk query kartu stok satu produk
+type StockCardRequest struct {
+	Page                int       `query:"page" validate:"min=1"`
+	Limit               int       `query:"limit" validate:"min=1,max=100"`
+	WarehouseLocationID uuid.UUID `query:"warehouse_location_id"` // lokasi beserta seluruh turunannya
+	From                string    `query:"from"`
+	To                  string    `query:"to"`
+	Unit                string    `query:"unit" validate:"max=20"` // tampilkan quantity juga dalam satuan ini
 }
 
 type Pagination struct {
@@ -190,9 +457,218 @@ type Pagination struct {
 }
 
 type ProductCategoryListResponse struct {
-	ID          uuid.UUID `json:"id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID              uuid.UUID             `json:"id"`
+	Name            string                `json:"name"`
+	Description     string                `json:"description"`
+	AttributeSchema []AttributeDefinition `json:"attribute_schema"`
+	CreatedAt       time.Time             `json:"created_at"`
+	UpdatedAt       time.Time             `json:"updated_at"`
+}
+
+// CreateStockMovementRequest untuk mencatat pergerakan stok manual ke ledger
+type CreateStockMovementRequest struct {
+	ProductID           uuid.UUID `json:"product_id" validate:"required"`
+	WarehouseLocationID uuid.UUID `json:"warehouse_location_id" validate:"required"`
+	MovementType        string    `json:"movement_type" validate:"required,oneof=receipt shipment adjustment damage return"`
+	Direction           string    `json:"direction" validate:"omitempty,oneof=in out"`
+	Quantity            int       `json:"quantity" validate:"required,min=1"`
+	Unit                string    `json:"unit" validate:"max=20"` // kosong berarti satuan dasar
+	LotNumber           string    `json:"lot_number" validate:"max=100"`
+	ExpiryDate          string    `json:"expiry_date" validate:"omitempty,datetime=2006-01-02"`
+	SerialNumbers       []string  `json:"serial_numbers" validate:"omitempty,dive,required,max=100"`
+	Reason              string    `json:"reason" validate:"required,max=50"`
+	ReferenceNote       string    `json:"reference_note" validate:"required,max=255"`
+	UnitCost            *float64  `json:"unit_cost" validate:"omitempty,min=0"` // biaya per unit untuk inbound; kosong berarti biaya rata-rata saat ini
+}
+
+// TrackStockMovementResponse: Movements terisi jika langsung dibukukan, Approval jika menunggu sign-off super_admin
+type TrackStockMovementResponse struct {
+	Movements []StockMovementResponse        `json:"movements,omitempty"`
+	Approval  *StockMovementApprovalResponse `json:"approval,omitempty"`
+}
+
+type MovementReasonCodesResponse struct {
+	MovementType string   `json:"movement_type"`
+	Direction    string   `json:"direction"`
+	ReasonCodes  []string `json:"reason_codes"`
+	// ApprovalAbove: quantity di atas nilai ini butuh sign-off super_admin (nil = tanpa batas)
+	ApprovalAbove *int `json:"approval_above"`
+}
+
+// StockMovementApprovalListRequest untuk query param list approval movement
+type StockMovementApprovalListRequest struct {
+	Page         int       `query:"page" validate:"min=1"`
+	Limit        int       `query:"limit" validate:"min=1,max=100"`
+	Status       string    `query:"status" validate:"omitempty,oneof=pending approved rejected"`
+	MovementType string    `query:"movement_type"`
+	ProductID    uuid.UUID `query:"product_id"`
+}
+
+type DecideStockMovementApprovalRequest struct {
+	Note string `json:"note" validate:"max=255"`
+}
+
+type StockMovementApprovalResponse struct {
+	ID                    uuid.UUID               `json:"id"`
+	ProductID             uuid.UUID               `json:"product_id"`
+	ProductName           string                  `json:"product_name"`
+	WarehouseLocationID   uuid.UUID               `json:"warehouse_location_id"`
+	WarehouseLocationName string                  `json:"warehouse_location_name"`
+	MovementType          string                  `json:"movement_type"`
+	Direction             string                  `json:"direction,omitempty"`
+	Quantity              int                     `json:"quantity"`
+	LotNumber             string                  `json:"lot_number,omitempty"`
+	ExpiryDate            *time.Time              `json:"expiry_date,omitempty"`
+	SerialNumbers         []string                `json:"serial_numbers,omitempty"`
+	UnitCost              *float64                `json:"unit_cost,omitempty"`
+	Reason                string                  `json:"reason"`
+	ReferenceNote         string                  `json:"reference_note"`
+	Status                string                  `json:"status"`
+	RequestedBy           uuid.UUID               `json:"requested_by"`
+	DecidedBy             *uuid.UUID              `json:"decided_by"`
+	DecidedAt             *time.Time              `json:"decided_at"`
+	DecisionNote          string                  `json:"decision_note,omitempty"`
+	CreatedAt             time.Time               `json:"created_at"`
+	Movements             []StockMovementResponse `json:"movements,omitempty"`
+}
+
+type StockMovementResponse struct {
+	ID                  uuid.UUID  `json:"id"`
+	ProductID           uuid.UUID  `json:"product_id"`
+	WarehouseLocationID uuid.UUID  `json:"warehouse_location_id"`
+	MovementType        string     `json:"movement_type"`
+	Quantity            int        `json:"quantity"`
+	Delta               int        `json:"delta"`
+	BalanceAfter        int        `json:"balance_after"`
+	UnitCost            float64    `json:"unit_cost"`
+	TotalCost           float64    `json:"total_cost"`
+	LotID               *uuid.UUID `json:"lot_id,omitempty"`
+	LotNumber           string     `json:"lot_number,omitempty"`
+	SerialNumbers       []string   `json:"serial_numbers,omitempty"`
+	Reason              string     `json:"reason"`
+	ReferenceType       string     `json:"reference_type"`
+	ReferenceID         *uuid.UUID `json:"reference_id"`
+	ReferenceNote       string     `json:"reference_note"`
+	CreatedBy           uuid.UUID  `json:"created_by"`
+	CreatedAt           time.Time  `json:"created_at"`
+}
+
+// StockMovementHistoryResponse adalah satu entri ledger. BalanceAfter adalah saldo di lokasi entri,
+// RunningBalance adalah saldo berjalan produk di seluruh lokasi (riwayat) atau di lingkup kartu stok.
+type StockMovementHistoryResponse struct {
+	ID                    uuid.UUID  `json:"id"`
+	ProductID             uuid.UUID  `json:"product_id"`
+	ProductName           string     `json:"product_name"`
+	SKU                   string     `json:"sku"`
+	WarehouseLocationID   uuid.UUID  `json:"warehouse_location_id"`
+	WarehouseLocationName string     `json:"warehouse_location_name"`
+	WarehouseLocationPath string     `json:"warehouse_location_path"`
+	MovementType          string     `json:"movement_type"`
+	Quantity              int        `json:"quantity"`
+	Delta                 int        `json:"delta"`
+	BalanceAfter          int        `json:"balance_after"`
+	RunningBalance        int        `json:"running_balance"`
+	UnitCost              float64    `json:"unit_cost"`
+	TotalCost             float64    `json:"total_cost"`
+	LotID                 *uuid.UUID `json:"lot_id,omitempty"`
+	LotNumber             string     `json:"lot_number,omitempty"`
+	Reason                string     `json:"reason"`
+	ReferenceType         string     `json:"reference_type"`
+	ReferenceID           *uuid.UUID `json:"reference_id"`
+	ReferenceNote         string     `json:"reference_note"`
+	CreatedBy             uuid.UUID  `json:"created_by"`
+	CreatedAt             time.Time  `json:"created_at"`
+	// Hanya untuk produk yang mengenal satuan yang diminta
+	InUnit *MovementQuantityInUnit `json:"in_unit,omitempty"`
+}
+
+// StockCardResponse: ClosingBalance = OpeningBalance + TotalIn - TotalOut untuk rentang yang diminta
+type StockCardResponse struct {
+	ProductID             uuid.UUID                      `json:"product_id"`
+	ProductName           string                         `json:"product_name"`
+	SKU                   string                         `json:"sku"`
+	WarehouseLocationID   *uuid.UUID                     `json:"warehouse_location_id"`
+	WarehouseLocationName string                         `json:"warehouse_location_name,omitempty"`
+	From                  *time.Time                     `json:"from"`
+	To                    *time.Time                     `json:"to"`
+	OpeningBalance        int                            `json:"opening_balance"`
+	TotalIn               int                            `json:"total_in"`
+	TotalOut              int                            `json:"total_out"`
+	ClosingBalance        int                            `json:"closing_balance"`
+	InUnit                *StockCardInUnit               `json:"in_unit,omitempty"`
+	Entries               []StockMovementHistoryResponse `json:"entries"`
+}
+
+// StockRebuildResult berisi selisih antara proyeksi ProductStock dan saldo ledger
+type StockRebuildResult struct {
+	ProductID           uuid.UUID `json:"product_id"`
+	WarehouseLocationID uuid.UUID `json:"warehouse_location_id"`
+	PreviousQuantity    int       `json:"previous_quantity"`
+	LedgerQuantity      int       `json:"ledger_quantity"`
+	PreviousValue       float64   `json:"previous_value"`
+	LedgerValue         float64   `json:"ledger_value"`
+}
+
+// StockLotResponse untuk rincian stok per lot
+type StockLotResponse struct {
+	ID             uuid.UUID  `json:"id"`
+	ProductStockID uuid.UUID  `json:"product_stock_id"`
+	LotNumber      string     `json:"lot_number"`
+	ExpiryDate     *time.Time `json:"expiry_date"`
+	Quantity       int        `json:"quantity"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// SerialNumberListRequest untuk query param list serial produk
+type SerialNumberListRequest struct {
+	Page                int       `query:"page" validate:"min=1"`
+	Limit               int       `query:"limit" validate:"min=1,max=100"`
+	Search              string    `query:"search"`
+	Status              string    `query:"status" validate:"omitempty,oneof=in_stock issued"`
+	WarehouseLocationID uuid.UUID `query:"warehouse_location_id"`
+}
+
+// SerialNumberResponse untuk registry serial; History hanya diisi pada detail
+type SerialNumberResponse struct {
+	ID                  uuid.UUID               `json:"id"`
+	ProductID           uuid.UUID               `json:"product_id"`
+	SerialNumber        string                  `json:"serial_number"`
+	Status              string                  `json:"status"`
+	WarehouseLocationID *uuid.UUID              `json:"warehouse_location_id"`
+	WarehouseName       string                  `json:"warehouse_name"`
+	CreatedAt           time.Time               `json:"created_at"`
+	UpdatedAt           time.Time               `json:"updated_at"`
+	History             []StockMovementResponse `json:"history,omitempty"`
+}
+
+// UpsertStockThresholdRequest: kosongkan product_id, warehouse_location_id, dan category_id untuk threshold global.
+// product_id tidak boleh digabung dengan category_id. min_quantity tidak boleh melebihi reorder_point.
+type UpsertStockThresholdRequest struct {
+	ProductID           *uuid.UUID `json:"product_id"`
+	WarehouseLocationID *uuid.UUID `json:"warehouse_location_id"`
+	CategoryID          *uuid.UUID `json:"category_id" validate:"omitempty,excluded_with=ProductID WarehouseLocationID"`
+	MinQuantity         int        `json:"min_quantity" validate:"min=0"`
+	ReorderPoint        int        `json:"reorder_point" validate:"min=0,gtefield=MinQuantity"`
+	MaxQuantity         int        `json:"max_quantity" validate:"min=0"`
+}
+
+type StockThresholdListRequest struct {
+	Page                int       `query:"page" validate:"min=1"`
+	Limit               int       `query:"limit" validate:"min=1,max=100"`
+	ProductID           uuid.UUID `query:"product_id"`
+	WarehouseLocationID uuid.UUID `query:"warehouse_location_id"`
+	CategoryID          uuid.UUID `query:"category_id"`
+}
+
+type StockThresholdResponse struct {
+	ID                  *uuid.UUID `json:"id"`
+	Scope               string     `json:"scope"`
+	ProductID           *uuid.UUID `json:"product_id"`
+	WarehouseLocationID *uuid.UUID `json:"warehouse_location_id"`
+	CategoryID          *uuid.UUID `json:"category_id"`
+	MinQuantity         int        `json:"min_quantity"`
+	ReorderPoint        int        `json:"reorder_point"`
+	MaxQuantity         int        `json:"max_quantity"`
+	UpdatedAt           *time.Time `json:"updated_at,omitempty"`
 }
@@ -0,0 +1,126 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PurchaseOrderLineRequest; unit_cost kosong memakai harga dari mapping supplier. Quantity dan unit_cost
+// dalam unit (kosong berarti satuan dasar) dan disimpan setelah dikonversi ke satuan dasar.
+type PurchaseOrderLineRequest struct {
+	ProductID uuid.UUID `json:"product_id" validate:"required"`
+	Quantity  int       `json:"quantity" validate:"required,min=1"`
+	Unit      string    `json:"unit" validate:"max=20"`
+	UnitCost  *float64  `json:"unit_cost" validate:"omitempty,min=0"`
+}
+
+// PurchaseOrderRequest dipakai untuk membuat PO draft maupun mengganti isinya selama masih draft.
+// expected_date kosong dihitung dari lead time supplier.
+type PurchaseOrderRequest struct {
+	SupplierID          uuid.UUID                  `json:"supplier_id" validate:"required"`
+	WarehouseLocationID uuid.UUID                  `json:"warehouse_location_id" validate:"required"`
+	ExpectedDate        string                     `json:"expected_date" validate:"omitempty,datetime=2006-01-02"`
+	Note                string                     `json:"note"`
+	Lines               []PurchaseOrderLineRequest `json:"lines" validate:"required,min=1,dive"`
+}
+
+// PurchaseOrderListRequest untuk query param list PO
+type PurchaseOrderListRequest struct {
+	Page                int       `query:"page" validate:"min=1"`
+	Limit               int       `query:"limit" validate:"min=1,max=100"`
+	Search              string    `query:"search"`
+	Status              string    `query:"status" validate:"omitempty,oneof=draft approved partially_received closed cancelled"`
+	SupplierID          uuid.UUID `query:"supplier_id"`
+	WarehouseLocationID uuid.UUID `query:"warehouse_location_id"`
+	ProductID           uuid.UUID `query:"product_id"`
+}
+
+// PurchaseOrderLineResponse; Variance = received - ordered, ReceiptStatus pending/partial/complete/over
+type PurchaseOrderLineResponse struct {
+	ID                  uuid.UUID `json:"id"`
+	ProductID           uuid.UUID `json:"product_id"`
+	ProductName         string    `json:"product_name"`
+	SKU                 string    `json:"sku"`
+	SupplierSKU         string    `json:"supplier_sku"`
+	Quantity            int       `json:"quantity"`
+	ReceivedQuantity    int       `json:"received_quantity"`
+	OutstandingQuantity int       `json:"outstanding_quantity"`
+	Variance            int       `json:"variance"`
+	ReceiptStatus       string    `json:"receipt_status"`
+	UnitCost            float64   `json:"unit_cost"`
+	LineTotal           float64   `json:"line_total"`
+}
+
+type PurchaseOrderResponse struct {
+	ID                    uuid.UUID                   `json:"id"`
+	PONumber              string                      `json:"po_number"`
+	SupplierID            uuid.UUID                   `json:"supplier_id"`
+	SupplierName          string                      `json:"supplier_name"`
+	WarehouseLocationID   uuid.UUID                   `json:"warehouse_location_id"`
+	WarehouseLocationName string                      `json:"warehouse_location_name"`
+	Status                string                      `json:"status"`
+	ExpectedDate          *time.Time                  `json:"expected_date"`
+	Note                  string                      `json:"note"`
+	TotalQuantity         int                         `json:"total_quantity"`
+	ReceivedQuantity      int                         `json:"received_quantity"`
+	TotalCost             float64                     `json:"total_cost"`
+	Lines                 []PurchaseOrderLineResponse `json:"lines"`
+	CreatedBy             uuid.UUID                   `json:"created_by"`
+	ApprovedBy            *uuid.UUID                  `json:"approved_by"`
+	ApprovedAt            *time.Time                  `json:"approved_at"`
+	ClosedBy              *uuid.UUID                  `json:"closed_by"`
+	ClosedAt              *time.Time                  `json:"closed_at"`
+	CancelledBy           *uuid.UUID                  `json:"cancelled_by"`
+	CancelledAt           *time.Time                  `json:"cancelled_at"`
+	CreatedAt             time.Time                   `json:"created_at"`
+	UpdatedAt             time.Time                   `json:"updated_at"`
+}
+
+// GoodsReceiptLineRequest: baris PO ditunjuk lewat purchase_order_line_id atau code hasil scan
+// (SKU produk atau SKU supplier). warehouse_location_id kosong berarti put-away ke lokasi PO.
+type GoodsReceiptLineRequest struct {
+	PurchaseOrderLineID *uuid.UUID `json:"purchase_order_line_id" validate:"required_without=Code"`
+	Code                string     `json:"code" validate:"required_without=PurchaseOrderLineID,max=100"`
+	Quantity            int        `json:"quantity" validate:"required,min=1"`
+	Unit                string     `json:"unit" validate:"max=20"` // kosong berarti satuan dasar
+	WarehouseLocationID *uuid.UUID `json:"warehouse_location_id"`
+	LotNumber           string     `json:"lot_number" validate:"max=100"`
+	ExpiryDate          string     `json:"expiry_date" validate:"omitempty,datetime=2006-01-02"`
+	SerialNumbers       []string   `json:"serial_numbers" validate:"omitempty,dive,required,max=100"`
+}
+
+// CreateGoodsReceiptRequest; close_purchase_order menutup PO setelah penerimaan ini walau masih kurang
+type CreateGoodsReceiptRequest struct {
+	DeliveryNote       string                    `json:"delivery_note" validate:"max=100"`
+	Note               string                    `json:"note"`
+	ClosePurchaseOrder bool                      `json:"close_purchase_order"`
+	Lines              []GoodsReceiptLineRequest `json:"lines" validate:"required,min=1,dive"`
+}
+
+type GoodsReceiptLineResponse struct {
+	ID                    uuid.UUID  `json:"id"`
+	PurchaseOrderLineID   uuid.UUID  `json:"purchase_order_line_id"`
+	ProductID             uuid.UUID  `json:"product_id"`
+	ProductName           string     `json:"product_name"`
+	SKU                   string     `json:"sku"`
+	WarehouseLocationID   uuid.UUID  `json:"warehouse_location_id"`
+	WarehouseLocationName string     `json:"warehouse_location_name"`
+	Quantity              int        `json:"quantity"`
+	LotNumber             string     `json:"lot_number,omitempty"`
+	ExpiryDate            *time.Time `json:"expiry_date,omitempty"`
+	SerialNumbers         []string   `json:"serial_numbers,omitempty"`
+}
+
+// GoodsReceiptResponse; PurchaseOrder hanya diisi setelah penerimaan dibukukan
+type GoodsReceiptResponse struct {
+	ID              uuid.UUID                  `json:"id"`
+	ReceiptNumber   string                     `json:"receipt_number"`
+	PurchaseOrderID uuid.UUID                  `json:"purchase_order_id"`
+	DeliveryNote    string                     `json:"delivery_note"`
+	Note            string                     `json:"note"`
+	ReceivedBy      uuid.UUID                  `json:"received_by"`
+	CreatedAt       time.Time                  `json:"created_at"`
+	Lines           []GoodsReceiptLineResponse `json:"lines"`
+	PurchaseOrder   *PurchaseOrderResponse     `json:"purchase_order,omitempty"`
+}
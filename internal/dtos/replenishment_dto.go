@@ -0,0 +1,49 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReplenishmentProposalListRequest untuk query param list proposal
+type ReplenishmentProposalListRequest struct {
+	Page                int       `query:"page" validate:"min=1"`
+	Limit               int       `query:"limit" validate:"min=1,max=100"`
+	Status              string    `query:"status" validate:"omitempty,oneof=open converted dismissed obsolete"`
+	SourceType          string    `query:"source_type" validate:"omitempty,oneof=transfer purchase"`
+	ProductID           uuid.UUID `query:"product_id"`
+	WarehouseLocationID uuid.UUID `query:"warehouse_location_id"`
+}
+
+type ReplenishmentProposalResponse struct {
+	ID                    uuid.UUID  `json:"id"`
+	ProductStockID        uuid.UUID  `json:"product_stock_id"`
+	ProductID             uuid.UUID  `json:"product_id"`
+	ProductName           string     `json:"product_name"`
+	SKU                   string     `json:"sku"`
+	WarehouseLocationID   uuid.UUID  `json:"warehouse_location_id"`
+	WarehouseLocationName string     `json:"warehouse_location_name"`
+	AvailableQuantity     int        `json:"available_quantity"`
+	IncomingQuantity      int        `json:"incoming_quantity"`
+	ReorderPoint          int        `json:"reorder_point"`
+	TargetQuantity        int        `json:"target_quantity"`
+	SuggestedQuantity     int        `json:"suggested_quantity"`
+	SourceType            string     `json:"source_type"`
+	SourceLocationID      *uuid.UUID `json:"source_location_id"`
+	SourceLocationName    string     `json:"source_location_name,omitempty"`
+	Status                string     `json:"status"`
+	ReferenceType         string     `json:"reference_type,omitempty"`
+	ReferenceID           *uuid.UUID `json:"reference_id"`
+	ResolvedBy            *uuid.UUID `json:"resolved_by"`
+	ResolvedAt            *time.Time `json:"resolved_at"`
+	CreatedAt             time.Time  `json:"created_at"`
+	UpdatedAt             time.Time  `json:"updated_at"`
+}
+
+// ReplenishmentRunResponse adalah ringkasan satu kali planner berjalan
+type ReplenishmentRunResponse struct {
+	Opened   int `json:"opened"`
+	Updated  int `json:"updated"`
+	Obsolete int `json:"obsolete"`
+}
@@ -0,0 +1,118 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ReturnAuthorizationLineRequest struct {
+	ProductID uuid.UUID `json:"product_id" validate:"required"`
+	Quantity  int       `json:"quantity" validate:"required,min=1"`
+	Unit      string    `json:"unit" validate:"max=20"` // kosong berarti satuan dasar
+}
+
+// CreateReturnAuthorizationRequest; bila outbound_order_id diisi, pesanan harus sudah shipped,
+// produk harus ada di pesanan, dan customer_name boleh kosong (diambil dari pesanan)
+type CreateReturnAuthorizationRequest struct {
+	OutboundOrderID   *uuid.UUID                       `json:"outbound_order_id"`
+	CustomerName      string                           `json:"customer_name" validate:"required_without=OutboundOrderID,max=150"`
+	CustomerReference string                           `json:"customer_reference" validate:"max=100"`
+	Reason            string                           `json:"reason" validate:"required,max=255"`
+	Note              string                           `json:"note"`
+	Lines             []ReturnAuthorizationLineRequest `json:"lines" validate:"required,min=1,dive"`
+}
+
+// ReturnAuthorizationListRequest untuk query param list RMA
+type ReturnAuthorizationListRequest struct {
+	Page            int       `query:"page" validate:"min=1"`
+	Limit           int       `query:"limit" validate:"min=1,max=100"`
+	Search          string    `query:"search"`
+	Status          string    `query:"status" validate:"omitempty,oneof=authorized received completed cancelled"`
+	OutboundOrderID uuid.UUID `query:"outbound_order_id"`
+	ProductID       uuid.UUID `query:"product_id"`
+}
+
+type ReceiveReturnLineRequest struct {
+	ReturnLineID uuid.UUID `json:"return_line_id" validate:"required"`
+	Quantity     int       `json:"quantity" validate:"min=0"`
+	Unit         string    `json:"unit" validate:"max=20"` // kosong berarti satuan dasar
+}
+
+// ReceiveReturnRequest; tanpa lines berarti seluruh quantity yang diizinkan diterima,
+// bila lines diisi maka baris yang tidak disebut dianggap tidak datang
+type ReceiveReturnRequest struct {
+	Lines []ReceiveReturnLineRequest `json:"lines" validate:"omitempty,dive"`
+}
+
+// ReturnInspectionLineRequest; warehouse_location_id wajib untuk restock dan quarantine
+type ReturnInspectionLineRequest struct {
+	ReturnLineID        uuid.UUID  `json:"return_line_id" validate:"required"`
+	Disposition         string     `json:"disposition" validate:"required,oneof=restock quarantine scrap"`
+	Quantity            int        `json:"quantity" validate:"required,min=1"`
+	Unit                string     `json:"unit" validate:"max=20"` // kosong berarti satuan dasar
+	WarehouseLocationID *uuid.UUID `json:"warehouse_location_id" validate:"required_unless=Disposition scrap"`
+	LotNumber           string     `json:"lot_number" validate:"max=100"`
+	ExpiryDate          string     `json:"expiry_date" validate:"omitempty,datetime=2006-01-02"`
+	SerialNumbers       []string   `json:"serial_numbers" validate:"omitempty,dive,required,max=100"`
+	Note                string     `json:"note"`
+}
+
+type InspectReturnRequest struct {
+	Lines []ReturnInspectionLineRequest `json:"lines" validate:"required,min=1,dive"`
+}
+
+// ReturnAuthorizationLineResponse; PendingQuantity = received - (restocked + quarantined + scrapped)
+type ReturnAuthorizationLineResponse struct {
+	ID                  uuid.UUID  `json:"id"`
+	OutboundOrderLineID *uuid.UUID `json:"outbound_order_line_id"`
+	ProductID           uuid.UUID  `json:"product_id"`
+	ProductName         string     `json:"product_name"`
+	SKU                 string     `json:"sku"`
+	Quantity            int        `json:"quantity"`
+	ReceivedQuantity    int        `json:"received_quantity"`
+	RestockedQuantity   int        `json:"restocked_quantity"`
+	QuarantinedQuantity int        `json:"quarantined_quantity"`
+	ScrappedQuantity    int        `json:"scrapped_quantity"`
+	PendingQuantity     int        `json:"pending_quantity"`
+}
+
+type ReturnInspectionResponse struct {
+	ID                    uuid.UUID  `json:"id"`
+	ReturnLineID          uuid.UUID  `json:"return_line_id"`
+	ProductID             uuid.UUID  `json:"product_id"`
+	SKU                   string     `json:"sku"`
+	Disposition           string     `json:"disposition"`
+	Quantity              int        `json:"quantity"`
+	WarehouseLocationID   *uuid.UUID `json:"warehouse_location_id"`
+	WarehouseLocationName string     `json:"warehouse_location_name,omitempty"`
+	LotNumber             string     `json:"lot_number,omitempty"`
+	ExpiryDate            *time.Time `json:"expiry_date,omitempty"`
+	SerialNumbers         []string   `json:"serial_numbers,omitempty"`
+	Note                  string     `json:"note"`
+	InspectedBy           uuid.UUID  `json:"inspected_by"`
+	CreatedAt             time.Time  `json:"created_at"`
+}
+
+type ReturnAuthorizationResponse struct {
+	ID                uuid.UUID                         `json:"id"`
+	RMANumber         string                            `json:"rma_number"`
+	OutboundOrderID   *uuid.UUID                        `json:"outbound_order_id"`
+	OrderNumber       string                            `json:"order_number,omitempty"`
+	CustomerName      string                            `json:"customer_name"`
+	CustomerReference string                            `json:"customer_reference"`
+	Reason            string                            `json:"reason"`
+	Status            string                            `json:"status"`
+	Note              string                            `json:"note"`
+	Lines             []ReturnAuthorizationLineResponse `json:"lines"`
+	Inspections       []ReturnInspectionResponse        `json:"inspections"`
+	CreatedBy         uuid.UUID                         `json:"created_by"`
+	ReceivedBy        *uuid.UUID                        `json:"received_by"`
+	ReceivedAt        *time.Time                        `json:"received_at"`
+	CompletedBy       *uuid.UUID                        `json:"completed_by"`
+	CompletedAt       *time.Time                        `json:"completed_at"`
+	CancelledBy       *uuid.UUID                        `json:"cancelled_by"`
+	CancelledAt       *time.Time                        `json:"cancelled_at"`
+	CreatedAt         time.Time                         `json:"created_at"`
+	UpdatedAt         time.Time                         `json:"updated_at"`
+}
@@ -0,0 +1,28 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreateStockReservationRequest struct {
+	Quantity       int        `json:"quantity" validate:"required,min=1"`
+	Unit           string     `json:"unit" validate:"max=20"` // kosong berarti satuan dasar
+	OwnerReference string     `json:"owner_reference" validate:"required,max=100"`
+	ExpiresAt      *time.Time `json:"expires_at"`
+}
+
+type StockReservationResponse struct {
+	ID                  uuid.UUID  `json:"id"`
+	ProductStockID      uuid.UUID  `json:"product_stock_id"`
+	ProductID           uuid.UUID  `json:"product_id"`
+	WarehouseLocationID uuid.UUID  `json:"warehouse_location_id"`
+	Quantity            int        `json:"quantity"`
+	OwnerReference      string     `json:"owner_reference"`
+	Status              string     `json:"status"`
+	ExpiresAt           *time.Time `json:"expires_at"`
+	ReleasedAt          *time.Time `json:"released_at"`
+	CreatedBy           uuid.UUID  `json:"created_by"`
+	CreatedAt           time.Time  `json:"created_at"`
+}
@@ -0,0 +1,59 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type StockTransferItemRequest struct {
+	ProductID     uuid.UUID `json:"product_id" validate:"required"`
+	Quantity      int       `json:"quantity" validate:"required,min=1"`
+	Unit          string    `json:"unit" validate:"max=20"` // kosong berarti satuan dasar
+	SerialNumbers []string  `json:"serial_numbers" validate:"omitempty,dive,required,max=100"`
+}
+
+type CreateStockTransferRequest struct {
+	SourceLocationID      uuid.UUID                  `json:"source_location_id" validate:"required"`
+	DestinationLocationID uuid.UUID                  `json:"destination_location_id" validate:"required,nefield=SourceLocationID"`
+	Note                  string                     `json:"note"`
+	Items                 []StockTransferItemRequest `json:"items" validate:"required,min=1,dive"`
+}
+
+// StockTransferListRequest untuk query param list transfer
+type StockTransferListRequest struct {
+	Page                  int       `query:"page" validate:"min=1"`
+	Limit                 int       `query:"limit" validate:"min=1,max=100"`
+	Search                string    `query:"search"`
+	Status                string    `query:"status" validate:"omitempty,oneof=draft in_transit received cancelled"`
+	SourceLocationID      uuid.UUID `query:"source_location_id"`
+	DestinationLocationID uuid.UUID `query:"destination_location_id"`
+}
+
+type StockTransferItemResponse struct {
+	ID            uuid.UUID `json:"id"`
+	ProductID     uuid.UUID `json:"product_id"`
+	ProductName   string    `json:"product_name"`
+	SKU           string    `json:"sku"`
+	Quantity      int       `json:"quantity"`
+	SerialNumbers []string  `json:"serial_numbers,omitempty"`
+	Quarantined   bool      `json:"quarantined"` // stok asal berstatus karantina saat dispatch
+}
+
+type StockTransferResponse struct {
+	ID                      uuid.UUID                   `json:"id"`
+	TransferNumber          string                      `json:"transfer_number"`
+	SourceLocationID        uuid.UUID                   `json:"source_location_id"`
+	SourceLocationName      string                      `json:"source_location_name"`
+	DestinationLocationID   uuid.UUID                   `json:"destination_location_id"`
+	DestinationLocationName string                      `json:"destination_location_name"`
+	Status                  string                      `json:"status"`
+	Note                    string                      `json:"note"`
+	Items                   []StockTransferItemResponse `json:"items"`
+	CreatedBy               uuid.UUID                   `json:"created_by"`
+	DispatchedAt            *time.Time                  `json:"dispatched_at"`
+	ReceivedAt              *time.Time                  `json:"received_at"`
+	CancelledAt             *time.Time                  `json:"cancelled_at"`
+	CreatedAt               time.Time                   `json:"created_at"`
+	UpdatedAt               time.Time                   `json:"updated_at"`
+}
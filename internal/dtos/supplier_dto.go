@@ -0,0 +1,100 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SupplierContactRequest struct {
+	Name      string `json:"name" validate:"required,max=100"`
+	Title     string `json:"title" validate:"max=100"`
+	Email     string `json:"email" validate:"omitempty,email,max=150"`
+	Phone     string `json:"phone" validate:"max=50"`
+	IsPrimary bool   `json:"is_primary"`
+}
+
+type CreateSupplierRequest struct {
+	Code         string                   `json:"code" validate:"required,max=50"`
+	Name         string                   `json:"name" validate:"required,max=150"`
+	Email        string                   `json:"email" validate:"omitempty,email,max=150"`
+	Phone        string                   `json:"phone" validate:"max=50"`
+	Address      string                   `json:"address"`
+	LeadTimeDays int                      `json:"lead_time_days" validate:"min=0"`
+	Note         string                   `json:"note"`
+	Contacts     []SupplierContactRequest `json:"contacts" validate:"omitempty,dive"`
+}
+
+// UpdateSupplierRequest: field kosong tidak diubah; contacts (jika dikirim) menggantikan seluruh kontak
+type UpdateSupplierRequest struct {
+	Code         string                   `json:"code" validate:"max=50"`
+	Name         string                   `json:"name" validate:"max=150"`
+	Email        string                   `json:"email" validate:"omitempty,email,max=150"`
+	Phone        string                   `json:"phone" validate:"max=50"`
+	Address      string                   `json:"address"`
+	LeadTimeDays *int                     `json:"lead_time_days" validate:"omitempty,min=0"`
+	IsActive     *bool                    `json:"is_active"`
+	Note         string                   `json:"note"`
+	Contacts     []SupplierContactRequest `json:"contacts" validate:"omitempty,dive"`
+}
+
+// SupplierListRequest untuk query param list supplier
+type SupplierListRequest struct {
+	Page      int       `query:"page" validate:"min=1"`
+	Limit     int       `query:"limit" validate:"min=1,max=100"`
+	Search    string    `query:"search"`
+	IsActive  *bool     `query:"is_active"`
+	ProductID uuid.UUID `query:"product_id"` // supplier yang memasok produk ini
+}
+
+// SupplierProductRequest memetakan SKU supplier ke SKU produk kita; mapping yang sudah ada ditimpa
+type SupplierProductRequest struct {
+	SKU              string  `json:"sku" validate:"required"`
+	SupplierSKU      string  `json:"supplier_sku" validate:"required,max=100"`
+	UnitCost         float64 `json:"unit_cost" validate:"min=0"`
+	LeadTimeDays     *int    `json:"lead_time_days" validate:"omitempty,min=0"`
+	MinOrderQuantity int     `json:"min_order_quantity" validate:"omitempty,min=1"`
+	Preferred        bool    `json:"preferred"`
+}
+
+type SupplierContactResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	Title     string    `json:"title"`
+	Email     string    `json:"email"`
+	Phone     string    `json:"phone"`
+	IsPrimary bool      `json:"is_primary"`
+}
+
+// SupplierProductResponse; LeadTimeDays adalah lead time efektif (override produk atau lead time supplier)
+type SupplierProductResponse struct {
+	ID               uuid.UUID `json:"id"`
+	SupplierID       uuid.UUID `json:"supplier_id"`
+	ProductID        uuid.UUID `json:"product_id"`
+	ProductName      string    `json:"product_name"`
+	SKU              string    `json:"sku"`
+	SupplierSKU      string    `json:"supplier_sku"`
+	UnitCost         float64   `json:"unit_cost"`
+	LeadTimeDays     int       `json:"lead_time_days"`
+	MinOrderQuantity int       `json:"min_order_quantity"`
+	Preferred        bool      `json:"preferred"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// SupplierResponse; Contacts dan Products hanya diisi pada detail
+type SupplierResponse struct {
+	ID           uuid.UUID                 `json:"id"`
+	Code         string                    `json:"code"`
+	Name         string                    `json:"name"`
+	Email        string                    `json:"email"`
+	Phone        string                    `json:"phone"`
+	Address      string                    `json:"address"`
+	LeadTimeDays int                       `json:"lead_time_days"`
+	IsActive     bool                      `json:"is_active"`
+	Note         string                    `json:"note"`
+	CreatedBy    uuid.UUID                 `json:"created_by"`
+	CreatedAt    time.Time                 `json:"created_at"`
+	UpdatedAt    time.Time                 `json:"updated_at"`
+	Contacts     []SupplierContactResponse `json:"contacts,omitempty"`
+	Products     []SupplierProductResponse `json:"products,omitempty"`
+}
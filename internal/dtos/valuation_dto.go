@@ -0,0 +1,74 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// InventoryValuationRequest; warehouse_location_id mencakup lokasi beserta seluruh turunannya
+type InventoryValuationRequest struct {
+	WarehouseLocationID uuid.UUID `query:"warehouse_location_id"`
+	CategoryID          uuid.UUID `query:"category_id"`
+}
+
+// InventoryValuationResponse adalah nilai persediaan saat ini, total dan per lokasi / category
+type InventoryValuationResponse struct {
+	DefaultCostingMethod string                               `json:"default_costing_method"`
+	TotalQuantity        int                                  `json:"total_quantity"`
+	TotalValue           float64                              `json:"total_value"`
+	ByLocation           []InventoryValuationLocationResponse `json:"by_location"`
+	ByCategory           []InventoryValuationCategoryResponse `json:"by_category"`
+}
+
+type InventoryValuationLocationResponse struct {
+	WarehouseLocationID   uuid.UUID `json:"warehouse_location_id"`
+	WarehouseLocationName string    `json:"warehouse_location_name"`
+	WarehouseLocationPath string    `json:"warehouse_location_path"`
+	Quantity              int       `json:"quantity"`
+	Value                 float64   `json:"value"`
+}
+
+type InventoryValuationCategoryResponse struct {
+	CategoryID   uuid.UUID `json:"category_id"`
+	CategoryName string    `json:"category_name"`
+	Quantity     int       `json:"quantity"`
+	Value        float64   `json:"value"`
+}
+
+// CostOfGoodsRequest untuk laporan harga pokok movement keluar pada rentang waktu
+type CostOfGoodsRequest struct {
+	From                string    `query:"from"` // RFC3339 atau YYYY-MM-DD (awal hari)
+	To                  string    `query:"to"`   // RFC3339 atau YYYY-MM-DD (akhir hari)
+	ProductID           uuid.UUID `query:"product_id"`
+	CategoryID          uuid.UUID `query:"category_id"`
+	WarehouseLocationID uuid.UUID `query:"warehouse_location_id"` // lokasi beserta seluruh turunannya
+	MovementType        string    `query:"movement_type" validate:"omitempty,oneof=shipment damage adjustment count_correction"`
+}
+
+// CostOfGoodsResponse; transfer_out dan assembly_out tidak termasuk karena nilainya berpindah, bukan keluar
+type CostOfGoodsResponse struct {
+	From           *time.Time                        `json:"from"`
+	To             *time.Time                        `json:"to"`
+	TotalQuantity  int                               `json:"total_quantity"`
+	TotalCost      float64                           `json:"total_cost"`
+	ByMovementType []CostOfGoodsMovementTypeResponse `json:"by_movement_type"`
+	Products       []CostOfGoodsProductResponse      `json:"products"`
+}
+
+type CostOfGoodsMovementTypeResponse struct {
+	MovementType string  `json:"movement_type"`
+	Quantity     int     `json:"quantity"`
+	TotalCost    float64 `json:"total_cost"`
+}
+
+// CostOfGoodsProductResponse; AverageUnitCost = TotalCost / Quantity
+type CostOfGoodsProductResponse struct {
+	ProductID       uuid.UUID `json:"product_id"`
+	ProductName     string    `json:"product_name"`
+	SKU             string    `json:"sku"`
+	CostingMethod   string    `json:"costing_method"`
+	Quantity        int       `json:"quantity"`
+	TotalCost       float64   `json:"total_cost"`
+	AverageUnitCost float64   `json:"average_unit_cost"`
+}
@@ -0,0 +1,32 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// RunEvery menjalankan fn secara periodik di goroutine terpisah sampai ctx dibatalkan.
+// Interval <= 0 berarti job dinonaktifkan.
+func RunEvery(ctx context.Context, log *logrus.Logger, name string, interval time.Duration, fn func(ctx context.Context) error) {
+	if interval <= 0 {
+		log.Infof("job %s disabled", name)
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := fn(ctx); err != nil {
+					log.Errorf("job %s failed: %v", name, err)
+				}
+			}
+		}
+	}()
+}
@@ -27,7 +27,8 @@ func (m *ProductMiddleware) Authorize(c *fiber.Ctx) error {
 		return c.Next()
 
 	case fiber.MethodPost, fiber.MethodPut, fiber.MethodDelete:
-		if role == "user" {
+		// Render label hanya membaca data; POST dipakai karena daftar item dikirim di body
+		if role == "user" && endpoint != "/api/labels/products" && endpoint != "/api/labels/locations" {
 			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
 				"error": "Forbidden: user role is only allowed to view data",
 			})
@@ -41,6 +42,32 @@ func (m *ProductMiddleware) Authorize(c *fiber.Ctx) error {
 				"error": "Forbidden: Only super_admin can modify warehouse locations or product stocks",
 			})
 		}
+	case "/api/cycle-counts/:id/approve":
+		// Approval membukukan koreksi stok, sama seperti overwrite quantity
+		if role != "super_admin" {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Forbidden: Only super_admin can approve cycle counts",
+			})
+		}
+	case "/api/stock-movements/approvals/:id/approve", "/api/stock-movements/approvals/:id/reject":
+		if role != "super_admin" {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Forbidden: Only super_admin can sign off stock movements",
+			})
+		}
+	case "/api/suppliers/:id":
+		if method == fiber.MethodDelete && role != "super_admin" {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Forbidden: Only super_admin can delete suppliers",
+			})
+		}
+	case "/api/purchase-orders/:id/approve":
+		// Approval membuat PO mengikat ke supplier
+		if role != "super_admin" {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Forbidden: Only super_admin can approve purchase orders",
+			})
+		}
 	}
 
 	return c.Next()
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CycleCount adalah sesi stock opname untuk subtree lokasi dan/atau satu produk.
+// Expected quantity setiap baris dibekukan saat sesi dibuka.
+type CycleCount struct {
+	ID                  uuid.UUID      `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	CountNumber         string         `gorm:"type:varchar(50);unique;not null"`
+	WarehouseLocationID *uuid.UUID     `gorm:"column:warehouse_location_id;type:uuid;index"`
+	SourceProductID     *uuid.UUID     `gorm:"column:source_product_id;type:uuid;index"`
+	Status              string         `gorm:"type:count_status;not null;default:'open';index"`
+	Note                string         `gorm:"type:text"`
+	CreatedBy           uuid.UUID      `gorm:"column:created_by;type:uuid"`
+	ApprovedBy          *uuid.UUID     `gorm:"column:approved_by;type:uuid"`
+	ApprovedAt          *time.Time     `gorm:"column:approved_at"`
+	CancelledBy         *uuid.UUID     `gorm:"column:cancelled_by;type:uuid"`
+	CancelledAt         *time.Time     `gorm:"column:cancelled_at"`
+	CreatedAt           time.Time      `gorm:"default:current_timestamp"`
+	UpdatedAt           time.Time      `gorm:"default:current_timestamp"`
+	DeletedAt           gorm.DeletedAt `gorm:"index"`
+
+	WarehouseLocation *WarehouseLocation `gorm:"foreignKey:WarehouseLocationID;references:ID"`
+	Product           *Product           `gorm:"foreignKey:SourceProductID;references:ID"`
+	Lines             []CycleCountLine   `gorm:"foreignKey:CycleCountID;references:ID"`
+}
+
+// CycleCountLine adalah satu stok (atau satu lot untuk produk lot-tracked) yang harus dihitung.
+// CountedQuantity baru terisi ketika semua penghitung sepakat.
+type CycleCountLine struct {
+	ID                  uuid.UUID  `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	CycleCountID        uuid.UUID  `gorm:"column:cycle_count_id;type:uuid;not null;index"`
+	ProductStockID      uuid.UUID  `gorm:"column:product_stock_id;type:uuid;not null"`
+	SourceProductID     uuid.UUID  `gorm:"column:source_product_id;type:uuid;not null"`
+	WarehouseLocationID uuid.UUID  `gorm:"column:warehouse_location_id;type:uuid;not null"`
+	LotID               *uuid.UUID `gorm:"column:lot_id;type:uuid"`
+	ExpectedQuantity    int        `gorm:"column:expected_quantity;not null"`
+	ExpectedSerials     StringList `gorm:"column:expected_serials;type:jsonb"`
+	CountedQuantity     *int       `gorm:"column:counted_quantity"`
+	CountedSerials      StringList `gorm:"column:counted_serials;type:jsonb"`
+	ReservedShortfall   int        `gorm:"column:reserved_shortfall;not null;default:0"` // reserved yang tidak lagi tertutup stok setelah approval
+	CreatedAt           time.Time  `gorm:"default:current_timestamp"`
+	UpdatedAt           time.Time  `gorm:"default:current_timestamp"`
+
+	Product           Product           `gorm:"foreignKey:SourceProductID;references:ID"`
+	WarehouseLocation WarehouseLocation `gorm:"foreignKey:WarehouseLocationID;references:ID"`
+	Lot               *StockLot         `gorm:"foreignKey:LotID;references:ID"`
+	Entries           []CycleCountEntry `gorm:"foreignKey:CycleCountLineID;references:ID"`
+}
+
+// CycleCountEntry adalah hasil hitung satu penghitung untuk satu baris (hitungan ulang menimpa entri sebelumnya)
+type CycleCountEntry struct {
+	ID               uuid.UUID  `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	CycleCountLineID uuid.UUID  `gorm:"column:cycle_count_line_id;type:uuid;not null;uniqueIndex:idx_cycle_count_entry_counter"`
+	CountedBy        uuid.UUID  `gorm:"column:counted_by;type:uuid;not null;uniqueIndex:idx_cycle_count_entry_counter"`
+	CountedQuantity  int        `gorm:"column:counted_quantity;not null"`
+	SerialNumbers    StringList `gorm:"column:serial_numbers;type:jsonb"`
+	CreatedAt        time.Time  `gorm:"default:current_timestamp"`
+	UpdatedAt        time.Time  `gorm:"default:current_timestamp"`
+}
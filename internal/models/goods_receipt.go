@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GoodsReceipt adalah satu kedatangan barang untuk sebuah PO. Satu PO bisa diterima beberapa kali.
+type GoodsReceipt struct {
+	ID              uuid.UUID `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	ReceiptNumber   string    `gorm:"type:varchar(50);unique;not null"`
+	PurchaseOrderID uuid.UUID `gorm:"column:purchase_order_id;type:uuid;not null;index"`
+	DeliveryNote    string    `gorm:"column:delivery_note;type:varchar(100)"` // nomor surat jalan supplier
+	Note            string    `gorm:"type:text"`
+	ReceivedBy      uuid.UUID `gorm:"column:received_by;type:uuid"`
+	CreatedAt       time.Time `gorm:"default:current_timestamp"`
+
+	Lines []GoodsReceiptLine `gorm:"foreignKey:GoodsReceiptID;references:ID"`
+}
+
+// GoodsReceiptLine adalah quantity satu baris PO yang diterima dan di-put-away ke satu lokasi
+type GoodsReceiptLine struct {
+	ID                  uuid.UUID  `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	GoodsReceiptID      uuid.UUID  `gorm:"column:goods_receipt_id;type:uuid;not null;index"`
+	PurchaseOrderLineID uuid.UUID  `gorm:"column:purchase_order_line_id;type:uuid;not null;index"`
+	SourceProductID     uuid.UUID  `gorm:"column:source_product_id;type:uuid;not null"`
+	WarehouseLocationID uuid.UUID  `gorm:"column:warehouse_location_id;type:uuid;not null"`
+	Quantity            int        `gorm:"not null"`
+	LotNumber           string     `gorm:"column:lot_number;type:varchar(100)"`
+	ExpiryDate          *time.Time `gorm:"column:expiry_date;type:date"`
+	SerialNumbers       StringList `gorm:"column:serial_numbers;type:jsonb"`
+	CreatedAt           time.Time  `gorm:"default:current_timestamp"`
+
+	Product           Product           `gorm:"foreignKey:SourceProductID;references:ID"`
+	WarehouseLocation WarehouseLocation `gorm:"foreignKey:WarehouseLocationID;references:ID"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProductComponent adalah satu baris bill of materials: Quantity unit komponen untuk membuat satu kit
+type ProductComponent struct {
+	ID                 uuid.UUID `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	KitProductID       uuid.UUID `gorm:"column:kit_product_id;type:uuid;not null;uniqueIndex:idx_product_component"`
+	ComponentProductID uuid.UUID `gorm:"column:component_product_id;type:uuid;not null;uniqueIndex:idx_product_component;index"`
+	Quantity           int       `gorm:"not null"`
+	CreatedAt          time.Time `gorm:"default:current_timestamp"`
+	UpdatedAt          time.Time `gorm:"default:current_timestamp"`
+
+	Component Product `gorm:"foreignKey:ComponentProductID;references:ID"`
+}
+
+// KitAssembly mencatat satu perakitan (assemble) atau pembongkaran (disassemble) kit di satu lokasi.
+// Movement komponen dan kit yang dibukukan merujuk ke assembly ini (reference_type kit_assembly).
+type KitAssembly struct {
+	ID                  uuid.UUID `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	AssemblyNumber      string    `gorm:"column:assembly_number;type:varchar(50);unique;not null"`
+	Operation           string    `gorm:"type:kit_operation;not null;index"`
+	KitProductID        uuid.UUID `gorm:"column:kit_product_id;type:uuid;not null;index"`
+	WarehouseLocationID uuid.UUID `gorm:"column:warehouse_location_id;type:uuid;not null;index"`
+	Quantity            int       `gorm:"not null"`
+	Note                string    `gorm:"type:text"`
+	CreatedBy           uuid.UUID `gorm:"column:created_by;type:uuid"`
+	CreatedAt           time.Time `gorm:"default:current_timestamp"`
+
+	Product           Product           `gorm:"foreignKey:KitProductID;references:ID"`
+	WarehouseLocation WarehouseLocation `gorm:"foreignKey:WarehouseLocationID;references:ID"`
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// OutboundOrder adalah pesanan customer yang dikeluarkan dari gudang.
+// Status: draft -> partially_allocated/allocated -> packed -> shipped; sebelum shipped bisa cancelled.
+// WarehouseLocationID (opsional) membatasi alokasi ke subtree lokasi tersebut.
+type OutboundOrder struct {
+	ID                  uuid.UUID      `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	OrderNumber         string         `gorm:"column:order_number;type:varchar(50);unique;not null"`
+	CustomerName        string         `gorm:"column:customer_name;type:varchar(150);not null"`
+	CustomerReference   string         `gorm:"column:customer_reference;type:varchar(100)"` // nomor pesanan dari customer
+	ShippingAddress     string         `gorm:"column:shipping_address;type:text"`
+	WarehouseLocationID *uuid.UUID     `gorm:"column:warehouse_location_id;type:uuid;index"`
+	PickWaveID          *uuid.UUID     `gorm:"column:pick_wave_id;type:uuid;index"`
+	Status              string         `gorm:"type:outbound_order_status;not null;default:'draft';index"`
+	RequestedShipDate   *time.Time     `gorm:"column:requested_ship_date;type:date"`
+	Note                string         `gorm:"type:text"`
+	PackageCount        int            `gorm:"column:package_count;not null;default:0"`
+	Carrier             string         `gorm:"type:varchar(100);index"` // rencana carrier, dikonfirmasi saat ship
+	TrackingNumber      string         `gorm:"column:tracking_number;type:varchar(100)"`
+	CreatedBy           uuid.UUID      `gorm:"column:created_by;type:uuid"`
+	PackedBy            *uuid.UUID     `gorm:"column:packed_by;type:uuid"`
+	PackedAt            *time.Time     `gorm:"column:packed_at"`
+	ShippedBy           *uuid.UUID     `gorm:"column:shipped_by;type:uuid"`
+	ShippedAt           *time.Time     `gorm:"column:shipped_at"`
+	CancelledBy         *uuid.UUID     `gorm:"column:cancelled_by;type:uuid"`
+	CancelledAt         *time.Time     `gorm:"column:cancelled_at"`
+	CreatedAt           time.Time      `gorm:"default:current_timestamp"`
+	UpdatedAt           time.Time      `gorm:"default:current_timestamp"`
+	DeletedAt           gorm.DeletedAt `gorm:"index"`
+
+	WarehouseLocation *WarehouseLocation  `gorm:"foreignKey:WarehouseLocationID;references:ID"`
+	Lines             []OutboundOrderLine `gorm:"foreignKey:OutboundOrderID;references:ID"`
+}
+
+// OutboundOrderLine; Status per baris: pending, partially_allocated, allocated, packed, shipped, short_shipped, cancelled
+type OutboundOrderLine struct {
+	ID                uuid.UUID `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	OutboundOrderID   uuid.UUID `gorm:"column:outbound_order_id;type:uuid;not null;index"`
+	SourceProductID   uuid.UUID `gorm:"column:source_product_id;type:uuid;not null;index"`
+	Quantity          int       `gorm:"not null"`
+	AllocatedQuantity int       `gorm:"column:allocated_quantity;not null;default:0"`
+	PackedQuantity    int       `gorm:"column:packed_quantity;not null;default:0"`
+	ShippedQuantity   int       `gorm:"column:shipped_quantity;not null;default:0"`
+	UnitPrice         float64   `gorm:"column:unit_price;type:numeric(18,4);not null;default:0"`
+	Status            string    `gorm:"type:outbound_line_status;not null;default:'pending'"`
+	CreatedAt         time.Time `gorm:"default:current_timestamp"`
+	UpdatedAt         time.Time `gorm:"default:current_timestamp"`
+
+	Product     Product              `gorm:"foreignKey:SourceProductID;references:ID"`
+	Allocations []OutboundAllocation `gorm:"foreignKey:OutboundOrderLineID;references:ID"`
+}
+
+// OutboundAllocation adalah bagian baris pesanan yang dialokasikan ke satu ProductStock
+// lewat StockReservation; reservasi menjadi consumed saat dikirim atau released saat dibatalkan.
+type OutboundAllocation struct {
+	ID                  uuid.UUID `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	OutboundOrderID     uuid.UUID `gorm:"column:outbound_order_id;type:uuid;not null;index"`
+	OutboundOrderLineID uuid.UUID `gorm:"column:outbound_order_line_id;type:uuid;not null;index"`
+	StockReservationID  uuid.UUID `gorm:"column:stock_reservation_id;type:uuid;not null;uniqueIndex"`
+	SourceProductID     uuid.UUID `gorm:"column:source_product_id;type:uuid;not null"`
+	WarehouseLocationID uuid.UUID `gorm:"column:warehouse_location_id;type:uuid;not null"`
+	Quantity            int       `gorm:"not null"`
+	CreatedAt           time.Time `gorm:"default:current_timestamp"`
+
+	Product           Product           `gorm:"foreignKey:SourceProductID;references:ID"`
+	WarehouseLocation WarehouseLocation `gorm:"foreignKey:WarehouseLocationID;references:ID"`
+	Reservation       StockReservation  `gorm:"foreignKey:StockReservationID;references:ID"`
+}
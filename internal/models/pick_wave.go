@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PickWave mengelompokkan beberapa outbound order agar diambil dalam satu rute.
+// Aturan pembentukan (carrier, cutoff, zone, max lines) disimpan untuk jejak audit.
+// Status: open -> completed; open bisa cancelled (pesanan dilepas dari wave).
+type PickWave struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	WaveNumber  string     `gorm:"column:wave_number;type:varchar(50);unique;not null"`
+	Status      string     `gorm:"type:pick_wave_status;not null;default:'open';index"`
+	Carrier     string     `gorm:"type:varchar(100)"`
+	Cutoff      *time.Time `gorm:"column:cutoff"`
+	ZoneID      *uuid.UUID `gorm:"column:zone_id;type:uuid"`
+	MaxLines    int        `gorm:"column:max_lines;not null"`
+	OrderCount  int        `gorm:"column:order_count;not null;default:0"`
+	LineCount   int        `gorm:"column:line_count;not null;default:0"`
+	Note        string     `gorm:"type:text"`
+	CreatedBy   uuid.UUID  `gorm:"column:created_by;type:uuid"`
+	CompletedBy *uuid.UUID `gorm:"column:completed_by;type:uuid"`
+	CompletedAt *time.Time `gorm:"column:completed_at"`
+	CancelledBy *uuid.UUID `gorm:"column:cancelled_by;type:uuid"`
+	CancelledAt *time.Time `gorm:"column:cancelled_at"`
+	CreatedAt   time.Time  `gorm:"default:current_timestamp"`
+	UpdatedAt   time.Time  `gorm:"default:current_timestamp"`
+
+	Zone   *WarehouseLocation `gorm:"foreignKey:ZoneID;references:ID"`
+	Orders []OutboundOrder    `gorm:"foreignKey:PickWaveID;references:ID"`
+}
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PriceList adalah daftar harga: list (harga jual standar), cost (harga pokok standar) atau
+// customer_group (harga khusus satu kelompok pelanggan). Hanya satu daftar list yang boleh menjadi default.
+type PriceList struct {
+	ID            uuid.UUID      `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	Code          string         `gorm:"type:varchar(50);unique;not null"`
+	Name          string         `gorm:"type:varchar(150);not null"`
+	Type          string         `gorm:"type:varchar(20);not null"`
+	CustomerGroup string         `gorm:"column:customer_group;type:varchar(100)"` // hanya untuk type customer_group
+	Currency      string         `gorm:"type:varchar(3);not null;default:'IDR'"`
+	IsDefault     bool           `gorm:"column:is_default;not null;default:false"`
+	IsActive      bool           `gorm:"column:is_active;not null;default:true"`
+	Description   string         `gorm:"type:text"`
+	CreatedBy     uuid.UUID      `gorm:"column:created_by;type:uuid"`
+	CreatedAt     time.Time      `gorm:"default:current_timestamp"`
+	UpdatedAt     time.Time      `gorm:"default:current_timestamp"`
+	DeletedAt     gorm.DeletedAt `gorm:"index"`
+}
+
+// ProductPrice adalah harga produk pada satu daftar harga yang berlaku mulai EffectiveFrom sampai
+// sebelum EffectiveTo (nil = tanpa batas). Periode harga produk yang sama dalam satu daftar tidak boleh tumpang tindih.
+type ProductPrice struct {
+	ID              uuid.UUID  `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	PriceListID     uuid.UUID  `gorm:"column:price_list_id;type:uuid;not null;index:idx_product_price_lookup"`
+	SourceProductID uuid.UUID  `gorm:"column:source_product_id;type:uuid;not null;index:idx_product_price_lookup"`
+	Price           float64    `gorm:"column:price;type:numeric(18,4);not null"`
+	EffectiveFrom   time.Time  `gorm:"column:effective_from;not null;index:idx_product_price_lookup"`
+	EffectiveTo     *time.Time `gorm:"column:effective_to"`
+	CreatedBy       uuid.UUID  `gorm:"column:created_by;type:uuid"`
+	UpdatedBy       uuid.UUID  `gorm:"column:updated_by;type:uuid"`
+	CreatedAt       time.Time  `gorm:"default:current_timestamp"`
+	UpdatedAt       time.Time  `gorm:"default:current_timestamp"`
+
+	PriceList PriceList `gorm:"foreignKey:PriceListID;references:ID"`
+	Product   Product   `gorm:"foreignKey:SourceProductID;references:ID"`
+}
+
+// ProductPriceHistory mencatat setiap perubahan ProductPrice (created, updated, deleted); tidak pernah diubah
+type ProductPriceHistory struct {
+	ID               uuid.UUID  `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	ProductPriceID   uuid.UUID  `gorm:"column:product_price_id;type:uuid;not null;index"`
+	PriceListID      uuid.UUID  `gorm:"column:price_list_id;type:uuid;not null;index"`
+	SourceProductID  uuid.UUID  `gorm:"column:source_product_id;type:uuid;not null;index"`
+	Action           string     `gorm:"type:varchar(10);not null"`
+	OldPrice         *float64   `gorm:"column:old_price;type:numeric(18,4)"`
+	NewPrice         *float64   `gorm:"column:new_price;type:numeric(18,4)"`
+	OldEffectiveFrom *time.Time `gorm:"column:old_effective_from"`
+	OldEffectiveTo   *time.Time `gorm:"column:old_effective_to"`
+	NewEffectiveFrom *time.Time `gorm:"column:new_effective_from"`
+	NewEffectiveTo   *time.Time `gorm:"column:new_effective_to"`
+	Note             string     `gorm:"type:text"`
+	ChangedBy        uuid.UUID  `gorm:"column:changed_by;type:uuid"`
+	ChangedAt        time.Time  `gorm:"column:changed_at;default:current_timestamp;index"`
+
+	Product Product `gorm:"foreignKey:SourceProductID;references:ID"`
+}
@@ -8,12 +8,14 @@ import (
 )
 
 type ProductCategory struct {
-	ID          uuid.UUID      `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
-	Name        string         `gorm:"type:varchar(100);unique;not null"`
-	Description string         `gorm:"type:text"`
-	CreatedAt   time.Time      `gorm:"default:current_timestamp"`
-	DeletedAt   gorm.DeletedAt `gorm:"index"`
-	UpdatedAt   time.Time      `gorm:"default:current_timestamp"`
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	Name        string    `gorm:"type:varchar(100);unique;not null"`
+	Description string    `gorm:"type:text"`
+	// AttributeSchema mendefinisikan atribut khusus yang divalidasi pada produk di category ini
+	AttributeSchema AttributeSchema `gorm:"column:attribute_schema;type:jsonb"`
+	CreatedAt       time.Time       `gorm:"default:current_timestamp"`
+	DeletedAt       gorm.DeletedAt  `gorm:"index"`
+	UpdatedAt       time.Time       `gorm:"default:current_timestamp"`
 }
 
 type Product struct {
@@ -22,29 +24,58 @@ type Product struct {
 	SKU         string    `gorm:"unique;not null"`
 	CategoryID  uuid.UUID `gorm:"not null"`
 	Description string
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
-	DeletedAt   gorm.DeletedAt `gorm:"index"`
-	CreatedBy   uuid.UUID      `gorm:"column:created_by;type:uuid"`
+	LotTracked  bool `gorm:"column:lot_tracked;not null;default:false"`
+	Serialized  bool `gorm:"column:serialized;not null;default:false"`
+	// BaseUnit adalah satuan penyimpanan seluruh quantity stok dan ledger produk ini
+	BaseUnit string `gorm:"column:base_unit;type:varchar(20);not null;default:'each'"`
+	// CostingMethod adalah metode valuasi (fifo / moving_average); kosong berarti mengikuti default global
+	CostingMethod string `gorm:"column:costing_method;type:varchar(20)"`
+	// ParentID diisi untuk varian; parent mendefinisikan atribut matriks (misal size, colour)
+	// di VariantAttributes dan tiap varian mengisi nilainya di Attributes
+	ParentID          *uuid.UUID `gorm:"column:parent_id;type:uuid;index"`
+	VariantAttributes StringList `gorm:"column:variant_attributes;type:jsonb"`
+	Attributes        StringMap  `gorm:"column:attributes;type:jsonb"`
+	// CustomAttributes berisi nilai atribut sesuai AttributeSchema category
+	CustomAttributes JSONMap `gorm:"column:custom_attributes;type:jsonb"`
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	DeletedAt        gorm.DeletedAt `gorm:"index"`
+	CreatedBy        uuid.UUID      `gorm:"column:created_by;type:uuid"`
 
 	// Relasi ke category
 	Category ProductCategory `gorm:"foreignKey:CategoryID;references:ID"`
+	// Bill of materials bila produk ini adalah kit
+	Components []ProductComponent `gorm:"foreignKey:KitProductID;references:ID"`
+	// Varian bila produk ini adalah parent
+	Variants []Product `gorm:"foreignKey:ParentID;references:ID"`
+	// Satuan alternatif beserta faktor konversinya ke BaseUnit
+	Units []ProductUnit `gorm:"foreignKey:ProductID;references:ID"`
 }
 
+// WarehouseLocation adalah node pada pohon lokasi (site -> zone -> aisle -> rack -> bin).
+// Path adalah gabungan Code dari root sampai node ini, misal JKT1-A-03-02.
 type WarehouseLocation struct {
-	ID          uuid.UUID      `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
-	Name        string         `gorm:"type:varchar(100);not null"`
-	Description string         `gorm:"type:text"`
-	CreatedAt   time.Time      `gorm:"default:current_timestamp"`
-	DeletedAt   gorm.DeletedAt `gorm:"index"`
-	UpdatedAt   time.Time      `gorm:"default:current_timestamp"`
+	ID          uuid.UUID  `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	ParentID    *uuid.UUID `gorm:"column:parent_id;type:uuid;index"`
+	Type        string     `gorm:"type:location_type;not null;default:'site'"`
+	Code        string     `gorm:"type:varchar(20);not null;default:''"`
+	Path        string     `gorm:"type:varchar(255);not null;default:'';uniqueIndex:idx_warehouse_location_path,where:path <> '' AND deleted_at IS NULL"`
+	Name        string     `gorm:"type:varchar(100);not null"`
+	Description string     `gorm:"type:text"`
+	// Quarantine menandai lokasi karantina: seluruh stok di sini non-sellable, dan stok karantina hanya boleh disimpan di sini
+	Quarantine bool           `gorm:"column:quarantine;not null;default:false"`
+	CreatedAt  time.Time      `gorm:"default:current_timestamp"`
+	DeletedAt  gorm.DeletedAt `gorm:"index"`
+	UpdatedAt  time.Time      `gorm:"default:current_timestamp"`
 }
 
 type ProductStock struct {
 	ID                  uuid.UUID      `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
-	SourceProductID     uuid.UUID      `gorm:"column:source_product_id;type:uuid;not null"`
-	WarehouseLocationID uuid.UUID      `gorm:"column:warehouse_location_id;type:uuid;not null"`
+	SourceProductID     uuid.UUID      `gorm:"column:source_product_id;type:uuid;not null;uniqueIndex:idx_product_stock_location,where:deleted_at IS NULL"`
+	WarehouseLocationID uuid.UUID      `gorm:"column:warehouse_location_id;type:uuid;not null;uniqueIndex:idx_product_stock_location,where:deleted_at IS NULL"`
 	Quantity            int            `gorm:"not null;default:0"`
+	ReservedQuantity    int            `gorm:"column:reserved_quantity;not null;default:0"`
+	StockValue          float64        `gorm:"column:stock_value;type:numeric(18,4);not null;default:0"`
 	Status              string         `gorm:"type:stock_status;default:'available'"`
 	UpdatedBy           uuid.UUID      `gorm:"column:updated_by;type:uuid"`
 	UpdatedAt           time.Time      `gorm:"default:current_timestamp"`
@@ -55,13 +86,28 @@ type ProductStock struct {
 	WarehouseLocation WarehouseLocation `gorm:"foreignKey:WarehouseLocationID;references:ID"`
 }
 
+// StockMovement adalah entri ledger stok yang bersifat append-only.
+// ProductStock.Quantity merupakan proyeksi dari SUM(delta) per produk dan lokasi.
+// TotalCost adalah nilai persediaan yang masuk/keluar bersama entri ini (selalu positif).
 type StockMovement struct {
-	ID              uuid.UUID      `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
-	SourceProductID uuid.UUID      `gorm:"column:source_product_id;type:uuid;not null"`
-	MovementType    string         `gorm:"type:movement_type;not null"`
-	Quantity        int            `gorm:"not null"`
-	ReferenceNote   string         `gorm:"type:text"`
-	CreatedBy       uuid.UUID      `gorm:"column:created_by;type:uuid"`
-	CreatedAt       time.Time      `gorm:"default:current_timestamp"`
-	DeletedAt       gorm.DeletedAt `gorm:"index"`
+	ID                  uuid.UUID      `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	SourceProductID     uuid.UUID      `gorm:"column:source_product_id;type:uuid;not null;index:idx_stock_movements_product_location"`
+	WarehouseLocationID uuid.UUID      `gorm:"column:warehouse_location_id;type:uuid;index:idx_stock_movements_product_location"`
+	MovementType        string         `gorm:"type:movement_type;not null"`
+	Quantity            int            `gorm:"not null"`
+	Delta               int            `gorm:"not null;default:0"`
+	BalanceAfter        int            `gorm:"column:balance_after;not null;default:0"`
+	UnitCost            float64        `gorm:"column:unit_cost;type:numeric(18,4);not null;default:0"`
+	TotalCost           float64        `gorm:"column:total_cost;type:numeric(18,4);not null;default:0"`
+	Reason              string         `gorm:"type:varchar(50)"`
+	ReferenceType       string         `gorm:"type:varchar(50)"`
+	ReferenceID         *uuid.UUID     `gorm:"column:reference_id;type:uuid;index"`
+	ReferenceNote       string         `gorm:"type:text"`
+	TransferID          *uuid.UUID     `gorm:"column:transfer_id;type:uuid;index"`
+	LotID               *uuid.UUID     `gorm:"column:lot_id;type:uuid;index"`
+	CreatedBy           uuid.UUID      `gorm:"column:created_by;type:uuid"`
+	CreatedAt           time.Time      `gorm:"default:current_timestamp;index"`
+	DeletedAt           gorm.DeletedAt `gorm:"index"`
+
+	Lot *StockLot `gorm:"foreignKey:LotID;references:ID"`
 }
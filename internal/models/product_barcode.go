@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProductBarcode adalah barcode produk; satu produk boleh punya banyak barcode. Barcode GTIN
+// (EAN-8, UPC-A, EAN-13, GTIN-14) juga disimpan sebagai 14 digit di GTIN agar UPC-A dan EAN-13
+// berawalan 0 dikenali sebagai barcode yang sama. Unit menunjuk satuan kemasan (misal carton),
+// kosong berarti satuan dasar.
+type ProductBarcode struct {
+	ID              uuid.UUID `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	SourceProductID uuid.UUID `gorm:"column:source_product_id;type:uuid;not null;index"`
+	Code            string    `gorm:"type:varchar(50);unique;not null"`
+	Symbology       string    `gorm:"type:varchar(20);not null"`
+	GTIN            *string   `gorm:"column:gtin;type:varchar(14);unique"`
+	Unit            string    `gorm:"type:varchar(20);not null;default:''"`
+	IsPrimary       bool      `gorm:"column:is_primary;not null;default:false"`
+	CreatedBy       uuid.UUID `gorm:"column:created_by;type:uuid"`
+	CreatedAt       time.Time `gorm:"default:current_timestamp"`
+
+	Product Product `gorm:"foreignKey:SourceProductID;references:ID"`
+}
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProductUnit adalah satuan alternatif produk: satu Unit setara Factor satuan dasar (Product.BaseUnit),
+// misal carton = 24 each. Seluruh quantity stok dan ledger disimpan dalam satuan dasar.
+type ProductUnit struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	ProductID uuid.UUID `gorm:"column:product_id;type:uuid;not null;uniqueIndex:idx_product_unit"`
+	Unit      string    `gorm:"type:varchar(20);not null;uniqueIndex:idx_product_unit"`
+	Factor    int       `gorm:"not null"`
+	CreatedAt time.Time `gorm:"default:current_timestamp"`
+	UpdatedAt time.Time `gorm:"default:current_timestamp"`
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PurchaseOrder adalah pesanan ke satu supplier untuk dikirim ke satu lokasi.
+// Status: draft -> approved -> partially_received -> closed; draft/approved bisa cancelled.
+type PurchaseOrder struct {
+	ID                  uuid.UUID      `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	PONumber            string         `gorm:"column:po_number;type:varchar(50);unique;not null"`
+	SupplierID          uuid.UUID      `gorm:"column:supplier_id;type:uuid;not null;index"`
+	WarehouseLocationID uuid.UUID      `gorm:"column:warehouse_location_id;type:uuid;not null;index"`
+	Status              string         `gorm:"type:po_status;not null;default:'draft';index"`
+	ExpectedDate        *time.Time     `gorm:"column:expected_date;type:date"`
+	Note                string         `gorm:"type:text"`
+	CreatedBy           uuid.UUID      `gorm:"column:created_by;type:uuid"`
+	ApprovedBy          *uuid.UUID     `gorm:"column:approved_by;type:uuid"`
+	ApprovedAt          *time.Time     `gorm:"column:approved_at"`
+	ClosedBy            *uuid.UUID     `gorm:"column:closed_by;type:uuid"`
+	ClosedAt            *time.Time     `gorm:"column:closed_at"`
+	CancelledBy         *uuid.UUID     `gorm:"column:cancelled_by;type:uuid"`
+	CancelledAt         *time.Time     `gorm:"column:cancelled_at"`
+	CreatedAt           time.Time      `gorm:"default:current_timestamp"`
+	UpdatedAt           time.Time      `gorm:"default:current_timestamp"`
+	DeletedAt           gorm.DeletedAt `gorm:"index"`
+
+	Supplier          Supplier            `gorm:"foreignKey:SupplierID;references:ID"`
+	WarehouseLocation WarehouseLocation   `gorm:"foreignKey:WarehouseLocationID;references:ID"`
+	Lines             []PurchaseOrderLine `gorm:"foreignKey:PurchaseOrderID;references:ID"`
+}
+
+// PurchaseOrderLine; ReceivedQuantity bertambah saat barang diterima
+type PurchaseOrderLine struct {
+	ID               uuid.UUID `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	PurchaseOrderID  uuid.UUID `gorm:"column:purchase_order_id;type:uuid;not null;index"`
+	SourceProductID  uuid.UUID `gorm:"column:source_product_id;type:uuid;not null;index"`
+	SupplierSKU      string    `gorm:"column:supplier_sku;type:varchar(100)"`
+	Quantity         int       `gorm:"not null"`
+	ReceivedQuantity int       `gorm:"column:received_quantity;not null;default:0"`
+	UnitCost         float64   `gorm:"column:unit_cost;type:numeric(18,4);not null;default:0"`
+	CreatedAt        time.Time `gorm:"default:current_timestamp"`
+	UpdatedAt        time.Time `gorm:"default:current_timestamp"`
+
+	Product Product `gorm:"foreignKey:SourceProductID;references:ID"`
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReplenishmentProposal adalah usulan pengisian ulang untuk satu ProductStock yang berada di bawah reorder point.
+// Hanya boleh ada satu proposal open per stok; proposal diperbarui setiap kali planner berjalan.
+type ReplenishmentProposal struct {
+	ID                  uuid.UUID  `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	ProductStockID      uuid.UUID  `gorm:"column:product_stock_id;type:uuid;not null;uniqueIndex:idx_replenishment_open_stock,where:status = 'open'"`
+	SourceProductID     uuid.UUID  `gorm:"column:source_product_id;type:uuid;not null;index"`
+	WarehouseLocationID uuid.UUID  `gorm:"column:warehouse_location_id;type:uuid;not null;index"`
+	AvailableQuantity   int        `gorm:"column:available_quantity;not null"`
+	IncomingQuantity    int        `gorm:"column:incoming_quantity;not null;default:0"`
+	ReorderPoint        int        `gorm:"column:reorder_point;not null"`
+	TargetQuantity      int        `gorm:"column:target_quantity;not null"`
+	SuggestedQuantity   int        `gorm:"column:suggested_quantity;not null"`
+	SourceType          string     `gorm:"column:source_type;type:replenishment_source;not null"`
+	SourceLocationID    *uuid.UUID `gorm:"column:source_location_id;type:uuid"`
+	Status              string     `gorm:"type:proposal_status;not null;default:'open';index"`
+	ReferenceType       string     `gorm:"column:reference_type;type:varchar(50)"`
+	ReferenceID         *uuid.UUID `gorm:"column:reference_id;type:uuid"`
+	ResolvedBy          *uuid.UUID `gorm:"column:resolved_by;type:uuid"`
+	ResolvedAt          *time.Time `gorm:"column:resolved_at"`
+	CreatedAt           time.Time  `gorm:"default:current_timestamp"`
+	UpdatedAt           time.Time  `gorm:"default:current_timestamp"`
+
+	Product           Product            `gorm:"foreignKey:SourceProductID;references:ID"`
+	WarehouseLocation WarehouseLocation  `gorm:"foreignKey:WarehouseLocationID;references:ID"`
+	SourceLocation    *WarehouseLocation `gorm:"foreignKey:SourceLocationID;references:ID"`
+}
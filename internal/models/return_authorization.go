@@ -0,0 +1,75 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ReturnAuthorization (RMA) adalah izin retur barang dari customer.
+// Status: authorized -> received -> completed; hanya authorized yang bisa cancelled.
+// OutboundOrderID (opsional) menautkan retur ke pesanan yang sudah dikirim.
+type ReturnAuthorization struct {
+	ID                uuid.UUID      `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	RMANumber         string         `gorm:"column:rma_number;type:varchar(50);unique;not null"`
+	OutboundOrderID   *uuid.UUID     `gorm:"column:outbound_order_id;type:uuid;index"`
+	CustomerName      string         `gorm:"column:customer_name;type:varchar(150);not null"`
+	CustomerReference string         `gorm:"column:customer_reference;type:varchar(100)"`
+	Reason            string         `gorm:"type:varchar(255);not null"`
+	Status            string         `gorm:"type:rma_status;not null;default:'authorized';index"`
+	Note              string         `gorm:"type:text"`
+	CreatedBy         uuid.UUID      `gorm:"column:created_by;type:uuid"`
+	ReceivedBy        *uuid.UUID     `gorm:"column:received_by;type:uuid"`
+	ReceivedAt        *time.Time     `gorm:"column:received_at"`
+	CompletedBy       *uuid.UUID     `gorm:"column:completed_by;type:uuid"`
+	CompletedAt       *time.Time     `gorm:"column:completed_at"`
+	CancelledBy       *uuid.UUID     `gorm:"column:cancelled_by;type:uuid"`
+	CancelledAt       *time.Time     `gorm:"column:cancelled_at"`
+	CreatedAt         time.Time      `gorm:"default:current_timestamp"`
+	UpdatedAt         time.Time      `gorm:"default:current_timestamp"`
+	DeletedAt         gorm.DeletedAt `gorm:"index"`
+
+	OutboundOrder *OutboundOrder            `gorm:"foreignKey:OutboundOrderID;references:ID"`
+	Lines         []ReturnAuthorizationLine `gorm:"foreignKey:ReturnAuthorizationID;references:ID"`
+	Inspections   []ReturnInspection        `gorm:"foreignKey:ReturnAuthorizationID;references:ID"`
+}
+
+// ReturnAuthorizationLine; ReceivedQuantity diisi saat barang tiba, lalu dibagi ke
+// restocked/quarantined/scrapped lewat inspeksi
+type ReturnAuthorizationLine struct {
+	ID                    uuid.UUID  `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	ReturnAuthorizationID uuid.UUID  `gorm:"column:return_authorization_id;type:uuid;not null;index"`
+	OutboundOrderLineID   *uuid.UUID `gorm:"column:outbound_order_line_id;type:uuid;index"`
+	SourceProductID       uuid.UUID  `gorm:"column:source_product_id;type:uuid;not null;index"`
+	Quantity              int        `gorm:"not null"`
+	ReceivedQuantity      int        `gorm:"column:received_quantity;not null;default:0"`
+	RestockedQuantity     int        `gorm:"column:restocked_quantity;not null;default:0"`
+	QuarantinedQuantity   int        `gorm:"column:quarantined_quantity;not null;default:0"`
+	ScrappedQuantity      int        `gorm:"column:scrapped_quantity;not null;default:0"`
+	CreatedAt             time.Time  `gorm:"default:current_timestamp"`
+	UpdatedAt             time.Time  `gorm:"default:current_timestamp"`
+
+	Product Product `gorm:"foreignKey:SourceProductID;references:ID"`
+}
+
+// ReturnInspection adalah hasil inspeksi sebagian quantity satu baris RMA beserta disposition-nya.
+// restock dan quarantine membukukan movement return ke WarehouseLocationID; scrap hanya dicatat.
+type ReturnInspection struct {
+	ID                        uuid.UUID  `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	ReturnAuthorizationID     uuid.UUID  `gorm:"column:return_authorization_id;type:uuid;not null;index"`
+	ReturnAuthorizationLineID uuid.UUID  `gorm:"column:return_authorization_line_id;type:uuid;not null;index"`
+	SourceProductID           uuid.UUID  `gorm:"column:source_product_id;type:uuid;not null"`
+	Disposition               string     `gorm:"type:return_disposition;not null"`
+	Quantity                  int        `gorm:"not null"`
+	WarehouseLocationID       *uuid.UUID `gorm:"column:warehouse_location_id;type:uuid"`
+	LotNumber                 string     `gorm:"column:lot_number;type:varchar(100)"`
+	ExpiryDate                *time.Time `gorm:"column:expiry_date;type:date"`
+	SerialNumbers             StringList `gorm:"column:serial_numbers;type:jsonb"`
+	Note                      string     `gorm:"type:text"`
+	InspectedBy               uuid.UUID  `gorm:"column:inspected_by;type:uuid"`
+	CreatedAt                 time.Time  `gorm:"default:current_timestamp"`
+
+	Product           Product            `gorm:"foreignKey:SourceProductID;references:ID"`
+	WarehouseLocation *WarehouseLocation `gorm:"foreignKey:WarehouseLocationID;references:ID"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SerialNumber adalah registry unit fisik untuk produk serialized.
+// WarehouseLocationID terisi selama serial berstatus in_stock.
+type SerialNumber struct {
+	ID                  uuid.UUID  `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	SourceProductID     uuid.UUID  `gorm:"column:source_product_id;type:uuid;not null;uniqueIndex:idx_serial_product_number"`
+	SerialNumber        string     `gorm:"column:serial_number;type:varchar(100);not null;uniqueIndex:idx_serial_product_number"`
+	WarehouseLocationID *uuid.UUID `gorm:"column:warehouse_location_id;type:uuid;index"`
+	Status              string     `gorm:"type:serial_status;not null;default:'in_stock'"`
+	CreatedAt           time.Time  `gorm:"default:current_timestamp"`
+	UpdatedAt           time.Time  `gorm:"default:current_timestamp"`
+
+	Product           Product            `gorm:"foreignKey:SourceProductID;references:ID"`
+	WarehouseLocation *WarehouseLocation `gorm:"foreignKey:WarehouseLocationID;references:ID"`
+}
+
+// StockMovementSerial menghubungkan entri ledger dengan serial yang dipindahkan,
+// sehingga riwayat lengkap setiap serial dapat ditelusuri dari stock_movements.
+type StockMovementSerial struct {
+	StockMovementID uuid.UUID `gorm:"column:stock_movement_id;type:uuid;primaryKey"`
+	SerialNumberID  uuid.UUID `gorm:"column:serial_number_id;type:uuid;primaryKey;index"`
+
+	StockMovement StockMovement `gorm:"foreignKey:StockMovementID;references:ID"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StockLot adalah rincian ProductStock per nomor lot/batch untuk produk yang lot-tracked.
+// Quantity merupakan proyeksi dari SUM(delta) stock_movements dengan lot_id yang sama.
+type StockLot struct {
+	ID                  uuid.UUID  `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	ProductStockID      uuid.UUID  `gorm:"column:product_stock_id;type:uuid;not null;uniqueIndex:idx_stock_lot_number"`
+	SourceProductID     uuid.UUID  `gorm:"column:source_product_id;type:uuid;not null;index"`
+	WarehouseLocationID uuid.UUID  `gorm:"column:warehouse_location_id;type:uuid;not null"`
+	LotNumber           string     `gorm:"type:varchar(100);not null;uniqueIndex:idx_stock_lot_number"`
+	ExpiryDate          *time.Time `gorm:"column:expiry_date;type:date;index"`
+	Quantity            int        `gorm:"not null;default:0"`
+	CreatedAt           time.Time  `gorm:"default:current_timestamp"`
+	UpdatedAt           time.Time  `gorm:"default:current_timestamp"`
+
+	Product           Product           `gorm:"foreignKey:SourceProductID;references:ID"`
+	WarehouseLocation WarehouseLocation `gorm:"foreignKey:WarehouseLocationID;references:ID"`
+}
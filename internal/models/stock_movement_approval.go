@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StockMovementApproval adalah movement manual yang menunggu sign-off super_admin karena
+// melebihi batas kebijakan approval untuk tipenya. Movement baru dibukukan setelah disetujui.
+type StockMovementApproval struct {
+	ID                  uuid.UUID  `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	SourceProductID     uuid.UUID  `gorm:"column:source_product_id;type:uuid;not null;index"`
+	WarehouseLocationID uuid.UUID  `gorm:"column:warehouse_location_id;type:uuid;not null"`
+	MovementType        string     `gorm:"type:movement_type;not null"`
+	Direction           int        `gorm:"not null;default:0"`
+	Quantity            int        `gorm:"not null"`
+	LotNumber           string     `gorm:"column:lot_number;type:varchar(100)"`
+	ExpiryDate          *time.Time `gorm:"column:expiry_date;type:date"`
+	SerialNumbers       StringList `gorm:"column:serial_numbers;type:jsonb"`
+	UnitCost            *float64   `gorm:"column:unit_cost;type:numeric(18,4)"`
+	Reason              string     `gorm:"type:varchar(50);not null"`
+	ReferenceNote       string     `gorm:"type:text;not null"`
+	Status              string     `gorm:"type:approval_status;not null;default:'pending';index"`
+	RequestedBy         uuid.UUID  `gorm:"column:requested_by;type:uuid;not null"`
+	DecidedBy           *uuid.UUID `gorm:"column:decided_by;type:uuid"`
+	DecidedAt           *time.Time `gorm:"column:decided_at"`
+	DecisionNote        string     `gorm:"column:decision_note;type:text"`
+	CreatedAt           time.Time  `gorm:"default:current_timestamp"`
+	UpdatedAt           time.Time  `gorm:"default:current_timestamp"`
+
+	Product           Product           `gorm:"foreignKey:SourceProductID;references:ID"`
+	WarehouseLocation WarehouseLocation `gorm:"foreignKey:WarehouseLocationID;references:ID"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StockReservation menahan sebagian stok untuk kebutuhan tertentu (misal pesanan) tanpa mengeluarkannya secara fisik
+type StockReservation struct {
+	ID                  uuid.UUID  `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	ProductStockID      uuid.UUID  `gorm:"column:product_stock_id;type:uuid;not null;index"`
+	SourceProductID     uuid.UUID  `gorm:"column:source_product_id;type:uuid;not null"`
+	WarehouseLocationID uuid.UUID  `gorm:"column:warehouse_location_id;type:uuid;not null"`
+	Quantity            int        `gorm:"not null"`
+	OwnerReference      string     `gorm:"type:varchar(100);not null;index"`
+	Status              string     `gorm:"type:reservation_status;not null;default:'active';index"`
+	ExpiresAt           *time.Time `gorm:"column:expires_at;index"`
+	ReleasedAt          *time.Time `gorm:"column:released_at"`
+	CreatedBy           uuid.UUID  `gorm:"column:created_by;type:uuid"`
+	CreatedAt           time.Time  `gorm:"default:current_timestamp"`
+	UpdatedAt           time.Time  `gorm:"default:current_timestamp"`
+
+	ProductStock ProductStock `gorm:"foreignKey:ProductStockID;references:ID"`
+}
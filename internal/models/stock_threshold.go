@@ -0,0 +1,74 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Threshold bawaan jika belum ada konfigurasi sama sekali (sama dengan aturan lama: low-stock < 10 unit)
+const (
+	DefaultMinQuantity  = 0
+	DefaultReorderPoint = 9
+	DefaultMaxQuantity  = 0
+)
+
+// StockThreshold menyimpan level min / reorder point / max.
+// Cakupan ditentukan oleh kolom yang terisi, dengan urutan fallback:
+// produk+lokasi -> produk -> kategori -> lokasi -> global (semua kosong).
+type StockThreshold struct {
+	ID                  uuid.UUID      `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	SourceProductID     *uuid.UUID     `gorm:"column:source_product_id;type:uuid;index"`
+	WarehouseLocationID *uuid.UUID     `gorm:"column:warehouse_location_id;type:uuid;index"`
+	CategoryID          *uuid.UUID     `gorm:"column:category_id;type:uuid;index"`
+	MinQuantity         int            `gorm:"column:min_quantity;not null;default:0"`
+	ReorderPoint        int            `gorm:"column:reorder_point;not null;default:0"`
+	MaxQuantity         int            `gorm:"column:max_quantity;not null;default:0"` // 0 berarti tanpa batas
+	UpdatedBy           uuid.UUID      `gorm:"column:updated_by;type:uuid"`
+	CreatedAt           time.Time      `gorm:"default:current_timestamp"`
+	UpdatedAt           time.Time      `gorm:"default:current_timestamp"`
+	DeletedAt           gorm.DeletedAt `gorm:"index"`
+}
+
+// Scope mengembalikan nama level fallback dari threshold ini
+func (t *StockThreshold) Scope() string {
+	switch {
+	case t.SourceProductID != nil && t.WarehouseLocationID != nil:
+		return "product_location"
+	case t.SourceProductID != nil:
+		return "product"
+	case t.CategoryID != nil:
+		return "category"
+	case t.WarehouseLocationID != nil:
+		return "location"
+	}
+	return "global"
+}
+
+// Priority mengembalikan urutan fallback threshold ini: 1 paling spesifik (produk+lokasi), 5 global
+func (t *StockThreshold) Priority() int {
+	switch t.Scope() {
+	case "product_location":
+		return 1
+	case "product":
+		return 2
+	case "category":
+		return 3
+	case "location":
+		return 4
+	}
+	return 5
+}
+
+// EffectiveStockThreshold memilih threshold paling spesifik dari kandidat yang berlaku untuk satu stok;
+// nil berarti threshold bawaan yang dipakai
+func EffectiveStockThreshold(candidates []StockThreshold) *StockThreshold {
+	var effective *StockThreshold
+	for i := range candidates {
+		if effective == nil || candidates[i].Priority() < effective.Priority() {
+			effective = &candidates[i]
+		}
+	}
+	return effective
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type StockTransfer struct {
+	ID                    uuid.UUID      `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	TransferNumber        string         `gorm:"type:varchar(50);unique;not null"`
+	SourceLocationID      uuid.UUID      `gorm:"column:source_location_id;type:uuid;not null"`
+	DestinationLocationID uuid.UUID      `gorm:"column:destination_location_id;type:uuid;not null"`
+	Status                string         `gorm:"type:transfer_status;not null;default:'draft'"`
+	Note                  string         `gorm:"type:text"`
+	CreatedBy             uuid.UUID      `gorm:"column:created_by;type:uuid"`
+	DispatchedBy          *uuid.UUID     `gorm:"column:dispatched_by;type:uuid"`
+	DispatchedAt          *time.Time     `gorm:"column:dispatched_at"`
+	ReceivedBy            *uuid.UUID     `gorm:"column:received_by;type:uuid"`
+	ReceivedAt            *time.Time     `gorm:"column:received_at"`
+	CancelledBy           *uuid.UUID     `gorm:"column:cancelled_by;type:uuid"`
+	CancelledAt           *time.Time     `gorm:"column:cancelled_at"`
+	CreatedAt             time.Time      `gorm:"default:current_timestamp"`
+	UpdatedAt             time.Time      `gorm:"default:current_timestamp"`
+	DeletedAt             gorm.DeletedAt `gorm:"index"`
+
+	SourceLocation      WarehouseLocation   `gorm:"foreignKey:SourceLocationID;references:ID"`
+	DestinationLocation WarehouseLocation   `gorm:"foreignKey:DestinationLocationID;references:ID"`
+	Items               []StockTransferItem `gorm:"foreignKey:TransferID;references:ID"`
+}
+
+type StockTransferItem struct {
+	ID              uuid.UUID  `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	TransferID      uuid.UUID  `gorm:"column:transfer_id;type:uuid;not null;index"`
+	SourceProductID uuid.UUID  `gorm:"column:source_product_id;type:uuid;not null"`
+	Quantity        int        `gorm:"not null"`
+	SerialNumbers   StringList `gorm:"column:serial_numbers;type:jsonb"`
+	// Quarantined diisi saat dispatch bila stok asal berstatus karantina; penerimaan tetap karantina
+	Quarantined bool      `gorm:"column:quarantined;not null;default:false"`
+	CreatedAt   time.Time `gorm:"default:current_timestamp"`
+
+	Product Product `gorm:"foreignKey:SourceProductID;references:ID"`
+}
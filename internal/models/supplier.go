@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Supplier adalah pemasok barang. LeadTimeDays dipakai sebagai default tanggal kedatangan PO.
+type Supplier struct {
+	ID           uuid.UUID      `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	Code         string         `gorm:"type:varchar(50);unique;not null"`
+	Name         string         `gorm:"type:varchar(150);not null"`
+	Email        string         `gorm:"type:varchar(150)"`
+	Phone        string         `gorm:"type:varchar(50)"`
+	Address      string         `gorm:"type:text"`
+	LeadTimeDays int            `gorm:"column:lead_time_days;not null;default:0"`
+	IsActive     bool           `gorm:"column:is_active;not null;default:true"`
+	Note         string         `gorm:"type:text"`
+	CreatedBy    uuid.UUID      `gorm:"column:created_by;type:uuid"`
+	CreatedAt    time.Time      `gorm:"default:current_timestamp"`
+	UpdatedAt    time.Time      `gorm:"default:current_timestamp"`
+	DeletedAt    gorm.DeletedAt `gorm:"index"`
+
+	Contacts []SupplierContact `gorm:"foreignKey:SupplierID;references:ID"`
+	Products []SupplierProduct `gorm:"foreignKey:SupplierID;references:ID"`
+}
+
+type SupplierContact struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	SupplierID uuid.UUID `gorm:"column:supplier_id;type:uuid;not null;index"`
+	Name       string    `gorm:"type:varchar(100);not null"`
+	Title      string    `gorm:"type:varchar(100)"`
+	Email      string    `gorm:"type:varchar(150)"`
+	Phone      string    `gorm:"type:varchar(50)"`
+	IsPrimary  bool      `gorm:"column:is_primary;not null;default:false"`
+	CreatedAt  time.Time `gorm:"default:current_timestamp"`
+}
+
+// SupplierProduct memetakan SKU pemasok ke produk (Product.SKU) kita, beserta harga dan lead time khusus produk
+type SupplierProduct struct {
+	ID               uuid.UUID `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	SupplierID       uuid.UUID `gorm:"column:supplier_id;type:uuid;not null;uniqueIndex:idx_supplier_product;uniqueIndex:idx_supplier_sku"`
+	SourceProductID  uuid.UUID `gorm:"column:source_product_id;type:uuid;not null;uniqueIndex:idx_supplier_product;index"`
+	SupplierSKU      string    `gorm:"column:supplier_sku;type:varchar(100);not null;uniqueIndex:idx_supplier_sku"`
+	UnitCost         float64   `gorm:"column:unit_cost;type:numeric(18,4);not null;default:0"`
+	LeadTimeDays     *int      `gorm:"column:lead_time_days"` // nil = lead time supplier
+	MinOrderQuantity int       `gorm:"column:min_order_quantity;not null;default:1"`
+	Preferred        bool      `gorm:"column:preferred;not null;default:false"`
+	CreatedAt        time.Time `gorm:"default:current_timestamp"`
+	UpdatedAt        time.Time `gorm:"default:current_timestamp"`
+
+	Supplier Supplier `gorm:"foreignKey:SupplierID;references:ID"`
+	Product  Product  `gorm:"foreignKey:SourceProductID;references:ID"`
+}
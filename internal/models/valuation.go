@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CostLayer adalah lapisan biaya FIFO: satu per entri ledger inbound produk ber-costing fifo.
+// Outbound mengonsumsi RemainingQuantity dari layer tertua lebih dulu.
+type CostLayer struct {
+	ID                  uuid.UUID `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	ProductStockID      uuid.UUID `gorm:"column:product_stock_id;type:uuid;not null;index"`
+	SourceProductID     uuid.UUID `gorm:"column:source_product_id;type:uuid;not null"`
+	WarehouseLocationID uuid.UUID `gorm:"column:warehouse_location_id;type:uuid;not null"`
+	StockMovementID     uuid.UUID `gorm:"column:stock_movement_id;type:uuid;not null"`
+	Quantity            int       `gorm:"not null"`
+	RemainingQuantity   int       `gorm:"column:remaining_quantity;not null"`
+	UnitCost            float64   `gorm:"column:unit_cost;type:numeric(18,4);not null"`
+	CreatedAt           time.Time `gorm:"default:current_timestamp;index"`
+}
@@ -0,0 +1,100 @@
+package repositorys
+
+import (
+	"auth-service/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type BarcodeRepository interface {
+	CreateProductBarcode(barcode *models.ProductBarcode) error
+	DeleteProductBarcode(productID, id uuid.UUID) error
+	GetProductBarcodeByID(productID, id uuid.UUID) (*models.ProductBarcode, error)
+	GetProductBarcodes(productID uuid.UUID) ([]models.ProductBarcode, error)
+	SetPrimaryProductBarcode(productID, id uuid.UUID) error
+	FindProductBarcode(code string, gtin *string) (*models.ProductBarcode, error)
+	GetStockLotsByNumber(productID uuid.UUID, lotNumber string, locationID *uuid.UUID) ([]models.StockLot, error)
+
+	// WithTransaction menjalankan fn dalam satu transaksi dengan repository barcode yang terikat ke transaksi tersebut
+	WithTransaction(fn func(repo BarcodeRepository) error) error
+}
+
+type barcodeRepository struct {
+	db *gorm.DB
+}
+
+func NewBarcodeRepository(db *gorm.DB) BarcodeRepository {
+	return &barcodeRepository{db: db}
+}
+
+func (r *barcodeRepository) WithTransaction(fn func(repo BarcodeRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&barcodeRepository{db: tx})
+	})
+}
+
+func (r *barcodeRepository) CreateProductBarcode(barcode *models.ProductBarcode) error {
+	return r.db.Omit(clause.Associations).Create(barcode).Error
+}
+
+func (r *barcodeRepository) DeleteProductBarcode(productID, id uuid.UUID) error {
+	result := r.db.Where("id = ? AND source_product_id = ?", id, productID).Delete(&models.ProductBarcode{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *barcodeRepository) GetProductBarcodeByID(productID, id uuid.UUID) (*models.ProductBarcode, error) {
+	var barcode models.ProductBarcode
+	if err := r.db.Where("id = ? AND source_product_id = ?", id, productID).First(&barcode).Error; err != nil {
+		return nil, err
+	}
+	return &barcode, nil
+}
+
+func (r *barcodeRepository) GetProductBarcodes(productID uuid.UUID) ([]models.ProductBarcode, error) {
+	return (&productRepository{db: r.db}).GetProductBarcodes(productID)
+}
+
+// SetPrimaryProductBarcode menjadikan satu barcode sebagai barcode utama produk dan melepas yang lain
+func (r *barcodeRepository) SetPrimaryProductBarcode(productID, id uuid.UUID) error {
+	return r.db.Model(&models.ProductBarcode{}).
+		Where("source_product_id = ?", productID).
+		Update("is_primary", gorm.Expr("id = ?", id)).Error
+}
+
+// FindProductBarcode mencari barcode berdasarkan kode persis atau GTIN 14 digit; hanya produk yang belum dihapus
+func (r *barcodeRepository) FindProductBarcode(code string, gtin *string) (*models.ProductBarcode, error) {
+	var barcode models.ProductBarcode
+	query := r.db.Joins("JOIN products p ON p.id = product_barcodes.source_product_id AND p.deleted_at IS NULL")
+	if gtin != nil {
+		query = query.Where("(product_barcodes.code = ? OR product_barcodes.gtin = ?)", code, *gtin)
+	} else {
+		query = query.Where("product_barcodes.code = ?", code)
+	}
+	if err := query.Preload("Product").First(&barcode).Error; err != nil {
+		return nil, err
+	}
+	return &barcode, nil
+}
+
+// GetStockLotsByNumber mengambil lot bersaldo dengan nomor tersebut per lokasi, opsional hanya di satu lokasi
+func (r *barcodeRepository) GetStockLotsByNumber(productID uuid.UUID, lotNumber string, locationID *uuid.UUID) ([]models.StockLot, error) {
+	var lots []models.StockLot
+	query := r.db.Where("source_product_id = ? AND lot_number = ? AND quantity > 0", productID, lotNumber)
+	if locationID != nil {
+		query = query.Where("warehouse_location_id = ?", *locationID)
+	}
+	if err := query.Preload("WarehouseLocation").
+		Order("quantity DESC").
+		Find(&lots).Error; err != nil {
+		return nil, err
+	}
+	return lots, nil
+}
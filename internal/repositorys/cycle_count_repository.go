@@ -0,0 +1,172 @@
+package repositorys
+
+import (
+	"auth-service/internal/dtos"
+	"auth-service/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CycleCountRepository interface {
+	CreateCycleCount(count *models.CycleCount) error
+	UpdateCycleCount(count *models.CycleCount) error
+	GetCycleCountByID(id uuid.UUID) (*models.CycleCount, error)
+	GetCycleCountForUpdate(id uuid.UUID) (*models.CycleCount, error)
+	GetCycleCountsList(req dtos.CycleCountListRequest) ([]models.CycleCount, int64, error)
+	UpdateCycleCountLine(line *models.CycleCountLine) error
+	SaveCycleCountEntry(entry *models.CycleCountEntry) error
+	GetCycleCountScopeStocks(root *models.WarehouseLocation, productID *uuid.UUID) ([]models.ProductStock, error)
+	GetInStockSerialNumbers(productID, locationID uuid.UUID) ([]string, error)
+	HasOpenCycleCountLines(stockIDs []uuid.UUID) (bool, error)
+
+	// WithTransaction menjalankan fn dalam satu transaksi, dengan repository sesi hitung dan stok yang terikat ke transaksi tersebut
+	WithTransaction(fn func(repo CycleCountRepository, stockRepo ProductRepository) error) error
+}
+
+type cycleCountRepository struct {
+	db *gorm.DB
+}
+
+func NewCycleCountRepository(db *gorm.DB) CycleCountRepository {
+	return &cycleCountRepository{db: db}
+}
+
+func (r *cycleCountRepository) WithTransaction(fn func(repo CycleCountRepository, stockRepo ProductRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&cycleCountRepository{db: tx}, &productRepository{db: tx})
+	})
+}
+
+// CreateCycleCount menyimpan header sesi beserta baris-baris yang sudah dibekukan
+func (r *cycleCountRepository) CreateCycleCount(count *models.CycleCount) error {
+	return r.db.Omit("WarehouseLocation", "Product", "Lines.Product", "Lines.WarehouseLocation", "Lines.Lot", "Lines.Entries").Create(count).Error
+}
+
+func (r *cycleCountRepository) UpdateCycleCount(count *models.CycleCount) error {
+	return r.db.Omit(clause.Associations).Save(count).Error
+}
+
+func (r *cycleCountRepository) GetCycleCountByID(id uuid.UUID) (*models.CycleCount, error) {
+	var count models.CycleCount
+	if err := r.db.Where("id = ? AND deleted_at IS NULL", id).
+		Preload("WarehouseLocation").
+		Preload("Product").
+		Preload("Lines").
+		Preload("Lines.Product").
+		Preload("Lines.WarehouseLocation").
+		Preload("Lines.Lot").
+		Preload("Lines.Entries").
+		First(&count).Error; err != nil {
+		return nil, err
+	}
+	return &count, nil
+}
+
+// GetCycleCountForUpdate mengunci baris sesi agar input hitung dan approval tidak balapan
+func (r *cycleCountRepository) GetCycleCountForUpdate(id uuid.UUID) (*models.CycleCount, error) {
+	var count models.CycleCount
+	if err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND deleted_at IS NULL", id).
+		First(&count).Error; err != nil {
+		return nil, err
+	}
+	if err := r.db.Where("cycle_count_id = ?", id).
+		Preload("Product").
+		Preload("Lot").
+		Preload("Entries").
+		Find(&count.Lines).Error; err != nil {
+		return nil, err
+	}
+	return &count, nil
+}
+
+func (r *cycleCountRepository) GetCycleCountsList(req dtos.CycleCountListRequest) ([]models.CycleCount, int64, error) {
+	var counts []models.CycleCount
+	var total int64
+
+	query := r.db.Model(&models.CycleCount{}).Where("deleted_at IS NULL")
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+	if req.WarehouseLocationID != uuid.Nil {
+		query = query.Where("warehouse_location_id = ?", req.WarehouseLocationID)
+	}
+	if req.ProductID != uuid.Nil {
+		query = query.Where("source_product_id = ?", req.ProductID)
+	}
+	if req.Search != "" {
+		query = query.Where("count_number ILIKE ?", "%"+req.Search+"%")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (req.Page - 1) * req.Limit
+	if err := query.Preload("WarehouseLocation").
+		Preload("Product").
+		Order("created_at DESC").
+		Limit(req.Limit).Offset(offset).
+		Find(&counts).Error; err != nil {
+		return nil, 0, err
+	}
+	return counts, total, nil
+}
+
+func (r *cycleCountRepository) UpdateCycleCountLine(line *models.CycleCountLine) error {
+	return r.db.Omit(clause.Associations).Save(line).Error
+}
+
+// SaveCycleCountEntry menyimpan hasil hitung; entri penghitung yang sama untuk baris yang sama ditimpa
+func (r *cycleCountRepository) SaveCycleCountEntry(entry *models.CycleCountEntry) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "cycle_count_line_id"}, {Name: "counted_by"}},
+		DoUpdates: clause.AssignmentColumns([]string{"counted_quantity", "serial_numbers", "updated_at"}),
+	}).Create(entry).Error
+}
+
+// GetCycleCountScopeStocks mengambil ProductStock di subtree root dan/atau milik satu produk, urut path lokasi
+func (r *cycleCountRepository) GetCycleCountScopeStocks(root *models.WarehouseLocation, productID *uuid.UUID) ([]models.ProductStock, error) {
+	var stocks []models.ProductStock
+	query := r.db.Model(&models.ProductStock{}).
+		Joins("JOIN warehouse_locations wl ON wl.id = product_stocks.warehouse_location_id").
+		Where("product_stocks.deleted_at IS NULL")
+	if root != nil {
+		query = query.Where("product_stocks.warehouse_location_id IN (?)", (&productRepository{db: r.db}).subtreeLocationIDs(root))
+	}
+	if productID != nil {
+		query = query.Where("product_stocks.source_product_id = ?", *productID)
+	}
+	if err := query.Preload("Product").
+		Order("wl.path ASC, product_stocks.created_at ASC").
+		Find(&stocks).Error; err != nil {
+		return nil, err
+	}
+	return stocks, nil
+}
+
+// GetInStockSerialNumbers mengambil nomor serial produk yang sedang berada di satu lokasi
+func (r *cycleCountRepository) GetInStockSerialNumbers(productID, locationID uuid.UUID) ([]string, error) {
+	var serials []string
+	err := r.db.Model(&models.SerialNumber{}).
+		Where("source_product_id = ? AND warehouse_location_id = ? AND status = ?", productID, locationID, "in_stock").
+		Order("serial_number ASC").
+		Pluck("serial_number", &serials).Error
+	if err != nil {
+		return nil, err
+	}
+	return serials, nil
+}
+
+// HasOpenCycleCountLines memeriksa apakah salah satu stok sedang dihitung di sesi lain yang masih open
+func (r *cycleCountRepository) HasOpenCycleCountLines(stockIDs []uuid.UUID) (bool, error) {
+	var exists bool
+	err := r.db.Raw(`SELECT EXISTS (
+		SELECT 1 FROM cycle_count_lines l
+		JOIN cycle_counts c ON c.id = l.cycle_count_id
+		WHERE c.status = 'open' AND c.deleted_at IS NULL AND l.product_stock_id IN ?
+	)`, stockIDs).Scan(&exists).Error
+	return exists, err
+}
@@ -0,0 +1,113 @@
+package repositorys
+
+import (
+	"auth-service/internal/dtos"
+	"auth-service/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type KitRepository interface {
+	ReplaceProductComponents(kitID uuid.UUID, components []models.ProductComponent) error
+	// IsKitComponent memeriksa apakah produk dipakai sebagai komponen di BOM kit lain
+	IsKitComponent(productID uuid.UUID) (bool, error)
+	CreateKitAssembly(assembly *models.KitAssembly) error
+	GetKitAssemblyByID(id uuid.UUID) (*models.KitAssembly, error)
+	GetKitAssembliesList(req dtos.KitAssemblyListRequest) ([]models.KitAssembly, int64, error)
+	GetKitAssemblyMovements(id uuid.UUID) ([]models.StockMovement, error)
+
+	// WithTransaction menjalankan fn dalam satu transaksi dengan repository kit dan stok yang terikat ke transaksi tersebut
+	WithTransaction(fn func(repo KitRepository, stockRepo ProductRepository) error) error
+}
+
+type kitRepository struct {
+	db *gorm.DB
+}
+
+func NewKitRepository(db *gorm.DB) KitRepository {
+	return &kitRepository{db: db}
+}
+
+func (r *kitRepository) WithTransaction(fn func(repo KitRepository, stockRepo ProductRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&kitRepository{db: tx}, &productRepository{db: tx})
+	})
+}
+
+// ReplaceProductComponents mengganti seluruh baris BOM sebuah kit
+func (r *kitRepository) ReplaceProductComponents(kitID uuid.UUID, components []models.ProductComponent) error {
+	if err := r.db.Where("kit_product_id = ?", kitID).Delete(&models.ProductComponent{}).Error; err != nil {
+		return err
+	}
+	if len(components) == 0 {
+		return nil
+	}
+	return r.db.Omit(clause.Associations).Create(&components).Error
+}
+
+func (r *kitRepository) IsKitComponent(productID uuid.UUID) (bool, error) {
+	var count int64
+	if err := r.db.Model(&models.ProductComponent{}).Where("component_product_id = ?", productID).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *kitRepository) CreateKitAssembly(assembly *models.KitAssembly) error {
+	return r.db.Omit(clause.Associations).Create(assembly).Error
+}
+
+func (r *kitRepository) GetKitAssemblyByID(id uuid.UUID) (*models.KitAssembly, error) {
+	var assembly models.KitAssembly
+	if err := r.db.Where("id = ?", id).
+		Preload("Product").
+		Preload("WarehouseLocation").
+		First(&assembly).Error; err != nil {
+		return nil, err
+	}
+	return &assembly, nil
+}
+
+func (r *kitRepository) GetKitAssembliesList(req dtos.KitAssemblyListRequest) ([]models.KitAssembly, int64, error) {
+	var assemblies []models.KitAssembly
+	var total int64
+
+	query := r.db.Model(&models.KitAssembly{})
+	if req.Operation != "" {
+		query = query.Where("operation = ?", req.Operation)
+	}
+	if req.ProductID != uuid.Nil {
+		query = query.Where("kit_product_id = ?", req.ProductID)
+	}
+	if req.WarehouseLocationID != uuid.Nil {
+		query = query.Where("warehouse_location_id = ?", req.WarehouseLocationID)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (req.Page - 1) * req.Limit
+	if err := query.Preload("Product").
+		Preload("WarehouseLocation").
+		Order("created_at DESC").
+		Limit(req.Limit).Offset(offset).
+		Find(&assemblies).Error; err != nil {
+		return nil, 0, err
+	}
+	return assemblies, total, nil
+}
+
+// GetKitAssemblyMovements mengambil seluruh entri ledger (komponen dan kit) yang dibukukan oleh satu assembly
+func (r *kitRepository) GetKitAssemblyMovements(id uuid.UUID) ([]models.StockMovement, error) {
+	var movements []models.StockMovement
+	if err := r.db.Where("reference_type = ? AND reference_id = ?", "kit_assembly", id).
+		Preload("Lot").
+		Order("created_at ASC").
+		Find(&movements).Error; err != nil {
+		return nil, err
+	}
+	return movements, nil
+}
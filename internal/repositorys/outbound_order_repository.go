@@ -0,0 +1,170 @@
+package repositorys
+
+import (
+	"auth-service/internal/dtos"
+	"auth-service/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OutboundOrderRepository interface {
+	CreateOutboundOrder(order *models.OutboundOrder) error
+	UpdateOutboundOrder(order *models.OutboundOrder) error
+	GetOutboundOrderByID(id uuid.UUID) (*models.OutboundOrder, error)
+	GetOutboundOrderForUpdate(id uuid.UUID) (*models.OutboundOrder, error)
+	GetOutboundOrdersList(req dtos.OutboundOrderListRequest) ([]models.OutboundOrder, int64, error)
+	ReplaceOutboundOrderLines(orderID uuid.UUID, lines []models.OutboundOrderLine) error
+	UpdateOutboundOrderLine(line *models.OutboundOrderLine) error
+	GetAllocatableStocksForUpdate(productID uuid.UUID, root *models.WarehouseLocation) ([]models.ProductStock, error)
+	CreateOutboundAllocation(allocation *models.OutboundAllocation) error
+	GetOutboundAllocationsByOrderID(orderID uuid.UUID) ([]models.OutboundAllocation, error)
+
+	// WithTransaction menjalankan fn dalam satu transaksi, dengan repository pesanan, reservasi, dan stok yang terikat ke transaksi tersebut
+	WithTransaction(fn func(repo OutboundOrderRepository, reservationRepo StockReservationRepository, stockRepo ProductRepository) error) error
+}
+
+type outboundOrderRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboundOrderRepository(db *gorm.DB) OutboundOrderRepository {
+	return &outboundOrderRepository{db: db}
+}
+
+func (r *outboundOrderRepository) WithTransaction(fn func(repo OutboundOrderRepository, reservationRepo StockReservationRepository, stockRepo ProductRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&outboundOrderRepository{db: tx}, &stockReservationRepository{db: tx}, &productRepository{db: tx})
+	})
+}
+
+// CreateOutboundOrder menyimpan header pesanan beserta baris-barisnya
+func (r *outboundOrderRepository) CreateOutboundOrder(order *models.OutboundOrder) error {
+	return r.db.Omit("WarehouseLocation", "Lines.Product", "Lines.Allocations").Create(order).Error
+}
+
+func (r *outboundOrderRepository) UpdateOutboundOrder(order *models.OutboundOrder) error {
+	return r.db.Omit(clause.Associations).Save(order).Error
+}
+
+func (r *outboundOrderRepository) GetOutboundOrderByID(id uuid.UUID) (*models.OutboundOrder, error) {
+	var order models.OutboundOrder
+	if err := r.db.Where("id = ? AND deleted_at IS NULL", id).
+		Preload("WarehouseLocation").
+		Preload("Lines", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		}).
+		Preload("Lines.Product").
+		Preload("Lines.Allocations", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		}).
+		Preload("Lines.Allocations.WarehouseLocation").
+		Preload("Lines.Allocations.Reservation").
+		First(&order).Error; err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// GetOutboundOrderForUpdate mengunci baris pesanan agar alokasi, packing, dan pengiriman tidak balapan
+func (r *outboundOrderRepository) GetOutboundOrderForUpdate(id uuid.UUID) (*models.OutboundOrder, error) {
+	var order models.OutboundOrder
+	if err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND deleted_at IS NULL", id).
+		First(&order).Error; err != nil {
+		return nil, err
+	}
+	if err := r.db.Where("outbound_order_id = ?", id).Preload("Product").Order("created_at ASC").Find(&order.Lines).Error; err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (r *outboundOrderRepository) GetOutboundOrdersList(req dtos.OutboundOrderListRequest) ([]models.OutboundOrder, int64, error) {
+	var orders []models.OutboundOrder
+	var total int64
+
+	query := r.db.Model(&models.OutboundOrder{}).Where("deleted_at IS NULL")
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+	if req.WarehouseLocationID != uuid.Nil {
+		query = query.Where("warehouse_location_id = ?", req.WarehouseLocationID)
+	}
+	if req.PickWaveID != uuid.Nil {
+		query = query.Where("pick_wave_id = ?", req.PickWaveID)
+	}
+	if req.ProductID != uuid.Nil {
+		query = query.Where("id IN (?)", r.db.Model(&models.OutboundOrderLine{}).Select("outbound_order_id").Where("source_product_id = ?", req.ProductID))
+	}
+	if req.Search != "" {
+		query = query.Where("(order_number ILIKE ? OR customer_name ILIKE ? OR customer_reference ILIKE ?)", "%"+req.Search+"%", "%"+req.Search+"%", "%"+req.Search+"%")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (req.Page - 1) * req.Limit
+	if err := query.Preload("WarehouseLocation").
+		Preload("Lines.Product").
+		Order("created_at DESC").
+		Limit(req.Limit).Offset(offset).
+		Find(&orders).Error; err != nil {
+		return nil, 0, err
+	}
+	return orders, total, nil
+}
+
+// ReplaceOutboundOrderLines mengganti seluruh baris pesanan (hanya untuk pesanan draft)
+func (r *outboundOrderRepository) ReplaceOutboundOrderLines(orderID uuid.UUID, lines []models.OutboundOrderLine) error {
+	if err := r.db.Where("outbound_order_id = ?", orderID).Delete(&models.OutboundOrderLine{}).Error; err != nil {
+		return err
+	}
+	return r.db.Omit("Product", "Allocations").Create(&lines).Error
+}
+
+func (r *outboundOrderRepository) UpdateOutboundOrderLine(line *models.OutboundOrderLine) error {
+	return r.db.Omit(clause.Associations).Save(line).Error
+}
+
+// GetAllocatableStocksForUpdate mengunci ProductStock produk yang masih punya stok tersedia
+// (on hand - reserved, bukan karantina), opsional di subtree root. Stok tersedia terbanyak didahulukan agar
+// pesanan diambil dari sesedikit mungkin lokasi.
+func (r *outboundOrderRepository) GetAllocatableStocksForUpdate(productID uuid.UUID, root *models.WarehouseLocation) ([]models.ProductStock, error) {
+	var stocks []models.ProductStock
+	query := r.db.Model(&models.ProductStock{}).
+		Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "product_stocks"}}).
+		Joins("JOIN warehouse_locations wl ON wl.id = product_stocks.warehouse_location_id").
+		Where("product_stocks.source_product_id = ? AND product_stocks.deleted_at IS NULL", productID).
+		Where("product_stocks.quantity > product_stocks.reserved_quantity AND product_stocks.status <> 'quarantined'")
+	if root != nil {
+		query = query.Where("product_stocks.warehouse_location_id IN (?)", (&productRepository{db: r.db}).subtreeLocationIDs(root))
+	}
+	if err := query.Order("product_stocks.quantity - product_stocks.reserved_quantity DESC, wl.path ASC").
+		Find(&stocks).Error; err != nil {
+		return nil, err
+	}
+	return stocks, nil
+}
+
+func (r *outboundOrderRepository) CreateOutboundAllocation(allocation *models.OutboundAllocation) error {
+	return r.db.Omit(clause.Associations).Create(allocation).Error
+}
+
+// GetOutboundAllocationsByOrderID mengambil seluruh alokasi pesanan beserta status reservasinya, urut path lokasi
+func (r *outboundOrderRepository) GetOutboundAllocationsByOrderID(orderID uuid.UUID) ([]models.OutboundAllocation, error) {
+	var allocations []models.OutboundAllocation
+	if err := r.db.Model(&models.OutboundAllocation{}).
+		Joins("JOIN warehouse_locations wl ON wl.id = outbound_allocations.warehouse_location_id").
+		Where("outbound_allocations.outbound_order_id = ?", orderID).
+		Preload("Product").
+		Preload("WarehouseLocation").
+		Preload("Reservation").
+		Order("wl.path ASC, outbound_allocations.created_at ASC").
+		Find(&allocations).Error; err != nil {
+		return nil, err
+	}
+	return allocations, nil
+}
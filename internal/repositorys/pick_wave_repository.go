@@ -0,0 +1,172 @@
+package repositorys
+
+import (
+	"time"
+
+	"auth-service/internal/dtos"
+	"auth-service/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// WaveCandidateOrder adalah pesanan yang memenuhi aturan wave beserta jumlah barisnya
+type WaveCandidateOrder struct {
+	ID          uuid.UUID
+	OrderNumber string
+	LineCount   int
+}
+
+type PickWaveRepository interface {
+	CreatePickWave(wave *models.PickWave) error
+	UpdatePickWave(wave *models.PickWave) error
+	GetPickWaveByID(id uuid.UUID) (*models.PickWave, error)
+	GetPickWaveForUpdate(id uuid.UUID) (*models.PickWave, error)
+	GetPickWavesList(req dtos.PickWaveListRequest) ([]models.PickWave, int64, error)
+	GetWaveCandidateOrdersForUpdate(carrier string, cutoff *time.Time, zone *models.WarehouseLocation) ([]WaveCandidateOrder, error)
+	AssignOrdersToWave(waveID uuid.UUID, orderIDs []uuid.UUID) error
+	ReleaseWaveOrders(waveID uuid.UUID) error
+	GetPickWaveAllocations(waveID uuid.UUID) ([]models.OutboundAllocation, error)
+	GetWarehouseLocationsByPaths(paths []string) ([]models.WarehouseLocation, error)
+
+	// WithTransaction menjalankan fn dalam satu transaksi dengan repository wave dan stok yang terikat ke transaksi tersebut
+	WithTransaction(fn func(repo PickWaveRepository, stockRepo ProductRepository) error) error
+}
+
+type pickWaveRepository struct {
+	db *gorm.DB
+}
+
+func NewPickWaveRepository(db *gorm.DB) PickWaveRepository {
+	return &pickWaveRepository{db: db}
+}
+
+func (r *pickWaveRepository) WithTransaction(fn func(repo PickWaveRepository, stockRepo ProductRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&pickWaveRepository{db: tx}, &productRepository{db: tx})
+	})
+}
+
+func (r *pickWaveRepository) CreatePickWave(wave *models.PickWave) error {
+	return r.db.Omit(clause.Associations).Create(wave).Error
+}
+
+func (r *pickWaveRepository) UpdatePickWave(wave *models.PickWave) error {
+	return r.db.Omit(clause.Associations).Save(wave).Error
+}
+
+func (r *pickWaveRepository) GetPickWaveByID(id uuid.UUID) (*models.PickWave, error) {
+	var wave models.PickWave
+	if err := r.db.Where("id = ?", id).
+		Preload("Zone").
+		Preload("Orders", func(db *gorm.DB) *gorm.DB {
+			return db.Where("deleted_at IS NULL").Order("created_at ASC")
+		}).
+		Preload("Orders.Lines").
+		First(&wave).Error; err != nil {
+		return nil, err
+	}
+	return &wave, nil
+}
+
+func (r *pickWaveRepository) GetPickWaveForUpdate(id uuid.UUID) (*models.PickWave, error) {
+	var wave models.PickWave
+	if err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&wave).Error; err != nil {
+		return nil, err
+	}
+	return &wave, nil
+}
+
+func (r *pickWaveRepository) GetPickWavesList(req dtos.PickWaveListRequest) ([]models.PickWave, int64, error) {
+	var waves []models.PickWave
+	var total int64
+
+	query := r.db.Model(&models.PickWave{})
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (req.Page - 1) * req.Limit
+	if err := query.Preload("Zone").
+		Order("created_at DESC").
+		Limit(req.Limit).Offset(offset).
+		Find(&waves).Error; err != nil {
+		return nil, 0, err
+	}
+	return waves, total, nil
+}
+
+// activeAllocationExists adalah kondisi EXISTS alokasi dengan reservasi aktif milik outbound_orders baris luar
+const activeAllocationExists = `EXISTS (SELECT 1 FROM outbound_allocations oa
+	JOIN stock_reservations sr ON sr.id = oa.stock_reservation_id
+	WHERE oa.outbound_order_id = outbound_orders.id AND sr.status = 'active'`
+
+// GetWaveCandidateOrdersForUpdate mengunci pesanan teralokasi yang belum masuk wave dan masih punya
+// alokasi aktif, difilter carrier, cutoff (waktu pesanan masuk), dan zone (seluruh alokasi aktif di subtree zone).
+// Urutan: tanggal kirim yang diminta paling awal, lalu pesanan paling lama.
+func (r *pickWaveRepository) GetWaveCandidateOrdersForUpdate(carrier string, cutoff *time.Time, zone *models.WarehouseLocation) ([]WaveCandidateOrder, error) {
+	var rows []WaveCandidateOrder
+	query := r.db.Table("outbound_orders").
+		Select("outbound_orders.id, outbound_orders.order_number, (SELECT COUNT(*) FROM outbound_order_lines l WHERE l.outbound_order_id = outbound_orders.id) AS line_count").
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("outbound_orders.deleted_at IS NULL AND outbound_orders.pick_wave_id IS NULL").
+		Where("outbound_orders.status IN ?", []string{"allocated", "partially_allocated"}).
+		Where(activeAllocationExists + ")")
+	if carrier != "" {
+		query = query.Where("LOWER(outbound_orders.carrier) = LOWER(?)", carrier)
+	}
+	if cutoff != nil {
+		query = query.Where("outbound_orders.created_at <= ?", *cutoff)
+	}
+	if zone != nil {
+		query = query.Where("NOT "+activeAllocationExists+" AND oa.warehouse_location_id NOT IN (?))", (&productRepository{db: r.db}).subtreeLocationIDs(zone))
+	}
+	if err := query.Order("outbound_orders.requested_ship_date ASC NULLS LAST, outbound_orders.created_at ASC").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (r *pickWaveRepository) AssignOrdersToWave(waveID uuid.UUID, orderIDs []uuid.UUID) error {
+	return r.db.Model(&models.OutboundOrder{}).
+		Where("id IN ?", orderIDs).
+		Updates(map[string]interface{}{"pick_wave_id": waveID, "updated_at": time.Now()}).Error
+}
+
+// ReleaseWaveOrders melepas pesanan dari wave sehingga bisa masuk wave lain
+func (r *pickWaveRepository) ReleaseWaveOrders(waveID uuid.UUID) error {
+	return r.db.Model(&models.OutboundOrder{}).
+		Where("pick_wave_id = ?", waveID).
+		Updates(map[string]interface{}{"pick_wave_id": nil, "updated_at": time.Now()}).Error
+}
+
+// GetPickWaveAllocations mengambil alokasi aktif seluruh pesanan wave, urut path lokasi
+func (r *pickWaveRepository) GetPickWaveAllocations(waveID uuid.UUID) ([]models.OutboundAllocation, error) {
+	var allocations []models.OutboundAllocation
+	if err := r.db.Model(&models.OutboundAllocation{}).
+		Joins("JOIN outbound_orders o ON o.id = outbound_allocations.outbound_order_id").
+		Joins("JOIN stock_reservations sr ON sr.id = outbound_allocations.stock_reservation_id").
+		Joins("JOIN warehouse_locations wl ON wl.id = outbound_allocations.warehouse_location_id").
+		Where("o.pick_wave_id = ? AND o.deleted_at IS NULL AND sr.status = 'active'", waveID).
+		Preload("Product").
+		Preload("WarehouseLocation").
+		Order("wl.path ASC, outbound_allocations.created_at ASC").
+		Find(&allocations).Error; err != nil {
+		return nil, err
+	}
+	return allocations, nil
+}
+
+func (r *pickWaveRepository) GetWarehouseLocationsByPaths(paths []string) ([]models.WarehouseLocation, error) {
+	var locations []models.WarehouseLocation
+	if err := r.db.Where("path IN ? AND deleted_at IS NULL", paths).Find(&locations).Error; err != nil {
+		return nil, err
+	}
+	return locations, nil
+}
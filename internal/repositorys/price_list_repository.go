@@ -0,0 +1,208 @@
+package repositorys
+
+import (
+	"time"
+
+	"auth-service/internal/dtos"
+	"auth-service/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PriceListRepository interface {
+	CreatePriceList(list *models.PriceList) error
+	UpdatePriceList(list *models.PriceList) error
+	DeletePriceList(id uuid.UUID) error
+	GetPriceListByID(id uuid.UUID) (*models.PriceList, error)
+	GetPriceListForUpdate(id uuid.UUID) (*models.PriceList, error)
+	GetPriceListsList(req dtos.PriceListListRequest) ([]models.PriceList, int64, error)
+	ClearDefaultPriceLists(exceptID uuid.UUID) error
+
+	CreateProductPrice(price *models.ProductPrice) error
+	UpdateProductPrice(price *models.ProductPrice) error
+	DeleteProductPrice(id uuid.UUID) error
+	GetProductPriceByID(priceListID, id uuid.UUID) (*models.ProductPrice, error)
+	GetProductPricesList(priceListID uuid.UUID, req dtos.ProductPriceListRequest, at *time.Time) ([]models.ProductPrice, int64, error)
+	GetOverlappingProductPrices(priceListID, productID uuid.UUID, from time.Time, to *time.Time, exceptID uuid.UUID) ([]models.ProductPrice, error)
+
+	CreateProductPriceHistory(entry *models.ProductPriceHistory) error
+	GetProductPriceHistory(priceListID uuid.UUID, req dtos.ProductPriceHistoryRequest, from, to *time.Time) ([]models.ProductPriceHistory, int64, error)
+
+	// WithTransaction menjalankan fn dalam satu transaksi dengan repository daftar harga yang terikat ke transaksi tersebut
+	WithTransaction(fn func(repo PriceListRepository) error) error
+}
+
+type priceListRepository struct {
+	db *gorm.DB
+}
+
+func NewPriceListRepository(db *gorm.DB) PriceListRepository {
+	return &priceListRepository{db: db}
+}
+
+func (r *priceListRepository) WithTransaction(fn func(repo PriceListRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&priceListRepository{db: tx})
+	})
+}
+
+func (r *priceListRepository) CreatePriceList(list *models.PriceList) error {
+	return r.db.Create(list).Error
+}
+
+func (r *priceListRepository) UpdatePriceList(list *models.PriceList) error {
+	return r.db.Save(list).Error
+}
+
+func (r *priceListRepository) DeletePriceList(id uuid.UUID) error {
+	return r.db.Where("id = ?", id).Delete(&models.PriceList{}).Error
+}
+
+func (r *priceListRepository) GetPriceListByID(id uuid.UUID) (*models.PriceList, error) {
+	return (&productRepository{db: r.db}).GetPriceListByID(id)
+}
+
+// GetPriceListForUpdate mengunci daftar harga agar perubahan harga di dalamnya berjalan berurutan
+func (r *priceListRepository) GetPriceListForUpdate(id uuid.UUID) (*models.PriceList, error) {
+	var list models.PriceList
+	if err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND deleted_at IS NULL", id).
+		First(&list).Error; err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+func (r *priceListRepository) GetPriceListsList(req dtos.PriceListListRequest) ([]models.PriceList, int64, error) {
+	var lists []models.PriceList
+	var total int64
+
+	query := r.db.Model(&models.PriceList{}).Where("deleted_at IS NULL")
+	if req.Type != "" {
+		query = query.Where("type = ?", req.Type)
+	}
+	if req.CustomerGroup != "" {
+		query = query.Where("customer_group = ?", req.CustomerGroup)
+	}
+	if req.IsActive != nil {
+		query = query.Where("is_active = ?", *req.IsActive)
+	}
+	if req.Search != "" {
+		query = query.Where("(code ILIKE ? OR name ILIKE ?)", "%"+req.Search+"%", "%"+req.Search+"%")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (req.Page - 1) * req.Limit
+	if err := query.Order("is_default DESC, name ASC").Limit(req.Limit).Offset(offset).Find(&lists).Error; err != nil {
+		return nil, 0, err
+	}
+	return lists, total, nil
+}
+
+// ClearDefaultPriceLists melepas tanda default daftar harga lain
+func (r *priceListRepository) ClearDefaultPriceLists(exceptID uuid.UUID) error {
+	return r.db.Model(&models.PriceList{}).
+		Where("id <> ? AND is_default", exceptID).
+		Update("is_default", false).Error
+}
+
+func (r *priceListRepository) CreateProductPrice(price *models.ProductPrice) error {
+	return r.db.Omit(clause.Associations).Create(price).Error
+}
+
+func (r *priceListRepository) UpdateProductPrice(price *models.ProductPrice) error {
+	return r.db.Omit(clause.Associations).Save(price).Error
+}
+
+func (r *priceListRepository) DeleteProductPrice(id uuid.UUID) error {
+	return r.db.Where("id = ?", id).Delete(&models.ProductPrice{}).Error
+}
+
+func (r *priceListRepository) GetProductPriceByID(priceListID, id uuid.UUID) (*models.ProductPrice, error) {
+	var price models.ProductPrice
+	if err := r.db.Where("id = ? AND price_list_id = ?", id, priceListID).
+		Preload("Product").
+		First(&price).Error; err != nil {
+		return nil, err
+	}
+	return &price, nil
+}
+
+func (r *priceListRepository) GetProductPricesList(priceListID uuid.UUID, req dtos.ProductPriceListRequest, at *time.Time) ([]models.ProductPrice, int64, error) {
+	var prices []models.ProductPrice
+	var total int64
+
+	query := r.db.Model(&models.ProductPrice{}).
+		Joins("JOIN products p ON p.id = product_prices.source_product_id").
+		Where("product_prices.price_list_id = ?", priceListID)
+	if req.ProductID != uuid.Nil {
+		query = query.Where("product_prices.source_product_id = ?", req.ProductID)
+	}
+	if req.Search != "" {
+		query = query.Where("(p.name ILIKE ? OR p.sku ILIKE ?)", "%"+req.Search+"%", "%"+req.Search+"%")
+	}
+	if at != nil {
+		query = query.Where("product_prices.effective_from <= ? AND (product_prices.effective_to IS NULL OR product_prices.effective_to > ?)", *at, *at)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (req.Page - 1) * req.Limit
+	if err := query.Order("p.name ASC, product_prices.effective_from DESC").
+		Preload("Product").
+		Limit(req.Limit).Offset(offset).
+		Find(&prices).Error; err != nil {
+		return nil, 0, err
+	}
+	return prices, total, nil
+}
+
+// GetOverlappingProductPrices mengambil harga produk yang periodenya beririsan dengan [from, to); to nil berarti tanpa batas
+func (r *priceListRepository) GetOverlappingProductPrices(priceListID, productID uuid.UUID, from time.Time, to *time.Time, exceptID uuid.UUID) ([]models.ProductPrice, error) {
+	var prices []models.ProductPrice
+	query := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("price_list_id = ? AND source_product_id = ? AND id <> ?", priceListID, productID, exceptID).
+		Where("(effective_to IS NULL OR effective_to > ?)", from)
+	if to != nil {
+		query = query.Where("effective_from < ?", *to)
+	}
+	if err := query.Order("effective_from ASC").Find(&prices).Error; err != nil {
+		return nil, err
+	}
+	return prices, nil
+}
+
+func (r *priceListRepository) CreateProductPriceHistory(entry *models.ProductPriceHistory) error {
+	return r.db.Omit(clause.Associations).Create(entry).Error
+}
+
+func (r *priceListRepository) GetProductPriceHistory(priceListID uuid.UUID, req dtos.ProductPriceHistoryRequest, from, to *time.Time) ([]models.ProductPriceHistory, int64, error) {
+	var entries []models.ProductPriceHistory
+	var total int64
+
+	query := r.db.Model(&models.ProductPriceHistory{}).Where("price_list_id = ?", priceListID)
+	if req.ProductID != uuid.Nil {
+		query = query.Where("source_product_id = ?", req.ProductID)
+	}
+	query = whereCreatedBetween(query, "changed_at", from, to)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (req.Page - 1) * req.Limit
+	if err := query.Order("changed_at DESC").
+		Preload("Product").
+		Limit(req.Limit).Offset(offset).
+		Find(&entries).Error; err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
@@ -3,6 +3,7 @@ package repositorys
 import (
 	"auth-service/internal/dtos"
 	"auth-service/internal/models"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
@@ -10,10 +11,12 @@ import (
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ProductRepository interface {
 	CreateProduct(product *models.Product) error
+	GetProductBySKU(sku string) (*models.Product, error)
 	GetProductByID(id uuid.UUID) (*models.Product, error)
 	UpdateProduct(product *models.Product) error
 	DeleteProduct(id uuid.UUID) error
@@ -24,6 +27,7 @@ type ProductRepository interface {
 	DeleteProductCategory(id uuid.UUID) error
 
 	CreateProductStock(stock *models.ProductStock) error
+	CreateProductStockIfMissing(stock *models.ProductStock) (bool, error)
 	GetProductStockByID(id uuid.UUID) (*models.ProductStock, error)
 	UpdateProductStock(stock *models.ProductStock) error
 	DeleteProductStock(id uuid.UUID) error
@@ -32,13 +36,70 @@ type ProductRepository interface {
 	GetWarehouseLocationByID(id uuid.UUID) (*models.WarehouseLocation, error)
 	UpdateWarehouseLocation(location *models.WarehouseLocation) error
 	DeleteWarehouseLocation(id uuid.UUID) error
+	GetWarehouseLocationByPath(path string) (*models.WarehouseLocation, error)
+	GetWarehouseLocationSubtree(root *models.WarehouseLocation) ([]models.WarehouseLocation, error)
+	CountChildLocations(id uuid.UUID) (int64, error)
+	UpdateLocationSubtreePath(oldPath, newPath string) error
+	GetLocationStockRollup(root *models.WarehouseLocation) ([]LocationStockRollupRow, error)
 
 	CreateStockMovement(movement *models.StockMovement) error
+	GetProductStockForUpdate(productID, locationID uuid.UUID) (*models.ProductStock, error)
+	GetProductStockByIDForUpdate(id uuid.UUID) (*models.ProductStock, error)
+	GetAllProductStocks() ([]models.ProductStock, error)
+	GetStockLedgerBalances() ([]StockLedgerBalance, error)
+	GetProductStocksAsOf(req dtos.StockAsOfRequest, at time.Time) ([]StockAsOfRow, int64, error)
+	GetStockMovementHistory(req dtos.StockMovementHistoryRequest, from, to *time.Time) ([]StockMovementHistoryRow, int64, error)
+	GetStockCardEntries(productID uuid.UUID, root *models.WarehouseLocation, from, to *time.Time, page, limit int) ([]StockMovementHistoryRow, int64, error)
+	GetStockCardTotals(productID uuid.UUID, root *models.WarehouseLocation, from, to *time.Time) (*StockCardTotals, error)
+	GetProductOnHandQuantity(productID uuid.UUID) (int64, error)
+	GetProductVariants(parentID uuid.UUID) ([]models.Product, error)
+	GetProductUnits(productID uuid.UUID) ([]models.ProductUnit, error)
+	ReplaceProductUnits(productID uuid.UUID, units []models.ProductUnit) error
+	GetProductUnitFactors(productIDs []uuid.UUID, unit string) (map[uuid.UUID]int, error)
+	GetProductBarcodes(productID uuid.UUID) ([]models.ProductBarcode, error)
+	GetProductStockRollups(productIDs []uuid.UUID, attributeFilter []string) (map[uuid.UUID]ProductStockRollup, error)
+	GetProductComponents(kitID uuid.UUID) ([]models.ProductComponent, error)
+	GetComponentAvailability(productIDs []uuid.UUID) ([]ComponentAvailabilityRow, error)
+	GetEffectiveStockThreshold(productID, locationID, categoryID uuid.UUID) (*models.StockThreshold, error)
+	GetDispatchedStockMovements(transferID uuid.UUID) ([]models.StockMovement, error)
+
+	CreateStockLot(lot *models.StockLot) error
+	UpdateStockLot(lot *models.StockLot) error
+	GetStockLotForUpdate(stockID uuid.UUID, lotNumber string) (*models.StockLot, error)
+	GetAvailableStockLotsForUpdate(stockID uuid.UUID) ([]models.StockLot, error)
+	GetStockLotsByStockID(stockID uuid.UUID) ([]models.StockLot, error)
+	GetAllStockLots() ([]models.StockLot, error)
+	GetLotLedgerBalances() (map[uuid.UUID]int, error)
+
+	CreateCostLayer(layer *models.CostLayer) error
+	UpdateCostLayer(layer *models.CostLayer) error
+	GetOpenCostLayersForUpdate(stockID uuid.UUID) ([]models.CostLayer, error)
+	GetProductUnitCost(productID uuid.UUID) (float64, error)
+
+	GetPriceListByID(id uuid.UUID) (*models.PriceList, error)
+	GetDefaultPriceList() (*models.PriceList, error)
+	GetCurrentProductPrices(priceListID uuid.UUID, productIDs []uuid.UUID, at time.Time) (map[uuid.UUID]models.ProductPrice, error)
+
+	CreateSerialNumber(serial *models.SerialNumber) error
+	UpdateSerialNumber(serial *models.SerialNumber) error
+	GetSerialNumberForUpdate(productID uuid.UUID, serialNumber string) (*models.SerialNumber, error)
+	GetSerialNumber(productID uuid.UUID, serialNumber string) (*models.SerialNumber, error)
+	GetSerialNumbersList(productID uuid.UUID, req dtos.SerialNumberListRequest) ([]models.SerialNumber, int64, error)
+	GetSerialNumbersByMovementID(movementID uuid.UUID) ([]string, error)
+	GetSerialNumberHistory(serialID uuid.UUID) ([]models.StockMovement, error)
+	CreateStockMovementSerial(link *models.StockMovementSerial) error
+
+	CreateStockMovementApproval(approval *models.StockMovementApproval) error
+	UpdateStockMovementApproval(approval *models.StockMovementApproval) error
+	GetStockMovementApprovalByID(id uuid.UUID) (*models.StockMovementApproval, error)
+	GetStockMovementApprovalForUpdate(id uuid.UUID) (*models.StockMovementApproval, error)
+	GetStockMovementApprovalsList(req dtos.StockMovementApprovalListRequest) ([]models.StockMovementApproval, int64, error)
+	WithTransaction(fn func(repo ProductRepository) error) error
 
 	GetProductsList(req dtos.PaginationRequest) ([]models.Product, int64, error)
 	GetWarehouseLocationsList(req dtos.PaginationRequest) ([]models.WarehouseLocation, int64, error)
 	GetProductStocksList(req dtos.PaginationRequest) ([]models.ProductStock, int64, error)
-	GetDashboardSummary() (*dtos.DashboardResponse, error)
+	GetDashboardSummary(expiringWithinDays int) (*dtos.DashboardResponse, error)
 	GetProductCategoriesList(req dtos.PaginationRequest) ([]models.ProductCategory, int64, error)
 }
 
@@ -70,6 +131,85 @@ func (r *productRepository) DeleteWarehouseLocation(id uuid.UUID) error {
 	return r.db.Where("id = ?", id).Delete(&models.WarehouseLocation{}).Error
 }
 
+func (r *productRepository) GetWarehouseLocationByPath(path string) (*models.WarehouseLocation, error) {
+	var location models.WarehouseLocation
+	if err := r.db.Where("path = ? AND deleted_at IS NULL", path).First(&location).Error; err != nil {
+		return nil, err
+	}
+	return &location, nil
+}
+
+// subtreeLocationIDs mengembalikan subquery id lokasi root beserta seluruh turunannya (prefix path)
+func (r *productRepository) subtreeLocationIDs(root *models.WarehouseLocation) *gorm.DB {
+	query := r.db.Model(&models.WarehouseLocation{}).Select("id").Where("deleted_at IS NULL")
+	if root.Path == "" {
+		// Lokasi lama tanpa path tidak punya turunan
+		return query.Where("id = ?", root.ID)
+	}
+	return query.Where("id = ? OR path LIKE ?", root.ID, root.Path+"-%")
+}
+
+// GetWarehouseLocationSubtree mengambil root beserta turunannya (seluruh lokasi jika root nil), urut path
+func (r *productRepository) GetWarehouseLocationSubtree(root *models.WarehouseLocation) ([]models.WarehouseLocation, error) {
+	var locations []models.WarehouseLocation
+	query := r.db.Where("deleted_at IS NULL")
+	if root != nil {
+		query = query.Where("id IN (?)", r.subtreeLocationIDs(root))
+	}
+	if err := query.Order("path ASC, name ASC").Find(&locations).Error; err != nil {
+		return nil, err
+	}
+	return locations, nil
+}
+
+func (r *productRepository) CountChildLocations(id uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.WarehouseLocation{}).Where("parent_id = ? AND deleted_at IS NULL", id).Count(&count).Error
+	return count, err
+}
+
+// UpdateLocationSubtreePath mengganti prefix path seluruh turunan setelah code sebuah lokasi berubah
+func (r *productRepository) UpdateLocationSubtreePath(oldPath, newPath string) error {
+	return r.db.Model(&models.WarehouseLocation{}).
+		Where("path LIKE ? AND deleted_at IS NULL", oldPath+"-%").
+		Update("path", gorm.Expr("? || substr(path, ?)", newPath, len(oldPath)+1)).Error
+}
+
+// LocationStockRollupRow adalah total stok satu produk pada sebuah subtree lokasi
+type LocationStockRollupRow struct {
+	ProductID   uuid.UUID
+	ProductName string
+	SKU         string
+	OnHand      int
+	Reserved    int
+	Quarantined int
+	Available   int
+}
+
+// rollupQuarantined dan rollupAvailable memisahkan stok karantina: tetap dihitung di on_hand,
+// tetapi tidak pernah tersedia
+const (
+	rollupQuarantined = "COALESCE(SUM(CASE WHEN ps.status = 'quarantined' THEN ps.quantity ELSE 0 END), 0)"
+	rollupAvailable   = "COALESCE(SUM(CASE WHEN ps.status = 'quarantined' THEN 0 ELSE ps.quantity - ps.reserved_quantity END), 0)"
+)
+
+// GetLocationStockRollup menjumlahkan ProductStock per produk di seluruh subtree root
+func (r *productRepository) GetLocationStockRollup(root *models.WarehouseLocation) ([]LocationStockRollupRow, error) {
+	var rows []LocationStockRollupRow
+	err := r.db.Table("product_stocks ps").
+		Select("ps.source_product_id as product_id, p.name as product_name, p.sku, SUM(ps.quantity) as on_hand, SUM(ps.reserved_quantity) as reserved, "+
+			rollupQuarantined+" as quarantined, "+rollupAvailable+" as available").
+		Joins("JOIN products p ON p.id = ps.source_product_id").
+		Where("ps.deleted_at IS NULL AND ps.warehouse_location_id IN (?)", r.subtreeLocationIDs(root)).
+		Group("ps.source_product_id, p.name, p.sku").
+		Order("p.name ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
 // Todo: Warhouse Implemetation
 
 func (r *productRepository) GetProductCategoriesList(req dtos.PaginationRequest) ([]models.ProductCategory, int64, error) {
@@ -106,6 +246,797 @@ func (r *productRepository) CreateStockMovement(movement *models.StockMovement)
 	return r.db.Create(movement).Error
 }
 
+// GetProductStockForUpdate mengambil stok produk di lokasi tertentu dengan row lock (SELECT ... FOR UPDATE)
+func (r *productRepository) GetProductStockForUpdate(productID, locationID uuid.UUID) (*models.ProductStock, error) {
+	var stock models.ProductStock
+	if err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("source_product_id = ? AND warehouse_location_id = ? AND deleted_at IS NULL", productID, locationID).
+		First(&stock).Error; err != nil {
+		return nil, err
+	}
+	return &stock, nil
+}
+
+// GetProductStockByIDForUpdate sama seperti GetProductStockByID namun dengan row lock
+func (r *productRepository) GetProductStockByIDForUpdate(id uuid.UUID) (*models.ProductStock, error) {
+	var stock models.ProductStock
+	if err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND deleted_at IS NULL", id).
+		First(&stock).Error; err != nil {
+		return nil, err
+	}
+	return &stock, nil
+}
+
+// GetAllProductStocks mengambil seluruh baris ProductStock yang aktif
+func (r *productRepository) GetAllProductStocks() ([]models.ProductStock, error) {
+	var stocks []models.ProductStock
+	if err := r.db.Where("deleted_at IS NULL").Find(&stocks).Error; err != nil {
+		return nil, err
+	}
+	return stocks, nil
+}
+
+// StockLedgerBalance adalah saldo per produk dan lokasi hasil penjumlahan ledger
+type StockLedgerBalance struct {
+	ProductID           uuid.UUID
+	WarehouseLocationID uuid.UUID
+	Quantity            int
+	Value               float64
+}
+
+// GetStockLedgerBalances menghitung SUM(delta) dan nilai bersih (total_cost bertanda) dari stock_movements per produk dan lokasi
+func (r *productRepository) GetStockLedgerBalances() ([]StockLedgerBalance, error) {
+	var balances []StockLedgerBalance
+	err := r.db.Model(&models.StockMovement{}).
+		Select("source_product_id as product_id, warehouse_location_id, SUM(delta) as quantity, SUM(SIGN(delta) * total_cost) as value").
+		Where("deleted_at IS NULL AND warehouse_location_id IS NOT NULL").
+		Group("source_product_id, warehouse_location_id").
+		Scan(&balances).Error
+	if err != nil {
+		return nil, err
+	}
+	return balances, nil
+}
+
+// StockAsOfRow adalah saldo produk per lokasi pada suatu waktu, direkonstruksi dari ledger
+type StockAsOfRow struct {
+	StockID             uuid.UUID
+	ProductID           uuid.UUID
+	ProductName         string
+	CategoryID          uuid.UUID
+	WarehouseLocationID uuid.UUID
+	WarehouseName       string
+	Quantity            int
+	StockValue          float64
+	LastMovementAt      time.Time
+	Quarantine          bool // lokasi karantina
+	MinQuantity         *int // threshold efektif saat ini; nil berarti threshold bawaan
+	ReorderPoint        *int
+}
+
+// GetProductStocksAsOf menjumlahkan delta stock_movements sampai waktu at, per produk dan lokasi,
+// beserta threshold efektif yang berlaku saat ini
+func (r *productRepository) GetProductStocksAsOf(req dtos.StockAsOfRequest, at time.Time) ([]StockAsOfRow, int64, error) {
+	var rows []StockAsOfRow
+	var total int64
+
+	query := r.db.Table("stock_movements sm").
+		Select("ps.id as stock_id, sm.source_product_id as product_id, p.name as product_name, p.category_id, sm.warehouse_location_id, wl.name as warehouse_name, wl.quarantine, SUM(sm.delta) as quantity, SUM(SIGN(sm.delta) * sm.total_cost) as stock_value, MAX(sm.created_at) as last_movement_at").
+		Joins("JOIN products p ON p.id = sm.source_product_id").
+		Joins("JOIN warehouse_locations wl ON wl.id = sm.warehouse_location_id").
+		Joins("LEFT JOIN product_stocks ps ON ps.source_product_id = sm.source_product_id AND ps.warehouse_location_id = sm.warehouse_location_id AND ps.deleted_at IS NULL").
+		Where("sm.deleted_at IS NULL AND sm.warehouse_location_id IS NOT NULL AND sm.created_at <= ?", at).
+		Group("ps.id, sm.source_product_id, p.name, p.category_id, sm.warehouse_location_id, wl.name, wl.quarantine")
+	if req.ProductID != uuid.Nil {
+		query = query.Where("sm.source_product_id = ?", req.ProductID)
+	}
+	if req.WarehouseLocationID != uuid.Nil {
+		query = query.Where("sm.warehouse_location_id = ?", req.WarehouseLocationID)
+	}
+	if req.Search != "" {
+		query = query.Where("p.name ILIKE ?", "%"+req.Search+"%")
+	}
+
+	// Hitung total grup
+	if err := r.db.Table("(?) as snapshot", query).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Threshold dicari sekali per baris snapshot, bukan per entri ledger
+	offset := (req.Page - 1) * req.Limit
+	err := r.db.Table("(?) as snapshot", query).
+		Select("snapshot.*, st.min_quantity, st.reorder_point").
+		Joins(thresholdJoin("snapshot.product_id", "snapshot.warehouse_location_id", "snapshot.category_id")).
+		Order("snapshot.product_name ASC, snapshot.warehouse_name ASC").
+		Limit(req.Limit).Offset(offset).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return rows, total, nil
+}
+
+// StockMovementHistoryRow adalah entri ledger beserta saldo berjalannya
+type StockMovementHistoryRow struct {
+	ID                    uuid.UUID
+	ProductID             uuid.UUID
+	ProductName           string
+	SKU                   string
+	WarehouseLocationID   uuid.UUID
+	WarehouseName         string
+	WarehouseLocationPath string
+	MovementType          string
+	Quantity              int
+	Delta                 int
+	BalanceAfter          int
+	RunningBalance        int
+	UnitCost              float64
+	TotalCost             float64
+	LotID                 *uuid.UUID
+	LotNumber             string
+	Reason                string
+	ReferenceType         string
+	ReferenceID           *uuid.UUID
+	ReferenceNote         string
+	CreatedBy             uuid.UUID
+	CreatedAt             time.Time
+}
+
+// StockCardTotals adalah saldo awal serta total masuk/keluar kartu stok pada rentang waktu
+type StockCardTotals struct {
+	OpeningBalance int
+	TotalIn        int
+	TotalOut       int
+}
+
+const stockMovementHistoryColumns = "m.id, m.source_product_id as product_id, p.name as product_name, p.sku, m.warehouse_location_id, wl.name as warehouse_name, wl.path as warehouse_location_path, m.movement_type, m.quantity, m.delta, m.balance_after, m.running_balance, m.unit_cost, m.total_cost, m.lot_id, l.lot_number, m.reason, m.reference_type, m.reference_id, m.reference_note, m.created_by, m.created_at"
+
+// ledgerWithRunningBalance membungkus ledger dengan saldo berjalan per produk.
+// Saldo dihitung atas seluruh histori sebelum filter tampilan, jadi filter di dalam scope harus
+// berupa filter lingkup (produk/lokasi), bukan filter tampilan seperti tanggal atau tipe.
+func (r *productRepository) ledgerWithRunningBalance(scope *gorm.DB) *gorm.DB {
+	ledger := scope.Table("stock_movements sm").
+		Select("sm.*, SUM(sm.delta) OVER (PARTITION BY sm.source_product_id ORDER BY sm.created_at, sm.id) as running_balance").
+		Where("sm.deleted_at IS NULL")
+	return r.db.Table("(?) as m", ledger).
+		Joins("JOIN products p ON p.id = m.source_product_id").
+		Joins("JOIN warehouse_locations wl ON wl.id = m.warehouse_location_id").
+		Joins("LEFT JOIN stock_lots l ON l.id = m.lot_id")
+}
+
+func whereCreatedBetween(query *gorm.DB, column string, from, to *time.Time) *gorm.DB {
+	if from != nil {
+		query = query.Where(column+" >= ?", *from)
+	}
+	if to != nil {
+		query = query.Where(column+" <= ?", *to)
+	}
+	return query
+}
+
+// GetStockMovementHistory mengambil riwayat ledger terfilter; running_balance adalah saldo produk di seluruh lokasi
+func (r *productRepository) GetStockMovementHistory(req dtos.StockMovementHistoryRequest, from, to *time.Time) ([]StockMovementHistoryRow, int64, error) {
+	var rows []StockMovementHistoryRow
+	var total int64
+
+	scope := r.db.Session(&gorm.Session{NewDB: true})
+	if req.ProductID != uuid.Nil {
+		// Aman dipersempit di dalam karena saldo dipartisi per produk
+		scope = scope.Where("sm.source_product_id = ?", req.ProductID)
+	}
+	query := r.ledgerWithRunningBalance(scope)
+
+	if req.WarehouseLocationID != uuid.Nil {
+		root, err := r.GetWarehouseLocationByID(req.WarehouseLocationID)
+		if err != nil {
+			return nil, 0, err
+		}
+		query = query.Where("m.warehouse_location_id IN (?)", r.subtreeLocationIDs(root))
+	}
+	if req.MovementType != "" {
+		query = query.Where("m.movement_type = ?", req.MovementType)
+	}
+	if req.UserID != uuid.Nil {
+		query = query.Where("m.created_by = ?", req.UserID)
+	}
+	if req.ReferenceType != "" {
+		query = query.Where("m.reference_type = ?", req.ReferenceType)
+	}
+	if req.ReferenceID != uuid.Nil {
+		query = query.Where("m.reference_id = ?", req.ReferenceID)
+	}
+	if req.Reference != "" {
+		query = query.Where("m.reference_note ILIKE ?", "%"+req.Reference+"%")
+	}
+	if req.Search != "" {
+		query = query.Where("(p.name ILIKE ? OR p.sku ILIKE ?)", "%"+req.Search+"%", "%"+req.Search+"%")
+	}
+	query = whereCreatedBetween(query, "m.created_at", from, to)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	order := "DESC"
+	if req.Order == "asc" {
+		order = "ASC"
+	}
+	offset := (req.Page - 1) * req.Limit
+	if err := query.Select(stockMovementHistoryColumns).
+		Order("m.created_at " + order + ", m.id " + order).
+		Limit(req.Limit).Offset(offset).
+		Scan(&rows).Error; err != nil {
+		return nil, 0, err
+	}
+	return rows, total, nil
+}
+
+// stockCardScope membatasi ledger ke satu produk dan (opsional) subtree lokasi
+func (r *productRepository) stockCardScope(productID uuid.UUID, root *models.WarehouseLocation) *gorm.DB {
+	scope := r.db.Session(&gorm.Session{NewDB: true}).Where("sm.source_product_id = ?", productID)
+	if root != nil {
+		scope = scope.Where("sm.warehouse_location_id IN (?)", r.subtreeLocationIDs(root))
+	}
+	return scope
+}
+
+// GetStockCardEntries mengambil entri kartu stok urut kronologis; running_balance adalah saldo di lingkup kartu
+func (r *productRepository) GetStockCardEntries(productID uuid.UUID, root *models.WarehouseLocation, from, to *time.Time, page, limit int) ([]StockMovementHistoryRow, int64, error) {
+	var rows []StockMovementHistoryRow
+	var total int64
+
+	query := whereCreatedBetween(r.ledgerWithRunningBalance(r.stockCardScope(productID, root)), "m.created_at", from, to)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Select(stockMovementHistoryColumns).
+		Order("m.created_at ASC, m.id ASC").
+		Limit(limit).Offset(offset).
+		Scan(&rows).Error; err != nil {
+		return nil, 0, err
+	}
+	return rows, total, nil
+}
+
+// GetStockCardTotals menghitung saldo awal (sebelum from) serta total masuk dan keluar sampai to
+func (r *productRepository) GetStockCardTotals(productID uuid.UUID, root *models.WarehouseLocation, from, to *time.Time) (*StockCardTotals, error) {
+	var totals StockCardTotals
+
+	query := r.stockCardScope(productID, root).Table("stock_movements sm").Where("sm.deleted_at IS NULL")
+	if to != nil {
+		query = query.Where("sm.created_at <= ?", *to)
+	}
+	// Tanpa from seluruh histori masuk rentang, sehingga saldo awal nol
+	var start time.Time
+	if from != nil {
+		start = *from
+	}
+	err := query.Select(`COALESCE(SUM(sm.delta) FILTER (WHERE sm.created_at < ?), 0) as opening_balance,
+		COALESCE(SUM(sm.delta) FILTER (WHERE sm.created_at >= ? AND sm.delta > 0), 0) as total_in,
+		COALESCE(-SUM(sm.delta) FILTER (WHERE sm.created_at >= ? AND sm.delta < 0), 0) as total_out`, start, start, start).
+		Scan(&totals).Error
+	if err != nil {
+		return nil, err
+	}
+	return &totals, nil
+}
+
+// GetProductOnHandQuantity menjumlahkan quantity produk di seluruh lokasi
+func (r *productRepository) GetProductOnHandQuantity(productID uuid.UUID) (int64, error) {
+	var total int64
+	err := r.db.Model(&models.ProductStock{}).
+		Select("COALESCE(SUM(quantity), 0)").
+		Where("source_product_id = ? AND deleted_at IS NULL", productID).
+		Scan(&total).Error
+	return total, err
+}
+
+// GetProductVariants mengambil seluruh varian sebuah parent, urut SKU
+func (r *productRepository) GetProductVariants(parentID uuid.UUID) ([]models.Product, error) {
+	var variants []models.Product
+	if err := r.db.Where("parent_id = ? AND deleted_at IS NULL", parentID).
+		Order("sku ASC").
+		Find(&variants).Error; err != nil {
+		return nil, err
+	}
+	return variants, nil
+}
+
+// GetProductUnits mengambil satuan alternatif produk, urut dari faktor terkecil
+func (r *productRepository) GetProductUnits(productID uuid.UUID) ([]models.ProductUnit, error) {
+	var units []models.ProductUnit
+	if err := r.db.Where("product_id = ?", productID).
+		Order("factor ASC").
+		Find(&units).Error; err != nil {
+		return nil, err
+	}
+	return units, nil
+}
+
+// ReplaceProductUnits mengganti seluruh satuan alternatif produk
+func (r *productRepository) ReplaceProductUnits(productID uuid.UUID, units []models.ProductUnit) error {
+	if err := r.db.Where("product_id = ?", productID).Delete(&models.ProductUnit{}).Error; err != nil {
+		return err
+	}
+	if len(units) == 0 {
+		return nil
+	}
+	return r.db.Create(&units).Error
+}
+
+// GetProductUnitFactors mengembalikan faktor konversi unit ke satuan dasar per produk.
+// Satuan dasar produk berfaktor 1; produk yang tidak mengenal unit tersebut tidak ada di hasil.
+func (r *productRepository) GetProductUnitFactors(productIDs []uuid.UUID, unit string) (map[uuid.UUID]int, error) {
+	var rows []struct {
+		ID     uuid.UUID
+		Factor int
+	}
+	if err := r.db.Table("products p").
+		Select("p.id, CASE WHEN p.base_unit = ? THEN 1 ELSE pu.factor END AS factor", unit).
+		Joins("LEFT JOIN product_units pu ON pu.product_id = p.id AND pu.unit = ?", unit).
+		Where("p.id IN ? AND (p.base_unit = ? OR pu.id IS NOT NULL)", productIDs, unit).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	factors := make(map[uuid.UUID]int, len(rows))
+	for _, row := range rows {
+		factors[row.ID] = row.Factor
+	}
+	return factors, nil
+}
+
+// GetProductBarcodes mengambil barcode produk, barcode utama lebih dulu
+func (r *productRepository) GetProductBarcodes(productID uuid.UUID) ([]models.ProductBarcode, error) {
+	var barcodes []models.ProductBarcode
+	if err := r.db.Where("source_product_id = ?", productID).
+		Order("is_primary DESC, created_at ASC").
+		Find(&barcodes).Error; err != nil {
+		return nil, err
+	}
+	return barcodes, nil
+}
+
+// ProductStockRollup adalah total stok sebuah produk; untuk parent berisi gabungan stok variannya
+type ProductStockRollup struct {
+	VariantCount int
+	OnHand       int
+	Reserved     int
+	Quarantined  int
+	Available    int
+}
+
+// GetProductStockRollups menghitung stok produk yang diminta. Stok varian ikut dijumlahkan ke parent-nya;
+// dengan attributeFilter hanya varian yang cocok yang dihitung.
+func (r *productRepository) GetProductStockRollups(productIDs []uuid.UUID, attributeFilter []string) (map[uuid.UUID]ProductStockRollup, error) {
+	var rows []struct {
+		ID          uuid.UUID
+		ParentID    *uuid.UUID
+		OnHand      int
+		Reserved    int
+		Quarantined int
+		Available   int
+	}
+	query := r.db.Table("products p").
+		Select("p.id, p.parent_id, COALESCE(SUM(ps.quantity), 0) AS on_hand, COALESCE(SUM(ps.reserved_quantity), 0) AS reserved, "+
+			rollupQuarantined+" AS quarantined, "+rollupAvailable+" AS available").
+		Joins("LEFT JOIN product_stocks ps ON ps.source_product_id = p.id AND ps.deleted_at IS NULL").
+		Where("p.deleted_at IS NULL AND (p.id IN ? OR p.parent_id IN ?)", productIDs, productIDs)
+	if filter, ok := attributeFilterJSON(attributeFilter); ok {
+		query = query.Where("(p.id IN ? OR p.attributes @> ?::jsonb)", productIDs, filter)
+	}
+	if err := query.Group("p.id, p.parent_id").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	requested := make(map[uuid.UUID]bool, len(productIDs))
+	for _, id := range productIDs {
+		requested[id] = true
+	}
+	rollups := make(map[uuid.UUID]ProductStockRollup, len(productIDs))
+	for _, row := range rows {
+		target := row.ID
+		if !requested[row.ID] {
+			target = *row.ParentID
+		}
+		rollup := rollups[target]
+		rollup.OnHand += row.OnHand
+		rollup.Reserved += row.Reserved
+		rollup.Quarantined += row.Quarantined
+		rollup.Available += row.Available
+		if target != row.ID {
+			rollup.VariantCount++
+		}
+		rollups[target] = rollup
+	}
+	return rollups, nil
+}
+
+// attributeFilterJSON mengubah filter "key:value" menjadi objek JSON untuk operator @>
+func attributeFilterJSON(values []string) (string, bool) {
+	if len(values) == 0 {
+		return "", false
+	}
+	filter := make(map[string]string, len(values))
+	for _, v := range values {
+		key, value, _ := strings.Cut(v, ":")
+		filter[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	b, err := json.Marshal(filter)
+	if err != nil {
+		return "", false
+	}
+	return string(b), true
+}
+
+// GetProductComponents mengambil bill of materials sebuah kit beserta produk komponennya
+func (r *productRepository) GetProductComponents(kitID uuid.UUID) ([]models.ProductComponent, error) {
+	var components []models.ProductComponent
+	if err := r.db.Where("kit_product_id = ?", kitID).
+		Preload("Component").
+		Order("created_at ASC").
+		Find(&components).Error; err != nil {
+		return nil, err
+	}
+	return components, nil
+}
+
+// ComponentAvailabilityRow adalah stok tersedia satu produk di satu lokasi
+type ComponentAvailabilityRow struct {
+	ProductID             uuid.UUID
+	WarehouseLocationID   uuid.UUID
+	WarehouseLocationName string
+	WarehouseLocationPath string
+	Available             int
+}
+
+// GetComponentAvailability menghitung stok tersedia (on hand - reserved, bukan karantina) per produk dan lokasi
+func (r *productRepository) GetComponentAvailability(productIDs []uuid.UUID) ([]ComponentAvailabilityRow, error) {
+	var rows []ComponentAvailabilityRow
+	if err := r.db.Table("product_stocks ps").
+		Select("ps.source_product_id AS product_id, ps.warehouse_location_id, wl.name AS warehouse_location_name, wl.path AS warehouse_location_path, ps.quantity - ps.reserved_quantity AS available").
+		Joins("JOIN warehouse_locations wl ON wl.id = ps.warehouse_location_id").
+		Where("ps.source_product_id IN ? AND ps.deleted_at IS NULL", productIDs).
+		Where("ps.quantity > ps.reserved_quantity AND ps.status <> 'quarantined'").
+		Order("wl.path ASC").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (r *productRepository) GetEffectiveStockThreshold(productID, locationID, categoryID uuid.UUID) (*models.StockThreshold, error) {
+	return findEffectiveStockThreshold(r.db, productID, locationID, categoryID)
+}
+
+// GetDispatchedStockMovements mengambil entri keluar (delta negatif) milik satu transfer beserta lot-nya.
+// Memakai delta agar entri lama bertipe 'outbound' tetap terbaca.
+func (r *productRepository) GetDispatchedStockMovements(transferID uuid.UUID) ([]models.StockMovement, error) {
+	var movements []models.StockMovement
+	err := r.db.Preload("Lot").
+		Where("transfer_id = ? AND delta < 0 AND deleted_at IS NULL", transferID).
+		Order("created_at ASC").
+		Find(&movements).Error
+	if err != nil {
+		return nil, err
+	}
+	return movements, nil
+}
+
+func (r *productRepository) CreateCostLayer(layer *models.CostLayer) error {
+	return r.db.Create(layer).Error
+}
+
+func (r *productRepository) UpdateCostLayer(layer *models.CostLayer) error {
+	return r.db.Model(layer).Update("remaining_quantity", layer.RemainingQuantity).Error
+}
+
+// GetOpenCostLayersForUpdate mengambil cost layer fifo yang masih bersisa, tertua lebih dulu, dengan row lock
+func (r *productRepository) GetOpenCostLayersForUpdate(stockID uuid.UUID) ([]models.CostLayer, error) {
+	var layers []models.CostLayer
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("product_stock_id = ? AND remaining_quantity > 0", stockID).
+		Order("created_at ASC").
+		Find(&layers).Error
+	if err != nil {
+		return nil, err
+	}
+	return layers, nil
+}
+
+// GetProductUnitCost mengembalikan biaya rata-rata produk di seluruh lokasi yang bersaldo,
+// atau biaya masuk terakhir bila produk sedang tidak punya stok
+func (r *productRepository) GetProductUnitCost(productID uuid.UUID) (float64, error) {
+	var average *float64
+	err := r.db.Model(&models.ProductStock{}).
+		Select("SUM(stock_value) / NULLIF(SUM(quantity), 0)").
+		Where("source_product_id = ? AND quantity > 0 AND deleted_at IS NULL", productID).
+		Scan(&average).Error
+	if err != nil {
+		return 0, err
+	}
+	if average != nil {
+		return *average, nil
+	}
+
+	var last []float64
+	err = r.db.Model(&models.StockMovement{}).
+		Where("source_product_id = ? AND delta > 0 AND unit_cost > 0 AND deleted_at IS NULL", productID).
+		Order("created_at DESC").
+		Limit(1).
+		Pluck("unit_cost", &last).Error
+	if err != nil || len(last) == 0 {
+		return 0, err
+	}
+	return last[0], nil
+}
+
+func (r *productRepository) GetPriceListByID(id uuid.UUID) (*models.PriceList, error) {
+	var list models.PriceList
+	if err := r.db.Where("id = ? AND deleted_at IS NULL", id).First(&list).Error; err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+func (r *productRepository) GetDefaultPriceList() (*models.PriceList, error) {
+	var list models.PriceList
+	if err := r.db.Where("is_default AND deleted_at IS NULL").First(&list).Error; err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// GetCurrentProductPrices mengembalikan harga yang berlaku pada waktu at per produk dalam satu daftar harga.
+// Produk tanpa harga pada waktu tersebut tidak ada di hasil.
+func (r *productRepository) GetCurrentProductPrices(priceListID uuid.UUID, productIDs []uuid.UUID, at time.Time) (map[uuid.UUID]models.ProductPrice, error) {
+	var rows []models.ProductPrice
+	if err := r.db.Raw(`SELECT DISTINCT ON (source_product_id) *
+		FROM product_prices
+		WHERE price_list_id = ? AND source_product_id IN ?
+			AND effective_from <= ? AND (effective_to IS NULL OR effective_to > ?)
+		ORDER BY source_product_id, effective_from DESC`, priceListID, productIDs, at, at).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	prices := make(map[uuid.UUID]models.ProductPrice, len(rows))
+	for _, row := range rows {
+		prices[row.SourceProductID] = row
+	}
+	return prices, nil
+}
+
+func (r *productRepository) CreateStockLot(lot *models.StockLot) error {
+	return r.db.Omit("Product", "WarehouseLocation").Create(lot).Error
+}
+
+func (r *productRepository) UpdateStockLot(lot *models.StockLot) error {
+	return r.db.Omit(clause.Associations).Save(lot).Error
+}
+
+// GetStockLotForUpdate mengambil lot berdasarkan nomor lot pada satu ProductStock dengan row lock
+func (r *productRepository) GetStockLotForUpdate(stockID uuid.UUID, lotNumber string) (*models.StockLot, error) {
+	var lot models.StockLot
+	if err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("product_stock_id = ? AND lot_number = ?", stockID, lotNumber).
+		First(&lot).Error; err != nil {
+		return nil, err
+	}
+	return &lot, nil
+}
+
+// GetAvailableStockLotsForUpdate mengambil lot yang masih bersaldo dalam urutan FEFO dengan row lock
+func (r *productRepository) GetAvailableStockLotsForUpdate(stockID uuid.UUID) ([]models.StockLot, error) {
+	var lots []models.StockLot
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("product_stock_id = ? AND quantity > 0", stockID).
+		Order("expiry_date ASC NULLS LAST, created_at ASC").
+		Find(&lots).Error
+	if err != nil {
+		return nil, err
+	}
+	return lots, nil
+}
+
+// GetStockLotsByStockID mengambil seluruh lot pada satu ProductStock dalam urutan FEFO
+func (r *productRepository) GetStockLotsByStockID(stockID uuid.UUID) ([]models.StockLot, error) {
+	var lots []models.StockLot
+	err := r.db.Where("product_stock_id = ?", stockID).
+		Order("expiry_date ASC NULLS LAST, created_at ASC").
+		Find(&lots).Error
+	if err != nil {
+		return nil, err
+	}
+	return lots, nil
+}
+
+func (r *productRepository) GetAllStockLots() ([]models.StockLot, error) {
+	var lots []models.StockLot
+	if err := r.db.Find(&lots).Error; err != nil {
+		return nil, err
+	}
+	return lots, nil
+}
+
+// GetLotLedgerBalances menghitung SUM(delta) dari stock_movements per lot
+func (r *productRepository) GetLotLedgerBalances() (map[uuid.UUID]int, error) {
+	var rows []struct {
+		LotID    uuid.UUID
+		Quantity int
+	}
+	err := r.db.Model(&models.StockMovement{}).
+		Select("lot_id, SUM(delta) as quantity").
+		Where("deleted_at IS NULL AND lot_id IS NOT NULL").
+		Group("lot_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make(map[uuid.UUID]int, len(rows))
+	for _, row := range rows {
+		balances[row.LotID] = row.Quantity
+	}
+	return balances, nil
+}
+
+func (r *productRepository) CreateSerialNumber(serial *models.SerialNumber) error {
+	return r.db.Omit(clause.Associations).Create(serial).Error
+}
+
+func (r *productRepository) UpdateSerialNumber(serial *models.SerialNumber) error {
+	return r.db.Omit(clause.Associations).Save(serial).Error
+}
+
+// GetSerialNumberForUpdate mengambil serial milik produk dengan row lock
+func (r *productRepository) GetSerialNumberForUpdate(productID uuid.UUID, serialNumber string) (*models.SerialNumber, error) {
+	var serial models.SerialNumber
+	if err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("source_product_id = ? AND serial_number = ?", productID, serialNumber).
+		First(&serial).Error; err != nil {
+		return nil, err
+	}
+	return &serial, nil
+}
+
+func (r *productRepository) GetSerialNumber(productID uuid.UUID, serialNumber string) (*models.SerialNumber, error) {
+	var serial models.SerialNumber
+	if err := r.db.Preload("WarehouseLocation").
+		Where("source_product_id = ? AND serial_number = ?", productID, serialNumber).
+		First(&serial).Error; err != nil {
+		return nil, err
+	}
+	return &serial, nil
+}
+
+// GetSerialNumbersList mengambil serial suatu produk dengan filter status dan lokasi
+func (r *productRepository) GetSerialNumbersList(productID uuid.UUID, req dtos.SerialNumberListRequest) ([]models.SerialNumber, int64, error) {
+	var serials []models.SerialNumber
+	var total int64
+
+	query := r.db.Model(&models.SerialNumber{}).Where("source_product_id = ?", productID)
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+	if req.WarehouseLocationID != uuid.Nil {
+		query = query.Where("warehouse_location_id = ?", req.WarehouseLocationID)
+	}
+	if req.Search != "" {
+		query = query.Where("serial_number ILIKE ?", "%"+req.Search+"%")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (req.Page - 1) * req.Limit
+	if err := query.Preload("WarehouseLocation").Order("serial_number ASC").Limit(req.Limit).Offset(offset).Find(&serials).Error; err != nil {
+		return nil, 0, err
+	}
+	return serials, total, nil
+}
+
+// GetSerialNumbersByMovementID mengambil nomor serial yang tertaut ke satu entri ledger
+func (r *productRepository) GetSerialNumbersByMovementID(movementID uuid.UUID) ([]string, error) {
+	var serials []string
+	err := r.db.Table("stock_movement_serials sms").
+		Select("sn.serial_number").
+		Joins("JOIN serial_numbers sn ON sn.id = sms.serial_number_id").
+		Where("sms.stock_movement_id = ?", movementID).
+		Order("sn.serial_number ASC").
+		Scan(&serials).Error
+	if err != nil {
+		return nil, err
+	}
+	return serials, nil
+}
+
+// GetSerialNumberHistory mengambil seluruh entri ledger yang memindahkan satu serial, urut kronologis
+func (r *productRepository) GetSerialNumberHistory(serialID uuid.UUID) ([]models.StockMovement, error) {
+	var movements []models.StockMovement
+	err := r.db.Joins("JOIN stock_movement_serials sms ON sms.stock_movement_id = stock_movements.id").
+		Where("sms.serial_number_id = ? AND stock_movements.deleted_at IS NULL", serialID).
+		Order("stock_movements.created_at ASC").
+		Find(&movements).Error
+	if err != nil {
+		return nil, err
+	}
+	return movements, nil
+}
+
+func (r *productRepository) CreateStockMovementSerial(link *models.StockMovementSerial) error {
+	return r.db.Omit(clause.Associations).Create(link).Error
+}
+
+func (r *productRepository) CreateStockMovementApproval(approval *models.StockMovementApproval) error {
+	return r.db.Omit(clause.Associations).Create(approval).Error
+}
+
+func (r *productRepository) UpdateStockMovementApproval(approval *models.StockMovementApproval) error {
+	return r.db.Omit(clause.Associations).Save(approval).Error
+}
+
+func (r *productRepository) GetStockMovementApprovalByID(id uuid.UUID) (*models.StockMovementApproval, error) {
+	var approval models.StockMovementApproval
+	if err := r.db.Where("id = ?", id).
+		Preload("Product").
+		Preload("WarehouseLocation").
+		First(&approval).Error; err != nil {
+		return nil, err
+	}
+	return &approval, nil
+}
+
+// GetStockMovementApprovalForUpdate mengunci baris approval agar tidak diputuskan dua kali
+func (r *productRepository) GetStockMovementApprovalForUpdate(id uuid.UUID) (*models.StockMovementApproval, error) {
+	var approval models.StockMovementApproval
+	if err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		First(&approval).Error; err != nil {
+		return nil, err
+	}
+	return &approval, nil
+}
+
+func (r *productRepository) GetStockMovementApprovalsList(req dtos.StockMovementApprovalListRequest) ([]models.StockMovementApproval, int64, error) {
+	var approvals []models.StockMovementApproval
+	var total int64
+
+	query := r.db.Model(&models.StockMovementApproval{})
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+	if req.MovementType != "" {
+		query = query.Where("movement_type = ?", req.MovementType)
+	}
+	if req.ProductID != uuid.Nil {
+		query = query.Where("source_product_id = ?", req.ProductID)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (req.Page - 1) * req.Limit
+	if err := query.Preload("Product").
+		Preload("WarehouseLocation").
+		Order("created_at DESC").
+		Limit(req.Limit).Offset(offset).
+		Find(&approvals).Error; err != nil {
+		return nil, 0, err
+	}
+	return approvals, total, nil
+}
+
+// WithTransaction menjalankan fn di dalam satu transaksi database
+func (r *productRepository) WithTransaction(fn func(repo ProductRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&productRepository{db: tx})
+	})
+}
+
 func (r *productRepository) CreateProduct(product *models.Product) error {
 	return r.db.Create(product).Error
 }
@@ -118,6 +1049,14 @@ func (r *productRepository) GetProductByID(id uuid.UUID) (*models.Product, error
 	return &product, nil
 }
 
+func (r *productRepository) GetProductBySKU(sku string) (*models.Product, error) {
+	var product models.Product
+	if err := r.db.Where("sku = ? AND deleted_at IS NULL", sku).First(&product).Error; err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
 func (r *productRepository) UpdateProduct(product *models.Product) error {
 	return r.db.Save(product).Error
 }
@@ -158,6 +1097,20 @@ func (r *productRepository) CreateProductStock(stock *models.ProductStock) error
 	return r.db.Create(stock).Error
 }
 
+// CreateProductStockIfMissing membuat ProductStock kecuali produk sudah punya stok aktif di lokasi itu
+// (misalnya dibuat transaksi lain secara bersamaan); created false berarti tidak ada baris yang dibuat
+func (r *productRepository) CreateProductStockIfMissing(stock *models.ProductStock) (bool, error) {
+	result := r.db.Omit(clause.Associations).Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "source_product_id"}, {Name: "warehouse_location_id"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "deleted_at IS NULL"}}},
+		DoNothing:   true,
+	}).Create(stock)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
 func (r *productRepository) GetProductStockByID(id uuid.UUID) (*models.ProductStock, error) {
 	var stock models.ProductStock
 	if err := r.db.Where("id = ? AND deleted_at IS NULL", id).First(&stock).Error; err != nil {
@@ -184,7 +1137,23 @@ func (r *productRepository) GetProductsList(req dtos.PaginationRequest) ([]model
 		query = query.Where("category_id = ?", req.CategoryID)
 	}
 	if req.Search != "" {
-		query = query.Where("name ILIKE ?", "%"+req.Search+"%")
+		// Kode hasil scan dicocokkan persis dengan barcode produk
+		query = query.Where("(name ILIKE ? OR EXISTS (SELECT 1 FROM product_barcodes b WHERE b.source_product_id = products.id AND b.code = ?))", "%"+req.Search+"%", req.Search)
+	}
+	// Tanpa parent_id hanya produk level atas (parent dan produk biasa) yang ditampilkan
+	if req.ParentID != uuid.Nil {
+		query = query.Where("parent_id = ?", req.ParentID)
+	} else {
+		query = query.Where("parent_id IS NULL")
+	}
+	// Parent cocok bila minimal satu variannya memiliki seluruh atribut yang difilter
+	if filter, ok := attributeFilterJSON(req.Attribute); ok {
+		query = query.Where("(attributes @> ?::jsonb OR EXISTS (SELECT 1 FROM products v WHERE v.parent_id = products.id AND v.deleted_at IS NULL AND v.attributes @> ?::jsonb))", filter, filter)
+	}
+	// Atribut khusus dibandingkan sebagai teks sehingga angka dan boolean bisa difilter, misal voltage:220
+	for _, f := range req.CustomAttribute {
+		key, value, _ := strings.Cut(f, ":")
+		query = query.Where("custom_attributes ->> ? = ?", strings.TrimSpace(key), strings.TrimSpace(value))
 	}
 
 	// Hitung total
@@ -239,13 +1208,22 @@ func (r *productRepository) GetProductStocksList(req dtos.PaginationRequest) ([]
 	var stocks []models.ProductStock
 	var total int64
 
-	query := r.db.Model(&models.ProductStock{}).Where("deleted_at IS NULL")
+	query := r.db.Table("product_stocks ps").
+		Joins("JOIN products p ON p.id = ps.source_product_id").
+		Where("ps.deleted_at IS NULL")
 	if req.Status != "" {
-		query = query.Where("status = ?", req.Status)
+		// Status dihitung langsung dari threshold efektif, bukan dari kolom status yang tersimpan
+		query = query.Joins(effectiveThresholdJoin).Where(stockStatusExpr+" = ?", req.Status)
+	}
+	if req.WarehouseLocationID != uuid.Nil {
+		root, err := r.GetWarehouseLocationByID(req.WarehouseLocationID)
+		if err != nil {
+			return nil, 0, err
+		}
+		query = query.Where("ps.warehouse_location_id IN (?)", r.subtreeLocationIDs(root))
 	}
 	if req.Search != "" {
-		query = query.Joins("JOIN products ON products.id = product_stocks.source_product_id").
-			Where("products.name ILIKE ?", "%"+req.Search+"%")
+		query = query.Where("p.name ILIKE ?", "%"+req.Search+"%")
 	}
 
 	if err := query.Count(&total).Error; err != nil {
@@ -253,9 +1231,10 @@ func (r *productRepository) GetProductStocksList(req dtos.PaginationRequest) ([]
 	}
 
 	offset := (req.Page - 1) * req.Limit
-	query = query.Limit(req.Limit).Offset(offset)
+	query = query.Select("ps.*").Limit(req.Limit).Offset(offset)
 	if req.SortBy != "" {
-		query = query.Order(fmt.Sprintf("%s %s", req.SortBy, req.Order))
+		sortColumns := map[string]string{"name": "p.name", "sku": "p.sku", "created_at": "ps.created_at"}
+		query = query.Order(fmt.Sprintf("%s %s", sortColumns[req.SortBy], req.Order))
 	}
 
 	// Preload relasi
@@ -268,7 +1247,7 @@ func (r *productRepository) GetProductStocksList(req dtos.PaginationRequest) ([]
 }
 
 // GetDashboardSummary
-func (r *productRepository) GetDashboardSummary() (*dtos.DashboardResponse, error) {
+func (r *productRepository) GetDashboardSummary(expiringWithinDays int) (*dtos.DashboardResponse, error) {
 	summary := &dtos.DashboardResponse{}
 
 	// Total stock: SUM(quantity)
@@ -285,17 +1264,23 @@ func (r *productRepository) GetDashboardSummary() (*dtos.DashboardResponse, erro
 		WarehouseName  string
 		Quantity       int
 		Status         string
+		MinQuantity    int
+		ReorderPoint   int
+		MaxQuantity    int
 		UpdatedByEmail string
 		UpdatedByName  string
 		UpdatedAt      time.Time
 	}
 	r.db.Table("product_stocks ps").
-		Select("ps.id as product_id, p.name as product_name, ps.warehouse_location_id as warehouse_id, wl.name as warehouse_name, ps.quantity, ps.status, u.email as updated_by_email, up.full_name as updated_by_name, ps.updated_at").
+		Select("ps.id as product_id, p.name as product_name, ps.warehouse_location_id as warehouse_id, wl.name as warehouse_name, ps.quantity, " + stockStatusExpr + " as status, " +
+			fmt.Sprintf("COALESCE(st.min_quantity, %d) as min_quantity, COALESCE(st.reorder_point, %d) as reorder_point, COALESCE(st.max_quantity, %d) as max_quantity, ", models.DefaultMinQuantity, models.DefaultReorderPoint, models.DefaultMaxQuantity) +
+			"u.email as updated_by_email, up.full_name as updated_by_name, ps.updated_at").
 		Joins("JOIN products p ON p.id = ps.source_product_id").
 		Joins("JOIN warehouse_locations wl ON wl.id = ps.warehouse_location_id").
 		Joins("JOIN users u ON u.id = ps.updated_by").
 		Joins("JOIN user_profiles up ON up.source_user_id = u.id").
-		Where("ps.status = 'low-stock' AND ps.deleted_at IS NULL").
+		Joins(effectiveThresholdJoin).
+		Where(stockStatusExpr + " = 'low-stock' AND ps.deleted_at IS NULL").
 		Limit(10). // Batasi untuk performa, misal top 10
 		Scan(&lowStockItems)
 	for _, item := range lowStockItems {
@@ -306,6 +1291,9 @@ func (r *productRepository) GetDashboardSummary() (*dtos.DashboardResponse, erro
 			WarehouseName:  item.WarehouseName,
 			Quantity:       item.Quantity,
 			Status:         item.Status,
+			MinQuantity:    item.MinQuantity,
+			ReorderPoint:   item.ReorderPoint,
+			MaxQuantity:    item.MaxQuantity,
 			UpdatedByEmail: item.UpdatedByEmail,
 			UpdatedByName:  item.UpdatedByName,
 			UpdatedAt:      item.UpdatedAt,
@@ -347,6 +1335,40 @@ func (r *productRepository) GetDashboardSummary() (*dtos.DashboardResponse, erro
 		})
 	}
 
+	// Lot yang kedaluwarsa dalam N hari (termasuk yang sudah lewat), urut FEFO
+	var expiringLots []struct {
+		LotID         uuid.UUID
+		LotNumber     string
+		ProductID     uuid.UUID
+		ProductName   string
+		WarehouseID   uuid.UUID
+		WarehouseName string
+		Quantity      int
+		ExpiryDate    time.Time
+	}
+	cutoff := time.Now().AddDate(0, 0, expiringWithinDays)
+	r.db.Table("stock_lots sl").
+		Select("sl.id as lot_id, sl.lot_number, sl.source_product_id as product_id, p.name as product_name, sl.warehouse_location_id as warehouse_id, wl.name as warehouse_name, sl.quantity, sl.expiry_date").
+		Joins("JOIN products p ON p.id = sl.source_product_id").
+		Joins("JOIN warehouse_locations wl ON wl.id = sl.warehouse_location_id").
+		Where("sl.quantity > 0 AND sl.expiry_date IS NOT NULL AND sl.expiry_date <= ? AND p.deleted_at IS NULL", cutoff).
+		Order("sl.expiry_date ASC").
+		Limit(50).
+		Scan(&expiringLots)
+	for _, item := range expiringLots {
+		summary.ExpiringLots = append(summary.ExpiringLots, dtos.ExpiringLotDetail{
+			LotID:         item.LotID,
+			LotNumber:     item.LotNumber,
+			ProductID:     item.ProductID,
+			ProductName:   item.ProductName,
+			WarehouseID:   item.WarehouseID,
+			WarehouseName: item.WarehouseName,
+			Quantity:      item.Quantity,
+			ExpiryDate:    item.ExpiryDate,
+			DaysToExpiry:  int(time.Until(item.ExpiryDate).Hours() / 24),
+		})
+	}
+
 	var recentAdditions []struct {
 		ProductID      uuid.UUID
 		ProductName    string
@@ -0,0 +1,162 @@
+package repositorys
+
+import (
+	"auth-service/internal/dtos"
+	"auth-service/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PurchaseOrderRepository interface {
+	CreatePurchaseOrder(order *models.PurchaseOrder) error
+	UpdatePurchaseOrder(order *models.PurchaseOrder) error
+	GetPurchaseOrderByID(id uuid.UUID) (*models.PurchaseOrder, error)
+	GetPurchaseOrderForUpdate(id uuid.UUID) (*models.PurchaseOrder, error)
+	GetPurchaseOrdersList(req dtos.PurchaseOrderListRequest) ([]models.PurchaseOrder, int64, error)
+	ReplacePurchaseOrderLines(orderID uuid.UUID, lines []models.PurchaseOrderLine) error
+	UpdatePurchaseOrderLine(line *models.PurchaseOrderLine) error
+	CreateGoodsReceipt(receipt *models.GoodsReceipt) error
+	GetGoodsReceiptByID(id uuid.UUID) (*models.GoodsReceipt, error)
+	GetGoodsReceiptsByOrderID(orderID uuid.UUID) ([]models.GoodsReceipt, error)
+
+	// WithTransaction menjalankan fn dalam satu transaksi, dengan repository PO dan stok yang terikat ke transaksi tersebut
+	WithTransaction(fn func(repo PurchaseOrderRepository, stockRepo ProductRepository) error) error
+}
+
+type purchaseOrderRepository struct {
+	db *gorm.DB
+}
+
+func NewPurchaseOrderRepository(db *gorm.DB) PurchaseOrderRepository {
+	return &purchaseOrderRepository{db: db}
+}
+
+func (r *purchaseOrderRepository) WithTransaction(fn func(repo PurchaseOrderRepository, stockRepo ProductRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&purchaseOrderRepository{db: tx}, &productRepository{db: tx})
+	})
+}
+
+// CreatePurchaseOrder menyimpan header PO beserta baris-barisnya
+func (r *purchaseOrderRepository) CreatePurchaseOrder(order *models.PurchaseOrder) error {
+	return r.db.Omit("Supplier", "WarehouseLocation", "Lines.Product").Create(order).Error
+}
+
+func (r *purchaseOrderRepository) UpdatePurchaseOrder(order *models.PurchaseOrder) error {
+	return r.db.Omit(clause.Associations).Save(order).Error
+}
+
+func (r *purchaseOrderRepository) GetPurchaseOrderByID(id uuid.UUID) (*models.PurchaseOrder, error) {
+	var order models.PurchaseOrder
+	if err := r.db.Where("id = ? AND deleted_at IS NULL", id).
+		Preload("Supplier").
+		Preload("WarehouseLocation").
+		Preload("Lines", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		}).
+		Preload("Lines.Product").
+		First(&order).Error; err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// GetPurchaseOrderForUpdate mengunci baris PO agar perubahan status dan penerimaan tidak balapan
+func (r *purchaseOrderRepository) GetPurchaseOrderForUpdate(id uuid.UUID) (*models.PurchaseOrder, error) {
+	var order models.PurchaseOrder
+	if err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND deleted_at IS NULL", id).
+		First(&order).Error; err != nil {
+		return nil, err
+	}
+	if err := r.db.Where("purchase_order_id = ?", id).Preload("Product").Order("created_at ASC").Find(&order.Lines).Error; err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (r *purchaseOrderRepository) GetPurchaseOrdersList(req dtos.PurchaseOrderListRequest) ([]models.PurchaseOrder, int64, error) {
+	var orders []models.PurchaseOrder
+	var total int64
+
+	query := r.db.Model(&models.PurchaseOrder{}).Where("deleted_at IS NULL")
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+	if req.SupplierID != uuid.Nil {
+		query = query.Where("supplier_id = ?", req.SupplierID)
+	}
+	if req.WarehouseLocationID != uuid.Nil {
+		query = query.Where("warehouse_location_id = ?", req.WarehouseLocationID)
+	}
+	if req.ProductID != uuid.Nil {
+		query = query.Where("id IN (?)", r.db.Model(&models.PurchaseOrderLine{}).Select("purchase_order_id").Where("source_product_id = ?", req.ProductID))
+	}
+	if req.Search != "" {
+		query = query.Where("(po_number ILIKE ? OR note ILIKE ?)", "%"+req.Search+"%", "%"+req.Search+"%")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (req.Page - 1) * req.Limit
+	if err := query.Preload("Supplier").
+		Preload("WarehouseLocation").
+		Preload("Lines.Product").
+		Order("created_at DESC").
+		Limit(req.Limit).Offset(offset).
+		Find(&orders).Error; err != nil {
+		return nil, 0, err
+	}
+	return orders, total, nil
+}
+
+// ReplacePurchaseOrderLines mengganti seluruh baris PO (hanya untuk PO draft)
+func (r *purchaseOrderRepository) ReplacePurchaseOrderLines(orderID uuid.UUID, lines []models.PurchaseOrderLine) error {
+	if err := r.db.Where("purchase_order_id = ?", orderID).Delete(&models.PurchaseOrderLine{}).Error; err != nil {
+		return err
+	}
+	return r.db.Omit("Product").Create(&lines).Error
+}
+
+func (r *purchaseOrderRepository) UpdatePurchaseOrderLine(line *models.PurchaseOrderLine) error {
+	return r.db.Omit(clause.Associations).Save(line).Error
+}
+
+// CreateGoodsReceipt menyimpan penerimaan beserta baris-barisnya
+func (r *purchaseOrderRepository) CreateGoodsReceipt(receipt *models.GoodsReceipt) error {
+	return r.db.Omit("Lines.Product", "Lines.WarehouseLocation").Create(receipt).Error
+}
+
+func (r *purchaseOrderRepository) GetGoodsReceiptByID(id uuid.UUID) (*models.GoodsReceipt, error) {
+	var receipt models.GoodsReceipt
+	if err := r.db.Where("id = ?", id).
+		Preload("Lines", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		}).
+		Preload("Lines.Product").
+		Preload("Lines.WarehouseLocation").
+		First(&receipt).Error; err != nil {
+		return nil, err
+	}
+	return &receipt, nil
+}
+
+// GetGoodsReceiptsByOrderID mengambil seluruh penerimaan sebuah PO, urut kedatangan
+func (r *purchaseOrderRepository) GetGoodsReceiptsByOrderID(orderID uuid.UUID) ([]models.GoodsReceipt, error) {
+	var receipts []models.GoodsReceipt
+	if err := r.db.Where("purchase_order_id = ?", orderID).
+		Preload("Lines", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		}).
+		Preload("Lines.Product").
+		Preload("Lines.WarehouseLocation").
+		Order("created_at ASC").
+		Find(&receipts).Error; err != nil {
+		return nil, err
+	}
+	return receipts, nil
+}
@@ -0,0 +1,201 @@
+package repositorys
+
+import (
+	"fmt"
+
+	"auth-service/internal/dtos"
+	"auth-service/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// replenishmentTargetExpr adalah level yang ingin dicapai sebuah stok "ps" dengan threshold "st":
+// max quantity jika diatur, selain itu dua kali reorder point (minimal 1 unit)
+var replenishmentTargetExpr = fmt.Sprintf(`CASE
+	WHEN COALESCE(st.max_quantity, %[1]d) > 0 THEN COALESCE(st.max_quantity, %[1]d)
+	ELSE GREATEST(2 * COALESCE(st.reorder_point, %[2]d), 1) END`, models.DefaultMaxQuantity, models.DefaultReorderPoint)
+
+// pendingTransferQuantity menjumlahkan item transfer draft/in_transit untuk produk "ps" di kolom lokasi tertentu
+const pendingTransferQuantity = `LEFT JOIN LATERAL (
+	SELECT COALESCE(SUM(sti.quantity), 0) AS quantity FROM stock_transfer_items sti
+	JOIN stock_transfers t ON t.id = sti.transfer_id
+	WHERE t.deleted_at IS NULL AND t.status IN (%s) AND t.%s = ps.warehouse_location_id
+		AND sti.source_product_id = ps.source_product_id
+) %s ON true`
+
+// pendingPurchaseQuantity menjumlahkan sisa baris PO draft/approved/partially_received untuk produk "ps" di lokasinya
+const pendingPurchaseQuantity = `LEFT JOIN LATERAL (
+	SELECT COALESCE(SUM(GREATEST(pol.quantity - pol.received_quantity, 0)), 0) AS quantity FROM purchase_order_lines pol
+	JOIN purchase_orders po ON po.id = pol.purchase_order_id
+	WHERE po.deleted_at IS NULL AND po.status IN ('draft', 'approved', 'partially_received')
+		AND po.warehouse_location_id = ps.warehouse_location_id AND pol.source_product_id = ps.source_product_id
+) pinc ON true`
+
+// ReplenishmentCandidateRow adalah stok yang (dengan barang dalam perjalanan) berada di bawah reorder point
+type ReplenishmentCandidateRow struct {
+	ProductStockID      uuid.UUID
+	ProductID           uuid.UUID
+	WarehouseLocationID uuid.UUID
+	Available           int
+	Incoming            int
+	ReorderPoint        int
+	Target              int
+}
+
+// SurplusStockRow adalah kelebihan stok sebuah lokasi di atas level targetnya
+type SurplusStockRow struct {
+	ProductID           uuid.UUID
+	WarehouseLocationID uuid.UUID
+	Surplus             int
+}
+
+type ReplenishmentRepository interface {
+	GetReplenishmentCandidates() ([]ReplenishmentCandidateRow, error)
+	GetSurplusStocks(productIDs []uuid.UUID) ([]SurplusStockRow, error)
+	CreateReplenishmentProposal(proposal *models.ReplenishmentProposal) error
+	UpdateReplenishmentProposal(proposal *models.ReplenishmentProposal) error
+	GetReplenishmentProposalByID(id uuid.UUID) (*models.ReplenishmentProposal, error)
+	GetReplenishmentProposalForUpdate(id uuid.UUID) (*models.ReplenishmentProposal, error)
+	GetOpenReplenishmentProposals() ([]models.ReplenishmentProposal, error)
+	GetReplenishmentProposalsList(req dtos.ReplenishmentProposalListRequest) ([]models.ReplenishmentProposal, int64, error)
+
+	// WithTransaction menjalankan fn dalam satu transaksi, dengan repository proposal, transfer, PO, dan stok yang terikat ke transaksi tersebut
+	WithTransaction(fn func(repo ReplenishmentRepository, transferRepo StockTransferRepository, purchaseRepo PurchaseOrderRepository, stockRepo ProductRepository) error) error
+}
+
+type replenishmentRepository struct {
+	db *gorm.DB
+}
+
+func NewReplenishmentRepository(db *gorm.DB) ReplenishmentRepository {
+	return &replenishmentRepository{db: db}
+}
+
+func (r *replenishmentRepository) WithTransaction(fn func(repo ReplenishmentRepository, transferRepo StockTransferRepository, purchaseRepo PurchaseOrderRepository, stockRepo ProductRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&replenishmentRepository{db: tx}, &stockTransferRepository{db: tx}, &purchaseOrderRepository{db: tx}, &productRepository{db: tx})
+	})
+}
+
+// GetReplenishmentCandidates mencari ProductStock dengan available + incoming <= reorder point efektif.
+// Incoming adalah item transfer draft/in_transit yang menuju lokasi stok tersebut ditambah sisa PO yang masih terbuka.
+// Stok karantina dan lokasi karantina tidak di-replenish.
+func (r *replenishmentRepository) GetReplenishmentCandidates() ([]ReplenishmentCandidateRow, error) {
+	var rows []ReplenishmentCandidateRow
+	err := r.db.Table("product_stocks ps").
+		Select(fmt.Sprintf(`ps.id AS product_stock_id, ps.source_product_id AS product_id, ps.warehouse_location_id,
+			ps.quantity - ps.reserved_quantity AS available, inc.quantity + pinc.quantity AS incoming,
+			COALESCE(st.reorder_point, %d) AS reorder_point, %s AS target`, models.DefaultReorderPoint, replenishmentTargetExpr)).
+		Joins("JOIN products p ON p.id = ps.source_product_id AND p.deleted_at IS NULL").
+		Joins("JOIN warehouse_locations wl ON wl.id = ps.warehouse_location_id AND NOT wl.quarantine").
+		Joins(effectiveThresholdJoin).
+		Joins(fmt.Sprintf(pendingTransferQuantity, "'draft', 'in_transit'", "destination_location_id", "inc")).
+		Joins(pendingPurchaseQuantity).
+		Where("ps.deleted_at IS NULL AND ps.status <> 'quarantined'").
+		Where(fmt.Sprintf("ps.quantity - ps.reserved_quantity + inc.quantity + pinc.quantity <= COALESCE(st.reorder_point, %d)", models.DefaultReorderPoint)).
+		Order("p.name ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// GetSurplusStocks mengembalikan lokasi yang masih memiliki stok tersedia di atas targetnya,
+// setelah dikurangi transfer draft yang akan keluar, urut dari surplus terbesar. Stok dan lokasi karantina bukan surplus.
+func (r *replenishmentRepository) GetSurplusStocks(productIDs []uuid.UUID) ([]SurplusStockRow, error) {
+	var rows []SurplusStockRow
+	if len(productIDs) == 0 {
+		return rows, nil
+	}
+	surplus := fmt.Sprintf("ps.quantity - ps.reserved_quantity - pout.quantity - (%s)", replenishmentTargetExpr)
+	err := r.db.Table("product_stocks ps").
+		Select(fmt.Sprintf("ps.source_product_id AS product_id, ps.warehouse_location_id, %s AS surplus", surplus)).
+		Joins("JOIN products p ON p.id = ps.source_product_id").
+		Joins("JOIN warehouse_locations wl ON wl.id = ps.warehouse_location_id AND NOT wl.quarantine").
+		Joins(effectiveThresholdJoin).
+		Joins(fmt.Sprintf(pendingTransferQuantity, "'draft'", "source_location_id", "pout")).
+		Where("ps.deleted_at IS NULL AND ps.status <> 'quarantined' AND ps.source_product_id IN ?", productIDs).
+		Where(surplus + " > 0").
+		Order("surplus DESC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (r *replenishmentRepository) CreateReplenishmentProposal(proposal *models.ReplenishmentProposal) error {
+	return r.db.Omit(clause.Associations).Create(proposal).Error
+}
+
+func (r *replenishmentRepository) UpdateReplenishmentProposal(proposal *models.ReplenishmentProposal) error {
+	return r.db.Omit(clause.Associations).Save(proposal).Error
+}
+
+func (r *replenishmentRepository) GetReplenishmentProposalByID(id uuid.UUID) (*models.ReplenishmentProposal, error) {
+	var proposal models.ReplenishmentProposal
+	if err := r.db.Where("id = ?", id).
+		Preload("Product").
+		Preload("WarehouseLocation").
+		Preload("SourceLocation").
+		First(&proposal).Error; err != nil {
+		return nil, err
+	}
+	return &proposal, nil
+}
+
+// GetReplenishmentProposalForUpdate mengunci baris proposal agar tidak dikonversi dua kali
+func (r *replenishmentRepository) GetReplenishmentProposalForUpdate(id uuid.UUID) (*models.ReplenishmentProposal, error) {
+	var proposal models.ReplenishmentProposal
+	if err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		First(&proposal).Error; err != nil {
+		return nil, err
+	}
+	return &proposal, nil
+}
+
+func (r *replenishmentRepository) GetOpenReplenishmentProposals() ([]models.ReplenishmentProposal, error) {
+	var proposals []models.ReplenishmentProposal
+	if err := r.db.Where("status = ?", "open").Find(&proposals).Error; err != nil {
+		return nil, err
+	}
+	return proposals, nil
+}
+
+func (r *replenishmentRepository) GetReplenishmentProposalsList(req dtos.ReplenishmentProposalListRequest) ([]models.ReplenishmentProposal, int64, error) {
+	var proposals []models.ReplenishmentProposal
+	var total int64
+
+	query := r.db.Model(&models.ReplenishmentProposal{})
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+	if req.SourceType != "" {
+		query = query.Where("source_type = ?", req.SourceType)
+	}
+	if req.ProductID != uuid.Nil {
+		query = query.Where("source_product_id = ?", req.ProductID)
+	}
+	if req.WarehouseLocationID != uuid.Nil {
+		query = query.Where("warehouse_location_id = ?", req.WarehouseLocationID)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (req.Page - 1) * req.Limit
+	if err := query.Preload("Product").
+		Preload("WarehouseLocation").
+		Preload("SourceLocation").
+		Order("updated_at DESC").
+		Limit(req.Limit).Offset(offset).
+		Find(&proposals).Error; err != nil {
+		return nil, 0, err
+	}
+	return proposals, total, nil
+}
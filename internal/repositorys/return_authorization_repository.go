@@ -0,0 +1,148 @@
+package repositorys
+
+import (
+	"auth-service/internal/dtos"
+	"auth-service/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReturnAuthorizationRepository interface {
+	CreateReturnAuthorization(rma *models.ReturnAuthorization) error
+	UpdateReturnAuthorization(rma *models.ReturnAuthorization) error
+	GetReturnAuthorizationByID(id uuid.UUID) (*models.ReturnAuthorization, error)
+	GetReturnAuthorizationForUpdate(id uuid.UUID) (*models.ReturnAuthorization, error)
+	GetReturnAuthorizationsList(req dtos.ReturnAuthorizationListRequest) ([]models.ReturnAuthorization, int64, error)
+	UpdateReturnAuthorizationLine(line *models.ReturnAuthorizationLine) error
+	CreateReturnInspection(inspection *models.ReturnInspection) error
+	GetOutboundOrderForReturnUpdate(orderID uuid.UUID) (*models.OutboundOrder, error)
+	// GetReturnedQuantitiesByOrderLine menjumlahkan quantity RMA (selain cancelled) per baris pesanan outbound
+	GetReturnedQuantitiesByOrderLine(orderID uuid.UUID) (map[uuid.UUID]int, error)
+
+	// WithTransaction menjalankan fn dalam satu transaksi dengan repository RMA dan stok yang terikat ke transaksi tersebut
+	WithTransaction(fn func(repo ReturnAuthorizationRepository, stockRepo ProductRepository) error) error
+}
+
+type returnAuthorizationRepository struct {
+	db *gorm.DB
+}
+
+func NewReturnAuthorizationRepository(db *gorm.DB) ReturnAuthorizationRepository {
+	return &returnAuthorizationRepository{db: db}
+}
+
+func (r *returnAuthorizationRepository) WithTransaction(fn func(repo ReturnAuthorizationRepository, stockRepo ProductRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&returnAuthorizationRepository{db: tx}, &productRepository{db: tx})
+	})
+}
+
+// CreateReturnAuthorization menyimpan header RMA beserta baris-barisnya
+func (r *returnAuthorizationRepository) CreateReturnAuthorization(rma *models.ReturnAuthorization) error {
+	return r.db.Omit("OutboundOrder", "Inspections", "Lines.Product").Create(rma).Error
+}
+
+func (r *returnAuthorizationRepository) UpdateReturnAuthorization(rma *models.ReturnAuthorization) error {
+	return r.db.Omit(clause.Associations).Save(rma).Error
+}
+
+func (r *returnAuthorizationRepository) GetReturnAuthorizationByID(id uuid.UUID) (*models.ReturnAuthorization, error) {
+	var rma models.ReturnAuthorization
+	if err := r.db.Where("id = ? AND deleted_at IS NULL", id).
+		Preload("OutboundOrder").
+		Preload("Lines", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		}).
+		Preload("Lines.Product").
+		Preload("Inspections", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		}).
+		Preload("Inspections.Product").
+		Preload("Inspections.WarehouseLocation").
+		First(&rma).Error; err != nil {
+		return nil, err
+	}
+	return &rma, nil
+}
+
+// GetReturnAuthorizationForUpdate mengunci RMA agar penerimaan dan inspeksi tidak balapan
+func (r *returnAuthorizationRepository) GetReturnAuthorizationForUpdate(id uuid.UUID) (*models.ReturnAuthorization, error) {
+	var rma models.ReturnAuthorization
+	if err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND deleted_at IS NULL", id).
+		First(&rma).Error; err != nil {
+		return nil, err
+	}
+	if err := r.db.Where("return_authorization_id = ?", id).Preload("Product").Order("created_at ASC").Find(&rma.Lines).Error; err != nil {
+		return nil, err
+	}
+	return &rma, nil
+}
+
+func (r *returnAuthorizationRepository) GetReturnAuthorizationsList(req dtos.ReturnAuthorizationListRequest) ([]models.ReturnAuthorization, int64, error) {
+	var list []models.ReturnAuthorization
+	var total int64
+
+	query := r.db.Model(&models.ReturnAuthorization{}).Where("deleted_at IS NULL")
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+	if req.OutboundOrderID != uuid.Nil {
+		query = query.Where("outbound_order_id = ?", req.OutboundOrderID)
+	}
+	if req.ProductID != uuid.Nil {
+		query = query.Where("id IN (?)", r.db.Model(&models.ReturnAuthorizationLine{}).Select("return_authorization_id").Where("source_product_id = ?", req.ProductID))
+	}
+	if req.Search != "" {
+		query = query.Where("(rma_number ILIKE ? OR customer_name ILIKE ? OR customer_reference ILIKE ?)", "%"+req.Search+"%", "%"+req.Search+"%", "%"+req.Search+"%")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (req.Page - 1) * req.Limit
+	if err := query.Preload("OutboundOrder").
+		Preload("Lines.Product").
+		Order("created_at DESC").
+		Limit(req.Limit).Offset(offset).
+		Find(&list).Error; err != nil {
+		return nil, 0, err
+	}
+	return list, total, nil
+}
+
+func (r *returnAuthorizationRepository) UpdateReturnAuthorizationLine(line *models.ReturnAuthorizationLine) error {
+	return r.db.Omit(clause.Associations).Save(line).Error
+}
+
+func (r *returnAuthorizationRepository) CreateReturnInspection(inspection *models.ReturnInspection) error {
+	return r.db.Omit(clause.Associations).Create(inspection).Error
+}
+
+// GetOutboundOrderForReturnUpdate mengunci pesanan asal retur agar RMA paralel tidak melebihi quantity terkirim
+func (r *returnAuthorizationRepository) GetOutboundOrderForReturnUpdate(orderID uuid.UUID) (*models.OutboundOrder, error) {
+	return (&outboundOrderRepository{db: r.db}).GetOutboundOrderForUpdate(orderID)
+}
+
+func (r *returnAuthorizationRepository) GetReturnedQuantitiesByOrderLine(orderID uuid.UUID) (map[uuid.UUID]int, error) {
+	var rows []struct {
+		OutboundOrderLineID uuid.UUID
+		Quantity            int
+	}
+	if err := r.db.Table("return_authorization_lines l").
+		Select("l.outbound_order_line_id, SUM(l.quantity) AS quantity").
+		Joins("JOIN return_authorizations ra ON ra.id = l.return_authorization_id").
+		Where("ra.outbound_order_id = ? AND ra.status <> 'cancelled' AND ra.deleted_at IS NULL AND l.outbound_order_line_id IS NOT NULL", orderID).
+		Group("l.outbound_order_line_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	returned := make(map[uuid.UUID]int, len(rows))
+	for _, row := range rows {
+		returned[row.OutboundOrderLineID] = row.Quantity
+	}
+	return returned, nil
+}
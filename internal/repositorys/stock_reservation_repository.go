@@ -0,0 +1,86 @@
+package repositorys
+
+import (
+	"auth-service/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type StockReservationRepository interface {
+	CreateStockReservation(reservation *models.StockReservation) error
+	GetStockReservationByID(id uuid.UUID) (*models.StockReservation, error)
+	GetStockReservationForUpdate(id uuid.UUID) (*models.StockReservation, error)
+	UpdateStockReservation(reservation *models.StockReservation) error
+	GetStockReservationsByStockID(stockID uuid.UUID, status string) ([]models.StockReservation, error)
+	GetExpiredReservationIDs(now time.Time, limit int) ([]uuid.UUID, error)
+
+	// WithTransaction menjalankan fn dalam satu transaksi, dengan repository reservasi dan stok yang terikat ke transaksi tersebut
+	WithTransaction(fn func(repo StockReservationRepository, stockRepo ProductRepository) error) error
+}
+
+type stockReservationRepository struct {
+	db *gorm.DB
+}
+
+func NewStockReservationRepository(db *gorm.DB) StockReservationRepository {
+	return &stockReservationRepository{db: db}
+}
+
+func (r *stockReservationRepository) WithTransaction(fn func(repo StockReservationRepository, stockRepo ProductRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&stockReservationRepository{db: tx}, &productRepository{db: tx})
+	})
+}
+
+func (r *stockReservationRepository) CreateStockReservation(reservation *models.StockReservation) error {
+	return r.db.Omit(clause.Associations).Create(reservation).Error
+}
+
+func (r *stockReservationRepository) GetStockReservationByID(id uuid.UUID) (*models.StockReservation, error) {
+	var reservation models.StockReservation
+	if err := r.db.Where("id = ?", id).First(&reservation).Error; err != nil {
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+func (r *stockReservationRepository) GetStockReservationForUpdate(id uuid.UUID) (*models.StockReservation, error) {
+	var reservation models.StockReservation
+	if err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&reservation).Error; err != nil {
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+func (r *stockReservationRepository) UpdateStockReservation(reservation *models.StockReservation) error {
+	return r.db.Omit(clause.Associations).Save(reservation).Error
+}
+
+func (r *stockReservationRepository) GetStockReservationsByStockID(stockID uuid.UUID, status string) ([]models.StockReservation, error) {
+	var reservations []models.StockReservation
+	query := r.db.Where("product_stock_id = ?", stockID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Order("created_at DESC").Find(&reservations).Error; err != nil {
+		return nil, err
+	}
+	return reservations, nil
+}
+
+// GetExpiredReservationIDs mengambil reservasi aktif yang sudah lewat masa berlakunya
+func (r *stockReservationRepository) GetExpiredReservationIDs(now time.Time, limit int) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.Model(&models.StockReservation{}).
+		Where("status = 'active' AND expires_at IS NOT NULL AND expires_at <= ?", now).
+		Order("expires_at ASC").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
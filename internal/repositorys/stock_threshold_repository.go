@@ -0,0 +1,182 @@
+package repositorys
+
+import (
+	"fmt"
+
+	"auth-service/internal/dtos"
+	"auth-service/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// thresholdCandidates adalah kondisi threshold yang berlaku untuk satu stok, dengan
+// placeholder produk, lokasi, dan kategori. thresholdPriority mengurutkan dari yang paling spesifik,
+// sama dengan models.StockThreshold.Priority.
+const (
+	thresholdCandidates = `st.deleted_at IS NULL AND (
+		(st.source_product_id = %[1]s AND (st.warehouse_location_id = %[2]s OR st.warehouse_location_id IS NULL))
+		OR (st.source_product_id IS NULL AND st.category_id = %[3]s AND st.warehouse_location_id IS NULL)
+		OR (st.source_product_id IS NULL AND st.category_id IS NULL AND (st.warehouse_location_id = %[2]s OR st.warehouse_location_id IS NULL))
+	)`
+	thresholdPriority = `CASE
+		WHEN st.source_product_id IS NOT NULL AND st.warehouse_location_id IS NOT NULL THEN 1
+		WHEN st.source_product_id IS NOT NULL THEN 2
+		WHEN st.category_id IS NOT NULL THEN 3
+		WHEN st.warehouse_location_id IS NOT NULL THEN 4
+		ELSE 5 END`
+)
+
+// effectiveThresholdJoin menambahkan alias "st" berisi threshold efektif untuk baris
+// product_stocks "ps" dan products "p"
+var effectiveThresholdJoin = thresholdJoin("ps.source_product_id", "ps.warehouse_location_id", "p.category_id")
+
+// thresholdJoin menambahkan alias "st" berisi threshold efektif untuk kolom produk, lokasi, dan kategori yang diberikan
+func thresholdJoin(productColumn, locationColumn, categoryColumn string) string {
+	return fmt.Sprintf(`LEFT JOIN LATERAL (
+	SELECT st.min_quantity, st.reorder_point, st.max_quantity FROM stock_thresholds st
+	WHERE %s
+	ORDER BY %s
+	LIMIT 1
+) st ON true`,
+		fmt.Sprintf(thresholdCandidates, productColumn, locationColumn, categoryColumn),
+		thresholdPriority)
+}
+
+// stockStatusExpr menghitung status stok "ps" secara langsung dari threshold efektif "st".
+// Stok karantina tetap quarantined selama masih ada quantity-nya.
+var stockStatusExpr = fmt.Sprintf(`CASE
+	WHEN ps.quantity <= 0 THEN 'out-of-stock'
+	WHEN ps.status = 'quarantined' THEN 'quarantined'
+	WHEN ps.quantity <= COALESCE(st.reorder_point, %d) OR ps.quantity < COALESCE(st.min_quantity, %d) THEN 'low-stock'
+	ELSE 'available' END`, models.DefaultReorderPoint, models.DefaultMinQuantity)
+
+type StockThresholdRepository interface {
+	CreateStockThreshold(threshold *models.StockThreshold) error
+	UpdateStockThreshold(threshold *models.StockThreshold) error
+	DeleteStockThreshold(id uuid.UUID) error
+	GetStockThresholdByID(id uuid.UUID) (*models.StockThreshold, error)
+	GetStockThresholdByScope(productID, locationID, categoryID *uuid.UUID) (*models.StockThreshold, error)
+	GetStockThresholdsList(req dtos.StockThresholdListRequest) ([]models.StockThreshold, int64, error)
+	GetEffectiveStockThreshold(productID, locationID, categoryID uuid.UUID) (*models.StockThreshold, error)
+	RefreshStockStatuses() (int64, error)
+
+	// WithTransaction menjalankan fn dalam satu transaksi dengan repository threshold yang terikat ke transaksi tersebut
+	WithTransaction(fn func(repo StockThresholdRepository) error) error
+}
+
+type stockThresholdRepository struct {
+	db *gorm.DB
+}
+
+func NewStockThresholdRepository(db *gorm.DB) StockThresholdRepository {
+	return &stockThresholdRepository{db: db}
+}
+
+func (r *stockThresholdRepository) WithTransaction(fn func(repo StockThresholdRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&stockThresholdRepository{db: tx})
+	})
+}
+
+func (r *stockThresholdRepository) CreateStockThreshold(threshold *models.StockThreshold) error {
+	return r.db.Create(threshold).Error
+}
+
+func (r *stockThresholdRepository) UpdateStockThreshold(threshold *models.StockThreshold) error {
+	return r.db.Save(threshold).Error
+}
+
+func (r *stockThresholdRepository) DeleteStockThreshold(id uuid.UUID) error {
+	return r.db.Where("id = ?", id).Delete(&models.StockThreshold{}).Error
+}
+
+func (r *stockThresholdRepository) GetStockThresholdByID(id uuid.UUID) (*models.StockThreshold, error) {
+	var threshold models.StockThreshold
+	if err := r.db.Where("id = ? AND deleted_at IS NULL", id).First(&threshold).Error; err != nil {
+		return nil, err
+	}
+	return &threshold, nil
+}
+
+// GetStockThresholdByScope mencari threshold dengan cakupan yang persis sama (kolom kosong = NULL)
+func (r *stockThresholdRepository) GetStockThresholdByScope(productID, locationID, categoryID *uuid.UUID) (*models.StockThreshold, error) {
+	query := r.db.Where("deleted_at IS NULL")
+	query = whereNullable(query, "source_product_id", productID)
+	query = whereNullable(query, "warehouse_location_id", locationID)
+	query = whereNullable(query, "category_id", categoryID)
+
+	var threshold models.StockThreshold
+	if err := query.First(&threshold).Error; err != nil {
+		return nil, err
+	}
+	return &threshold, nil
+}
+
+func (r *stockThresholdRepository) GetStockThresholdsList(req dtos.StockThresholdListRequest) ([]models.StockThreshold, int64, error) {
+	var thresholds []models.StockThreshold
+	var total int64
+
+	query := r.db.Model(&models.StockThreshold{}).Where("deleted_at IS NULL")
+	if req.ProductID != uuid.Nil {
+		query = query.Where("source_product_id = ?", req.ProductID)
+	}
+	if req.WarehouseLocationID != uuid.Nil {
+		query = query.Where("warehouse_location_id = ?", req.WarehouseLocationID)
+	}
+	if req.CategoryID != uuid.Nil {
+		query = query.Where("category_id = ?", req.CategoryID)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (req.Page - 1) * req.Limit
+	if err := query.Order("updated_at DESC").Limit(req.Limit).Offset(offset).Find(&thresholds).Error; err != nil {
+		return nil, 0, err
+	}
+	return thresholds, total, nil
+}
+
+func (r *stockThresholdRepository) GetEffectiveStockThreshold(productID, locationID, categoryID uuid.UUID) (*models.StockThreshold, error) {
+	return findEffectiveStockThreshold(r.db, productID, locationID, categoryID)
+}
+
+// findEffectiveStockThreshold memilih threshold paling spesifik untuk produk di lokasi tertentu.
+// Kandidatnya paling banyak satu per level; urutan fallback mengikuti models.StockThreshold.Priority.
+// Mengembalikan nil tanpa error jika belum ada threshold yang berlaku.
+func findEffectiveStockThreshold(db *gorm.DB, productID, locationID, categoryID uuid.UUID) (*models.StockThreshold, error) {
+	var thresholds []models.StockThreshold
+	err := db.Table("stock_thresholds st").
+		Select("st.*").
+		Where(fmt.Sprintf(thresholdCandidates, "@product", "@location", "@category"),
+			map[string]interface{}{"product": productID, "location": locationID, "category": categoryID}).
+		Find(&thresholds).Error
+	if err != nil {
+		return nil, err
+	}
+	return models.EffectiveStockThreshold(thresholds), nil
+}
+
+// RefreshStockStatuses menghitung ulang kolom status seluruh ProductStock dari threshold efektif
+func (r *stockThresholdRepository) RefreshStockStatuses() (int64, error) {
+	result := r.db.Exec(fmt.Sprintf(`UPDATE product_stocks
+		SET status = eff.status::stock_status
+		FROM (
+			SELECT ps.id, %s AS status
+			FROM product_stocks ps
+			JOIN products p ON p.id = ps.source_product_id
+			%s
+			WHERE ps.deleted_at IS NULL
+		) eff
+		WHERE product_stocks.id = eff.id AND product_stocks.status::text <> eff.status`, stockStatusExpr, effectiveThresholdJoin))
+	return result.RowsAffected, result.Error
+}
+
+func whereNullable(query *gorm.DB, column string, value *uuid.UUID) *gorm.DB {
+	if value == nil {
+		return query.Where(column + " IS NULL")
+	}
+	return query.Where(column+" = ?", *value)
+}
@@ -0,0 +1,109 @@
+package repositorys
+
+import (
+	"auth-service/internal/dtos"
+	"auth-service/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type StockTransferRepository interface {
+	CreateStockTransfer(transfer *models.StockTransfer) error
+	GetStockTransferByID(id uuid.UUID) (*models.StockTransfer, error)
+	GetStockTransferForUpdate(id uuid.UUID) (*models.StockTransfer, error)
+	UpdateStockTransfer(transfer *models.StockTransfer) error
+	UpdateStockTransferItem(item *models.StockTransferItem) error
+	GetStockTransfersList(req dtos.StockTransferListRequest) ([]models.StockTransfer, int64, error)
+
+	// WithTransaction menjalankan fn dalam satu transaksi, dengan repository transfer dan stok yang terikat ke transaksi tersebut
+	WithTransaction(fn func(repo StockTransferRepository, stockRepo ProductRepository) error) error
+}
+
+type stockTransferRepository struct {
+	db *gorm.DB
+}
+
+func NewStockTransferRepository(db *gorm.DB) StockTransferRepository {
+	return &stockTransferRepository{db: db}
+}
+
+func (r *stockTransferRepository) WithTransaction(fn func(repo StockTransferRepository, stockRepo ProductRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&stockTransferRepository{db: tx}, &productRepository{db: tx})
+	})
+}
+
+// CreateStockTransfer menyimpan header transfer beserta item-itemnya
+func (r *stockTransferRepository) CreateStockTransfer(transfer *models.StockTransfer) error {
+	return r.db.Omit("SourceLocation", "DestinationLocation", "Items.Product").Create(transfer).Error
+}
+
+func (r *stockTransferRepository) GetStockTransferByID(id uuid.UUID) (*models.StockTransfer, error) {
+	var transfer models.StockTransfer
+	if err := r.db.Where("id = ? AND deleted_at IS NULL", id).
+		Preload("SourceLocation").
+		Preload("DestinationLocation").
+		Preload("Items.Product").
+		First(&transfer).Error; err != nil {
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+// GetStockTransferForUpdate mengunci baris transfer agar perubahan status tidak balapan
+func (r *stockTransferRepository) GetStockTransferForUpdate(id uuid.UUID) (*models.StockTransfer, error) {
+	var transfer models.StockTransfer
+	if err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND deleted_at IS NULL", id).
+		First(&transfer).Error; err != nil {
+		return nil, err
+	}
+	if err := r.db.Where("transfer_id = ?", id).Find(&transfer.Items).Error; err != nil {
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+func (r *stockTransferRepository) UpdateStockTransfer(transfer *models.StockTransfer) error {
+	return r.db.Omit(clause.Associations).Save(transfer).Error
+}
+
+func (r *stockTransferRepository) UpdateStockTransferItem(item *models.StockTransferItem) error {
+	return r.db.Omit(clause.Associations).Save(item).Error
+}
+
+func (r *stockTransferRepository) GetStockTransfersList(req dtos.StockTransferListRequest) ([]models.StockTransfer, int64, error) {
+	var transfers []models.StockTransfer
+	var total int64
+
+	query := r.db.Model(&models.StockTransfer{}).Where("deleted_at IS NULL")
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+	if req.SourceLocationID != uuid.Nil {
+		query = query.Where("source_location_id = ?", req.SourceLocationID)
+	}
+	if req.DestinationLocationID != uuid.Nil {
+		query = query.Where("destination_location_id = ?", req.DestinationLocationID)
+	}
+	if req.Search != "" {
+		query = query.Where("transfer_number ILIKE ? OR note ILIKE ?", "%"+req.Search+"%", "%"+req.Search+"%")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (req.Page - 1) * req.Limit
+	query = query.Limit(req.Limit).Offset(offset).Order("created_at DESC")
+
+	// Preload relasi
+	query = query.Preload("SourceLocation").Preload("DestinationLocation").Preload("Items.Product")
+
+	if err := query.Find(&transfers).Error; err != nil {
+		return nil, 0, err
+	}
+	return transfers, total, nil
+}
@@ -0,0 +1,172 @@
+package repositorys
+
+import (
+	"auth-service/internal/dtos"
+	"auth-service/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SupplierRepository interface {
+	CreateSupplier(supplier *models.Supplier) error
+	UpdateSupplier(supplier *models.Supplier) error
+	DeleteSupplier(id uuid.UUID) error
+	GetSupplierByID(id uuid.UUID) (*models.Supplier, error)
+	GetSuppliersList(req dtos.SupplierListRequest) ([]models.Supplier, int64, error)
+	ReplaceSupplierContacts(supplierID uuid.UUID, contacts []models.SupplierContact) error
+	HasOpenPurchaseOrders(supplierID uuid.UUID) (bool, error)
+
+	SaveSupplierProduct(mapping *models.SupplierProduct) error
+	DeleteSupplierProduct(supplierID, productID uuid.UUID) error
+	GetSupplierProduct(supplierID, productID uuid.UUID) (*models.SupplierProduct, error)
+	GetPreferredSupplierProduct(productID uuid.UUID) (*models.SupplierProduct, error)
+	ClearPreferredSupplierProducts(productID, exceptID uuid.UUID) error
+
+	// WithTransaction menjalankan fn dalam satu transaksi dengan repository supplier yang terikat ke transaksi tersebut
+	WithTransaction(fn func(repo SupplierRepository) error) error
+}
+
+type supplierRepository struct {
+	db *gorm.DB
+}
+
+func NewSupplierRepository(db *gorm.DB) SupplierRepository {
+	return &supplierRepository{db: db}
+}
+
+func (r *supplierRepository) WithTransaction(fn func(repo SupplierRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&supplierRepository{db: tx})
+	})
+}
+
+// CreateSupplier menyimpan supplier beserta kontaknya
+func (r *supplierRepository) CreateSupplier(supplier *models.Supplier) error {
+	return r.db.Omit("Products").Create(supplier).Error
+}
+
+func (r *supplierRepository) UpdateSupplier(supplier *models.Supplier) error {
+	return r.db.Omit(clause.Associations).Save(supplier).Error
+}
+
+func (r *supplierRepository) DeleteSupplier(id uuid.UUID) error {
+	return r.db.Where("id = ?", id).Delete(&models.Supplier{}).Error
+}
+
+func (r *supplierRepository) GetSupplierByID(id uuid.UUID) (*models.Supplier, error) {
+	var supplier models.Supplier
+	if err := r.db.Where("id = ? AND deleted_at IS NULL", id).
+		Preload("Contacts", func(db *gorm.DB) *gorm.DB {
+			return db.Order("is_primary DESC, name ASC")
+		}).
+		Preload("Products", func(db *gorm.DB) *gorm.DB {
+			return db.Order("supplier_sku ASC")
+		}).
+		Preload("Products.Product").
+		First(&supplier).Error; err != nil {
+		return nil, err
+	}
+	return &supplier, nil
+}
+
+func (r *supplierRepository) GetSuppliersList(req dtos.SupplierListRequest) ([]models.Supplier, int64, error) {
+	var suppliers []models.Supplier
+	var total int64
+
+	query := r.db.Model(&models.Supplier{}).Where("deleted_at IS NULL")
+	if req.IsActive != nil {
+		query = query.Where("is_active = ?", *req.IsActive)
+	}
+	if req.ProductID != uuid.Nil {
+		query = query.Where("id IN (?)", r.db.Model(&models.SupplierProduct{}).Select("supplier_id").Where("source_product_id = ?", req.ProductID))
+	}
+	if req.Search != "" {
+		query = query.Where("(code ILIKE ? OR name ILIKE ?)", "%"+req.Search+"%", "%"+req.Search+"%")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (req.Page - 1) * req.Limit
+	if err := query.Order("name ASC").Limit(req.Limit).Offset(offset).Find(&suppliers).Error; err != nil {
+		return nil, 0, err
+	}
+	return suppliers, total, nil
+}
+
+// ReplaceSupplierContacts mengganti seluruh kontak supplier
+func (r *supplierRepository) ReplaceSupplierContacts(supplierID uuid.UUID, contacts []models.SupplierContact) error {
+	if err := r.db.Where("supplier_id = ?", supplierID).Delete(&models.SupplierContact{}).Error; err != nil {
+		return err
+	}
+	if len(contacts) == 0 {
+		return nil
+	}
+	return r.db.Create(&contacts).Error
+}
+
+// HasOpenPurchaseOrders memeriksa apakah supplier masih punya PO yang belum closed/cancelled
+func (r *supplierRepository) HasOpenPurchaseOrders(supplierID uuid.UUID) (bool, error) {
+	var exists bool
+	err := r.db.Raw(`SELECT EXISTS (
+		SELECT 1 FROM purchase_orders
+		WHERE supplier_id = ? AND deleted_at IS NULL AND status IN ('draft', 'approved', 'partially_received')
+	)`, supplierID).Scan(&exists).Error
+	return exists, err
+}
+
+// SaveSupplierProduct menyimpan mapping; mapping supplier-produk yang sama ditimpa
+func (r *supplierRepository) SaveSupplierProduct(mapping *models.SupplierProduct) error {
+	return r.db.Omit(clause.Associations).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "supplier_id"}, {Name: "source_product_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"supplier_sku", "unit_cost", "lead_time_days", "min_order_quantity", "preferred", "updated_at"}),
+	}).Create(mapping).Error
+}
+
+func (r *supplierRepository) DeleteSupplierProduct(supplierID, productID uuid.UUID) error {
+	result := r.db.Where("supplier_id = ? AND source_product_id = ?", supplierID, productID).Delete(&models.SupplierProduct{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *supplierRepository) GetSupplierProduct(supplierID, productID uuid.UUID) (*models.SupplierProduct, error) {
+	var mapping models.SupplierProduct
+	if err := r.db.Where("supplier_id = ? AND source_product_id = ?", supplierID, productID).
+		Preload("Supplier").
+		Preload("Product").
+		First(&mapping).Error; err != nil {
+		return nil, err
+	}
+	return &mapping, nil
+}
+
+// GetPreferredSupplierProduct memilih supplier aktif untuk produk: yang ditandai preferred,
+// lalu lead time efektif terpendek, lalu harga termurah
+func (r *supplierRepository) GetPreferredSupplierProduct(productID uuid.UUID) (*models.SupplierProduct, error) {
+	var mapping models.SupplierProduct
+	if err := r.db.Model(&models.SupplierProduct{}).
+		Joins("JOIN suppliers s ON s.id = supplier_products.supplier_id AND s.deleted_at IS NULL AND s.is_active").
+		Where("supplier_products.source_product_id = ?", productID).
+		Order("supplier_products.preferred DESC, COALESCE(supplier_products.lead_time_days, s.lead_time_days) ASC, supplier_products.unit_cost ASC").
+		Preload("Supplier").
+		Preload("Product").
+		First(&mapping).Error; err != nil {
+		return nil, err
+	}
+	return &mapping, nil
+}
+
+// ClearPreferredSupplierProducts melepas tanda preferred mapping lain untuk produk yang sama
+func (r *supplierRepository) ClearPreferredSupplierProducts(productID, exceptID uuid.UUID) error {
+	return r.db.Model(&models.SupplierProduct{}).
+		Where("source_product_id = ? AND id <> ? AND preferred", productID, exceptID).
+		Update("preferred", false).Error
+}
@@ -0,0 +1,136 @@
+package repositorys
+
+import (
+	"time"
+
+	"auth-service/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ValuationRepository interface {
+	GetInventoryValueByLocation(root *models.WarehouseLocation, categoryID uuid.UUID) ([]LocationValueRow, error)
+	GetInventoryValueByCategory(root *models.WarehouseLocation, categoryID uuid.UUID) ([]CategoryValueRow, error)
+	GetCostOfGoods(filter CostOfGoodsFilter) ([]CostOfGoodsRow, error)
+}
+
+type valuationRepository struct {
+	db *gorm.DB
+}
+
+func NewValuationRepository(db *gorm.DB) ValuationRepository {
+	return &valuationRepository{db: db}
+}
+
+// LocationValueRow adalah quantity dan nilai persediaan di satu lokasi
+type LocationValueRow struct {
+	WarehouseLocationID uuid.UUID
+	Name                string
+	Path                string
+	Quantity            int
+	Value               float64
+}
+
+// CategoryValueRow adalah quantity dan nilai persediaan satu category
+type CategoryValueRow struct {
+	CategoryID uuid.UUID
+	Name       string
+	Quantity   int
+	Value      float64
+}
+
+// CostOfGoodsFilter membatasi laporan harga pokok; nilai kosong berarti tanpa filter
+type CostOfGoodsFilter struct {
+	From         *time.Time
+	To           *time.Time
+	ProductID    uuid.UUID
+	CategoryID   uuid.UUID
+	Location     *models.WarehouseLocation
+	MovementType string
+}
+
+// CostOfGoodsRow adalah harga pokok satu produk per tipe movement keluar
+type CostOfGoodsRow struct {
+	ProductID     uuid.UUID
+	ProductName   string
+	SKU           string
+	CostingMethod string
+	MovementType  string
+	Quantity      int
+	TotalCost     float64
+}
+
+// stockValueScope membatasi ProductStock yang bersaldo ke subtree lokasi dan/atau category
+func (r *valuationRepository) stockValueScope(root *models.WarehouseLocation, categoryID uuid.UUID) *gorm.DB {
+	query := r.db.Table("product_stocks ps").
+		Joins("JOIN products p ON p.id = ps.source_product_id").
+		Where("ps.deleted_at IS NULL AND ps.quantity <> 0")
+	if root != nil {
+		query = query.Where("ps.warehouse_location_id IN (?)", (&productRepository{db: r.db}).subtreeLocationIDs(root))
+	}
+	if categoryID != uuid.Nil {
+		query = query.Where("p.category_id = ?", categoryID)
+	}
+	return query
+}
+
+// GetInventoryValueByLocation menjumlahkan stock_value per lokasi, urut path
+func (r *valuationRepository) GetInventoryValueByLocation(root *models.WarehouseLocation, categoryID uuid.UUID) ([]LocationValueRow, error) {
+	var rows []LocationValueRow
+	err := r.stockValueScope(root, categoryID).
+		Select("ps.warehouse_location_id, wl.name, wl.path, SUM(ps.quantity) as quantity, SUM(ps.stock_value) as value").
+		Joins("JOIN warehouse_locations wl ON wl.id = ps.warehouse_location_id").
+		Group("ps.warehouse_location_id, wl.name, wl.path").
+		Order("wl.path ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// GetInventoryValueByCategory menjumlahkan stock_value per category, urut nama
+func (r *valuationRepository) GetInventoryValueByCategory(root *models.WarehouseLocation, categoryID uuid.UUID) ([]CategoryValueRow, error) {
+	var rows []CategoryValueRow
+	err := r.stockValueScope(root, categoryID).
+		Select("p.category_id, pc.name, SUM(ps.quantity) as quantity, SUM(ps.stock_value) as value").
+		Joins("JOIN product_categories pc ON pc.id = p.category_id").
+		Group("p.category_id, pc.name").
+		Order("pc.name ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// GetCostOfGoods menjumlahkan total_cost entri ledger keluar (delta negatif) per produk dan tipe movement.
+// transfer_out dan assembly_out tidak dihitung karena nilainya pindah ke lokasi atau kit lain.
+func (r *valuationRepository) GetCostOfGoods(filter CostOfGoodsFilter) ([]CostOfGoodsRow, error) {
+	var rows []CostOfGoodsRow
+	query := r.db.Table("stock_movements sm").
+		Select("sm.source_product_id as product_id, p.name as product_name, p.sku, p.costing_method, sm.movement_type, SUM(sm.quantity) as quantity, SUM(sm.total_cost) as total_cost").
+		Joins("JOIN products p ON p.id = sm.source_product_id").
+		Where("sm.deleted_at IS NULL AND sm.delta < 0 AND sm.movement_type NOT IN ?", []string{"transfer_out", "assembly_out"})
+	query = whereCreatedBetween(query, "sm.created_at", filter.From, filter.To)
+	if filter.ProductID != uuid.Nil {
+		query = query.Where("sm.source_product_id = ?", filter.ProductID)
+	}
+	if filter.CategoryID != uuid.Nil {
+		query = query.Where("p.category_id = ?", filter.CategoryID)
+	}
+	if filter.Location != nil {
+		query = query.Where("sm.warehouse_location_id IN (?)", (&productRepository{db: r.db}).subtreeLocationIDs(filter.Location))
+	}
+	if filter.MovementType != "" {
+		query = query.Where("sm.movement_type = ?", filter.MovementType)
+	}
+	err := query.Group("sm.source_product_id, p.name, p.sku, p.costing_method, sm.movement_type").
+		Order("p.name ASC, sm.movement_type ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
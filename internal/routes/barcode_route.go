@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"auth-service/internal/controllers"
+	middleware "auth-service/internal/middlewares"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type BarcodeRouteConfig struct {
+	App               *fiber.App
+	BarcodeController controllers.BarcodeController
+	ProductMiddleware *middleware.ProductMiddleware
+	AuthMiddleware    *middleware.AuthMiddleware
+}
+
+func (r *BarcodeRouteConfig) Setup() {
+	api := r.App.Group("/api")
+
+	products := api.Group("/products", r.AuthMiddleware.Authenticate)
+	products.Get("/:id/barcodes", r.ProductMiddleware.Authorize, r.BarcodeController.GetProductBarcodes)
+	products.Post("/:id/barcodes", r.ProductMiddleware.Authorize, r.BarcodeController.AddProductBarcode)
+	products.Post("/:id/barcodes/:barcodeId/primary", r.ProductMiddleware.Authorize, r.BarcodeController.SetPrimaryProductBarcode)
+	products.Delete("/:id/barcodes/:barcodeId", r.ProductMiddleware.Authorize, r.BarcodeController.DeleteProductBarcode)
+
+	scan := api.Group("/scan", r.AuthMiddleware.Authenticate)
+	scan.Get("/:code", r.ProductMiddleware.Authorize, r.BarcodeController.Scan)
+}
@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"auth-service/internal/controllers"
+	middleware "auth-service/internal/middlewares"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type CycleCountRouteConfig struct {
+	App                  *fiber.App
+	CycleCountController controllers.CycleCountController
+	ProductMiddleware    *middleware.ProductMiddleware
+	AuthMiddleware       *middleware.AuthMiddleware
+}
+
+func (r *CycleCountRouteConfig) Setup() {
+	api := r.App.Group("/api")
+
+	counts := api.Group("/cycle-counts", r.AuthMiddleware.Authenticate)
+	counts.Post("/", r.ProductMiddleware.Authorize, r.CycleCountController.CreateCycleCount)
+	counts.Get("/", r.ProductMiddleware.Authorize, r.CycleCountController.GetCycleCountsList)
+	counts.Get("/:id", r.ProductMiddleware.Authorize, r.CycleCountController.GetCycleCountByID)
+	counts.Post("/:id/counts", r.ProductMiddleware.Authorize, r.CycleCountController.SubmitCycleCount)
+	counts.Post("/:id/approve", r.ProductMiddleware.Authorize, r.CycleCountController.ApproveCycleCount)
+	counts.Post("/:id/cancel", r.ProductMiddleware.Authorize, r.CycleCountController.CancelCycleCount)
+}
@@ -0,0 +1,29 @@
+package routes
+
+import (
+	"auth-service/internal/controllers"
+	middleware "auth-service/internal/middlewares"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type KitRouteConfig struct {
+	App               *fiber.App
+	KitController     controllers.KitController
+	ProductMiddleware *middleware.ProductMiddleware
+	AuthMiddleware    *middleware.AuthMiddleware
+}
+
+func (r *KitRouteConfig) Setup() {
+	api := r.App.Group("/api")
+
+	products := api.Group("/products", r.AuthMiddleware.Authenticate)
+	products.Get("/:id/bom", r.ProductMiddleware.Authorize, r.KitController.GetBillOfMaterials)
+	products.Put("/:id/bom", r.ProductMiddleware.Authorize, r.KitController.SetBillOfMaterials)
+	products.Post("/:id/assemble", r.ProductMiddleware.Authorize, r.KitController.AssembleKit)
+	products.Post("/:id/disassemble", r.ProductMiddleware.Authorize, r.KitController.DisassembleKit)
+
+	assemblies := api.Group("/kit-assemblies", r.AuthMiddleware.Authenticate)
+	assemblies.Get("/", r.ProductMiddleware.Authorize, r.KitController.GetKitAssembliesList)
+	assemblies.Get("/:id", r.ProductMiddleware.Authorize, r.KitController.GetKitAssemblyByID)
+}
@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"auth-service/internal/controllers"
+	middleware "auth-service/internal/middlewares"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type LabelRouteConfig struct {
+	App               *fiber.App
+	LabelController   controllers.LabelController
+	ProductMiddleware *middleware.ProductMiddleware
+	AuthMiddleware    *middleware.AuthMiddleware
+}
+
+func (r *LabelRouteConfig) Setup() {
+	api := r.App.Group("/api")
+
+	labels := api.Group("/labels", r.AuthMiddleware.Authenticate)
+	labels.Get("/templates", r.ProductMiddleware.Authorize, r.LabelController.GetLabelTemplates)
+	labels.Post("/products", r.ProductMiddleware.Authorize, r.LabelController.RenderProductLabels)
+	labels.Post("/locations", r.ProductMiddleware.Authorize, r.LabelController.RenderLocationLabels)
+}
@@ -0,0 +1,30 @@
+package routes
+
+import (
+	"auth-service/internal/controllers"
+	middleware "auth-service/internal/middlewares"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type OutboundOrderRouteConfig struct {
+	App                     *fiber.App
+	OutboundOrderController controllers.OutboundOrderController
+	ProductMiddleware       *middleware.ProductMiddleware
+	AuthMiddleware          *middleware.AuthMiddleware
+}
+
+func (r *OutboundOrderRouteConfig) Setup() {
+	api := r.App.Group("/api")
+
+	orders := api.Group("/outbound-orders", r.AuthMiddleware.Authenticate)
+	orders.Post("/", r.ProductMiddleware.Authorize, r.OutboundOrderController.CreateOutboundOrder)
+	orders.Get("/", r.ProductMiddleware.Authorize, r.OutboundOrderController.GetOutboundOrdersList)
+	orders.Get("/:id", r.ProductMiddleware.Authorize, r.OutboundOrderController.GetOutboundOrderByID)
+	orders.Put("/:id", r.ProductMiddleware.Authorize, r.OutboundOrderController.UpdateOutboundOrder)
+	orders.Post("/:id/allocate", r.ProductMiddleware.Authorize, r.OutboundOrderController.AllocateOutboundOrder)
+	orders.Get("/:id/pick-list", r.ProductMiddleware.Authorize, r.OutboundOrderController.GetPickList)
+	orders.Post("/:id/pack", r.ProductMiddleware.Authorize, r.OutboundOrderController.PackOutboundOrder)
+	orders.Post("/:id/ship", r.ProductMiddleware.Authorize, r.OutboundOrderController.ShipOutboundOrder)
+	orders.Post("/:id/cancel", r.ProductMiddleware.Authorize, r.OutboundOrderController.CancelOutboundOrder)
+}
@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"auth-service/internal/controllers"
+	middleware "auth-service/internal/middlewares"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type PickWaveRouteConfig struct {
+	App                *fiber.App
+	PickWaveController controllers.PickWaveController
+	ProductMiddleware  *middleware.ProductMiddleware
+	AuthMiddleware     *middleware.AuthMiddleware
+}
+
+func (r *PickWaveRouteConfig) Setup() {
+	api := r.App.Group("/api")
+
+	waves := api.Group("/pick-waves", r.AuthMiddleware.Authenticate)
+	waves.Post("/", r.ProductMiddleware.Authorize, r.PickWaveController.CreatePickWave)
+	waves.Get("/", r.ProductMiddleware.Authorize, r.PickWaveController.GetPickWavesList)
+	waves.Get("/:id", r.ProductMiddleware.Authorize, r.PickWaveController.GetPickWaveByID)
+	waves.Get("/:id/pick-list", r.ProductMiddleware.Authorize, r.PickWaveController.GetPickRoute)
+	waves.Post("/:id/complete", r.ProductMiddleware.Authorize, r.PickWaveController.CompletePickWave)
+	waves.Post("/:id/cancel", r.ProductMiddleware.Authorize, r.PickWaveController.CancelPickWave)
+}
@@ -0,0 +1,32 @@
+package routes
+
+import (
+	"auth-service/internal/controllers"
+	middleware "auth-service/internal/middlewares"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type PriceListRouteConfig struct {
+	App                 *fiber.App
+	PriceListController controllers.PriceListController
+	ProductMiddleware   *middleware.ProductMiddleware
+	AuthMiddleware      *middleware.AuthMiddleware
+}
+
+func (r *PriceListRouteConfig) Setup() {
+	api := r.App.Group("/api")
+
+	priceLists := api.Group("/price-lists", r.AuthMiddleware.Authenticate)
+	priceLists.Post("/", r.ProductMiddleware.Authorize, r.PriceListController.CreatePriceList)
+	priceLists.Get("/", r.ProductMiddleware.Authorize, r.PriceListController.GetPriceListsList)
+	priceLists.Get("/:id", r.ProductMiddleware.Authorize, r.PriceListController.GetPriceListByID)
+	priceLists.Put("/:id", r.ProductMiddleware.Authorize, r.PriceListController.UpdatePriceList)
+	priceLists.Delete("/:id", r.ProductMiddleware.Authorize, r.PriceListController.DeletePriceList)
+	priceLists.Post("/:id/prices", r.ProductMiddleware.Authorize, r.PriceListController.CreateProductPrice)
+	priceLists.Get("/:id/prices", r.ProductMiddleware.Authorize, r.PriceListController.GetProductPricesList)
+	priceLists.Put("/:id/prices/:priceId", r.ProductMiddleware.Authorize, r.PriceListController.UpdateProductPrice)
+	priceLists.Delete("/:id/prices/:priceId", r.ProductMiddleware.Authorize, r.PriceListController.DeleteProductPrice)
+	priceLists.Get("/:id/history", r.ProductMiddleware.Authorize, r.PriceListController.GetProductPriceHistory)
+	priceLists.Get("/:id/products/:productId/price", r.ProductMiddleware.Authorize, r.PriceListController.GetCurrentPrice)
+}
@@ -20,6 +20,9 @@ func (r *ProductRouteConfig) Setup() {
 	products := api.Group("/products", r.AuthMiddleware.Authenticate)
 	products.Post("/", r.ProductMiddleware.Authorize, r.ProductController.CreateProduct)
 	products.Get("/:id", r.ProductMiddleware.Authorize, r.ProductController.GetProductByID)
+	products.Get("/:id/stock-card", r.ProductMiddleware.Authorize, r.ProductController.GetStockCard)
+	products.Get("/:id/serials", r.ProductMiddleware.Authorize, r.ProductController.GetSerialNumbersList)
+	products.Get("/:id/serials/:serial", r.ProductMiddleware.Authorize, r.ProductController.GetSerialNumber)
 	products.Put("/:id", r.ProductMiddleware.Authorize, r.ProductController.UpdateProduct)
 	products.Delete("/:id", r.ProductMiddleware.Authorize, r.ProductController.DeleteProduct)
 	products.Get("/", r.ProductMiddleware.Authorize, r.ProductController.GetProductsList)
@@ -33,14 +36,26 @@ func (r *ProductRouteConfig) Setup() {
 
 	stocks := api.Group("/product-stocks", r.AuthMiddleware.Authenticate)
 	stocks.Post("/", r.ProductMiddleware.Authorize, r.ProductController.CreateProductStock)
+	stocks.Get("/as-of", r.ProductMiddleware.Authorize, r.ProductController.GetProductStocksAsOf)
 	stocks.Get("/:id", r.ProductMiddleware.Authorize, r.ProductController.GetProductStockByID)
+	stocks.Get("/:id/lots", r.ProductMiddleware.Authorize, r.ProductController.GetStockLotsByStockID)
 	stocks.Put("/:id", r.ProductMiddleware.Authorize, r.ProductController.UpdateProductStock)
 	stocks.Delete("/:id", r.ProductMiddleware.Authorize, r.ProductController.DeleteProductStock)
 	stocks.Get("/", r.ProductMiddleware.Authorize, r.ProductController.GetProductStocksList)
 
+	movements := api.Group("/stock-movements", r.AuthMiddleware.Authenticate)
+	movements.Post("/", r.ProductMiddleware.Authorize, r.ProductController.TrackStockMovement)
+	movements.Get("/", r.ProductMiddleware.Authorize, r.ProductController.GetStockMovementHistory)
+	movements.Get("/reason-codes", r.ProductMiddleware.Authorize, r.ProductController.GetMovementReasonCodes)
+	movements.Get("/approvals", r.ProductMiddleware.Authorize, r.ProductController.GetStockMovementApprovalsList)
+	movements.Post("/approvals/:id/approve", r.ProductMiddleware.Authorize, r.ProductController.ApproveStockMovement)
+	movements.Post("/approvals/:id/reject", r.ProductMiddleware.Authorize, r.ProductController.RejectStockMovement)
+
 	warehouse := api.Group("/warehouse-locations", r.AuthMiddleware.Authenticate)
 	warehouse.Get("/", r.ProductMiddleware.Authorize, r.ProductController.GetWarehouseLocationsList)
 	warehouse.Post("/", r.ProductMiddleware.Authorize, r.ProductController.CreateWarehouseLocation)
+	warehouse.Get("/tree", r.ProductMiddleware.Authorize, r.ProductController.GetWarehouseLocationTree)
+	warehouse.Get("/:id/stock-rollup", r.ProductMiddleware.Authorize, r.ProductController.GetLocationStockRollup)
 	warehouse.Get("/:id", r.ProductMiddleware.Authorize, r.ProductController.GetWarehouseLocationByID)
 	warehouse.Put("/:id", r.ProductMiddleware.Authorize, r.ProductController.UpdateWarehouseLocation)
 	warehouse.Delete("/:id", r.ProductMiddleware.Authorize, r.ProductController.DeleteWarehouseLocation)
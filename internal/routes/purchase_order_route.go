@@ -0,0 +1,30 @@
+package routes
+
+import (
+	"auth-service/internal/controllers"
+	middleware "auth-service/internal/middlewares"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type PurchaseOrderRouteConfig struct {
+	App                     *fiber.App
+	PurchaseOrderController controllers.PurchaseOrderController
+	ProductMiddleware       *middleware.ProductMiddleware
+	AuthMiddleware          *middleware.AuthMiddleware
+}
+
+func (r *PurchaseOrderRouteConfig) Setup() {
+	api := r.App.Group("/api")
+
+	orders := api.Group("/purchase-orders", r.AuthMiddleware.Authenticate)
+	orders.Post("/", r.ProductMiddleware.Authorize, r.PurchaseOrderController.CreatePurchaseOrder)
+	orders.Get("/", r.ProductMiddleware.Authorize, r.PurchaseOrderController.GetPurchaseOrdersList)
+	orders.Get("/:id", r.ProductMiddleware.Authorize, r.PurchaseOrderController.GetPurchaseOrderByID)
+	orders.Put("/:id", r.ProductMiddleware.Authorize, r.PurchaseOrderController.UpdatePurchaseOrder)
+	orders.Post("/:id/approve", r.ProductMiddleware.Authorize, r.PurchaseOrderController.ApprovePurchaseOrder)
+	orders.Post("/:id/close", r.ProductMiddleware.Authorize, r.PurchaseOrderController.ClosePurchaseOrder)
+	orders.Post("/:id/cancel", r.ProductMiddleware.Authorize, r.PurchaseOrderController.CancelPurchaseOrder)
+	orders.Post("/:id/receipts", r.ProductMiddleware.Authorize, r.PurchaseOrderController.ReceivePurchaseOrder)
+	orders.Get("/:id/receipts", r.ProductMiddleware.Authorize, r.PurchaseOrderController.GetGoodsReceipts)
+}
@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"auth-service/internal/controllers"
+	middleware "auth-service/internal/middlewares"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ReplenishmentRouteConfig struct {
+	App                     *fiber.App
+	ReplenishmentController controllers.ReplenishmentController
+	ProductMiddleware       *middleware.ProductMiddleware
+	AuthMiddleware          *middleware.AuthMiddleware
+}
+
+func (r *ReplenishmentRouteConfig) Setup() {
+	api := r.App.Group("/api")
+
+	proposals := api.Group("/replenishment-proposals", r.AuthMiddleware.Authenticate)
+	proposals.Get("/", r.ProductMiddleware.Authorize, r.ReplenishmentController.GetReplenishmentProposalsList)
+	proposals.Post("/generate", r.ProductMiddleware.Authorize, r.ReplenishmentController.GenerateReplenishmentProposals)
+	proposals.Post("/:id/convert", r.ProductMiddleware.Authorize, r.ReplenishmentController.ConvertReplenishmentProposal)
+	proposals.Post("/:id/dismiss", r.ProductMiddleware.Authorize, r.ReplenishmentController.DismissReplenishmentProposal)
+}
@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"auth-service/internal/controllers"
+	middleware "auth-service/internal/middlewares"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ReturnAuthorizationRouteConfig struct {
+	App                           *fiber.App
+	ReturnAuthorizationController controllers.ReturnAuthorizationController
+	ProductMiddleware             *middleware.ProductMiddleware
+	AuthMiddleware                *middleware.AuthMiddleware
+}
+
+func (r *ReturnAuthorizationRouteConfig) Setup() {
+	api := r.App.Group("/api")
+
+	returns := api.Group("/returns", r.AuthMiddleware.Authenticate)
+	returns.Post("/", r.ProductMiddleware.Authorize, r.ReturnAuthorizationController.CreateReturnAuthorization)
+	returns.Get("/", r.ProductMiddleware.Authorize, r.ReturnAuthorizationController.GetReturnAuthorizationsList)
+	returns.Get("/:id", r.ProductMiddleware.Authorize, r.ReturnAuthorizationController.GetReturnAuthorizationByID)
+	returns.Post("/:id/receive", r.ProductMiddleware.Authorize, r.ReturnAuthorizationController.ReceiveReturn)
+	returns.Post("/:id/inspect", r.ProductMiddleware.Authorize, r.ReturnAuthorizationController.InspectReturn)
+	returns.Post("/:id/cancel", r.ProductMiddleware.Authorize, r.ReturnAuthorizationController.CancelReturnAuthorization)
+}
@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"auth-service/internal/controllers"
+	middleware "auth-service/internal/middlewares"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type StockReservationRouteConfig struct {
+	App                        *fiber.App
+	StockReservationController controllers.StockReservationController
+	ProductMiddleware          *middleware.ProductMiddleware
+	AuthMiddleware             *middleware.AuthMiddleware
+}
+
+func (r *StockReservationRouteConfig) Setup() {
+	api := r.App.Group("/api")
+
+	stocks := api.Group("/product-stocks", r.AuthMiddleware.Authenticate)
+	stocks.Post("/:id/reservations", r.ProductMiddleware.Authorize, r.StockReservationController.CreateStockReservation)
+	stocks.Get("/:id/reservations", r.ProductMiddleware.Authorize, r.StockReservationController.GetStockReservationsByStockID)
+
+	reservations := api.Group("/stock-reservations", r.AuthMiddleware.Authenticate)
+	reservations.Post("/:id/release", r.ProductMiddleware.Authorize, r.StockReservationController.ReleaseStockReservation)
+}
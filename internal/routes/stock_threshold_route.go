@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"auth-service/internal/controllers"
+	middleware "auth-service/internal/middlewares"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type StockThresholdRouteConfig struct {
+	App                      *fiber.App
+	StockThresholdController controllers.StockThresholdController
+	ProductMiddleware        *middleware.ProductMiddleware
+	AuthMiddleware           *middleware.AuthMiddleware
+}
+
+func (r *StockThresholdRouteConfig) Setup() {
+	api := r.App.Group("/api")
+
+	thresholds := api.Group("/stock-thresholds", r.AuthMiddleware.Authenticate)
+	thresholds.Get("/", r.ProductMiddleware.Authorize, r.StockThresholdController.GetStockThresholdsList)
+	thresholds.Post("/", r.ProductMiddleware.Authorize, r.StockThresholdController.UpsertStockThreshold)
+	thresholds.Delete("/:id", r.ProductMiddleware.Authorize, r.StockThresholdController.DeleteStockThreshold)
+
+	stocks := api.Group("/product-stocks", r.AuthMiddleware.Authenticate)
+	stocks.Get("/:id/threshold", r.ProductMiddleware.Authorize, r.StockThresholdController.GetEffectiveStockThreshold)
+}
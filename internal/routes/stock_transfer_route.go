@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"auth-service/internal/controllers"
+	middleware "auth-service/internal/middlewares"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type StockTransferRouteConfig struct {
+	App                     *fiber.App
+	StockTransferController controllers.StockTransferController
+	ProductMiddleware       *middleware.ProductMiddleware
+	AuthMiddleware          *middleware.AuthMiddleware
+}
+
+func (r *StockTransferRouteConfig) Setup() {
+	api := r.App.Group("/api")
+
+	transfers := api.Group("/stock-transfers", r.AuthMiddleware.Authenticate)
+	transfers.Post("/", r.ProductMiddleware.Authorize, r.StockTransferController.CreateStockTransfer)
+	transfers.Get("/", r.ProductMiddleware.Authorize, r.StockTransferController.GetStockTransfersList)
+	transfers.Get("/:id", r.ProductMiddleware.Authorize, r.StockTransferController.GetStockTransferByID)
+	transfers.Post("/:id/dispatch", r.ProductMiddleware.Authorize, r.StockTransferController.DispatchStockTransfer)
+	transfers.Post("/:id/receive", r.ProductMiddleware.Authorize, r.StockTransferController.ReceiveStockTransfer)
+	transfers.Post("/:id/cancel", r.ProductMiddleware.Authorize, r.StockTransferController.CancelStockTransfer)
+}
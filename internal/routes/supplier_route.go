@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"auth-service/internal/controllers"
+	middleware "auth-service/internal/middlewares"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type SupplierRouteConfig struct {
+	App                *fiber.App
+	SupplierController controllers.SupplierController
+	ProductMiddleware  *middleware.ProductMiddleware
+	AuthMiddleware     *middleware.AuthMiddleware
+}
+
+func (r *SupplierRouteConfig) Setup() {
+	api := r.App.Group("/api")
+
+	suppliers := api.Group("/suppliers", r.AuthMiddleware.Authenticate)
+	suppliers.Post("/", r.ProductMiddleware.Authorize, r.SupplierController.CreateSupplier)
+	suppliers.Get("/", r.ProductMiddleware.Authorize, r.SupplierController.GetSuppliersList)
+	suppliers.Get("/:id", r.ProductMiddleware.Authorize, r.SupplierController.GetSupplierByID)
+	suppliers.Put("/:id", r.ProductMiddleware.Authorize, r.SupplierController.UpdateSupplier)
+	suppliers.Delete("/:id", r.ProductMiddleware.Authorize, r.SupplierController.DeleteSupplier)
+	suppliers.Post("/:id/products", r.ProductMiddleware.Authorize, r.SupplierController.SaveSupplierProduct)
+	suppliers.Delete("/:id/products/:productId", r.ProductMiddleware.Authorize, r.SupplierController.DeleteSupplierProduct)
+}
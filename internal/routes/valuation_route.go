@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"auth-service/internal/controllers"
+	middleware "auth-service/internal/middlewares"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ValuationRouteConfig struct {
+	App                 *fiber.App
+	ValuationController controllers.ValuationController
+	ProductMiddleware   *middleware.ProductMiddleware
+	AuthMiddleware      *middleware.AuthMiddleware
+}
+
+func (r *ValuationRouteConfig) Setup() {
+	api := r.App.Group("/api")
+
+	valuation := api.Group("/inventory-valuation", r.AuthMiddleware.Authenticate)
+	valuation.Get("/", r.ProductMiddleware.Authorize, r.ValuationController.GetInventoryValuation)
+	valuation.Get("/cogs", r.ProductMiddleware.Authorize, r.ValuationController.GetCostOfGoods)
+}
@@ -0,0 +1,170 @@
+package usecases
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrUnsupportedBarcodeData = errors.New("barcode data contains characters that cannot be encoded")
+
+// code128Patterns adalah lebar bar/spasi bergantian (diawali bar) untuk nilai 0-105; indeks 106 adalah stop
+var code128Patterns = [107]string{
+	"212222", "222122", "222221", "121223", "121322", "131222", "122213", "122312", "132212", "221213",
+	"221312", "231212", "112232", "122132", "122231", "113222", "123122", "123221", "223211", "221132",
+	"221231", "213212", "223112", "312131", "311222", "321122", "321221", "312212", "322112", "322211",
+	"212123", "212321", "232121", "111323", "131123", "131321", "112313", "132113", "132311", "211313",
+	"231113", "231311", "112133", "112331", "132131", "113123", "113321", "133121", "313121", "211331",
+	"231131", "213113", "213311", "213131", "311123", "311321", "331121", "312113", "312311", "332111",
+	"314111", "221411", "431111", "111224", "111422", "121124", "121421", "141122", "141221", "112214",
+	"112412", "122114", "122411", "142112", "142211", "241211", "221114", "413111", "241112", "134111",
+	"111242", "121142", "121241", "114212", "124112", "124211", "411212", "421112", "421211", "212141",
+	"214121", "412121", "111143", "111341", "131141", "114113", "114311", "411113", "411311", "113141",
+	"114131", "311141", "411131", "211412", "211214", "211232", "2331112",
+}
+
+const (
+	code128CodeC  = 99
+	code128CodeB  = 100
+	code128StartB = 104
+	code128StartC = 105
+	code128Stop   = 106
+)
+
+// eanLCodes adalah pola digit kiri paritas ganjil; pola R adalah kebalikan bitnya dan pola G adalah R yang dibalik urutannya
+var eanLCodes = [10]string{
+	"0001101", "0011001", "0010011", "0111101", "0100011",
+	"0110001", "0101111", "0111011", "0110111", "0001011",
+}
+
+// ean13Parity menentukan paritas L/G enam digit kiri EAN-13 dari digit pertama
+var ean13Parity = [10]string{
+	"LLLLLL", "LLGLGG", "LLGGLG", "LLGGGL", "LGLLGG",
+	"LGGLLG", "LGGGLL", "LGLGLG", "LGLGGL", "LGGLGL",
+}
+
+func digitRun(data string, from int) int {
+	n := 0
+	for from+n < len(data) && data[from+n] >= '0' && data[from+n] <= '9' {
+		n++
+	}
+	return n
+}
+
+// code128Modules mengkodekan data ke Code 128 (code set B, beralih ke code set C untuk deret digit
+// genap minimal 4) dan mengembalikan modul barcode; true berarti bar
+func code128Modules(data string) ([]bool, error) {
+	if data == "" {
+		return nil, fmt.Errorf("%w: empty barcode", ErrUnsupportedBarcodeData)
+	}
+
+	var values []int
+	setC := false
+	if run := digitRun(data, 0); run >= 4 && run%2 == 0 {
+		values = append(values, code128StartC)
+		setC = true
+	} else {
+		values = append(values, code128StartB)
+	}
+
+	for i := 0; i < len(data); {
+		if setC {
+			if digitRun(data, i) >= 2 {
+				values = append(values, int(data[i]-'0')*10+int(data[i+1]-'0'))
+				i += 2
+				continue
+			}
+			values = append(values, code128CodeB)
+			setC = false
+			continue
+		}
+		if run := digitRun(data, i); run >= 4 && run%2 == 0 {
+			values = append(values, code128CodeC)
+			setC = true
+			continue
+		}
+		c := data[i]
+		if c < 32 || c > 126 {
+			return nil, fmt.Errorf("%w: %q", ErrUnsupportedBarcodeData, data)
+		}
+		values = append(values, int(c)-32)
+		i++
+	}
+
+	checksum := values[0]
+	for i, value := range values[1:] {
+		checksum += (i + 1) * value
+	}
+	values = append(values, checksum%103, code128Stop)
+
+	var modules []bool
+	for _, value := range values {
+		for i, width := range code128Patterns[value] {
+			for w := 0; w < int(width-'0'); w++ {
+				modules = append(modules, i%2 == 0)
+			}
+		}
+	}
+	return modules, nil
+}
+
+// eanModules mengkodekan EAN-13, UPC-A (dicetak sebagai EAN-13 berawalan 0) atau EAN-8 lengkap dengan check digit
+func eanModules(code string) ([]bool, error) {
+	if len(code) == 12 {
+		code = "0" + code
+	}
+	if (len(code) != 13 && len(code) != 8) || !isDigits(code) {
+		return nil, fmt.Errorf("%w: %s is not an EAN-13, UPC-A or EAN-8 code", ErrUnsupportedBarcodeData, code)
+	}
+	if gtinCheckDigit(code[:len(code)-1]) != code[len(code)-1] {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidGTIN, code)
+	}
+
+	parity := "LLLL"
+	left, right := code[:4], code[4:]
+	if len(code) == 13 {
+		parity = ean13Parity[code[0]-'0']
+		left, right = code[1:7], code[7:]
+	}
+
+	var pattern strings.Builder
+	pattern.WriteString("101")
+	for i := 0; i < len(left); i++ {
+		l := eanLCodes[left[i]-'0']
+		if parity[i] == 'G' {
+			l = reverseString(invertBits(l))
+		}
+		pattern.WriteString(l)
+	}
+	pattern.WriteString("01010")
+	for i := 0; i < len(right); i++ {
+		pattern.WriteString(invertBits(eanLCodes[right[i]-'0']))
+	}
+	pattern.WriteString("101")
+
+	modules := make([]bool, pattern.Len())
+	for i, bit := range pattern.String() {
+		modules[i] = bit == '1'
+	}
+	return modules, nil
+}
+
+func invertBits(bits string) string {
+	inverted := []byte(bits)
+	for i, b := range inverted {
+		if b == '0' {
+			inverted[i] = '1'
+		} else {
+			inverted[i] = '0'
+		}
+	}
+	return string(inverted)
+}
+
+func reverseString(s string) string {
+	reversed := []byte(s)
+	for i, j := 0, len(reversed)-1; i < j; i, j = i+1, j-1 {
+		reversed[i], reversed[j] = reversed[j], reversed[i]
+	}
+	return string(reversed)
+}
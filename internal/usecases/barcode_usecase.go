@@ -0,0 +1,387 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"auth-service/internal/dtos"
+	"auth-service/internal/models"
+	"auth-service/internal/repositorys"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrDuplicateBarcode = errors.New("barcode is already assigned to a product")
+	ErrSymbologyLength  = errors.New("barcode length does not match its symbology")
+)
+
+type BarcodeUseCase interface {
+	AddProductBarcode(ctx context.Context, productID uuid.UUID, req dtos.ProductBarcodeRequest, userID uuid.UUID) (*dtos.ProductBarcodeResponse, error)
+	GetProductBarcodes(ctx context.Context, productID uuid.UUID) ([]dtos.ProductBarcodeResponse, error)
+	SetPrimaryProductBarcode(ctx context.Context, productID, id uuid.UUID, userID uuid.UUID) (*dtos.ProductBarcodeResponse, error)
+	DeleteProductBarcode(ctx context.Context, productID, id uuid.UUID, userID uuid.UUID) error
+	Scan(ctx context.Context, code string) (*dtos.ScanResponse, error)
+}
+
+type barcodeUseCase struct {
+	repo        repositorys.BarcodeRepository
+	productRepo repositorys.ProductRepository
+	validate    *validator.Validate
+	log         *logrus.Logger
+}
+
+func NewBarcodeUseCase(repo repositorys.BarcodeRepository, productRepo repositorys.ProductRepository, log *logrus.Logger, validate *validator.Validate) BarcodeUseCase {
+	return &barcodeUseCase{repo: repo, productRepo: productRepo, log: log, validate: validate}
+}
+
+// barcodeSymbology menentukan symbology dan GTIN 14 digit sebuah kode. Kode GTIN selalu divalidasi check digit-nya.
+func barcodeSymbology(code, symbology string) (string, *string, error) {
+	detected := gtinSymbology(code)
+	if symbology == "" {
+		symbology = detected
+		if symbology == "" {
+			symbology = SymbologyCode128
+		}
+	}
+	if symbology == SymbologyCode128 {
+		return symbology, nil, nil
+	}
+	if detected != symbology {
+		return "", nil, fmt.Errorf("%w: %s is not a valid %s", ErrSymbologyLength, code, symbology)
+	}
+	gtin, err := normalizeGTIN(code)
+	if err != nil {
+		return "", nil, err
+	}
+	return symbology, &gtin, nil
+}
+
+// AddProductBarcode menambah barcode produk. Barcode pertama produk, atau yang ditandai is_primary,
+// menjadi barcode utama.
+func (u *barcodeUseCase) AddProductBarcode(ctx context.Context, productID uuid.UUID, req dtos.ProductBarcodeRequest, userID uuid.UUID) (*dtos.ProductBarcodeResponse, error) {
+	if err := u.validate.Struct(req); err != nil {
+		return nil, err
+	}
+	code := strings.TrimSpace(req.Code)
+	symbology, gtin, err := barcodeSymbology(code, req.Symbology)
+	if err != nil {
+		return nil, err
+	}
+	product, err := u.productRepo.GetProductByID(productID)
+	if err != nil {
+		return nil, err
+	}
+	unit := req.Unit
+	if unit == product.BaseUnit {
+		unit = ""
+	}
+	if unit != "" {
+		if _, err := unitFactor(u.productRepo, product.ID, unit); err != nil {
+			return nil, err
+		}
+	}
+
+	barcode := &models.ProductBarcode{
+		ID:              uuid.New(),
+		SourceProductID: product.ID,
+		Code:            code,
+		Symbology:       symbology,
+		GTIN:            gtin,
+		Unit:            unit,
+		IsPrimary:       req.IsPrimary,
+		CreatedBy:       userID,
+	}
+	err = u.repo.WithTransaction(func(repo repositorys.BarcodeRepository) error {
+		existing, err := repo.FindProductBarcode(code, gtin)
+		if err == nil {
+			return fmt.Errorf("%w: %s (product %s)", ErrDuplicateBarcode, existing.Code, existing.Product.SKU)
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		barcodes, err := repo.GetProductBarcodes(product.ID)
+		if err != nil {
+			return err
+		}
+		barcode.IsPrimary = barcode.IsPrimary || len(barcodes) == 0
+		if err := repo.CreateProductBarcode(barcode); err != nil {
+			return err
+		}
+		if barcode.IsPrimary {
+			return repo.SetPrimaryProductBarcode(product.ID, barcode.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	u.log.Info(fmt.Sprintf("Barcode %s added to product %s", barcode.Code, product.SKU))
+
+	saved, err := u.repo.GetProductBarcodeByID(product.ID, barcode.ID)
+	if err != nil {
+		return nil, err
+	}
+	response := toProductBarcodeResponse(saved)
+	return &response, nil
+}
+
+func (u *barcodeUseCase) GetProductBarcodes(ctx context.Context, productID uuid.UUID) ([]dtos.ProductBarcodeResponse, error) {
+	if _, err := u.productRepo.GetProductByID(productID); err != nil {
+		return nil, err
+	}
+	barcodes, err := u.repo.GetProductBarcodes(productID)
+	if err != nil {
+		return nil, err
+	}
+	return toProductBarcodeResponses(barcodes), nil
+}
+
+func (u *barcodeUseCase) SetPrimaryProductBarcode(ctx context.Context, productID, id uuid.UUID, userID uuid.UUID) (*dtos.ProductBarcodeResponse, error) {
+	err := u.repo.WithTransaction(func(repo repositorys.BarcodeRepository) error {
+		if _, err := repo.GetProductBarcodeByID(productID, id); err != nil {
+			return err
+		}
+		return repo.SetPrimaryProductBarcode(productID, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	barcode, err := u.repo.GetProductBarcodeByID(productID, id)
+	if err != nil {
+		return nil, err
+	}
+	response := toProductBarcodeResponse(barcode)
+	return &response, nil
+}
+
+// DeleteProductBarcode menghapus barcode; bila barcode utama yang dihapus, barcode tertua berikutnya menjadi utama
+func (u *barcodeUseCase) DeleteProductBarcode(ctx context.Context, productID, id uuid.UUID, userID uuid.UUID) error {
+	return u.repo.WithTransaction(func(repo repositorys.BarcodeRepository) error {
+		barcode, err := repo.GetProductBarcodeByID(productID, id)
+		if err != nil {
+			return err
+		}
+		if err := repo.DeleteProductBarcode(productID, id); err != nil {
+			return err
+		}
+		if !barcode.IsPrimary {
+			return nil
+		}
+		remaining, err := repo.GetProductBarcodes(productID)
+		if err != nil || len(remaining) == 0 {
+			return err
+		}
+		return repo.SetPrimaryProductBarcode(productID, remaining[0].ID)
+	})
+}
+
+// Scan meresolusi kode hasil scan. Data GS1 diurai per application identifier (GTIN, lot, expiry,
+// serial, path lokasi di AI 91); kode biasa dicocokkan ke barcode produk, lalu SKU, lalu path lokasi,
+// dan terakhir dicoba diurai sebagai GS1 tanpa separator.
+func (u *barcodeUseCase) Scan(ctx context.Context, code string) (*dtos.ScanResponse, error) {
+	if isGS1Data(code) {
+		return u.scanGS1(code)
+	}
+	code = strings.TrimSpace(code)
+	response := &dtos.ScanResponse{Code: code}
+
+	var gtin *string
+	if normalized, err := normalizeGTIN(code); err == nil {
+		gtin = &normalized
+		response.GTIN = normalized
+	}
+	barcode, err := u.repo.FindProductBarcode(code, gtin)
+	if err == nil {
+		response.MatchedBy = "barcode"
+		return response, u.applyScannedBarcode(response, barcode)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	product, err := u.productRepo.GetProductBySKU(code)
+	if err == nil {
+		response.MatchedBy = "sku"
+		response.Product = toScanProductResponse(product)
+		response.Unit = product.BaseUnit
+		response.Factor = 1
+		return response, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	location, err := u.productRepo.GetWarehouseLocationByPath(code)
+	if err == nil {
+		response.MatchedBy = "location"
+		response.Location = toScanLocationResponse(location)
+		return response, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	// Scanner mode keyboard bisa mengirim GS1 tanpa symbology identifier maupun FNC1
+	if _, err := parseGS1(code); err == nil {
+		return u.scanGS1(code)
+	}
+	return nil, fmt.Errorf("no barcode, product or location matches %q: %w", code, gorm.ErrRecordNotFound)
+}
+
+func (u *barcodeUseCase) scanGS1(code string) (*dtos.ScanResponse, error) {
+	elements, err := parseGS1(code)
+	if err != nil {
+		return nil, err
+	}
+	response := &dtos.ScanResponse{Code: code, MatchedBy: "gs1", Elements: elements}
+
+	var productID *uuid.UUID
+	gtinValue := elements[AIGTIN]
+	if gtinValue == "" {
+		gtinValue = elements[AIContentGTIN]
+	}
+	if gtinValue != "" {
+		gtin, err := normalizeGTIN(gtinValue)
+		if err != nil {
+			return nil, err
+		}
+		response.GTIN = gtin
+		barcode, err := u.repo.FindProductBarcode(gtin, &gtin)
+		if err != nil {
+			return nil, fmt.Errorf("GTIN %s: %w", gtin, err)
+		}
+		if err := u.applyScannedBarcode(response, barcode); err != nil {
+			return nil, err
+		}
+		productID = &barcode.SourceProductID
+	}
+
+	var locationID *uuid.UUID
+	if path := elements[AILocationPath]; path != "" {
+		location, err := u.productRepo.GetWarehouseLocationByPath(path)
+		if err != nil {
+			return nil, fmt.Errorf("location %s: %w", path, err)
+		}
+		response.Location = toScanLocationResponse(location)
+		locationID = &location.ID
+	}
+	if productID == nil && locationID == nil {
+		return nil, fmt.Errorf("GS1 data has no GTIN or location to resolve: %w", gorm.ErrRecordNotFound)
+	}
+
+	if value := elements[AIExpiryDate]; value != "" {
+		expiry, err := parseGS1Date(value)
+		if err != nil {
+			return nil, err
+		}
+		response.ExpiryDate = &expiry
+	}
+	if lotNumber := elements[AILotNumber]; lotNumber != "" {
+		response.LotNumber = lotNumber
+		if productID != nil {
+			lots, err := u.repo.GetStockLotsByNumber(*productID, lotNumber, locationID)
+			if err != nil {
+				return nil, err
+			}
+			for _, lot := range lots {
+				response.Lots = append(response.Lots, dtos.ScanLotResponse{
+					LotID:               lot.ID,
+					LotNumber:           lot.LotNumber,
+					ExpiryDate:          lot.ExpiryDate,
+					WarehouseLocationID: lot.WarehouseLocationID,
+					LocationPath:        lot.WarehouseLocation.Path,
+					Quantity:            lot.Quantity,
+				})
+			}
+		}
+	}
+	if serialNumber := elements[AISerialNumber]; serialNumber != "" {
+		response.SerialNumber = serialNumber
+		if productID != nil {
+			serial, err := u.productRepo.GetSerialNumber(*productID, serialNumber)
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, err
+			}
+			if err == nil {
+				response.Serial = &dtos.ScanSerialResponse{
+					SerialNumber:        serial.SerialNumber,
+					Status:              serial.Status,
+					WarehouseLocationID: serial.WarehouseLocationID,
+				}
+				if serial.WarehouseLocation != nil {
+					response.Serial.LocationPath = serial.WarehouseLocation.Path
+				}
+			}
+		}
+	}
+	return response, nil
+}
+
+// applyScannedBarcode mengisi produk, barcode dan satuan kemasan barcode pada hasil scan
+func (u *barcodeUseCase) applyScannedBarcode(response *dtos.ScanResponse, barcode *models.ProductBarcode) error {
+	barcodeResponse := toProductBarcodeResponse(barcode)
+	response.Barcode = &barcodeResponse
+	response.Product = toScanProductResponse(&barcode.Product)
+	response.Unit = barcode.Product.BaseUnit
+	response.Factor = 1
+	if barcode.Unit == "" {
+		return nil
+	}
+	factor, err := unitFactor(u.productRepo, barcode.SourceProductID, barcode.Unit)
+	if err != nil {
+		return err
+	}
+	response.Unit = barcode.Unit
+	response.Factor = factor
+	return nil
+}
+
+func toProductBarcodeResponse(b *models.ProductBarcode) dtos.ProductBarcodeResponse {
+	response := dtos.ProductBarcodeResponse{
+		ID:        b.ID,
+		ProductID: b.SourceProductID,
+		Code:      b.Code,
+		Symbology: b.Symbology,
+		Unit:      b.Unit,
+		IsPrimary: b.IsPrimary,
+		CreatedAt: b.CreatedAt,
+	}
+	if b.GTIN != nil {
+		response.GTIN = *b.GTIN
+	}
+	return response
+}
+
+func toProductBarcodeResponses(barcodes []models.ProductBarcode) []dtos.ProductBarcodeResponse {
+	list := make([]dtos.ProductBarcodeResponse, 0, len(barcodes))
+	for i := range barcodes {
+		list = append(list, toProductBarcodeResponse(&barcodes[i]))
+	}
+	return list
+}
+
+func toScanProductResponse(p *models.Product) *dtos.ScanProductResponse {
+	return &dtos.ScanProductResponse{
+		ID:         p.ID,
+		Name:       p.Name,
+		SKU:        p.SKU,
+		BaseUnit:   p.BaseUnit,
+		LotTracked: p.LotTracked,
+		Serialized: p.Serialized,
+	}
+}
+
+func toScanLocationResponse(l *models.WarehouseLocation) *dtos.ScanLocationResponse {
+	return &dtos.ScanLocationResponse{
+		ID:   l.ID,
+		Type: l.Type,
+		Code: l.Code,
+		Path: l.Path,
+		Name: l.Name,
+	}
+}
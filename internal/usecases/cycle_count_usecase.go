@@ -0,0 +1,581 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"auth-service/internal/dtos"
+	"auth-service/internal/models"
+	"auth-service/internal/repositorys"
+	"auth-service/internal/utils"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrCycleCountNotOpen    = errors.New("cycle count is not open")
+	ErrCycleCountEmpty      = errors.New("no product stock found in the cycle count scope")
+	ErrStockAlreadyCounting = errors.New("some stock in this scope is already part of an open cycle count")
+	ErrCycleCountUnresolved = errors.New("every line must be counted and all counters must agree before approval")
+)
+
+type CycleCountUseCase interface {
+	CreateCycleCount(ctx context.Context, req dtos.CreateCycleCountRequest, userID uuid.UUID) (*dtos.CycleCountResponse, error)
+	GetCycleCountByID(ctx context.Context, id uuid.UUID) (*dtos.CycleCountResponse, error)
+	GetCycleCountsList(ctx context.Context, req dtos.CycleCountListRequest) ([]dtos.CycleCountResponse, dtos.Pagination, error)
+	SubmitCycleCount(ctx context.Context, id uuid.UUID, req dtos.SubmitCycleCountRequest, userID uuid.UUID) (*dtos.CycleCountResponse, error)
+	ApproveCycleCount(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*dtos.CycleCountResponse, error)
+	CancelCycleCount(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*dtos.CycleCountResponse, error)
+}
+
+type cycleCountUseCase struct {
+	repo        repositorys.CycleCountRepository
+	productRepo repositorys.ProductRepository
+	// costingMethod adalah metode valuasi default untuk produk tanpa costing_method (valuation.default_method)
+	costingMethod string
+	validate      *validator.Validate
+	log           *logrus.Logger
+}
+
+func NewCycleCountUseCase(repo repositorys.CycleCountRepository, productRepo repositorys.ProductRepository, costingMethod string, log *logrus.Logger, validate *validator.Validate) CycleCountUseCase {
+	return &cycleCountUseCase{repo: repo, productRepo: productRepo, costingMethod: costingMethod, log: log, validate: validate}
+}
+
+// CreateCycleCount membuka sesi hitung dan membekukan expected quantity setiap stok dalam cakupan.
+// Produk lot-tracked dihitung per lot, produk serialized juga membekukan daftar serial.
+func (u *cycleCountUseCase) CreateCycleCount(ctx context.Context, req dtos.CreateCycleCountRequest, userID uuid.UUID) (*dtos.CycleCountResponse, error) {
+	if err := u.validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	var root *models.WarehouseLocation
+	if req.WarehouseLocationID != nil {
+		location, err := u.productRepo.GetWarehouseLocationByID(*req.WarehouseLocationID)
+		if err != nil {
+			return nil, fmt.Errorf("warehouse location not found: %w", err)
+		}
+		root = location
+	}
+	if req.ProductID != nil {
+		if _, err := u.productRepo.GetProductByID(*req.ProductID); err != nil {
+			return nil, fmt.Errorf("product not found: %w", err)
+		}
+	}
+
+	now := time.Now()
+	count := &models.CycleCount{
+		ID:                  uuid.New(),
+		CountNumber:         utils.GenerateDocumentNumber("CNT"),
+		WarehouseLocationID: req.WarehouseLocationID,
+		SourceProductID:     req.ProductID,
+		Status:              "open",
+		Note:                req.Note,
+		CreatedBy:           userID,
+		CreatedAt:           now,
+		UpdatedAt:           now,
+	}
+
+	err := u.repo.WithTransaction(func(repo repositorys.CycleCountRepository, stockRepo repositorys.ProductRepository) error {
+		stocks, err := repo.GetCycleCountScopeStocks(root, req.ProductID)
+		if err != nil {
+			return err
+		}
+		if len(stocks) == 0 {
+			return ErrCycleCountEmpty
+		}
+
+		stockIDs := make([]uuid.UUID, 0, len(stocks))
+		for _, stock := range stocks {
+			stockIDs = append(stockIDs, stock.ID)
+		}
+		counting, err := repo.HasOpenCycleCountLines(stockIDs)
+		if err != nil {
+			return err
+		}
+		if counting {
+			return ErrStockAlreadyCounting
+		}
+
+		for _, stock := range stocks {
+			line := models.CycleCountLine{
+				CycleCountID:        count.ID,
+				ProductStockID:      stock.ID,
+				SourceProductID:     stock.SourceProductID,
+				WarehouseLocationID: stock.WarehouseLocationID,
+				ExpectedQuantity:    stock.Quantity,
+				CreatedAt:           now,
+				UpdatedAt:           now,
+			}
+
+			switch {
+			case stock.Product.LotTracked:
+				lots, err := stockRepo.GetStockLotsByStockID(stock.ID)
+				if err != nil {
+					return err
+				}
+				for _, lot := range lots {
+					if lot.Quantity <= 0 {
+						continue
+					}
+					lotLine := line
+					lotLine.ID = uuid.New()
+					lotLine.LotID = &lot.ID
+					lotLine.ExpectedQuantity = lot.Quantity
+					count.Lines = append(count.Lines, lotLine)
+				}
+				continue
+			case stock.Product.Serialized:
+				if line.ExpectedSerials, err = repo.GetInStockSerialNumbers(stock.SourceProductID, stock.WarehouseLocationID); err != nil {
+					return err
+				}
+			}
+			line.ID = uuid.New()
+			count.Lines = append(count.Lines, line)
+		}
+		if len(count.Lines) == 0 {
+			return ErrCycleCountEmpty
+		}
+		return repo.CreateCycleCount(count)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	u.log.Info(fmt.Sprintf("Cycle count %s opened with %d line(s)", count.CountNumber, len(count.Lines)))
+	return u.GetCycleCountByID(ctx, count.ID)
+}
+
+func (u *cycleCountUseCase) GetCycleCountByID(ctx context.Context, id uuid.UUID) (*dtos.CycleCountResponse, error) {
+	count, err := u.repo.GetCycleCountByID(id)
+	if err != nil {
+		return nil, err
+	}
+	response := toCycleCountResponse(count, true)
+	return &response, nil
+}
+
+func (u *cycleCountUseCase) GetCycleCountsList(ctx context.Context, req dtos.CycleCountListRequest) ([]dtos.CycleCountResponse, dtos.Pagination, error) {
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.Limit == 0 {
+		req.Limit = 10
+	}
+	if err := u.validate.Struct(req); err != nil {
+		return nil, dtos.Pagination{}, err
+	}
+
+	counts, total, err := u.repo.GetCycleCountsList(req)
+	if err != nil {
+		return nil, dtos.Pagination{}, err
+	}
+
+	list := make([]dtos.CycleCountResponse, 0, len(counts))
+	for i := range counts {
+		list = append(list, toCycleCountResponse(&counts[i], false))
+	}
+	return list, buildPagination(req.Page, req.Limit, total), nil
+}
+
+// SubmitCycleCount mencatat hasil hitung penghitung (userID). Hitungan ulang oleh penghitung yang sama
+// menimpa hasil sebelumnya. Sebuah baris dianggap terhitung jika semua penghitung sepakat.
+func (u *cycleCountUseCase) SubmitCycleCount(ctx context.Context, id uuid.UUID, req dtos.SubmitCycleCountRequest, userID uuid.UUID) (*dtos.CycleCountResponse, error) {
+	if err := u.validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	err := u.repo.WithTransaction(func(repo repositorys.CycleCountRepository, stockRepo repositorys.ProductRepository) error {
+		count, err := repo.GetCycleCountForUpdate(id)
+		if err != nil {
+			return err
+		}
+		if count.Status != "open" {
+			return ErrCycleCountNotOpen
+		}
+
+		lines := make(map[uuid.UUID]*models.CycleCountLine, len(count.Lines))
+		for i := range count.Lines {
+			lines[count.Lines[i].ID] = &count.Lines[i]
+		}
+
+		now := time.Now()
+		for _, item := range req.Counts {
+			line, ok := lines[item.LineID]
+			if !ok {
+				return fmt.Errorf("cycle count line %s: %w", item.LineID, gorm.ErrRecordNotFound)
+			}
+			counted, err := baseQuantity(stockRepo, line.SourceProductID, item.Unit, item.CountedQuantity)
+			if err != nil {
+				return fmt.Errorf("line %s: %w", item.LineID, err)
+			}
+			if line.Product.Serialized {
+				if err := checkSerialList(item.SerialNumbers, counted); err != nil {
+					return fmt.Errorf("line %s: %w", item.LineID, err)
+				}
+			} else if len(item.SerialNumbers) > 0 {
+				return fmt.Errorf("line %s: %w", item.LineID, ErrProductNotSerialized)
+			}
+
+			entry := models.CycleCountEntry{
+				ID:               uuid.New(),
+				CycleCountLineID: line.ID,
+				CountedBy:        userID,
+				CountedQuantity:  counted,
+				SerialNumbers:    item.SerialNumbers,
+				CreatedAt:        now,
+				UpdatedAt:        now,
+			}
+			if err := repo.SaveCycleCountEntry(&entry); err != nil {
+				return err
+			}
+
+			replaced := false
+			for i := range line.Entries {
+				if line.Entries[i].CountedBy == userID {
+					line.Entries[i] = entry
+					replaced = true
+				}
+			}
+			if !replaced {
+				line.Entries = append(line.Entries, entry)
+			}
+			resolveCycleCountLine(line)
+			line.UpdatedAt = now
+			if err := repo.UpdateCycleCountLine(line); err != nil {
+				return err
+			}
+		}
+
+		count.UpdatedAt = now
+		return repo.UpdateCycleCount(count)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return u.GetCycleCountByID(ctx, id)
+}
+
+// ApproveCycleCount membukukan selisih setiap baris sebagai movement count_correction.
+// Selisih dihitung terhadap expected yang dibekukan, sehingga movement lain selama sesi tetap terjaga.
+func (u *cycleCountUseCase) ApproveCycleCount(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*dtos.CycleCountResponse, error) {
+	var countNumber string
+	err := u.repo.WithTransaction(func(repo repositorys.CycleCountRepository, stockRepo repositorys.ProductRepository) error {
+		count, err := repo.GetCycleCountForUpdate(id)
+		if err != nil {
+			return err
+		}
+		if count.Status != "open" {
+			return ErrCycleCountNotOpen
+		}
+		countNumber = count.CountNumber
+
+		unresolved := 0
+		for _, line := range count.Lines {
+			if line.CountedQuantity == nil {
+				unresolved++
+			}
+		}
+		if unresolved > 0 {
+			return fmt.Errorf("%w (%d line(s) pending or disputed)", ErrCycleCountUnresolved, unresolved)
+		}
+
+		base := stockMovementInput{
+			MovementType:  "count_correction",
+			Reason:        "cycle_count",
+			ReferenceType: "cycle_count",
+			ReferenceID:   &count.ID,
+			ReferenceNote: count.CountNumber,
+			UserID:        userID,
+		}
+		for _, line := range count.Lines {
+			in := base
+			in.ProductID = line.SourceProductID
+			in.WarehouseLocationID = line.WarehouseLocationID
+
+			if line.Product.Serialized {
+				current, err := repo.GetInStockSerialNumbers(line.SourceProductID, line.WarehouseLocationID)
+				if err != nil {
+					return err
+				}
+				missing, unexpected := serialCountVariance(line, current)
+				if len(missing) > 0 {
+					out := in
+					out.Direction, out.Quantity, out.SerialNumbers = -1, len(missing), missing
+					if _, err := applyStockMovement(stockRepo, u.costingMethod, out); err != nil {
+						return fmt.Errorf("line %s: %w", line.ID, err)
+					}
+				}
+				if len(unexpected) > 0 {
+					if err := relocateCountedSerials(stockRepo, u.costingMethod, in, unexpected); err != nil {
+						return fmt.Errorf("line %s: %w", line.ID, err)
+					}
+					found := in
+					found.Direction, found.Quantity, found.SerialNumbers = 1, len(unexpected), unexpected
+					if _, err := applyStockMovement(stockRepo, u.costingMethod, found); err != nil {
+						return fmt.Errorf("line %s: %w", line.ID, err)
+					}
+				}
+				continue
+			}
+
+			variance := *line.CountedQuantity - line.ExpectedQuantity
+			if variance == 0 {
+				continue
+			}
+			in.Direction, in.Quantity = 1, variance
+			if variance < 0 {
+				in.Direction, in.Quantity = -1, -variance
+			}
+			if line.Lot != nil {
+				in.LotNumber = line.Lot.LotNumber
+			}
+			if _, err := applyStockMovement(stockRepo, u.costingMethod, in); err != nil {
+				return fmt.Errorf("line %s: %w", line.ID, err)
+			}
+		}
+
+		// Koreksi hitung bisa membuat stok lebih kecil dari reserved; kekurangannya dicatat
+		// pada baris pertama stok tersebut agar reservasinya bisa dilepas atau dikurangi
+		checked := make(map[uuid.UUID]bool)
+		for i := range count.Lines {
+			line := &count.Lines[i]
+			if checked[line.ProductStockID] {
+				continue
+			}
+			checked[line.ProductStockID] = true
+			stock, err := stockRepo.GetProductStockByIDForUpdate(line.ProductStockID)
+			if err != nil {
+				return err
+			}
+			if shortfall := stock.ReservedQuantity - stock.Quantity; shortfall > 0 {
+				line.ReservedShortfall = shortfall
+				if err := repo.UpdateCycleCountLine(line); err != nil {
+					return err
+				}
+				u.log.Warn(fmt.Sprintf("Cycle count %s: stock %s is %d unit(s) short of its reservations", count.CountNumber, stock.ID, shortfall))
+			}
+		}
+
+		now := time.Now()
+		count.Status = "approved"
+		count.ApprovedBy = &userID
+		count.ApprovedAt = &now
+		count.UpdatedAt = now
+		return repo.UpdateCycleCount(count)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	u.log.Info(fmt.Sprintf("Cycle count %s approved", countNumber))
+	return u.GetCycleCountByID(ctx, id)
+}
+
+func (u *cycleCountUseCase) CancelCycleCount(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*dtos.CycleCountResponse, error) {
+	err := u.repo.WithTransaction(func(repo repositorys.CycleCountRepository, _ repositorys.ProductRepository) error {
+		count, err := repo.GetCycleCountForUpdate(id)
+		if err != nil {
+			return err
+		}
+		if count.Status != "open" {
+			return ErrCycleCountNotOpen
+		}
+
+		now := time.Now()
+		count.Status = "cancelled"
+		count.CancelledBy = &userID
+		count.CancelledAt = &now
+		count.UpdatedAt = now
+		return repo.UpdateCycleCount(count)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return u.GetCycleCountByID(ctx, id)
+}
+
+// resolveCycleCountLine mengisi hasil hitung baris jika semua entri sepakat, selain itu dikosongkan
+func resolveCycleCountLine(line *models.CycleCountLine) {
+	line.CountedQuantity = nil
+	line.CountedSerials = nil
+	if len(line.Entries) == 0 {
+		return
+	}
+
+	first := line.Entries[0]
+	serials := sortedSerials(first.SerialNumbers)
+	for _, entry := range line.Entries[1:] {
+		if entry.CountedQuantity != first.CountedQuantity || !slices.Equal(sortedSerials(entry.SerialNumbers), serials) {
+			return
+		}
+	}
+	quantity := first.CountedQuantity
+	line.CountedQuantity = &quantity
+	if len(serials) > 0 {
+		line.CountedSerials = serials
+	}
+}
+
+// serialCountVariance membandingkan serial hasil hitung dengan serial yang dibekukan:
+// missing = serial yang diharapkan, masih tercatat di lokasi, tapi tidak ditemukan;
+// unexpected = serial yang ditemukan tapi tidak diharapkan dan tidak tercatat di lokasi
+func serialCountVariance(line models.CycleCountLine, current []string) (missing, unexpected []string) {
+	counted := make(map[string]bool, len(line.CountedSerials))
+	for _, sn := range line.CountedSerials {
+		counted[sn] = true
+	}
+	expected := make(map[string]bool, len(line.ExpectedSerials))
+	for _, sn := range line.ExpectedSerials {
+		expected[sn] = true
+	}
+	inStock := make(map[string]bool, len(current))
+	for _, sn := range current {
+		inStock[sn] = true
+		if expected[sn] && !counted[sn] {
+			missing = append(missing, sn)
+		}
+	}
+	for _, sn := range line.CountedSerials {
+		if !expected[sn] && !inStock[sn] {
+			unexpected = append(unexpected, sn)
+		}
+	}
+	return missing, unexpected
+}
+
+// relocateCountedSerials memperlakukan serial tak terduga yang masih tercatat in_stock di lokasi lain
+// sebagai perpindahan: serial tersebut dikeluarkan dulu dari lokasi lamanya (count_correction keluar)
+// sebelum dicatat masuk di lokasi yang dihitung, sehingga approval tidak gagal karena serial sudah di stok
+func relocateCountedSerials(stockRepo repositorys.ProductRepository, costingMethod string, in stockMovementInput, serials []string) error {
+	var locations []uuid.UUID
+	byLocation := make(map[uuid.UUID][]string)
+	for _, sn := range serials {
+		serial, err := stockRepo.GetSerialNumberForUpdate(in.ProductID, sn)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if serial.Status != "in_stock" || serial.WarehouseLocationID == nil || *serial.WarehouseLocationID == in.WarehouseLocationID {
+			continue
+		}
+		if _, ok := byLocation[*serial.WarehouseLocationID]; !ok {
+			locations = append(locations, *serial.WarehouseLocationID)
+		}
+		byLocation[*serial.WarehouseLocationID] = append(byLocation[*serial.WarehouseLocationID], sn)
+	}
+
+	for _, locationID := range locations {
+		out := in
+		out.WarehouseLocationID = locationID
+		out.Direction, out.Quantity, out.SerialNumbers = -1, len(byLocation[locationID]), byLocation[locationID]
+		if _, err := applyStockMovement(stockRepo, costingMethod, out); err != nil {
+			return fmt.Errorf("relocating serials from location %s: %w", locationID, err)
+		}
+	}
+	return nil
+}
+
+func sortedSerials(serials []string) []string {
+	sorted := slices.Clone(serials)
+	slices.Sort(sorted)
+	return sorted
+}
+
+func toCycleCountResponse(c *models.CycleCount, withLines bool) dtos.CycleCountResponse {
+	response := dtos.CycleCountResponse{
+		ID:                  c.ID,
+		CountNumber:         c.CountNumber,
+		WarehouseLocationID: c.WarehouseLocationID,
+		ProductID:           c.SourceProductID,
+		Status:              c.Status,
+		Note:                c.Note,
+		CreatedBy:           c.CreatedBy,
+		ApprovedBy:          c.ApprovedBy,
+		ApprovedAt:          c.ApprovedAt,
+		CancelledBy:         c.CancelledBy,
+		CancelledAt:         c.CancelledAt,
+		CreatedAt:           c.CreatedAt,
+	}
+	if c.WarehouseLocation != nil {
+		response.WarehouseLocationName = c.WarehouseLocation.Name
+	}
+	if c.Product != nil {
+		response.ProductName = c.Product.Name
+	}
+	if !withLines {
+		return response
+	}
+
+	summary := &dtos.CycleCountSummary{TotalLines: len(c.Lines)}
+	lines := make([]dtos.CycleCountLineResponse, 0, len(c.Lines))
+	for _, l := range c.Lines {
+		line := dtos.CycleCountLineResponse{
+			ID:                    l.ID,
+			ProductStockID:        l.ProductStockID,
+			ProductID:             l.SourceProductID,
+			ProductName:           l.Product.Name,
+			SKU:                   l.Product.SKU,
+			WarehouseLocationID:   l.WarehouseLocationID,
+			WarehouseLocationName: l.WarehouseLocation.Name,
+			WarehouseLocationPath: l.WarehouseLocation.Path,
+			ExpectedQuantity:      l.ExpectedQuantity,
+			CountedQuantity:       l.CountedQuantity,
+			ReservedShortfall:     l.ReservedShortfall,
+			Entries:               make([]dtos.CycleCountEntryResponse, 0, len(l.Entries)),
+		}
+		if l.Lot != nil {
+			line.LotNumber = l.Lot.LotNumber
+		}
+		for _, e := range l.Entries {
+			line.Entries = append(line.Entries, dtos.CycleCountEntryResponse{
+				CountedBy:       e.CountedBy,
+				CountedQuantity: e.CountedQuantity,
+				SerialNumbers:   e.SerialNumbers,
+				UpdatedAt:       e.UpdatedAt,
+			})
+		}
+
+		switch {
+		case len(l.Entries) == 0:
+			line.Status = "pending"
+			summary.PendingLines++
+		case l.CountedQuantity == nil:
+			line.Status = "disputed"
+			summary.DisputedLines++
+		default:
+			line.Status = "counted"
+			variance := *l.CountedQuantity - l.ExpectedQuantity
+			line.Variance = &variance
+			if l.Product.Serialized {
+				line.MissingSerials, line.UnexpectedSerials = serialCountVariance(l, l.ExpectedSerials)
+			}
+			if variance != 0 || len(line.MissingSerials) > 0 || len(line.UnexpectedSerials) > 0 {
+				summary.VarianceLines++
+				summary.NetVariance += variance
+			}
+		}
+		lines = append(lines, line)
+	}
+
+	sort.SliceStable(lines, func(i, j int) bool {
+		if lines[i].WarehouseLocationPath != lines[j].WarehouseLocationPath {
+			return lines[i].WarehouseLocationPath < lines[j].WarehouseLocationPath
+		}
+		if lines[i].ProductName != lines[j].ProductName {
+			return lines[i].ProductName < lines[j].ProductName
+		}
+		return lines[i].LotNumber < lines[j].LotNumber
+	})
+	response.Summary = summary
+	response.Lines = lines
+	return response
+}
@@ -0,0 +1,221 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"auth-service/internal/dtos"
+	"auth-service/internal/models"
+	"auth-service/internal/repositorys"
+	"auth-service/internal/utils"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrReceiptLineNotOnOrder = errors.New("scanned item is not on this purchase order")
+	ErrOverReceiptLimit      = errors.New("received quantity exceeds the over-delivery tolerance")
+)
+
+// ReceivePurchaseOrder membukukan kedatangan barang untuk PO approved/partially_received.
+// Setiap baris diposting sebagai movement receipt yang merujuk ke PO dan di-put-away ke lokasinya.
+// PO menjadi closed bila semua baris lengkap (atau close_purchase_order), selain itu partially_received.
+func (u *purchaseOrderUseCase) ReceivePurchaseOrder(ctx context.Context, id uuid.UUID, req dtos.CreateGoodsReceiptRequest, userID uuid.UUID) (*dtos.GoodsReceiptResponse, error) {
+	if err := u.validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	var receiptID uuid.UUID
+	err := u.repo.WithTransaction(func(repo repositorys.PurchaseOrderRepository, stockRepo repositorys.ProductRepository) error {
+		order, err := repo.GetPurchaseOrderForUpdate(id)
+		if err != nil {
+			return err
+		}
+		if order.Status != "approved" && order.Status != "partially_received" {
+			return fmt.Errorf("%w: cannot receive a %s purchase order", ErrInvalidPurchaseOrderState, order.Status)
+		}
+
+		receipt := &models.GoodsReceipt{
+			ID:              uuid.New(),
+			ReceiptNumber:   utils.GenerateDocumentNumber("GRN"),
+			PurchaseOrderID: order.ID,
+			DeliveryNote:    req.DeliveryNote,
+			Note:            req.Note,
+			ReceivedBy:      userID,
+			CreatedAt:       time.Now(),
+		}
+		referenceNote := fmt.Sprintf("%s / %s", order.PONumber, receipt.ReceiptNumber)
+		if req.DeliveryNote != "" {
+			referenceNote += " / " + req.DeliveryNote
+		}
+
+		touched := make(map[uuid.UUID]*models.PurchaseOrderLine)
+		for _, item := range req.Lines {
+			line, err := matchPurchaseOrderLine(order, item)
+			if err != nil {
+				return err
+			}
+
+			locationID := order.WarehouseLocationID
+			if item.WarehouseLocationID != nil {
+				if _, err := stockRepo.GetWarehouseLocationByID(*item.WarehouseLocationID); err != nil {
+					return fmt.Errorf("put-away location not found: %w", err)
+				}
+				locationID = *item.WarehouseLocationID
+			}
+			expiryDate, err := parseExpiryDate(item.ExpiryDate)
+			if err != nil {
+				return err
+			}
+
+			quantity, err := baseQuantity(stockRepo, line.SourceProductID, item.Unit, item.Quantity)
+			if err != nil {
+				return fmt.Errorf("%s: %w", line.Product.SKU, err)
+			}
+
+			line.ReceivedQuantity += quantity
+			if limit := line.Quantity + line.Quantity*u.overReceiptTolerance/100; line.ReceivedQuantity > limit {
+				return fmt.Errorf("%s: %d received, at most %d allowed: %w", line.Product.SKU, line.ReceivedQuantity, limit, ErrOverReceiptLimit)
+			}
+			touched[line.ID] = line
+
+			if _, err := applyStockMovement(stockRepo, u.costingMethod, stockMovementInput{
+				ProductID:           line.SourceProductID,
+				WarehouseLocationID: locationID,
+				MovementType:        "receipt",
+				Quantity:            quantity,
+				LotNumber:           item.LotNumber,
+				ExpiryDate:          expiryDate,
+				SerialNumbers:       item.SerialNumbers,
+				Reason:              "purchase_receipt",
+				ReferenceType:       "purchase_order",
+				ReferenceID:         &order.ID,
+				ReferenceNote:       referenceNote,
+				UserID:              userID,
+				UnitCost:            &line.UnitCost,
+			}); err != nil {
+				return fmt.Errorf("%s: %w", line.Product.SKU, err)
+			}
+
+			receipt.Lines = append(receipt.Lines, models.GoodsReceiptLine{
+				ID:                  uuid.New(),
+				GoodsReceiptID:      receipt.ID,
+				PurchaseOrderLineID: line.ID,
+				SourceProductID:     line.SourceProductID,
+				WarehouseLocationID: locationID,
+				Quantity:            quantity,
+				LotNumber:           item.LotNumber,
+				ExpiryDate:          expiryDate,
+				SerialNumbers:       item.SerialNumbers,
+				CreatedAt:           receipt.CreatedAt,
+			})
+		}
+
+		now := time.Now()
+		for _, line := range touched {
+			line.UpdatedAt = now
+			if err := repo.UpdatePurchaseOrderLine(line); err != nil {
+				return err
+			}
+		}
+		if err := repo.CreateGoodsReceipt(receipt); err != nil {
+			return err
+		}
+		receiptID = receipt.ID
+
+		complete := true
+		for _, line := range order.Lines {
+			if line.ReceivedQuantity < line.Quantity {
+				complete = false
+				break
+			}
+		}
+		if complete || req.ClosePurchaseOrder {
+			return closePurchaseOrder(repo, order, userID)
+		}
+		order.Status = "partially_received"
+		order.UpdatedAt = now
+		return repo.UpdatePurchaseOrder(order)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	receipt, err := u.repo.GetGoodsReceiptByID(receiptID)
+	if err != nil {
+		return nil, err
+	}
+	response := toGoodsReceiptResponse(receipt)
+	if response.PurchaseOrder, err = u.GetPurchaseOrderByID(ctx, id); err != nil {
+		return nil, err
+	}
+	u.log.Info(fmt.Sprintf("Goods receipt %s posted for purchase order %s", receipt.ReceiptNumber, id))
+	return response, nil
+}
+
+// matchPurchaseOrderLine mencari baris PO dari id-nya atau dari code hasil scan (SKU produk / SKU supplier)
+func matchPurchaseOrderLine(order *models.PurchaseOrder, item dtos.GoodsReceiptLineRequest) (*models.PurchaseOrderLine, error) {
+	for i := range order.Lines {
+		line := &order.Lines[i]
+		if item.PurchaseOrderLineID != nil {
+			if line.ID == *item.PurchaseOrderLineID {
+				return line, nil
+			}
+			continue
+		}
+		if strings.EqualFold(line.Product.SKU, item.Code) || (line.SupplierSKU != "" && strings.EqualFold(line.SupplierSKU, item.Code)) {
+			return line, nil
+		}
+	}
+	if item.PurchaseOrderLineID != nil {
+		return nil, fmt.Errorf("%w: line %s", ErrReceiptLineNotOnOrder, *item.PurchaseOrderLineID)
+	}
+	return nil, fmt.Errorf("%w: %s", ErrReceiptLineNotOnOrder, item.Code)
+}
+
+func (u *purchaseOrderUseCase) GetGoodsReceipts(ctx context.Context, id uuid.UUID) ([]dtos.GoodsReceiptResponse, error) {
+	if _, err := u.repo.GetPurchaseOrderByID(id); err != nil {
+		return nil, err
+	}
+	receipts, err := u.repo.GetGoodsReceiptsByOrderID(id)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]dtos.GoodsReceiptResponse, 0, len(receipts))
+	for i := range receipts {
+		list = append(list, *toGoodsReceiptResponse(&receipts[i]))
+	}
+	return list, nil
+}
+
+func toGoodsReceiptResponse(r *models.GoodsReceipt) *dtos.GoodsReceiptResponse {
+	response := &dtos.GoodsReceiptResponse{
+		ID:              r.ID,
+		ReceiptNumber:   r.ReceiptNumber,
+		PurchaseOrderID: r.PurchaseOrderID,
+		DeliveryNote:    r.DeliveryNote,
+		Note:            r.Note,
+		ReceivedBy:      r.ReceivedBy,
+		CreatedAt:       r.CreatedAt,
+		Lines:           make([]dtos.GoodsReceiptLineResponse, 0, len(r.Lines)),
+	}
+	for _, line := range r.Lines {
+		response.Lines = append(response.Lines, dtos.GoodsReceiptLineResponse{
+			ID:                    line.ID,
+			PurchaseOrderLineID:   line.PurchaseOrderLineID,
+			ProductID:             line.SourceProductID,
+			ProductName:           line.Product.Name,
+			SKU:                   line.Product.SKU,
+			WarehouseLocationID:   line.WarehouseLocationID,
+			WarehouseLocationName: line.WarehouseLocation.Name,
+			Quantity:              line.Quantity,
+			LotNumber:             line.LotNumber,
+			ExpiryDate:            line.ExpiryDate,
+			SerialNumbers:         line.SerialNumbers,
+		})
+	}
+	return response
+}
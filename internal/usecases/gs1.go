@@ -0,0 +1,243 @@
+package usecases
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidGTIN = errors.New("invalid GTIN check digit")
+	ErrInvalidGS1  = errors.New("invalid GS1 barcode data")
+)
+
+const (
+	SymbologyEAN8    = "ean8"
+	SymbologyUPCA    = "upca"
+	SymbologyEAN13   = "ean13"
+	SymbologyGTIN14  = "gtin14"
+	SymbologyCode128 = "code128"
+
+	// gs1GroupSeparator (FNC1) memisahkan element GS1 yang panjangnya variabel
+	gs1GroupSeparator = "\x1d"
+)
+
+// Application identifier GS1 yang dikenali. AI 91 (internal perusahaan) dipakai untuk path lokasi gudang.
+const (
+	AIGTIN         = "01"
+	AIContentGTIN  = "02"
+	AILotNumber    = "10"
+	AIExpiryDate   = "17"
+	AISerialNumber = "21"
+	AILocationPath = "91"
+)
+
+// gs1ElementLength; fixed true berarti panjang data tepat length, selain itu maksimal length
+type gs1ElementLength struct {
+	length int
+	fixed  bool
+}
+
+var gs1Elements = map[string]gs1ElementLength{
+	"00":  {18, true}, // SSCC
+	"01":  {14, true},
+	"02":  {14, true},
+	"10":  {20, false},
+	"11":  {6, true}, // tanggal produksi
+	"12":  {6, true},
+	"13":  {6, true},
+	"15":  {6, true}, // best before
+	"16":  {6, true},
+	"17":  {6, true},
+	"20":  {2, true},
+	"21":  {20, false},
+	"30":  {8, false}, // jumlah
+	"37":  {8, false},
+	"91":  {90, false},
+	"92":  {90, false},
+	"240": {30, false},
+	"241": {30, false},
+	"400": {30, false},
+	"410": {13, true},
+	"414": {13, true},
+	"254": {20, false},
+}
+
+// gs1SymbologyPrefixes adalah symbology identifier yang dikirim scanner di depan data GS1
+var gs1SymbologyPrefixes = []string{"]C1", "]d2", "]Q3", "]e0", "]J1"}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// gtinCheckDigit menghitung check digit GS1 mod 10 untuk digit tanpa check digit
+func gtinCheckDigit(digits string) byte {
+	sum := 0
+	for i := 0; i < len(digits); i++ {
+		d := int(digits[len(digits)-1-i] - '0')
+		if i%2 == 0 {
+			d *= 3
+		}
+		sum += d
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+// gtinSymbology mengembalikan symbology untuk panjang GTIN, atau "" bila bukan panjang GTIN
+func gtinSymbology(code string) string {
+	if !isDigits(code) {
+		return ""
+	}
+	switch len(code) {
+	case 8:
+		return SymbologyEAN8
+	case 12:
+		return SymbologyUPCA
+	case 13:
+		return SymbologyEAN13
+	case 14:
+		return SymbologyGTIN14
+	}
+	return ""
+}
+
+// normalizeGTIN memvalidasi check digit dan mengembalikan GTIN dalam bentuk 14 digit,
+// sehingga UPC-A dan EAN-13 berawalan 0 dikenali sebagai GTIN yang sama
+func normalizeGTIN(code string) (string, error) {
+	if gtinSymbology(code) == "" {
+		return "", fmt.Errorf("%w: %s is not an 8, 12, 13 or 14 digit GTIN", ErrInvalidGTIN, code)
+	}
+	if gtinCheckDigit(code[:len(code)-1]) != code[len(code)-1] {
+		return "", fmt.Errorf("%w: %s", ErrInvalidGTIN, code)
+	}
+	return strings.Repeat("0", 14-len(code)) + code, nil
+}
+
+// isGS1Data mengenali data GS1 dari symbology identifier, separator FNC1 atau AI berkurung
+func isGS1Data(code string) bool {
+	for _, prefix := range gs1SymbologyPrefixes {
+		if strings.HasPrefix(code, prefix) {
+			return true
+		}
+	}
+	return strings.Contains(code, gs1GroupSeparator) || strings.HasPrefix(code, "(")
+}
+
+// parseGS1 mengurai data GS1-128/DataMatrix menjadi element per AI. Diterima bentuk mentah
+// (opsional diawali symbology identifier, element variabel diakhiri FNC1) maupun bentuk
+// human readable "(01)...(10)...".
+func parseGS1(code string) (map[string]string, error) {
+	for _, prefix := range gs1SymbologyPrefixes {
+		code = strings.TrimPrefix(code, prefix)
+	}
+	code = strings.TrimPrefix(code, gs1GroupSeparator)
+	if strings.HasPrefix(code, "(") {
+		return parseGS1Bracketed(code)
+	}
+
+	elements := map[string]string{}
+	for code != "" {
+		ai, spec, ok := gs1ElementAt(code)
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown application identifier at %q", ErrInvalidGS1, code)
+		}
+		data := code[len(ai):]
+		var value string
+		if spec.fixed {
+			if len(data) < spec.length {
+				return nil, fmt.Errorf("%w: AI %s needs %d characters", ErrInvalidGS1, ai, spec.length)
+			}
+			value, data = data[:spec.length], data[spec.length:]
+			data = strings.TrimPrefix(data, gs1GroupSeparator)
+		} else {
+			value, data, _ = strings.Cut(data, gs1GroupSeparator)
+		}
+		if err := setGS1Element(elements, ai, spec, value); err != nil {
+			return nil, err
+		}
+		code = data
+	}
+	return elements, nil
+}
+
+func parseGS1Bracketed(code string) (map[string]string, error) {
+	elements := map[string]string{}
+	for code != "" {
+		if !strings.HasPrefix(code, "(") {
+			return nil, fmt.Errorf("%w: expected '(' at %q", ErrInvalidGS1, code)
+		}
+		ai, rest, ok := strings.Cut(code[1:], ")")
+		if !ok {
+			return nil, fmt.Errorf("%w: unterminated application identifier", ErrInvalidGS1)
+		}
+		spec, known := gs1Elements[ai]
+		if !known {
+			return nil, fmt.Errorf("%w: unknown application identifier %s", ErrInvalidGS1, ai)
+		}
+		value := rest
+		if i := strings.Index(rest, "("); i >= 0 {
+			value = rest[:i]
+		}
+		if err := setGS1Element(elements, ai, spec, value); err != nil {
+			return nil, err
+		}
+		code = rest[len(value):]
+	}
+	return elements, nil
+}
+
+// gs1ElementAt mencari AI (2 sampai 4 digit) yang dikenal di awal data
+func gs1ElementAt(code string) (string, gs1ElementLength, bool) {
+	for n := 2; n <= 4 && n <= len(code); n++ {
+		if spec, ok := gs1Elements[code[:n]]; ok {
+			return code[:n], spec, true
+		}
+	}
+	return "", gs1ElementLength{}, false
+}
+
+func setGS1Element(elements map[string]string, ai string, spec gs1ElementLength, value string) error {
+	switch {
+	case value == "":
+		return fmt.Errorf("%w: AI %s is empty", ErrInvalidGS1, ai)
+	case spec.fixed && len(value) != spec.length:
+		return fmt.Errorf("%w: AI %s needs %d characters", ErrInvalidGS1, ai, spec.length)
+	case !spec.fixed && len(value) > spec.length:
+		return fmt.Errorf("%w: AI %s is longer than %d characters", ErrInvalidGS1, ai, spec.length)
+	}
+	if _, duplicate := elements[ai]; duplicate {
+		return fmt.Errorf("%w: AI %s appears twice", ErrInvalidGS1, ai)
+	}
+	elements[ai] = value
+	return nil
+}
+
+// parseGS1Date mengubah tanggal GS1 YYMMDD; hari 00 berarti hari terakhir bulan tersebut
+func parseGS1Date(value string) (time.Time, error) {
+	if len(value) != 6 || !isDigits(value) {
+		return time.Time{}, fmt.Errorf("%w: date %q must be YYMMDD", ErrInvalidGS1, value)
+	}
+	year := 2000 + int(value[0]-'0')*10 + int(value[1]-'0')
+	month := time.Month(int(value[2]-'0')*10 + int(value[3]-'0'))
+	day := int(value[4]-'0')*10 + int(value[5]-'0')
+	if month < 1 || month > 12 {
+		return time.Time{}, fmt.Errorf("%w: date %q has an invalid month", ErrInvalidGS1, value)
+	}
+	if day == 0 {
+		return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC), nil
+	}
+	date := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	if date.Day() != day {
+		return time.Time{}, fmt.Errorf("%w: date %q has an invalid day", ErrInvalidGS1, value)
+	}
+	return date, nil
+}
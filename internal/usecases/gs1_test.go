@@ -0,0 +1,191 @@
+package usecases
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGTINCheckDigit(t *testing.T) {
+	tests := []struct {
+		digits string
+		want   byte
+	}{
+		{"9638507", '4'},       // EAN-8
+		{"03600029145", '2'},   // UPC-A
+		{"400638133393", '1'},  // EAN-13
+		{"590123412345", '7'},  // EAN-13
+		{"1001234567890", '2'}, // GTIN-14
+		{"0001234560001", '2'}, // GTIN-14
+		{"0000000000000", '0'}, // check digit 0
+		{"7351353", '7'},       // EAN-8
+	}
+	for _, tt := range tests {
+		t.Run(tt.digits, func(t *testing.T) {
+			assert.Equal(t, string(tt.want), string(gtinCheckDigit(tt.digits)))
+		})
+	}
+}
+
+func TestNormalizeGTIN(t *testing.T) {
+	tests := []struct {
+		name    string
+		code    string
+		want    string
+		wantErr bool
+	}{
+		{name: "ean8", code: "96385074", want: "00000096385074"},
+		{name: "upca", code: "036000291452", want: "00036000291452"},
+		{name: "ean13", code: "4006381333931", want: "04006381333931"},
+		{name: "ean13 with leading zero equals upca", code: "0036000291452", want: "00036000291452"},
+		{name: "gtin14", code: "10012345678902", want: "10012345678902"},
+		{name: "ean8 bad check digit", code: "96385075", wantErr: true},
+		{name: "upca bad check digit", code: "036000291453", wantErr: true},
+		{name: "ean13 bad check digit", code: "4006381333932", wantErr: true},
+		{name: "gtin14 bad check digit", code: "10012345678901", wantErr: true},
+		{name: "unsupported length", code: "1234567890", wantErr: true},
+		{name: "non digits", code: "40063813339A1", wantErr: true},
+		{name: "empty", code: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeGTIN(tt.code)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidGTIN)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseGS1(t *testing.T) {
+	tests := []struct {
+		name    string
+		code    string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name: "raw with symbology identifier and FNC1 after variable element",
+			code: "]C10110012345678902" + "17261231" + "10LOT42" + gs1GroupSeparator + "21SN-1",
+			want: map[string]string{"01": "10012345678902", "17": "261231", "10": "LOT42", "21": "SN-1"},
+		},
+		{
+			name: "datamatrix identifier and leading FNC1",
+			code: "]d2" + gs1GroupSeparator + "0110012345678902" + "10ABC",
+			want: map[string]string{"01": "10012345678902", "10": "ABC"},
+		},
+		{
+			name: "redundant FNC1 after fixed element",
+			code: "0110012345678902" + gs1GroupSeparator + "21X9",
+			want: map[string]string{"01": "10012345678902", "21": "X9"},
+		},
+		{
+			name: "three digit application identifier",
+			code: "0110012345678902" + "240PART-7",
+			want: map[string]string{"01": "10012345678902", "240": "PART-7"},
+		},
+		{
+			name: "location path in AI 91",
+			code: "91WH1/A/01/B3",
+			want: map[string]string{"91": "WH1/A/01/B3"},
+		},
+		{
+			name: "bracketed form is delegated",
+			code: "(01)10012345678902(10)LOT42",
+			want: map[string]string{"01": "10012345678902", "10": "LOT42"},
+		},
+		{name: "unknown application identifier", code: "9912345", wantErr: true},
+		{name: "fixed element too short", code: "0112345", wantErr: true},
+		{name: "variable element too long", code: "10" + strings.Repeat("A", 21), wantErr: true},
+		{name: "empty variable element", code: "10" + gs1GroupSeparator + "21X", wantErr: true},
+		{name: "duplicate application identifier", code: "10A" + gs1GroupSeparator + "10B", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseGS1(tt.code)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidGS1)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseGS1Bracketed(t *testing.T) {
+	tests := []struct {
+		name    string
+		code    string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name: "fixed and variable elements",
+			code: "(01)10012345678902(17)261231(10)LOT42(21)SN-1",
+			want: map[string]string{"01": "10012345678902", "17": "261231", "10": "LOT42", "21": "SN-1"},
+		},
+		{
+			name: "variable element does not need FNC1",
+			code: "(10)LOT42(01)10012345678902",
+			want: map[string]string{"10": "LOT42", "01": "10012345678902"},
+		},
+		{name: "fixed element with wrong length", code: "(01)1001234567890", wantErr: true},
+		{name: "variable element too long", code: "(21)" + strings.Repeat("9", 21), wantErr: true},
+		{name: "unknown application identifier", code: "(99)X", wantErr: true},
+		{name: "unterminated application identifier", code: "(01", wantErr: true},
+		{name: "empty element", code: "(10)(21)X", wantErr: true},
+		{name: "data before the first bracket", code: "X(10)A", wantErr: true},
+		{name: "duplicate application identifier", code: "(10)A(10)B", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseGS1Bracketed(tt.code)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidGS1)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseGS1Date(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{value: "261231", want: "2026-12-31"},
+		{value: "250115", want: "2025-01-15"},
+		{value: "240200", want: "2024-02-29"}, // hari 00 pada tahun kabisat
+		{value: "230200", want: "2023-02-28"},
+		{value: "241200", want: "2024-12-31"},
+		{value: "250400", want: "2025-04-30"},
+		{value: "240230", wantErr: true},
+		{value: "250431", wantErr: true},
+		{value: "251301", wantErr: true},
+		{value: "250001", wantErr: true},
+		{value: "2512", wantErr: true},
+		{value: "25AB01", wantErr: true},
+		{value: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseGS1Date(tt.value)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidGS1)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.Format(time.DateOnly))
+		})
+	}
+}
@@ -0,0 +1,445 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"auth-service/internal/dtos"
+	"auth-service/internal/models"
+	"auth-service/internal/repositorys"
+	"auth-service/internal/utils"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrKitContainsItself    = errors.New("a kit cannot be a component of itself")
+	ErrNestedKit            = errors.New("kits cannot be nested: a component cannot have its own bill of materials")
+	ErrProductNotKit        = errors.New("product has no bill of materials")
+	ErrComponentNotInKitBOM = errors.New("component is not in the bill of materials of this kit")
+)
+
+type KitUseCase interface {
+	GetBillOfMaterials(ctx context.Context, kitID uuid.UUID) (*dtos.BillOfMaterialsResponse, error)
+	SetBillOfMaterials(ctx context.Context, kitID uuid.UUID, req dtos.SetBillOfMaterialsRequest) (*dtos.BillOfMaterialsResponse, error)
+	AssembleKit(ctx context.Context, kitID uuid.UUID, req dtos.KitAssemblyRequest, userID uuid.UUID) (*dtos.KitAssemblyResponse, error)
+	DisassembleKit(ctx context.Context, kitID uuid.UUID, req dtos.KitAssemblyRequest, userID uuid.UUID) (*dtos.KitAssemblyResponse, error)
+	GetKitAssemblyByID(ctx context.Context, id uuid.UUID) (*dtos.KitAssemblyResponse, error)
+	GetKitAssembliesList(ctx context.Context, req dtos.KitAssemblyListRequest) ([]dtos.KitAssemblyResponse, dtos.Pagination, error)
+}
+
+type kitUseCase struct {
+	repo        repositorys.KitRepository
+	productRepo repositorys.ProductRepository
+	// costingMethod adalah metode valuasi default untuk produk tanpa costing_method (valuation.default_method)
+	costingMethod string
+	validate      *validator.Validate
+	log           *logrus.Logger
+}
+
+func NewKitUseCase(repo repositorys.KitRepository, productRepo repositorys.ProductRepository, costingMethod string, log *logrus.Logger, validate *validator.Validate) KitUseCase {
+	return &kitUseCase{repo: repo, productRepo: productRepo, costingMethod: costingMethod, log: log, validate: validate}
+}
+
+func (u *kitUseCase) GetBillOfMaterials(ctx context.Context, kitID uuid.UUID) (*dtos.BillOfMaterialsResponse, error) {
+	product, err := u.productRepo.GetProductByID(kitID)
+	if err != nil {
+		return nil, err
+	}
+	return billOfMaterialsFor(u.productRepo, product)
+}
+
+// SetBillOfMaterials mengganti BOM kit. Kit hanya satu level: komponen tidak boleh punya BOM
+// sendiri dan produk yang sudah menjadi komponen kit lain tidak boleh menjadi kit.
+func (u *kitUseCase) SetBillOfMaterials(ctx context.Context, kitID uuid.UUID, req dtos.SetBillOfMaterialsRequest) (*dtos.BillOfMaterialsResponse, error) {
+	if err := u.validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	err := u.repo.WithTransaction(func(repo repositorys.KitRepository, stockRepo repositorys.ProductRepository) error {
+		kit, err := stockRepo.GetProductByID(kitID)
+		if err != nil {
+			return err
+		}
+		if len(req.Components) > 0 {
+			if isVariantParent(kit) {
+				return fmt.Errorf("%w: %s", ErrVariantParentStock, kit.SKU)
+			}
+			isComponent, err := repo.IsKitComponent(kit.ID)
+			if err != nil {
+				return err
+			}
+			if isComponent {
+				return fmt.Errorf("%w: %s is a component of another kit", ErrNestedKit, kit.SKU)
+			}
+		}
+
+		// Baris dengan komponen yang sama digabung
+		quantities := make(map[uuid.UUID]int)
+		var componentOrder []uuid.UUID
+		for _, item := range req.Components {
+			if _, ok := quantities[item.ProductID]; !ok {
+				componentOrder = append(componentOrder, item.ProductID)
+			}
+			quantities[item.ProductID] += item.Quantity
+		}
+
+		now := time.Now()
+		components := make([]models.ProductComponent, 0, len(componentOrder))
+		for _, productID := range componentOrder {
+			if productID == kit.ID {
+				return ErrKitContainsItself
+			}
+			component, err := stockRepo.GetProductByID(productID)
+			if err != nil {
+				return fmt.Errorf("component %s not found: %w", productID, err)
+			}
+			if isVariantParent(component) {
+				return fmt.Errorf("%w: %s", ErrVariantParentStock, component.SKU)
+			}
+			nested, err := stockRepo.GetProductComponents(component.ID)
+			if err != nil {
+				return err
+			}
+			if len(nested) > 0 {
+				return fmt.Errorf("%w: %s", ErrNestedKit, component.SKU)
+			}
+			components = append(components, models.ProductComponent{
+				ID:                 uuid.New(),
+				KitProductID:       kit.ID,
+				ComponentProductID: component.ID,
+				Quantity:           quantities[productID],
+				CreatedAt:          now,
+				UpdatedAt:          now,
+			})
+		}
+		return repo.ReplaceProductComponents(kit.ID, components)
+	})
+	if err != nil {
+		return nil, err
+	}
+	u.log.Info(fmt.Sprintf("Bill of materials of product %s updated", kitID))
+	return u.GetBillOfMaterials(ctx, kitID)
+}
+
+// AssembleKit mengonsumsi komponen (assembly_out) dan menghasilkan kit (assembly_in) di lokasi yang sama
+func (u *kitUseCase) AssembleKit(ctx context.Context, kitID uuid.UUID, req dtos.KitAssemblyRequest, userID uuid.UUID) (*dtos.KitAssemblyResponse, error) {
+	return u.runKitAssembly(ctx, kitID, "assemble", req, userID)
+}
+
+// DisassembleKit membongkar kit (assembly_out) dan mengembalikan komponennya ke stok (assembly_in)
+func (u *kitUseCase) DisassembleKit(ctx context.Context, kitID uuid.UUID, req dtos.KitAssemblyRequest, userID uuid.UUID) (*dtos.KitAssemblyResponse, error) {
+	return u.runKitAssembly(ctx, kitID, "disassemble", req, userID)
+}
+
+// runKitAssembly membukukan seluruh movement komponen dan kit dalam satu transaksi.
+// Semua entri merujuk ke KitAssembly yang sama (reference_type kit_assembly).
+func (u *kitUseCase) runKitAssembly(ctx context.Context, kitID uuid.UUID, operation string, req dtos.KitAssemblyRequest, userID uuid.UUID) (*dtos.KitAssemblyResponse, error) {
+	if err := u.validate.Struct(req); err != nil {
+		return nil, err
+	}
+	kitExpiry, err := parseExpiryDate(req.ExpiryDate)
+	if err != nil {
+		return nil, err
+	}
+
+	var assemblyID uuid.UUID
+	var quantity int
+	err = u.repo.WithTransaction(func(repo repositorys.KitRepository, stockRepo repositorys.ProductRepository) error {
+		kit, err := stockRepo.GetProductByID(kitID)
+		if err != nil {
+			return err
+		}
+		components, err := stockRepo.GetProductComponents(kit.ID)
+		if err != nil {
+			return err
+		}
+		if len(components) == 0 {
+			return fmt.Errorf("%w: %s", ErrProductNotKit, kit.SKU)
+		}
+		if quantity, err = baseQuantity(stockRepo, kit.ID, req.Unit, req.Quantity); err != nil {
+			return fmt.Errorf("%s: %w", kit.SKU, err)
+		}
+		if _, err := stockRepo.GetWarehouseLocationByID(req.WarehouseLocationID); err != nil {
+			return fmt.Errorf("warehouse location not found: %w", err)
+		}
+
+		selections := make(map[uuid.UUID]dtos.KitComponentSelection, len(req.Components))
+		for _, selection := range req.Components {
+			selections[selection.ProductID] = selection
+		}
+		for productID := range selections {
+			found := false
+			for _, component := range components {
+				if component.ComponentProductID == productID {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return fmt.Errorf("%w: %s", ErrComponentNotInKitBOM, productID)
+			}
+		}
+
+		assembly := &models.KitAssembly{
+			ID:                  uuid.New(),
+			AssemblyNumber:      utils.GenerateDocumentNumber("KIT"),
+			Operation:           operation,
+			KitProductID:        kit.ID,
+			WarehouseLocationID: req.WarehouseLocationID,
+			Quantity:            quantity,
+			Note:                req.Note,
+			CreatedBy:           userID,
+			CreatedAt:           time.Now(),
+		}
+		if err := repo.CreateKitAssembly(assembly); err != nil {
+			return err
+		}
+		assemblyID = assembly.ID
+
+		reason := "kit_assembly"
+		kitType, componentType := "assembly_in", "assembly_out"
+		if operation == "disassemble" {
+			reason = "kit_disassembly"
+			kitType, componentType = "assembly_out", "assembly_in"
+		}
+		base := stockMovementInput{
+			WarehouseLocationID: req.WarehouseLocationID,
+			Reason:              reason,
+			ReferenceType:       "kit_assembly",
+			ReferenceID:         &assembly.ID,
+			ReferenceNote:       fmt.Sprintf("%s / %s", assembly.AssemblyNumber, kit.SKU),
+			UserID:              userID,
+		}
+
+		// Nilai kit hasil rakitan adalah harga pokok komponen yang dikonsumsi; saat dibongkar,
+		// harga pokok kit dibagikan kembali ke komponennya
+		var consumedCost float64
+		postKit := func() error {
+			in := base
+			in.ProductID = kit.ID
+			in.MovementType = kitType
+			in.Quantity = quantity
+			in.LotNumber = req.LotNumber
+			in.ExpiryDate = kitExpiry
+			in.SerialNumbers = req.SerialNumbers
+			if operation == "assemble" {
+				unitCost := consumedCost / float64(quantity)
+				in.UnitCost = &unitCost
+			}
+			movements, err := applyStockMovement(stockRepo, u.costingMethod, in)
+			if err != nil {
+				return fmt.Errorf("%s: %w", kit.SKU, err)
+			}
+			for _, m := range movements {
+				consumedCost += m.TotalCost
+			}
+			return nil
+		}
+		postComponents := func() error {
+			var unitCosts map[uuid.UUID]float64
+			if operation == "disassemble" {
+				if unitCosts, err = allocateKitCost(stockRepo, components, quantity, consumedCost); err != nil {
+					return err
+				}
+			}
+			for _, component := range components {
+				selection := selections[component.ComponentProductID]
+				expiryDate, err := parseExpiryDate(selection.ExpiryDate)
+				if err != nil {
+					return err
+				}
+				in := base
+				in.ProductID = component.ComponentProductID
+				in.MovementType = componentType
+				in.Quantity = component.Quantity * quantity
+				in.LotNumber = selection.LotNumber
+				in.ExpiryDate = expiryDate
+				in.SerialNumbers = selection.SerialNumbers
+				if unitCost, ok := unitCosts[component.ComponentProductID]; ok {
+					in.UnitCost = &unitCost
+				}
+				movements, err := applyStockMovement(stockRepo, u.costingMethod, in)
+				if err != nil {
+					return fmt.Errorf("%s: %w", component.Component.SKU, err)
+				}
+				for _, m := range movements {
+					consumedCost += m.TotalCost
+				}
+			}
+			return nil
+		}
+
+		// Stok keluar dibukukan lebih dulu agar urutan ledger mengikuti urutan fisik
+		if operation == "assemble" {
+			if err := postComponents(); err != nil {
+				return err
+			}
+			return postKit()
+		}
+		if err := postKit(); err != nil {
+			return err
+		}
+		return postComponents()
+	})
+	if err != nil {
+		return nil, err
+	}
+	u.log.Info(fmt.Sprintf("Kit %s: %s %d unit(s)", kitID, operation, quantity))
+	return u.GetKitAssemblyByID(ctx, assemblyID)
+}
+
+func (u *kitUseCase) GetKitAssemblyByID(ctx context.Context, id uuid.UUID) (*dtos.KitAssemblyResponse, error) {
+	assembly, err := u.repo.GetKitAssemblyByID(id)
+	if err != nil {
+		return nil, err
+	}
+	movements, err := u.repo.GetKitAssemblyMovements(id)
+	if err != nil {
+		return nil, err
+	}
+	response := toKitAssemblyResponse(assembly)
+	for i := range movements {
+		response.Movements = append(response.Movements, *toStockMovementResponse(&movements[i]))
+	}
+	return response, nil
+}
+
+func (u *kitUseCase) GetKitAssembliesList(ctx context.Context, req dtos.KitAssemblyListRequest) ([]dtos.KitAssemblyResponse, dtos.Pagination, error) {
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.Limit == 0 {
+		req.Limit = 10
+	}
+	if err := u.validate.Struct(req); err != nil {
+		return nil, dtos.Pagination{}, err
+	}
+
+	assemblies, total, err := u.repo.GetKitAssembliesList(req)
+	if err != nil {
+		return nil, dtos.Pagination{}, err
+	}
+
+	list := make([]dtos.KitAssemblyResponse, 0, len(assemblies))
+	for i := range assemblies {
+		list = append(list, *toKitAssemblyResponse(&assemblies[i]))
+	}
+	return list, buildPagination(req.Page, req.Limit, total), nil
+}
+
+// billOfMaterialsFor menyusun BOM produk beserta jumlah kit yang bisa dirakit dari stok komponen
+// yang tersedia (on hand - reserved, bukan karantina). Perakitan terjadi di satu lokasi, sehingga
+// kapasitas dihitung per lokasi lalu dijumlahkan.
+func billOfMaterialsFor(repo repositorys.ProductRepository, product *models.Product) (*dtos.BillOfMaterialsResponse, error) {
+	response := &dtos.BillOfMaterialsResponse{
+		ProductID:           product.ID,
+		ProductName:         product.Name,
+		SKU:                 product.SKU,
+		Components:          []dtos.ProductComponentResponse{},
+		BuildableByLocation: []dtos.KitBuildableLocationResponse{},
+	}
+	components, err := repo.GetProductComponents(product.ID)
+	if err != nil || len(components) == 0 {
+		return response, err
+	}
+
+	componentIDs := make([]uuid.UUID, 0, len(components))
+	for _, component := range components {
+		componentIDs = append(componentIDs, component.ComponentProductID)
+	}
+	rows, err := repo.GetComponentAvailability(componentIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	totals := make(map[uuid.UUID]int)
+	available := make(map[uuid.UUID]map[uuid.UUID]int)
+	var locations []dtos.KitBuildableLocationResponse // urut path lokasi
+	for _, row := range rows {
+		totals[row.ProductID] += row.Available
+		if _, ok := available[row.WarehouseLocationID]; !ok {
+			available[row.WarehouseLocationID] = make(map[uuid.UUID]int)
+			locations = append(locations, dtos.KitBuildableLocationResponse{
+				WarehouseLocationID:   row.WarehouseLocationID,
+				WarehouseLocationName: row.WarehouseLocationName,
+				WarehouseLocationPath: row.WarehouseLocationPath,
+			})
+		}
+		available[row.WarehouseLocationID][row.ProductID] += row.Available
+	}
+
+	for _, component := range components {
+		response.Components = append(response.Components, dtos.ProductComponentResponse{
+			ProductID:   component.ComponentProductID,
+			ProductName: component.Component.Name,
+			SKU:         component.Component.SKU,
+			Quantity:    component.Quantity,
+			Available:   totals[component.ComponentProductID],
+		})
+	}
+	for _, location := range locations {
+		buildable := -1
+		for _, component := range components {
+			n := available[location.WarehouseLocationID][component.ComponentProductID] / component.Quantity
+			if buildable < 0 || n < buildable {
+				buildable = n
+			}
+		}
+		if buildable > 0 {
+			location.Quantity = buildable
+			response.BuildableByLocation = append(response.BuildableByLocation, location)
+			response.BuildableQuantity += buildable
+		}
+	}
+	return response, nil
+}
+
+// allocateKitCost membagi harga pokok kit yang dibongkar ke komponennya secara proporsional terhadap
+// biaya rata-rata masing-masing komponen saat ini, atau rata per quantity bila belum ada data biaya.
+// Hasilnya adalah biaya per satuan dasar tiap komponen.
+func allocateKitCost(repo repositorys.ProductRepository, components []models.ProductComponent, kitQuantity int, kitCost float64) (map[uuid.UUID]float64, error) {
+	weights := make(map[uuid.UUID]float64, len(components))
+	totalWeight := 0.0
+	totalQuantity := 0
+	for _, component := range components {
+		cost, err := repo.GetProductUnitCost(component.ComponentProductID)
+		if err != nil {
+			return nil, err
+		}
+		weights[component.ComponentProductID] = cost * float64(component.Quantity)
+		totalWeight += cost * float64(component.Quantity)
+		totalQuantity += component.Quantity
+	}
+
+	unitCosts := make(map[uuid.UUID]float64, len(components))
+	for _, component := range components {
+		share := float64(component.Quantity) / float64(totalQuantity)
+		if totalWeight > 0 {
+			share = weights[component.ComponentProductID] / totalWeight
+		}
+		unitCosts[component.ComponentProductID] = kitCost * share / float64(component.Quantity*kitQuantity)
+	}
+	return unitCosts, nil
+}
+
+func toKitAssemblyResponse(a *models.KitAssembly) *dtos.KitAssemblyResponse {
+	return &dtos.KitAssemblyResponse{
+		ID:                    a.ID,
+		AssemblyNumber:        a.AssemblyNumber,
+		Operation:             a.Operation,
+		ProductID:             a.KitProductID,
+		ProductName:           a.Product.Name,
+		SKU:                   a.Product.SKU,
+		WarehouseLocationID:   a.WarehouseLocationID,
+		WarehouseLocationName: a.WarehouseLocation.Name,
+		Quantity:              a.Quantity,
+		Note:                  a.Note,
+		CreatedBy:             a.CreatedBy,
+		CreatedAt:             a.CreatedAt,
+	}
+}
@@ -0,0 +1,345 @@
+package usecases
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	LabelKindProduct  = "product"
+	LabelKindLocation = "location"
+
+	LabelFormatZPL = "zpl"
+	LabelFormatPDF = "pdf"
+
+	// zplDotsPerMM untuk printer Zebra 203 dpi
+	zplDotsPerMM = 8
+	// pdfPointsPerMM; lembar pdf berukuran A4
+	pdfPointsPerMM  = 72 / 25.4
+	pdfPageWidthMM  = 210.0
+	pdfPageHeightMM = 297.0
+)
+
+// labelContent adalah isi satu label yang sudah diisi dari produk atau lokasi
+type labelContent struct {
+	Title     string
+	Subtitle  string
+	Caption   string
+	Barcode   string
+	Symbology string
+	Copies    int
+}
+
+// labelText menempatkan field title, subtitle atau caption; posisi dan ukuran dalam milimeter dari kiri atas label
+type labelText struct {
+	field   string
+	xMM     float64
+	yMM     float64
+	sizeMM  float64
+	widthMM float64
+	bold    bool
+}
+
+// labelBarcode; moduleMM adalah lebar bar tersempit, diperkecil bila barcode tidak muat di widthMM
+type labelBarcode struct {
+	xMM           float64
+	yMM           float64
+	heightMM      float64
+	widthMM       float64
+	moduleMM      float64
+	humanReadable bool
+	textSizeMM    float64
+}
+
+// labelTemplate adalah ukuran dan tata letak satu jenis label. Columns x rows adalah susunan label
+// per lembar A4 saat dicetak sebagai pdf di printer laser.
+type labelTemplate struct {
+	name        string
+	kind        string
+	description string
+	widthMM     float64
+	heightMM    float64
+	columns     int
+	rows        int
+	isDefault   bool
+	texts       []labelText
+	barcode     labelBarcode
+}
+
+var labelTemplates = []labelTemplate{
+	{
+		name: "product-small", kind: LabelKindProduct, isDefault: true,
+		description: "50 x 25 mm product label with name, SKU and barcode",
+		widthMM:     50, heightMM: 25, columns: 4, rows: 10,
+		texts: []labelText{
+			{field: "title", xMM: 2, yMM: 1.5, sizeMM: 3, widthMM: 46, bold: true},
+			{field: "subtitle", xMM: 2, yMM: 5, sizeMM: 2.4, widthMM: 46},
+		},
+		barcode: labelBarcode{xMM: 3, yMM: 8.5, heightMM: 11, widthMM: 44, moduleMM: 0.25, humanReadable: true, textSizeMM: 2.2},
+	},
+	{
+		name: "product-large", kind: LabelKindProduct,
+		description: "100 x 50 mm product label with name, SKU, unit and barcode",
+		widthMM:     100, heightMM: 50, columns: 2, rows: 5,
+		texts: []labelText{
+			{field: "title", xMM: 4, yMM: 3, sizeMM: 5, widthMM: 92, bold: true},
+			{field: "subtitle", xMM: 4, yMM: 9.5, sizeMM: 3.5, widthMM: 92},
+			{field: "caption", xMM: 4, yMM: 14, sizeMM: 3, widthMM: 92},
+		},
+		barcode: labelBarcode{xMM: 5, yMM: 19, heightMM: 22, widthMM: 90, moduleMM: 0.4, humanReadable: true, textSizeMM: 3},
+	},
+	{
+		name: "bin", kind: LabelKindLocation, isDefault: true,
+		description: "100 x 30 mm bin label with location path, name and barcode",
+		widthMM:     100, heightMM: 30, columns: 2, rows: 9,
+		texts: []labelText{
+			{field: "title", xMM: 4, yMM: 2, sizeMM: 6, widthMM: 92, bold: true},
+			{field: "subtitle", xMM: 4, yMM: 9, sizeMM: 3, widthMM: 92},
+		},
+		barcode: labelBarcode{xMM: 5, yMM: 13.5, heightMM: 14, widthMM: 90, moduleMM: 0.4},
+	},
+	{
+		name: "bin-small", kind: LabelKindLocation,
+		description: "50 x 25 mm bin label with location path and barcode",
+		widthMM:     50, heightMM: 25, columns: 4, rows: 10,
+		texts: []labelText{
+			{field: "title", xMM: 2, yMM: 1.5, sizeMM: 4, widthMM: 46, bold: true},
+			{field: "subtitle", xMM: 2, yMM: 21, sizeMM: 2.4, widthMM: 46},
+		},
+		barcode: labelBarcode{xMM: 3, yMM: 7, heightMM: 13, widthMM: 44, moduleMM: 0.25},
+	},
+}
+
+func (t labelTemplate) slotsPerSheet() int {
+	return t.columns * t.rows
+}
+
+func (c labelContent) field(name string) string {
+	switch name {
+	case "title":
+		return c.Title
+	case "subtitle":
+		return c.Subtitle
+	case "caption":
+		return c.Caption
+	}
+	return ""
+}
+
+// fitText memotong teks agar muat di lebar yang tersedia; lebar karakter diperkirakan 0,55 x tinggi huruf
+func fitText(text string, widthMM, sizeMM float64) string {
+	maxChars := int(widthMM / (sizeMM * 0.55))
+	runes := []rune(text)
+	if len(runes) <= maxChars {
+		return text
+	}
+	if maxChars <= 3 {
+		return string(runes[:maxChars])
+	}
+	return string(runes[:maxChars-3]) + "..."
+}
+
+// barcodeModules mengembalikan modul barcode sesuai symbology; symbology selain EAN/UPC dicetak sebagai Code 128
+func barcodeModules(data, symbology string) ([]bool, error) {
+	switch symbology {
+	case SymbologyEAN8, SymbologyUPCA, SymbologyEAN13:
+		return eanModules(data)
+	}
+	return code128Modules(data)
+}
+
+// renderZPL membuat satu format ZPL per label; jumlah cetak memakai ^PQ
+func renderZPL(template labelTemplate, labels []labelContent) ([]byte, error) {
+	dots := func(mm float64) int { return int(math.Round(mm * zplDotsPerMM)) }
+
+	var buf bytes.Buffer
+	for _, label := range labels {
+		modules, err := barcodeModules(label.Barcode, label.Symbology)
+		if err != nil {
+			return nil, err
+		}
+
+		fmt.Fprintf(&buf, "^XA\n^CI28\n^PW%d\n^LL%d\n^LH0,0\n", dots(template.widthMM), dots(template.heightMM))
+		for _, text := range template.texts {
+			value := fitText(label.field(text.field), text.widthMM, text.sizeMM)
+			if value == "" {
+				continue
+			}
+			height := dots(text.sizeMM)
+			width := height
+			if text.bold {
+				width = height * 6 / 5
+			}
+			fmt.Fprintf(&buf, "^FO%d,%d^A0N,%d,%d^FH_^FD%s^FS\n", dots(text.xMM), dots(text.yMM), height, width, zplEscape(value))
+		}
+
+		bc := template.barcode
+		module := dots(bc.moduleMM)
+		if fit := dots(bc.widthMM) / len(modules); fit < module {
+			module = fit
+		}
+		module = max(module, 1)
+		interpretation := "N"
+		if bc.humanReadable {
+			interpretation = "Y"
+		}
+		fmt.Fprintf(&buf, "^FO%d,%d^BY%d,3,%d", dots(bc.xMM), dots(bc.yMM), module, dots(bc.heightMM))
+		data := label.Barcode
+		switch label.Symbology {
+		case SymbologyEAN13:
+			fmt.Fprintf(&buf, "^BEN,%d,%s,N^FD%s^FS\n", dots(bc.heightMM), interpretation, data[:12])
+		case SymbologyUPCA:
+			fmt.Fprintf(&buf, "^BUN,%d,%s,N,Y^FD%s^FS\n", dots(bc.heightMM), interpretation, data[:11])
+		case SymbologyEAN8:
+			fmt.Fprintf(&buf, "^B8N,%d,%s,N^FD%s^FS\n", dots(bc.heightMM), interpretation, data[:7])
+		default:
+			// ">" adalah karakter invocation pada ^BC sehingga ditulis sebagai ">0"
+			data = strings.ReplaceAll(data, ">", ">0")
+			fmt.Fprintf(&buf, "^BCN,%d,%s,N,N^FH_^FD%s^FS\n", dots(bc.heightMM), interpretation, zplEscape(data))
+		}
+
+		fmt.Fprintf(&buf, "^PQ%d\n^XZ\n", label.Copies)
+	}
+	return buf.Bytes(), nil
+}
+
+// zplEscape menulis karakter kontrol ZPL sebagai hex (dipakai bersama ^FH_)
+func zplEscape(value string) string {
+	return strings.NewReplacer("_", "_5F", "^", "_5E", "~", "_7E").Replace(value)
+}
+
+// renderPDF menyusun label pada lembar A4 sesuai grid template, dimulai dari slot startPosition (1-based)
+// pada lembar pertama. Teks memakai font standar Helvetica dan barcode digambar sebagai persegi panjang.
+func renderPDF(template labelTemplate, labels []labelContent, startPosition int) ([]byte, error) {
+	marginX := (pdfPageWidthMM - float64(template.columns)*template.widthMM) / 2
+	marginY := (pdfPageHeightMM - float64(template.rows)*template.heightMM) / 2
+
+	var pages []*bytes.Buffer
+	slot := max(startPosition-1, 0)
+	for _, label := range labels {
+		modules, err := barcodeModules(label.Barcode, label.Symbology)
+		if err != nil {
+			return nil, err
+		}
+		for n := 0; n < label.Copies; n++ {
+			position := slot % template.slotsPerSheet()
+			if position == 0 || len(pages) == 0 {
+				pages = append(pages, &bytes.Buffer{})
+			}
+			originX := marginX + float64(position%template.columns)*template.widthMM
+			originY := marginY + float64(position/template.columns)*template.heightMM
+			pdfLabel(pages[len(pages)-1], template, label, modules, originX, originY)
+			slot++
+		}
+	}
+	return pdfDocument(pages), nil
+}
+
+func pdfLabel(page *bytes.Buffer, template labelTemplate, label labelContent, modules []bool, originX, originY float64) {
+	for _, text := range template.texts {
+		value := fitText(label.field(text.field), text.widthMM, text.sizeMM)
+		if value == "" {
+			continue
+		}
+		font := "F1"
+		if text.bold {
+			font = "F2"
+		}
+		pdfText(page, font, originX+text.xMM, originY+text.yMM, text.sizeMM, value)
+	}
+
+	bc := template.barcode
+	module := math.Min(bc.moduleMM, bc.widthMM/float64(len(modules)))
+	x := originX + bc.xMM
+	top := originY + bc.yMM
+	for i := 0; i < len(modules); {
+		if !modules[i] {
+			i++
+			continue
+		}
+		start := i
+		for i < len(modules) && modules[i] {
+			i++
+		}
+		fmt.Fprintf(page, "%s %s %s %s re\n",
+			pdfNumber(x+float64(start)*module), pdfNumber(pdfPageHeightMM-top-bc.heightMM),
+			pdfNumber(float64(i-start)*module), pdfNumber(bc.heightMM))
+	}
+	page.WriteString("f\n")
+
+	if bc.humanReadable {
+		textWidth := float64(len(label.Barcode)) * bc.textSizeMM * 0.55
+		textX := x + (float64(len(modules))*module-textWidth)/2
+		pdfText(page, "F1", textX, top+bc.heightMM+0.5, bc.textSizeMM, label.Barcode)
+	}
+}
+
+// pdfText menulis teks dengan posisi kiri atas (mm dari kiri atas halaman) dan tinggi huruf sizeMM
+func pdfText(page *bytes.Buffer, font string, xMM, yMM, sizeMM float64, value string) {
+	baseline := pdfPageHeightMM - yMM - sizeMM*0.8
+	fmt.Fprintf(page, "BT /%s %s Tf %s %s Td (%s) Tj ET\n",
+		font, pdfNumber(sizeMM), pdfNumber(xMM), pdfNumber(baseline), pdfEscape(value))
+}
+
+// pdfNumber menulis angka dalam milimeter sebagai point
+func pdfNumber(mm float64) string {
+	return strconv.FormatFloat(mm*pdfPointsPerMM, 'f', 2, 64)
+}
+
+// pdfEscape mengubah teks ke WinAnsi (Latin-1); karakter di luar Latin-1 diganti "?"
+func pdfEscape(value string) string {
+	var buf bytes.Buffer
+	for _, r := range value {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			buf.WriteByte('\\')
+			buf.WriteByte(byte(r))
+		case r < 32:
+			buf.WriteByte(' ')
+		case r > 255:
+			buf.WriteByte('?')
+		default:
+			buf.WriteByte(byte(r))
+		}
+	}
+	return buf.String()
+}
+
+// pdfDocument menyusun objek PDF 1.4: catalog, pages, dua font standar, lalu page dan content stream per halaman
+func pdfDocument(pages []*bytes.Buffer) []byte {
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>",
+	}
+	kids := make([]string, len(pages))
+	for i, page := range pages {
+		pageObject := len(objects) + 1
+		kids[i] = fmt.Sprintf("%d 0 R", pageObject)
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+				pdfNumber(pdfPageWidthMM), pdfNumber(pdfPageHeightMM), pageObject+1),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.Len(), page.String()),
+		)
+	}
+	objects[1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages))
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return buf.Bytes()
+}
@@ -0,0 +1,143 @@
+package usecases
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Jalankan `go test ./internal/usecases -run Golden -update` untuk menulis ulang file golden
+var updateGolden = flag.Bool("update", false, "rewrite golden files in testdata")
+
+func assertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", "labels", name)
+	if *updateGolden {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, got, 0o644))
+	}
+	want, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, string(want), string(got), "output differs from %s", path)
+}
+
+func goldenTemplate(t *testing.T, kind, name string) labelTemplate {
+	t.Helper()
+	template, err := labelTemplateFor(kind, name)
+	require.NoError(t, err)
+	return template
+}
+
+// goldenProductLabel memuat karakter kontrol ZPL (_ ^ ~) dan teks di luar Latin-1
+var goldenProductLabel = labelContent{
+	Title:     "Kopi_Arabika ^Café~ (1 kg) – 日本",
+	Subtitle:  "SKU-KOPI-01",
+	Caption:   "Unit: pack",
+	Barcode:   "4006381333931",
+	Symbology: SymbologyEAN13,
+	Copies:    3,
+}
+
+var goldenBinLabel = labelContent{
+	Title:     "WH1/A-01/B_03",
+	Subtitle:  "Bin B_03 > rack A-01",
+	Barcode:   "WH1/A-01/B_03>X",
+	Symbology: SymbologyCode128,
+	Copies:    1,
+}
+
+func TestRenderZPLGolden(t *testing.T) {
+	t.Run("product", func(t *testing.T) {
+		out, err := renderZPL(goldenTemplate(t, LabelKindProduct, "product-large"), []labelContent{goldenProductLabel})
+		require.NoError(t, err)
+		assertGolden(t, "product-large.zpl", out)
+
+		zpl := string(out)
+		assert.Contains(t, zpl, "^FH_^FDKopi_5FArabika _5ECafé_7E (1 kg) – 日本^FS")
+		assert.Contains(t, zpl, "^BEN,176,Y,N^FD400638133393^FS")
+		assert.Contains(t, zpl, "^PQ3\n^XZ\n")
+	})
+
+	t.Run("bin", func(t *testing.T) {
+		out, err := renderZPL(goldenTemplate(t, LabelKindLocation, "bin"), []labelContent{goldenBinLabel})
+		require.NoError(t, err)
+		assertGolden(t, "bin.zpl", out)
+
+		zpl := string(out)
+		assert.Contains(t, zpl, "^FH_^FDWH1/A-01/B_5F03^FS")
+		assert.Contains(t, zpl, "^BCN,112,N,N,N^FH_^FDWH1/A-01/B_5F03>0X^FS")
+	})
+}
+
+func TestRenderPDFGolden(t *testing.T) {
+	t.Run("product", func(t *testing.T) {
+		// Mulai dari slot 9 dari 10: dua salinan di lembar pertama, salinan ketiga di lembar baru
+		template := goldenTemplate(t, LabelKindProduct, "product-large")
+		out, err := renderPDF(template, []labelContent{goldenProductLabel}, 9)
+		require.NoError(t, err)
+		assertGolden(t, "product-large.pdf", out)
+
+		assert.Equal(t, 2, bytes.Count(out, []byte("/Type /Page /Parent")))
+		assert.Contains(t, string(out), "/Count 2 >>")
+		// WinAnsi: é tetap satu byte Latin-1, karakter di luar Latin-1 menjadi "?", kurung di-escape
+		assert.Contains(t, string(out), "(Kopi_Arabika ^Caf\xe9~ \\(1 kg\\) ? ??) Tj")
+		assertPDFXref(t, out)
+	})
+
+	t.Run("bin", func(t *testing.T) {
+		template := goldenTemplate(t, LabelKindLocation, "bin")
+		out, err := renderPDF(template, []labelContent{goldenBinLabel}, 0)
+		require.NoError(t, err)
+		assertGolden(t, "bin.pdf", out)
+
+		assert.Equal(t, 1, bytes.Count(out, []byte("/Type /Page /Parent")))
+		assertPDFXref(t, out)
+	})
+
+	t.Run("start position fills a fresh sheet", func(t *testing.T) {
+		template := goldenTemplate(t, LabelKindLocation, "bin")
+		label := goldenBinLabel
+		label.Copies = template.slotsPerSheet()
+		out, err := renderPDF(template, []labelContent{label}, 1)
+		require.NoError(t, err)
+		assert.Equal(t, 1, bytes.Count(out, []byte("/Type /Page /Parent")))
+
+		out, err = renderPDF(template, []labelContent{label}, 2)
+		require.NoError(t, err)
+		assert.Equal(t, 2, bytes.Count(out, []byte("/Type /Page /Parent")))
+	})
+}
+
+var pdfXrefEntry = regexp.MustCompile(`^(\d{10}) (\d{5}) ([nf]) $`)
+
+// assertPDFXref memastikan startxref menunjuk ke tabel xref dan setiap offset menunjuk ke "N 0 obj"
+func assertPDFXref(t *testing.T, pdf []byte) {
+	t.Helper()
+	i := bytes.LastIndex(pdf, []byte("startxref\n"))
+	require.GreaterOrEqual(t, i, 0, "startxref missing")
+	line, _, _ := bytes.Cut(pdf[i+len("startxref\n"):], []byte("\n"))
+	xref, err := strconv.Atoi(string(line))
+	require.NoError(t, err)
+	require.True(t, bytes.HasPrefix(pdf[xref:], []byte("xref\n0 ")), "startxref does not point to the xref table")
+
+	lines := bytes.Split(pdf[xref:], []byte("\n"))
+	size, err := strconv.Atoi(string(bytes.TrimPrefix(lines[1], []byte("0 "))))
+	require.NoError(t, err)
+	require.Contains(t, string(pdf), fmt.Sprintf("/Size %d ", size))
+	for n := 1; n < size; n++ {
+		entry := pdfXrefEntry.FindSubmatch(lines[2+n])
+		require.NotNil(t, entry, "malformed xref entry %q", lines[2+n])
+		offset, err := strconv.Atoi(string(entry[1]))
+		require.NoError(t, err)
+		assert.True(t, bytes.HasPrefix(pdf[offset:], []byte(fmt.Sprintf("%d 0 obj\n", n))),
+			"xref entry %d points to %q", n, pdf[offset:min(offset+12, len(pdf))])
+	}
+}
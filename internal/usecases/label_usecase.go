@@ -0,0 +1,233 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"auth-service/internal/dtos"
+	"auth-service/internal/models"
+	"auth-service/internal/repositorys"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUnknownLabelTemplate      = errors.New("unknown label template")
+	ErrLabelTemplateKind         = errors.New("label template does not match the labelled item")
+	ErrTooManyLabels             = errors.New("too many labels in one request")
+	ErrInvalidLabelStartPosition = errors.New("start position is outside the label sheet")
+)
+
+// maxLabelsPerRequest membatasi jumlah label (termasuk copies) dalam satu dokumen
+const maxLabelsPerRequest = 2000
+
+type LabelUseCase interface {
+	GetLabelTemplates(ctx context.Context, req dtos.LabelTemplateListRequest) ([]dtos.LabelTemplateResponse, error)
+	RenderProductLabels(ctx context.Context, req dtos.ProductLabelRequest) (*dtos.LabelDocument, error)
+	RenderLocationLabels(ctx context.Context, req dtos.LocationLabelRequest) (*dtos.LabelDocument, error)
+}
+
+type labelUseCase struct {
+	productRepo repositorys.ProductRepository
+	validate    *validator.Validate
+	log         *logrus.Logger
+}
+
+func NewLabelUseCase(productRepo repositorys.ProductRepository, log *logrus.Logger, validate *validator.Validate) LabelUseCase {
+	return &labelUseCase{productRepo: productRepo, log: log, validate: validate}
+}
+
+func (u *labelUseCase) GetLabelTemplates(ctx context.Context, req dtos.LabelTemplateListRequest) ([]dtos.LabelTemplateResponse, error) {
+	templates := []dtos.LabelTemplateResponse{}
+	for _, t := range labelTemplates {
+		if req.Kind != "" && t.kind != req.Kind {
+			continue
+		}
+		templates = append(templates, dtos.LabelTemplateResponse{
+			Name:         t.name,
+			Kind:         t.kind,
+			Description:  t.description,
+			WidthMM:      t.widthMM,
+			HeightMM:     t.heightMM,
+			SheetColumns: t.columns,
+			SheetRows:    t.rows,
+			IsDefault:    t.isDefault,
+		})
+	}
+	return templates, nil
+}
+
+// RenderProductLabels mencetak nama, SKU dan barcode produk. Barcode yang dicetak adalah barcode
+// pilihan item, barcode utama, atau SKU (Code 128) bila produk belum punya barcode; semuanya dikenali scan.
+func (u *labelUseCase) RenderProductLabels(ctx context.Context, req dtos.ProductLabelRequest) (*dtos.LabelDocument, error) {
+	template, err := labelTemplateFor(LabelKindProduct, req.Template)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkLabelStartPosition(template, req.Format, req.StartPosition); err != nil {
+		return nil, err
+	}
+
+	var labels []labelContent
+	for _, item := range req.Items {
+		product, err := u.productRepo.GetProductByID(item.ProductID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, fmt.Errorf("product %s: %w", item.ProductID, err)
+			}
+			return nil, err
+		}
+		barcodes, err := u.productRepo.GetProductBarcodes(product.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		label := labelContent{
+			Title:     product.Name,
+			Subtitle:  "SKU: " + product.SKU,
+			Caption:   "Unit: " + product.BaseUnit,
+			Barcode:   product.SKU,
+			Symbology: SymbologyCode128,
+			Copies:    labelCopies(item.Copies),
+		}
+		barcode, err := labelBarcodeFor(barcodes, item.BarcodeID)
+		if err != nil {
+			return nil, err
+		}
+		if barcode != nil {
+			label.Barcode = barcode.Code
+			label.Symbology = barcode.Symbology
+			if barcode.Unit != "" {
+				label.Caption = "Unit: " + barcode.Unit
+			}
+		}
+		labels = append(labels, label)
+	}
+	return renderLabelDocument(template, req.Format, req.StartPosition, labels)
+}
+
+// RenderLocationLabels mencetak path lokasi sebagai barcode Code 128 sehingga hasil scan langsung
+// mengarah ke lokasi tersebut; include_children ikut mencetak seluruh lokasi turunan.
+func (u *labelUseCase) RenderLocationLabels(ctx context.Context, req dtos.LocationLabelRequest) (*dtos.LabelDocument, error) {
+	template, err := labelTemplateFor(LabelKindLocation, req.Template)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkLabelStartPosition(template, req.Format, req.StartPosition); err != nil {
+		return nil, err
+	}
+
+	var labels []labelContent
+	for _, item := range req.Items {
+		location, err := u.productRepo.GetWarehouseLocationByID(item.LocationID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, fmt.Errorf("warehouse location %s: %w", item.LocationID, err)
+			}
+			return nil, err
+		}
+		locations := []models.WarehouseLocation{*location}
+		if item.IncludeChildren {
+			if locations, err = u.productRepo.GetWarehouseLocationSubtree(location); err != nil {
+				return nil, err
+			}
+		}
+
+		for _, l := range locations {
+			code := l.Path
+			if code == "" {
+				code = l.Code
+			}
+			if code == "" {
+				code = l.ID.String()
+			}
+			labels = append(labels, labelContent{
+				Title:     code,
+				Subtitle:  l.Name,
+				Caption:   l.Type,
+				Barcode:   code,
+				Symbology: SymbologyCode128,
+				Copies:    labelCopies(item.Copies),
+			})
+		}
+	}
+	return renderLabelDocument(template, req.Format, req.StartPosition, labels)
+}
+
+// labelTemplateFor mencari template berdasarkan nama; nama kosong berarti template default untuk kind tersebut
+func labelTemplateFor(kind, name string) (labelTemplate, error) {
+	for _, t := range labelTemplates {
+		if name == "" && t.kind == kind && t.isDefault {
+			return t, nil
+		}
+		if name != "" && t.name == name {
+			if t.kind != kind {
+				return labelTemplate{}, fmt.Errorf("%w: %s is a %s template", ErrLabelTemplateKind, name, t.kind)
+			}
+			return t, nil
+		}
+	}
+	return labelTemplate{}, fmt.Errorf("%w: %s", ErrUnknownLabelTemplate, name)
+}
+
+func checkLabelStartPosition(template labelTemplate, format string, startPosition int) error {
+	if format == LabelFormatPDF && startPosition > template.slotsPerSheet() {
+		return fmt.Errorf("%w: %s has %d labels per sheet", ErrInvalidLabelStartPosition, template.name, template.slotsPerSheet())
+	}
+	return nil
+}
+
+// labelBarcodeFor memilih barcode item; barcodeID kosong berarti barcode utama (barcode pertama), nil bila produk tanpa barcode
+func labelBarcodeFor(barcodes []models.ProductBarcode, barcodeID uuid.UUID) (*models.ProductBarcode, error) {
+	if barcodeID == uuid.Nil {
+		if len(barcodes) == 0 {
+			return nil, nil
+		}
+		return &barcodes[0], nil
+	}
+	for i := range barcodes {
+		if barcodes[i].ID == barcodeID {
+			return &barcodes[i], nil
+		}
+	}
+	return nil, fmt.Errorf("barcode %s: %w", barcodeID, gorm.ErrRecordNotFound)
+}
+
+func labelCopies(copies int) int {
+	if copies <= 0 {
+		return 1
+	}
+	return copies
+}
+
+func renderLabelDocument(template labelTemplate, format string, startPosition int, labels []labelContent) (*dtos.LabelDocument, error) {
+	total := 0
+	for _, label := range labels {
+		total += label.Copies
+	}
+	if total > maxLabelsPerRequest {
+		return nil, fmt.Errorf("%w: %d labels, maximum is %d", ErrTooManyLabels, total, maxLabelsPerRequest)
+	}
+
+	document := &dtos.LabelDocument{
+		FileName: fmt.Sprintf("labels-%s-%s.%s", template.name, time.Now().Format("20060102150405"), format),
+		Labels:   total,
+	}
+	var err error
+	if format == LabelFormatPDF {
+		document.ContentType = "application/pdf"
+		document.Content, err = renderPDF(template, labels, startPosition)
+	} else {
+		document.ContentType = "text/plain; charset=utf-8"
+		document.Content, err = renderZPL(template, labels)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return document, nil
+}
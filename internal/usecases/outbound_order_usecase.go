@@ -0,0 +1,638 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"auth-service/internal/dtos"
+	"auth-service/internal/models"
+	"auth-service/internal/repositorys"
+	"auth-service/internal/utils"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidOutboundOrderState = errors.New("invalid outbound order state for this action")
+	ErrNoStockToAllocate         = errors.New("no available stock to allocate for this order")
+	ErrOrderNotFullyAllocated    = errors.New("order is only partially allocated; set allow_partial to pack it anyway")
+	ErrOutboundLineNotOnOrder    = errors.New("line is not on this outbound order")
+)
+
+type OutboundOrderUseCase interface {
+	CreateOutboundOrder(ctx context.Context, req dtos.OutboundOrderRequest, userID uuid.UUID) (*dtos.OutboundOrderResponse, error)
+	UpdateOutboundOrder(ctx context.Context, id uuid.UUID, req dtos.OutboundOrderRequest, userID uuid.UUID) (*dtos.OutboundOrderResponse, error)
+	GetOutboundOrderByID(ctx context.Context, id uuid.UUID) (*dtos.OutboundOrderResponse, error)
+	GetOutboundOrdersList(ctx context.Context, req dtos.OutboundOrderListRequest) ([]dtos.OutboundOrderResponse, dtos.Pagination, error)
+	AllocateOutboundOrder(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*dtos.OutboundOrderResponse, error)
+	GetPickList(ctx context.Context, id uuid.UUID) (*dtos.PickListResponse, error)
+	PackOutboundOrder(ctx context.Context, id uuid.UUID, req dtos.PackOutboundOrderRequest, userID uuid.UUID) (*dtos.OutboundOrderResponse, error)
+	ShipOutboundOrder(ctx context.Context, id uuid.UUID, req dtos.ShipOutboundOrderRequest, userID uuid.UUID) (*dtos.OutboundOrderResponse, error)
+	CancelOutboundOrder(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*dtos.OutboundOrderResponse, error)
+}
+
+type outboundOrderUseCase struct {
+	repo        repositorys.OutboundOrderRepository
+	productRepo repositorys.ProductRepository
+	// costingMethod adalah metode valuasi default untuk produk tanpa costing_method (valuation.default_method)
+	costingMethod string
+	validate      *validator.Validate
+	log           *logrus.Logger
+}
+
+func NewOutboundOrderUseCase(repo repositorys.OutboundOrderRepository, productRepo repositorys.ProductRepository, costingMethod string, log *logrus.Logger, validate *validator.Validate) OutboundOrderUseCase {
+	return &outboundOrderUseCase{repo: repo, productRepo: productRepo, costingMethod: costingMethod, log: log, validate: validate}
+}
+
+func (u *outboundOrderUseCase) CreateOutboundOrder(ctx context.Context, req dtos.OutboundOrderRequest, userID uuid.UUID) (*dtos.OutboundOrderResponse, error) {
+	if err := u.validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	order, err := buildOutboundOrder(u.productRepo, req, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.repo.CreateOutboundOrder(order); err != nil {
+		return nil, err
+	}
+	u.log.Info(fmt.Sprintf("Outbound order %s created", order.OrderNumber))
+	return u.GetOutboundOrderByID(ctx, order.ID)
+}
+
+// buildOutboundOrder memvalidasi lokasi dan produk lalu menyusun pesanan draft (belum disimpan).
+// Baris dengan produk yang sama digabung; unit_price pertama yang diisi dipakai.
+func buildOutboundOrder(productRepo repositorys.ProductRepository, req dtos.OutboundOrderRequest, userID uuid.UUID) (*models.OutboundOrder, error) {
+	if req.WarehouseLocationID != nil {
+		if _, err := productRepo.GetWarehouseLocationByID(*req.WarehouseLocationID); err != nil {
+			return nil, fmt.Errorf("warehouse location not found: %w", err)
+		}
+	}
+	shipDate, err := parseExpiryDate(req.RequestedShipDate)
+	if err != nil {
+		return nil, err
+	}
+
+	order := &models.OutboundOrder{
+		ID:                  uuid.New(),
+		OrderNumber:         utils.GenerateDocumentNumber("SO"),
+		CustomerName:        req.CustomerName,
+		CustomerReference:   req.CustomerReference,
+		ShippingAddress:     req.ShippingAddress,
+		WarehouseLocationID: req.WarehouseLocationID,
+		Status:              "draft",
+		RequestedShipDate:   shipDate,
+		Carrier:             req.Carrier,
+		Note:                req.Note,
+		CreatedBy:           userID,
+	}
+
+	quantities := make(map[uuid.UUID]int)
+	prices := make(map[uuid.UUID]float64)
+	var productOrder []uuid.UUID
+	for _, line := range req.Lines {
+		quantity, err := baseQuantity(productRepo, line.ProductID, line.Unit, line.Quantity)
+		if err != nil {
+			return nil, fmt.Errorf("product %s: %w", line.ProductID, err)
+		}
+		if _, ok := quantities[line.ProductID]; !ok {
+			productOrder = append(productOrder, line.ProductID)
+		}
+		quantities[line.ProductID] += quantity
+		if prices[line.ProductID] == 0 {
+			prices[line.ProductID] = *baseUnitCost(&line.UnitPrice, line.Quantity, quantity)
+		}
+	}
+
+	for _, productID := range productOrder {
+		if _, err := productRepo.GetProductByID(productID); err != nil {
+			return nil, fmt.Errorf("product %s not found: %w", productID, err)
+		}
+		order.Lines = append(order.Lines, models.OutboundOrderLine{
+			ID:              uuid.New(),
+			OutboundOrderID: order.ID,
+			SourceProductID: productID,
+			Quantity:        quantities[productID],
+			UnitPrice:       prices[productID],
+			Status:          "pending",
+		})
+	}
+	return order, nil
+}
+
+// UpdateOutboundOrder mengganti data customer, lokasi, tanggal, catatan, dan seluruh baris pesanan yang masih draft
+func (u *outboundOrderUseCase) UpdateOutboundOrder(ctx context.Context, id uuid.UUID, req dtos.OutboundOrderRequest, userID uuid.UUID) (*dtos.OutboundOrderResponse, error) {
+	if err := u.validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	err := u.repo.WithTransaction(func(repo repositorys.OutboundOrderRepository, _ repositorys.StockReservationRepository, stockRepo repositorys.ProductRepository) error {
+		order, err := repo.GetOutboundOrderForUpdate(id)
+		if err != nil {
+			return err
+		}
+		if order.Status != "draft" {
+			return fmt.Errorf("%w: cannot edit a %s order", ErrInvalidOutboundOrderState, order.Status)
+		}
+
+		built, err := buildOutboundOrder(stockRepo, req, userID)
+		if err != nil {
+			return err
+		}
+		for i := range built.Lines {
+			built.Lines[i].OutboundOrderID = order.ID
+		}
+		if err := repo.ReplaceOutboundOrderLines(order.ID, built.Lines); err != nil {
+			return err
+		}
+
+		order.CustomerName = built.CustomerName
+		order.CustomerReference = built.CustomerReference
+		order.ShippingAddress = built.ShippingAddress
+		order.WarehouseLocationID = built.WarehouseLocationID
+		order.RequestedShipDate = built.RequestedShipDate
+		order.Carrier = built.Carrier
+		order.Note = built.Note
+		order.UpdatedAt = time.Now()
+		return repo.UpdateOutboundOrder(order)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return u.GetOutboundOrderByID(ctx, id)
+}
+
+func (u *outboundOrderUseCase) GetOutboundOrderByID(ctx context.Context, id uuid.UUID) (*dtos.OutboundOrderResponse, error) {
+	order, err := u.repo.GetOutboundOrderByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return toOutboundOrderResponse(order), nil
+}
+
+func (u *outboundOrderUseCase) GetOutboundOrdersList(ctx context.Context, req dtos.OutboundOrderListRequest) ([]dtos.OutboundOrderResponse, dtos.Pagination, error) {
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.Limit == 0 {
+		req.Limit = 10
+	}
+	if err := u.validate.Struct(req); err != nil {
+		return nil, dtos.Pagination{}, err
+	}
+
+	orders, total, err := u.repo.GetOutboundOrdersList(req)
+	if err != nil {
+		return nil, dtos.Pagination{}, err
+	}
+
+	list := make([]dtos.OutboundOrderResponse, 0, len(orders))
+	for i := range orders {
+		list = append(list, *toOutboundOrderResponse(&orders[i]))
+	}
+	return list, buildPagination(req.Page, req.Limit, total), nil
+}
+
+// AllocateOutboundOrder mereservasi stok tersedia untuk sisa quantity setiap baris.
+// Bisa dipanggil ulang selama pesanan partially_allocated untuk melengkapi alokasi.
+func (u *outboundOrderUseCase) AllocateOutboundOrder(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*dtos.OutboundOrderResponse, error) {
+	allocated := 0
+	err := u.repo.WithTransaction(func(repo repositorys.OutboundOrderRepository, reservationRepo repositorys.StockReservationRepository, stockRepo repositorys.ProductRepository) error {
+		order, err := repo.GetOutboundOrderForUpdate(id)
+		if err != nil {
+			return err
+		}
+		if order.Status != "draft" && order.Status != "partially_allocated" {
+			return fmt.Errorf("%w: cannot allocate a %s order", ErrInvalidOutboundOrderState, order.Status)
+		}
+
+		var root *models.WarehouseLocation
+		if order.WarehouseLocationID != nil {
+			if root, err = stockRepo.GetWarehouseLocationByID(*order.WarehouseLocationID); err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		complete := true
+		for i := range order.Lines {
+			line := &order.Lines[i]
+			need := line.Quantity - line.AllocatedQuantity
+			if need > 0 {
+				stocks, err := repo.GetAllocatableStocksForUpdate(line.SourceProductID, root)
+				if err != nil {
+					return err
+				}
+				for _, stock := range stocks {
+					if need == 0 {
+						break
+					}
+					take := min(stock.Quantity-stock.ReservedQuantity, need)
+					reservation, err := reserveStock(reservationRepo, stockRepo, stock.ID, take, order.OrderNumber, nil, userID)
+					if err != nil {
+						return err
+					}
+					if err := repo.CreateOutboundAllocation(&models.OutboundAllocation{
+						ID:                  uuid.New(),
+						OutboundOrderID:     order.ID,
+						OutboundOrderLineID: line.ID,
+						StockReservationID:  reservation.ID,
+						SourceProductID:     line.SourceProductID,
+						WarehouseLocationID: stock.WarehouseLocationID,
+						Quantity:            take,
+						CreatedAt:           now,
+					}); err != nil {
+						return err
+					}
+					line.AllocatedQuantity += take
+					need -= take
+					allocated += take
+				}
+			}
+
+			switch {
+			case line.AllocatedQuantity >= line.Quantity:
+				line.Status = "allocated"
+			case line.AllocatedQuantity > 0:
+				line.Status = "partially_allocated"
+				complete = false
+			default:
+				line.Status = "pending"
+				complete = false
+			}
+			line.UpdatedAt = now
+			if err := repo.UpdateOutboundOrderLine(line); err != nil {
+				return err
+			}
+		}
+		if allocated == 0 {
+			return ErrNoStockToAllocate
+		}
+
+		order.Status = "partially_allocated"
+		if complete {
+			order.Status = "allocated"
+		}
+		order.UpdatedAt = now
+		return repo.UpdateOutboundOrder(order)
+	})
+	if err != nil {
+		return nil, err
+	}
+	u.log.Info(fmt.Sprintf("Allocated %d unit(s) to outbound order %s", allocated, id))
+	return u.GetOutboundOrderByID(ctx, id)
+}
+
+// GetPickList menyusun daftar ambil dari alokasi yang masih aktif, dikelompokkan per lokasi
+func (u *outboundOrderUseCase) GetPickList(ctx context.Context, id uuid.UUID) (*dtos.PickListResponse, error) {
+	order, err := u.repo.GetOutboundOrderByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != "partially_allocated" && order.Status != "allocated" && order.Status != "packed" {
+		return nil, fmt.Errorf("%w: a %s order has nothing to pick", ErrInvalidOutboundOrderState, order.Status)
+	}
+
+	allocations, err := u.repo.GetOutboundAllocationsByOrderID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dtos.PickListResponse{
+		OrderID:     order.ID,
+		OrderNumber: order.OrderNumber,
+		Status:      order.Status,
+		Locations:   []dtos.PickListLocationResponse{},
+	}
+	// Alokasi sudah urut path lokasi, cukup buka grup baru setiap lokasi berganti
+	for _, allocation := range allocations {
+		if allocation.Reservation.Status != "active" {
+			continue
+		}
+		last := len(response.Locations) - 1
+		if last < 0 || response.Locations[last].WarehouseLocationID != allocation.WarehouseLocationID {
+			response.Locations = append(response.Locations, dtos.PickListLocationResponse{
+				WarehouseLocationID:   allocation.WarehouseLocationID,
+				WarehouseLocationName: allocation.WarehouseLocation.Name,
+				WarehouseLocationPath: allocation.WarehouseLocation.Path,
+			})
+			last++
+		}
+		response.Locations[last].Items = append(response.Locations[last].Items, dtos.PickListItemResponse{
+			AllocationID: allocation.ID,
+			OrderLineID:  allocation.OutboundOrderLineID,
+			ProductID:    allocation.SourceProductID,
+			ProductName:  allocation.Product.Name,
+			SKU:          allocation.Product.SKU,
+			Quantity:     allocation.Quantity,
+			LotTracked:   allocation.Product.LotTracked,
+			Serialized:   allocation.Product.Serialized,
+		})
+		response.TotalQuantity += allocation.Quantity
+	}
+	return response, nil
+}
+
+// PackOutboundOrder mengonfirmasi bahwa seluruh quantity teralokasi sudah diambil dan dikemas
+func (u *outboundOrderUseCase) PackOutboundOrder(ctx context.Context, id uuid.UUID, req dtos.PackOutboundOrderRequest, userID uuid.UUID) (*dtos.OutboundOrderResponse, error) {
+	if err := u.validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	err := u.repo.WithTransaction(func(repo repositorys.OutboundOrderRepository, _ repositorys.StockReservationRepository, _ repositorys.ProductRepository) error {
+		order, err := repo.GetOutboundOrderForUpdate(id)
+		if err != nil {
+			return err
+		}
+		if order.Status == "partially_allocated" && !req.AllowPartial {
+			return ErrOrderNotFullyAllocated
+		}
+		if order.Status != "allocated" && order.Status != "partially_allocated" {
+			return fmt.Errorf("%w: cannot pack a %s order", ErrInvalidOutboundOrderState, order.Status)
+		}
+
+		now := time.Now()
+		for i := range order.Lines {
+			line := &order.Lines[i]
+			line.PackedQuantity = line.AllocatedQuantity
+			if line.PackedQuantity > 0 {
+				line.Status = "packed"
+			}
+			line.UpdatedAt = now
+			if err := repo.UpdateOutboundOrderLine(line); err != nil {
+				return err
+			}
+		}
+
+		order.Status = "packed"
+		order.PackageCount = req.PackageCount
+		order.PackedBy = &userID
+		order.PackedAt = &now
+		order.UpdatedAt = now
+		return repo.UpdateOutboundOrder(order)
+	})
+	if err != nil {
+		return nil, err
+	}
+	u.log.Info(fmt.Sprintf("Outbound order %s packed", id))
+	return u.GetOutboundOrderByID(ctx, id)
+}
+
+// ShipOutboundOrder mengonsumsi reservasi pesanan yang sudah dikemas dan memposting movement shipment
+// per alokasi yang merujuk ke pesanan. Baris produk serialized wajib menyertakan nomor serial.
+func (u *outboundOrderUseCase) ShipOutboundOrder(ctx context.Context, id uuid.UUID, req dtos.ShipOutboundOrderRequest, userID uuid.UUID) (*dtos.OutboundOrderResponse, error) {
+	if err := u.validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	err := u.repo.WithTransaction(func(repo repositorys.OutboundOrderRepository, reservationRepo repositorys.StockReservationRepository, stockRepo repositorys.ProductRepository) error {
+		order, err := repo.GetOutboundOrderForUpdate(id)
+		if err != nil {
+			return err
+		}
+		if order.Status != "packed" {
+			return fmt.Errorf("%w: cannot ship a %s order", ErrInvalidOutboundOrderState, order.Status)
+		}
+
+		allocations, err := repo.GetOutboundAllocationsByOrderID(order.ID)
+		if err != nil {
+			return err
+		}
+		byLine := make(map[uuid.UUID][]*models.OutboundAllocation)
+		for i := range allocations {
+			if allocations[i].Reservation.Status == "active" {
+				byLine[allocations[i].OutboundOrderLineID] = append(byLine[allocations[i].OutboundOrderLineID], &allocations[i])
+			}
+		}
+
+		onOrder := make(map[uuid.UUID]bool, len(order.Lines))
+		for _, line := range order.Lines {
+			onOrder[line.ID] = true
+		}
+		serials := make(map[uuid.UUID][]string)
+		for _, item := range req.Lines {
+			if !onOrder[item.OrderLineID] {
+				return fmt.Errorf("%w: %s", ErrOutboundLineNotOnOrder, item.OrderLineID)
+			}
+			serials[item.OrderLineID] = append(serials[item.OrderLineID], item.SerialNumbers...)
+		}
+
+		referenceNote := order.OrderNumber
+		if order.CustomerReference != "" {
+			referenceNote += " / " + order.CustomerReference
+		}
+
+		now := time.Now()
+		for i := range order.Lines {
+			line := &order.Lines[i]
+			var split map[uuid.UUID][]string
+			if line.Product.Serialized {
+				if split, err = splitShipmentSerials(stockRepo, line, byLine[line.ID], serials[line.ID]); err != nil {
+					return err
+				}
+			} else if len(serials[line.ID]) > 0 {
+				return fmt.Errorf("%s: %w", line.Product.SKU, ErrProductNotSerialized)
+			}
+
+			shipped := 0
+			for _, allocation := range byLine[line.ID] {
+				if _, err := closeReservation(reservationRepo, stockRepo, allocation.StockReservationID, "consumed"); err != nil {
+					return err
+				}
+				if _, err := applyStockMovement(stockRepo, u.costingMethod, stockMovementInput{
+					ProductID:           allocation.SourceProductID,
+					WarehouseLocationID: allocation.WarehouseLocationID,
+					MovementType:        "shipment",
+					Quantity:            allocation.Quantity,
+					SerialNumbers:       split[allocation.ID],
+					Reason:              "sales_order",
+					ReferenceType:       "outbound_order",
+					ReferenceID:         &order.ID,
+					ReferenceNote:       referenceNote,
+					UserID:              userID,
+				}); err != nil {
+					return fmt.Errorf("%s: %w", line.Product.SKU, err)
+				}
+				shipped += allocation.Quantity
+			}
+
+			line.ShippedQuantity = shipped
+			line.Status = "shipped"
+			if shipped < line.Quantity {
+				line.Status = "short_shipped"
+			}
+			line.UpdatedAt = now
+			if err := repo.UpdateOutboundOrderLine(line); err != nil {
+				return err
+			}
+		}
+		order.Status = "shipped"
+		if req.Carrier != "" {
+			order.Carrier = req.Carrier
+		}
+		order.TrackingNumber = req.TrackingNumber
+		order.ShippedBy = &userID
+		order.ShippedAt = &now
+		order.UpdatedAt = now
+		return repo.UpdateOutboundOrder(order)
+	})
+	if err != nil {
+		return nil, err
+	}
+	u.log.Info(fmt.Sprintf("Outbound order %s shipped", id))
+	return u.GetOutboundOrderByID(ctx, id)
+}
+
+// splitShipmentSerials membagi nomor serial satu baris ke alokasinya berdasarkan lokasi serial saat ini
+func splitShipmentSerials(stockRepo repositorys.ProductRepository, line *models.OutboundOrderLine, allocations []*models.OutboundAllocation, serials []string) (map[uuid.UUID][]string, error) {
+	total := 0
+	for _, allocation := range allocations {
+		total += allocation.Quantity
+	}
+	if err := checkSerialList(serials, total); err != nil {
+		return nil, fmt.Errorf("%s: %w", line.Product.SKU, err)
+	}
+
+	byLocation := make(map[uuid.UUID][]string)
+	for _, sn := range serials {
+		serial, err := stockRepo.GetSerialNumber(line.SourceProductID, sn)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: %s", ErrSerialNotAvailable, sn)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if serial.Status != "in_stock" || serial.WarehouseLocationID == nil {
+			return nil, fmt.Errorf("%w: %s", ErrSerialNotAvailable, sn)
+		}
+		byLocation[*serial.WarehouseLocationID] = append(byLocation[*serial.WarehouseLocationID], sn)
+	}
+
+	split := make(map[uuid.UUID][]string, len(allocations))
+	for _, allocation := range allocations {
+		available := byLocation[allocation.WarehouseLocationID]
+		if len(available) < allocation.Quantity {
+			return nil, fmt.Errorf("%s: %d serial(s) needed from %s: %w", line.Product.SKU, allocation.Quantity, allocation.WarehouseLocation.Name, ErrSerialNotAvailable)
+		}
+		split[allocation.ID] = available[:allocation.Quantity]
+		byLocation[allocation.WarehouseLocationID] = available[allocation.Quantity:]
+	}
+	return split, nil
+}
+
+// CancelOutboundOrder membatalkan pesanan yang belum dikirim dan melepas seluruh reservasinya
+func (u *outboundOrderUseCase) CancelOutboundOrder(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*dtos.OutboundOrderResponse, error) {
+	err := u.repo.WithTransaction(func(repo repositorys.OutboundOrderRepository, reservationRepo repositorys.StockReservationRepository, stockRepo repositorys.ProductRepository) error {
+		order, err := repo.GetOutboundOrderForUpdate(id)
+		if err != nil {
+			return err
+		}
+		if order.Status == "shipped" || order.Status == "cancelled" {
+			return fmt.Errorf("%w: cannot cancel a %s order", ErrInvalidOutboundOrderState, order.Status)
+		}
+
+		allocations, err := repo.GetOutboundAllocationsByOrderID(order.ID)
+		if err != nil {
+			return err
+		}
+		for _, allocation := range allocations {
+			if allocation.Reservation.Status != "active" {
+				continue
+			}
+			if _, err := closeReservation(reservationRepo, stockRepo, allocation.StockReservationID, "released"); err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		for i := range order.Lines {
+			order.Lines[i].Status = "cancelled"
+			order.Lines[i].UpdatedAt = now
+			if err := repo.UpdateOutboundOrderLine(&order.Lines[i]); err != nil {
+				return err
+			}
+		}
+
+		order.Status = "cancelled"
+		order.CancelledBy = &userID
+		order.CancelledAt = &now
+		order.UpdatedAt = now
+		return repo.UpdateOutboundOrder(order)
+	})
+	if err != nil {
+		return nil, err
+	}
+	u.log.Info(fmt.Sprintf("Outbound order %s cancelled", id))
+	return u.GetOutboundOrderByID(ctx, id)
+}
+
+func toOutboundOrderResponse(o *models.OutboundOrder) *dtos.OutboundOrderResponse {
+	response := &dtos.OutboundOrderResponse{
+		ID:                  o.ID,
+		OrderNumber:         o.OrderNumber,
+		CustomerName:        o.CustomerName,
+		CustomerReference:   o.CustomerReference,
+		ShippingAddress:     o.ShippingAddress,
+		WarehouseLocationID: o.WarehouseLocationID,
+		PickWaveID:          o.PickWaveID,
+		Status:              o.Status,
+		RequestedShipDate:   o.RequestedShipDate,
+		Note:                o.Note,
+		PackageCount:        o.PackageCount,
+		Carrier:             o.Carrier,
+		TrackingNumber:      o.TrackingNumber,
+		Lines:               make([]dtos.OutboundOrderLineResponse, 0, len(o.Lines)),
+		CreatedBy:           o.CreatedBy,
+		PackedBy:            o.PackedBy,
+		PackedAt:            o.PackedAt,
+		ShippedBy:           o.ShippedBy,
+		ShippedAt:           o.ShippedAt,
+		CancelledBy:         o.CancelledBy,
+		CancelledAt:         o.CancelledAt,
+		CreatedAt:           o.CreatedAt,
+		UpdatedAt:           o.UpdatedAt,
+	}
+	if o.WarehouseLocation != nil {
+		response.WarehouseLocationName = o.WarehouseLocation.Name
+	}
+	for _, line := range o.Lines {
+		lineTotal := float64(line.Quantity) * line.UnitPrice
+		lineResponse := dtos.OutboundOrderLineResponse{
+			ID:                line.ID,
+			ProductID:         line.SourceProductID,
+			ProductName:       line.Product.Name,
+			SKU:               line.Product.SKU,
+			Quantity:          line.Quantity,
+			AllocatedQuantity: line.AllocatedQuantity,
+			BackorderQuantity: max(line.Quantity-line.AllocatedQuantity, 0),
+			PackedQuantity:    line.PackedQuantity,
+			ShippedQuantity:   line.ShippedQuantity,
+			UnitPrice:         line.UnitPrice,
+			LineTotal:         lineTotal,
+			Status:            line.Status,
+			Allocations:       make([]dtos.OutboundAllocationResponse, 0, len(line.Allocations)),
+		}
+		for _, allocation := range line.Allocations {
+			lineResponse.Allocations = append(lineResponse.Allocations, dtos.OutboundAllocationResponse{
+				ID:                    allocation.ID,
+				StockReservationID:    allocation.StockReservationID,
+				WarehouseLocationID:   allocation.WarehouseLocationID,
+				WarehouseLocationName: allocation.WarehouseLocation.Name,
+				WarehouseLocationPath: allocation.WarehouseLocation.Path,
+				Quantity:              allocation.Quantity,
+				Status:                allocation.Reservation.Status,
+			})
+		}
+		response.Lines = append(response.Lines, lineResponse)
+		response.TotalQuantity += line.Quantity
+		response.AllocatedQuantity += line.AllocatedQuantity
+		response.ShippedQuantity += line.ShippedQuantity
+		response.TotalAmount += lineTotal
+	}
+	return response
+}
@@ -0,0 +1,21 @@
+package usecases
+
+import "auth-service/internal/dtos"
+
+// buildPagination menghitung informasi pagination dari total item
+func buildPagination(page, limit int, total int64) dtos.Pagination {
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+	hasNextPage := page < totalPages
+	nextPage := page + 1
+	if !hasNextPage {
+		nextPage = 0
+	}
+
+	return dtos.Pagination{
+		HasNextPage: hasNextPage,
+		NextPage:    &nextPage,
+		CurrentPage: page,
+		TotalPages:  totalPages,
+		TotalItems:  int(total),
+	}
+}
@@ -0,0 +1,435 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"auth-service/internal/dtos"
+	"auth-service/internal/models"
+	"auth-service/internal/repositorys"
+	"auth-service/internal/utils"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrInvalidPickWaveState = errors.New("invalid pick wave state for this action")
+	ErrNoOrdersForWave      = errors.New("no allocated orders match the wave rules")
+)
+
+// fallbackWaveMaxLines dipakai bila pick_waves.default_max_lines tidak dikonfigurasi
+const fallbackWaveMaxLines = 100
+
+type PickWaveUseCase interface {
+	CreatePickWave(ctx context.Context, req dtos.CreatePickWaveRequest, userID uuid.UUID) (*dtos.PickWaveResponse, error)
+	GetPickWaveByID(ctx context.Context, id uuid.UUID) (*dtos.PickWaveResponse, error)
+	GetPickWavesList(ctx context.Context, req dtos.PickWaveListRequest) ([]dtos.PickWaveResponse, dtos.Pagination, error)
+	GetPickRoute(ctx context.Context, id uuid.UUID) (*dtos.PickRouteResponse, error)
+	CompletePickWave(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*dtos.PickWaveResponse, error)
+	CancelPickWave(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*dtos.PickWaveResponse, error)
+}
+
+type pickWaveUseCase struct {
+	repo            repositorys.PickWaveRepository
+	defaultMaxLines int
+	validate        *validator.Validate
+	log             *logrus.Logger
+}
+
+func NewPickWaveUseCase(repo repositorys.PickWaveRepository, defaultMaxLines int, log *logrus.Logger, validate *validator.Validate) PickWaveUseCase {
+	if defaultMaxLines <= 0 {
+		defaultMaxLines = fallbackWaveMaxLines
+	}
+	return &pickWaveUseCase{repo: repo, defaultMaxLines: defaultMaxLines, log: log, validate: validate}
+}
+
+// CreatePickWave memilih pesanan teralokasi yang memenuhi aturan lalu mengikatnya ke wave baru.
+// Pesanan diisi first-fit: pesanan yang membuat total baris melewati max_lines dilewati.
+func (u *pickWaveUseCase) CreatePickWave(ctx context.Context, req dtos.CreatePickWaveRequest, userID uuid.UUID) (*dtos.PickWaveResponse, error) {
+	if err := u.validate.Struct(req); err != nil {
+		return nil, err
+	}
+	var cutoff *time.Time
+	if req.Cutoff != "" {
+		t, ok := parseLedgerTime(req.Cutoff, true)
+		if !ok {
+			return nil, ErrInvalidDateFilter
+		}
+		cutoff = &t
+	}
+	maxLines := req.MaxLines
+	if maxLines == 0 {
+		maxLines = u.defaultMaxLines
+	}
+
+	wave := &models.PickWave{
+		ID:         uuid.New(),
+		WaveNumber: utils.GenerateDocumentNumber("WAV"),
+		Status:     "open",
+		Carrier:    req.Carrier,
+		Cutoff:     cutoff,
+		ZoneID:     req.ZoneID,
+		MaxLines:   maxLines,
+		Note:       req.Note,
+		CreatedBy:  userID,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+	err := u.repo.WithTransaction(func(repo repositorys.PickWaveRepository, stockRepo repositorys.ProductRepository) error {
+		var zone *models.WarehouseLocation
+		if req.ZoneID != nil {
+			var err error
+			if zone, err = stockRepo.GetWarehouseLocationByID(*req.ZoneID); err != nil {
+				return fmt.Errorf("zone not found: %w", err)
+			}
+		}
+
+		candidates, err := repo.GetWaveCandidateOrdersForUpdate(req.Carrier, cutoff, zone)
+		if err != nil {
+			return err
+		}
+		var orderIDs []uuid.UUID
+		for _, candidate := range candidates {
+			if wave.LineCount+candidate.LineCount > maxLines {
+				continue
+			}
+			orderIDs = append(orderIDs, candidate.ID)
+			wave.LineCount += candidate.LineCount
+		}
+		if len(orderIDs) == 0 {
+			return ErrNoOrdersForWave
+		}
+		wave.OrderCount = len(orderIDs)
+
+		if err := repo.CreatePickWave(wave); err != nil {
+			return err
+		}
+		return repo.AssignOrdersToWave(wave.ID, orderIDs)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	u.log.Info(fmt.Sprintf("Pick wave %s created with %d order(s), %d line(s)", wave.WaveNumber, wave.OrderCount, wave.LineCount))
+	return u.GetPickWaveByID(ctx, wave.ID)
+}
+
+func (u *pickWaveUseCase) GetPickWaveByID(ctx context.Context, id uuid.UUID) (*dtos.PickWaveResponse, error) {
+	wave, err := u.repo.GetPickWaveByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return toPickWaveResponse(wave), nil
+}
+
+func (u *pickWaveUseCase) GetPickWavesList(ctx context.Context, req dtos.PickWaveListRequest) ([]dtos.PickWaveResponse, dtos.Pagination, error) {
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.Limit == 0 {
+		req.Limit = 10
+	}
+	if err := u.validate.Struct(req); err != nil {
+		return nil, dtos.Pagination{}, err
+	}
+
+	waves, total, err := u.repo.GetPickWavesList(req)
+	if err != nil {
+		return nil, dtos.Pagination{}, err
+	}
+
+	list := make([]dtos.PickWaveResponse, 0, len(waves))
+	for i := range waves {
+		list = append(list, *toPickWaveResponse(&waves[i]))
+	}
+	return list, buildPagination(req.Page, req.Limit, total), nil
+}
+
+// GetPickRoute menggabungkan alokasi aktif seluruh pesanan wave menjadi stop per lokasi.
+// Stop diurutkan menurut path lokasi, lalu arah setiap aisle dibuat bergantian (pola S)
+// sehingga picker tidak perlu kembali ke ujung aisle sebelum masuk aisle berikutnya.
+func (u *pickWaveUseCase) GetPickRoute(ctx context.Context, id uuid.UUID) (*dtos.PickRouteResponse, error) {
+	wave, err := u.repo.GetPickWaveByID(id)
+	if err != nil {
+		return nil, err
+	}
+	allocations, err := u.repo.GetPickWaveAllocations(id)
+	if err != nil {
+		return nil, err
+	}
+
+	orderNumbers := make(map[uuid.UUID]string, len(wave.Orders))
+	for _, order := range wave.Orders {
+		orderNumbers[order.ID] = order.OrderNumber
+	}
+
+	response := &dtos.PickRouteResponse{
+		WaveID:     wave.ID,
+		WaveNumber: wave.WaveNumber,
+		Status:     wave.Status,
+		Stops:      []dtos.PickStopResponse{},
+	}
+	// Alokasi sudah dikelompokkan per path lokasi, cukup buka stop baru setiap lokasi berganti
+	for _, allocation := range allocations {
+		last := len(response.Stops) - 1
+		if last < 0 || response.Stops[last].WarehouseLocationID != allocation.WarehouseLocationID {
+			response.Stops = append(response.Stops, dtos.PickStopResponse{
+				WarehouseLocationID:   allocation.WarehouseLocationID,
+				WarehouseLocationName: allocation.WarehouseLocation.Name,
+				WarehouseLocationPath: allocation.WarehouseLocation.Path,
+			})
+			last++
+		}
+		stop := &response.Stops[last]
+
+		index := slices.IndexFunc(stop.Items, func(item dtos.PickStopItemResponse) bool {
+			return item.ProductID == allocation.SourceProductID
+		})
+		if index < 0 {
+			stop.Items = append(stop.Items, dtos.PickStopItemResponse{
+				ProductID:   allocation.SourceProductID,
+				ProductName: allocation.Product.Name,
+				SKU:         allocation.Product.SKU,
+				LotTracked:  allocation.Product.LotTracked,
+				Serialized:  allocation.Product.Serialized,
+			})
+			index = len(stop.Items) - 1
+		}
+		stop.Items[index].Quantity += allocation.Quantity
+		stop.Items[index].Orders = append(stop.Items[index].Orders, dtos.PickStopOrderResponse{
+			OrderID:      allocation.OutboundOrderID,
+			OrderNumber:  orderNumbers[allocation.OutboundOrderID],
+			AllocationID: allocation.ID,
+			Quantity:     allocation.Quantity,
+		})
+		response.TotalQuantity += allocation.Quantity
+	}
+
+	if response.Stops, err = u.serpentineStops(response.Stops); err != nil {
+		return nil, err
+	}
+	for i := range response.Stops {
+		response.Stops[i].Sequence = i + 1
+	}
+	return response, nil
+}
+
+// serpentineStops mengurutkan stop menurut path lokasi secara natural (A-2 sebelum A-10), lalu membalik
+// urutan stop pada setiap aisle kedua. Stop dikelompokkan menurut leluhur bertipe aisle; lokasi di luar
+// aisle dikelompokkan menurut parent path-nya.
+func (u *pickWaveUseCase) serpentineStops(stops []dtos.PickStopResponse) ([]dtos.PickStopResponse, error) {
+	stops = slices.Clone(stops)
+	slices.SortStableFunc(stops, func(a, b dtos.PickStopResponse) int {
+		return compareLocationPaths(a.WarehouseLocationPath, b.WarehouseLocationPath)
+	})
+
+	var prefixes []string
+	seen := make(map[string]bool)
+	for _, stop := range stops {
+		for _, prefix := range locationPathPrefixes(stop.WarehouseLocationPath) {
+			if !seen[prefix] {
+				seen[prefix] = true
+				prefixes = append(prefixes, prefix)
+			}
+		}
+	}
+	if len(prefixes) == 0 {
+		return stops, nil
+	}
+	locations, err := u.repo.GetWarehouseLocationsByPaths(prefixes)
+	if err != nil {
+		return nil, err
+	}
+	aisles := make(map[string]bool)
+	for _, location := range locations {
+		if location.Type == "aisle" {
+			aisles[location.Path] = true
+		}
+	}
+
+	aisleKey := func(path string) string {
+		ancestors := locationPathPrefixes(path)
+		for i := len(ancestors) - 1; i >= 0; i-- {
+			if aisles[ancestors[i]] {
+				return ancestors[i]
+			}
+		}
+		if len(ancestors) > 1 {
+			return ancestors[len(ancestors)-2]
+		}
+		return ""
+	}
+
+	ordered := make([]dtos.PickStopResponse, 0, len(stops))
+	group := 0
+	for start := 0; start < len(stops); {
+		key := aisleKey(stops[start].WarehouseLocationPath)
+		end := start + 1
+		for end < len(stops) && aisleKey(stops[end].WarehouseLocationPath) == key {
+			end++
+		}
+		segment := slices.Clone(stops[start:end])
+		if group%2 == 1 {
+			slices.Reverse(segment)
+		}
+		ordered = append(ordered, segment...)
+		group++
+		start = end
+	}
+	return ordered, nil
+}
+
+// locationPathPrefixes mengembalikan path setiap leluhur beserta path itu sendiri, dari root
+func locationPathPrefixes(path string) []string {
+	if path == "" {
+		return nil
+	}
+	parts := strings.Split(path, "-")
+	prefixes := make([]string, len(parts))
+	for i := range parts {
+		prefixes[i] = strings.Join(parts[:i+1], "-")
+	}
+	return prefixes
+}
+
+// compareLocationPaths membandingkan path per segmen; angka di dalam segmen dibandingkan sebagai
+// bilangan sehingga A-2 berada sebelum A-10, dan parent berada sebelum turunannya
+func compareLocationPaths(a, b string) int {
+	as, bs := strings.Split(a, "-"), strings.Split(b, "-")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		if c := compareNatural(as[i], bs[i]); c != 0 {
+			return c
+		}
+	}
+	return len(as) - len(bs)
+}
+
+// compareNatural membandingkan dua teks dengan deretan digit dibaca sebagai bilangan
+func compareNatural(a, b string) int {
+	for a != "" && b != "" {
+		ra, rb := leadingRun(a), leadingRun(b)
+		da, db := isDigit(ra[0]), isDigit(rb[0])
+		var c int
+		switch {
+		case da && db:
+			na, nb := strings.TrimLeft(ra, "0"), strings.TrimLeft(rb, "0")
+			if c = len(na) - len(nb); c == 0 {
+				c = strings.Compare(na, nb)
+			}
+		default:
+			c = strings.Compare(ra, rb)
+		}
+		if c != 0 {
+			return c
+		}
+		a, b = a[len(ra):], b[len(rb):]
+	}
+	return len(a) - len(b)
+}
+
+// leadingRun mengembalikan awalan s yang seluruhnya digit atau seluruhnya bukan digit
+func leadingRun(s string) string {
+	digit := isDigit(s[0])
+	i := 1
+	for i < len(s) && isDigit(s[i]) == digit {
+		i++
+	}
+	return s[:i]
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// CompletePickWave menandai seluruh pengambilan wave selesai
+func (u *pickWaveUseCase) CompletePickWave(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*dtos.PickWaveResponse, error) {
+	err := u.repo.WithTransaction(func(repo repositorys.PickWaveRepository, _ repositorys.ProductRepository) error {
+		wave, err := repo.GetPickWaveForUpdate(id)
+		if err != nil {
+			return err
+		}
+		if wave.Status != "open" {
+			return fmt.Errorf("%w: cannot complete a %s wave", ErrInvalidPickWaveState, wave.Status)
+		}
+
+		now := time.Now()
+		wave.Status = "completed"
+		wave.CompletedBy = &userID
+		wave.CompletedAt = &now
+		wave.UpdatedAt = now
+		return repo.UpdatePickWave(wave)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return u.GetPickWaveByID(ctx, id)
+}
+
+// CancelPickWave membatalkan wave open dan melepas pesanannya agar bisa masuk wave lain
+func (u *pickWaveUseCase) CancelPickWave(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*dtos.PickWaveResponse, error) {
+	err := u.repo.WithTransaction(func(repo repositorys.PickWaveRepository, _ repositorys.ProductRepository) error {
+		wave, err := repo.GetPickWaveForUpdate(id)
+		if err != nil {
+			return err
+		}
+		if wave.Status != "open" {
+			return fmt.Errorf("%w: cannot cancel a %s wave", ErrInvalidPickWaveState, wave.Status)
+		}
+		if err := repo.ReleaseWaveOrders(wave.ID); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		wave.Status = "cancelled"
+		wave.CancelledBy = &userID
+		wave.CancelledAt = &now
+		wave.UpdatedAt = now
+		return repo.UpdatePickWave(wave)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return u.GetPickWaveByID(ctx, id)
+}
+
+func toPickWaveResponse(w *models.PickWave) *dtos.PickWaveResponse {
+	response := &dtos.PickWaveResponse{
+		ID:          w.ID,
+		WaveNumber:  w.WaveNumber,
+		Status:      w.Status,
+		Carrier:     w.Carrier,
+		Cutoff:      w.Cutoff,
+		ZoneID:      w.ZoneID,
+		MaxLines:    w.MaxLines,
+		OrderCount:  w.OrderCount,
+		LineCount:   w.LineCount,
+		Note:        w.Note,
+		CreatedBy:   w.CreatedBy,
+		CompletedBy: w.CompletedBy,
+		CompletedAt: w.CompletedAt,
+		CancelledBy: w.CancelledBy,
+		CancelledAt: w.CancelledAt,
+		CreatedAt:   w.CreatedAt,
+		UpdatedAt:   w.UpdatedAt,
+	}
+	if w.Zone != nil {
+		response.ZoneName = w.Zone.Name
+	}
+	for _, order := range w.Orders {
+		response.Orders = append(response.Orders, dtos.PickWaveOrderResponse{
+			ID:           order.ID,
+			OrderNumber:  order.OrderNumber,
+			CustomerName: order.CustomerName,
+			Carrier:      order.Carrier,
+			Status:       order.Status,
+			LineCount:    len(order.Lines),
+		})
+	}
+	return response
+}
@@ -0,0 +1,153 @@
+package usecases
+
+import (
+	"testing"
+
+	"auth-service/internal/dtos"
+	"auth-service/internal/models"
+	"auth-service/internal/repositorys"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePickWaveRepository hanya menyediakan lokasi untuk pencarian aisle
+type fakePickWaveRepository struct {
+	repositorys.PickWaveRepository
+	types map[string]string // path -> type lokasi
+}
+
+func (r *fakePickWaveRepository) GetWarehouseLocationsByPaths(paths []string) ([]models.WarehouseLocation, error) {
+	var locations []models.WarehouseLocation
+	for _, path := range paths {
+		if locationType, ok := r.types[path]; ok {
+			locations = append(locations, models.WarehouseLocation{Path: path, Type: locationType})
+		}
+	}
+	return locations, nil
+}
+
+func pickStops(paths ...string) []dtos.PickStopResponse {
+	stops := make([]dtos.PickStopResponse, len(paths))
+	for i, path := range paths {
+		stops[i] = dtos.PickStopResponse{WarehouseLocationPath: path}
+	}
+	return stops
+}
+
+func stopPaths(stops []dtos.PickStopResponse) []string {
+	paths := make([]string, len(stops))
+	for i, stop := range stops {
+		paths[i] = stop.WarehouseLocationPath
+	}
+	return paths
+}
+
+func TestCompareLocationPaths(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int // tanda hasil perbandingan
+	}{
+		{"WH1-A-2", "WH1-A-10", -1},
+		{"WH1-A-10", "WH1-A-9", 1},
+		{"WH1-A-10", "WH1-B-1", -1},
+		{"WH1-A", "WH1-A-1", -1},
+		{"WH1-A2-B03", "WH1-A2-B3", 0},
+		{"WH1-R2-B1", "WH1-R10-B1", -1},
+		{"WH2-A-1", "WH10-A-1", -1},
+		{"WH1-A-1", "WH1-A-1", 0},
+		{"WH1-A-1A", "WH1-A-1B", -1},
+		{"WH1-A-1", "WH1-A-1A", -1},
+	}
+	sign := func(n int) int {
+		switch {
+		case n < 0:
+			return -1
+		case n > 0:
+			return 1
+		}
+		return 0
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+" vs "+tt.b, func(t *testing.T) {
+			assert.Equal(t, tt.want, sign(compareLocationPaths(tt.a, tt.b)))
+			assert.Equal(t, -tt.want, sign(compareLocationPaths(tt.b, tt.a)))
+		})
+	}
+}
+
+func TestSerpentineStops(t *testing.T) {
+	aisles := map[string]string{
+		"WH1":        "warehouse",
+		"WH1-A":      "aisle",
+		"WH1-B":      "aisle",
+		"WH1-C":      "aisle",
+		"WH1-A-R1":   "rack",
+		"WH1-A-R2":   "rack",
+		"WH1-DOCK":   "zone",
+		"WH1-DOCK-1": "bin",
+	}
+	tests := []struct {
+		name  string
+		types map[string]string
+		stops []dtos.PickStopResponse
+		want  []string
+	}{
+		{
+			name:  "bins sort naturally within an aisle",
+			types: aisles,
+			stops: pickStops("WH1-A-10", "WH1-A-2", "WH1-A-1"),
+			want:  []string{"WH1-A-1", "WH1-A-2", "WH1-A-10"},
+		},
+		{
+			name:  "direction alternates per aisle",
+			types: aisles,
+			stops: pickStops("WH1-A-1", "WH1-A-2", "WH1-B-1", "WH1-B-2", "WH1-B-10", "WH1-C-1", "WH1-C-2"),
+			want:  []string{"WH1-A-1", "WH1-A-2", "WH1-B-10", "WH1-B-2", "WH1-B-1", "WH1-C-1", "WH1-C-2"},
+		},
+		{
+			name:  "racks and shelves are grouped under their aisle",
+			types: aisles,
+			stops: pickStops("WH1-A-R1-S1", "WH1-A-R2-S1", "WH1-A-R10-S1", "WH1-B-R1-S1", "WH1-B-R1-S2"),
+			want:  []string{"WH1-A-R1-S1", "WH1-A-R2-S1", "WH1-A-R10-S1", "WH1-B-R1-S2", "WH1-B-R1-S1"},
+		},
+		{
+			name:  "an aisle without stops does not change the direction",
+			types: aisles,
+			stops: pickStops("WH1-A-1", "WH1-A-2", "WH1-C-1", "WH1-C-2"),
+			want:  []string{"WH1-A-1", "WH1-A-2", "WH1-C-2", "WH1-C-1"},
+		},
+		{
+			name:  "locations outside an aisle are grouped by parent",
+			types: aisles,
+			stops: pickStops("WH1-A-1", "WH1-A-2", "WH1-DOCK-1", "WH1-DOCK-2"),
+			want:  []string{"WH1-A-1", "WH1-A-2", "WH1-DOCK-2", "WH1-DOCK-1"},
+		},
+		{
+			name:  "without aisle types stops are grouped by parent path",
+			types: map[string]string{},
+			stops: pickStops("Z2-10", "Z1-2", "Z1-10", "Z2-2"),
+			want:  []string{"Z1-2", "Z1-10", "Z2-10", "Z2-2"},
+		},
+		{
+			name:  "single stop",
+			types: aisles,
+			stops: pickStops("WH1-B-3"),
+			want:  []string{"WH1-B-3"},
+		},
+		{
+			name:  "no stops",
+			types: aisles,
+			stops: pickStops(),
+			want:  []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := &pickWaveUseCase{repo: &fakePickWaveRepository{types: tt.types}}
+			stops, err := u.serpentineStops(tt.stops)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, stopPaths(stops))
+		})
+	}
+}
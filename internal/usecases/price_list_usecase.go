@@ -0,0 +1,669 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"auth-service/internal/dtos"
+	"auth-service/internal/models"
+	"auth-service/internal/repositorys"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidCustomerGroup   = errors.New("customer_group is required for, and only allowed on, customer_group price lists")
+	ErrDefaultPriceListType   = errors.New("only a price list of type list can be the default")
+	ErrPriceListIsDefault     = errors.New("the default price list cannot be deleted or deactivated")
+	ErrPriceListInactive      = errors.New("price list is inactive")
+	ErrNoDefaultPriceList     = errors.New("no default price list is set")
+	ErrInvalidEffectiveDate   = errors.New("invalid 'effective_from'/'effective_to' value, use RFC3339 or YYYY-MM-DD")
+	ErrInvalidEffectivePeriod = errors.New("'effective_to' must be after 'effective_from'")
+	ErrPriceOverlap           = errors.New("price period overlaps an existing price for this product")
+)
+
+const (
+	PriceListTypeList          = "list"
+	PriceListTypeCost          = "cost"
+	PriceListTypeCustomerGroup = "customer_group"
+
+	defaultCurrency = "IDR"
+)
+
+type PriceListUseCase interface {
+	CreatePriceList(ctx context.Context, req dtos.CreatePriceListRequest, userID uuid.UUID) (*dtos.PriceListResponse, error)
+	GetPriceListByID(ctx context.Context, id uuid.UUID) (*dtos.PriceListResponse, error)
+	GetPriceListsList(ctx context.Context, req dtos.PriceListListRequest) ([]dtos.PriceListResponse, dtos.Pagination, error)
+	UpdatePriceList(ctx context.Context, id uuid.UUID, req dtos.UpdatePriceListRequest, userID uuid.UUID) (*dtos.PriceListResponse, error)
+	DeletePriceList(ctx context.Context, id uuid.UUID, userID uuid.UUID) error
+
+	CreateProductPrice(ctx context.Context, priceListID uuid.UUID, req dtos.ProductPriceRequest, userID uuid.UUID) (*dtos.ProductPriceResponse, error)
+	GetProductPricesList(ctx context.Context, priceListID uuid.UUID, req dtos.ProductPriceListRequest) ([]dtos.ProductPriceResponse, dtos.Pagination, error)
+	UpdateProductPrice(ctx context.Context, priceListID, id uuid.UUID, req dtos.UpdateProductPriceRequest, userID uuid.UUID) (*dtos.ProductPriceResponse, error)
+	DeleteProductPrice(ctx context.Context, priceListID, id uuid.UUID, userID uuid.UUID) error
+	GetProductPriceHistory(ctx context.Context, priceListID uuid.UUID, req dtos.ProductPriceHistoryRequest) ([]dtos.ProductPriceHistoryResponse, dtos.Pagination, error)
+	GetCurrentPrice(ctx context.Context, priceListID, productID uuid.UUID, req dtos.PriceLookupRequest) (*dtos.PriceLookupResponse, error)
+}
+
+type priceListUseCase struct {
+	repo        repositorys.PriceListRepository
+	productRepo repositorys.ProductRepository
+	validate    *validator.Validate
+	log         *logrus.Logger
+}
+
+func NewPriceListUseCase(repo repositorys.PriceListRepository, productRepo repositorys.ProductRepository, log *logrus.Logger, validate *validator.Validate) PriceListUseCase {
+	return &priceListUseCase{repo: repo, productRepo: productRepo, log: log, validate: validate}
+}
+
+// checkPriceList memastikan customer_group hanya diisi pada daftar customer_group dan
+// hanya daftar list yang aktif yang boleh menjadi default
+func checkPriceList(list *models.PriceList) error {
+	if (list.Type == PriceListTypeCustomerGroup) != (strings.TrimSpace(list.CustomerGroup) != "") {
+		return ErrInvalidCustomerGroup
+	}
+	if list.IsDefault && list.Type != PriceListTypeList {
+		return ErrDefaultPriceListType
+	}
+	if list.IsDefault && !list.IsActive {
+		return ErrPriceListIsDefault
+	}
+	return nil
+}
+
+func (u *priceListUseCase) CreatePriceList(ctx context.Context, req dtos.CreatePriceListRequest, userID uuid.UUID) (*dtos.PriceListResponse, error) {
+	if err := u.validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	list := &models.PriceList{
+		ID:            uuid.New(),
+		Code:          req.Code,
+		Name:          req.Name,
+		Type:          req.Type,
+		CustomerGroup: req.CustomerGroup,
+		Currency:      strings.ToUpper(req.Currency),
+		IsDefault:     req.IsDefault,
+		IsActive:      true,
+		Description:   req.Description,
+		CreatedBy:     userID,
+	}
+	if list.Currency == "" {
+		list.Currency = defaultCurrency
+	}
+	if err := checkPriceList(list); err != nil {
+		return nil, err
+	}
+
+	err := u.repo.WithTransaction(func(repo repositorys.PriceListRepository) error {
+		if err := repo.CreatePriceList(list); err != nil {
+			return err
+		}
+		if list.IsDefault {
+			return repo.ClearDefaultPriceLists(list.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	u.log.Info(fmt.Sprintf("Price list %s created", list.Code))
+	return u.GetPriceListByID(ctx, list.ID)
+}
+
+func (u *priceListUseCase) GetPriceListByID(ctx context.Context, id uuid.UUID) (*dtos.PriceListResponse, error) {
+	list, err := u.repo.GetPriceListByID(id)
+	if err != nil {
+		return nil, err
+	}
+	response := toPriceListResponse(list)
+	return &response, nil
+}
+
+func (u *priceListUseCase) GetPriceListsList(ctx context.Context, req dtos.PriceListListRequest) ([]dtos.PriceListResponse, dtos.Pagination, error) {
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.Limit == 0 {
+		req.Limit = 10
+	}
+	if err := u.validate.Struct(req); err != nil {
+		return nil, dtos.Pagination{}, err
+	}
+
+	lists, total, err := u.repo.GetPriceListsList(req)
+	if err != nil {
+		return nil, dtos.Pagination{}, err
+	}
+
+	responses := make([]dtos.PriceListResponse, 0, len(lists))
+	for i := range lists {
+		responses = append(responses, toPriceListResponse(&lists[i]))
+	}
+	return responses, buildPagination(req.Page, req.Limit, total), nil
+}
+
+// UpdatePriceList mengubah daftar harga; menjadikannya default melepas tanda default daftar lain
+func (u *priceListUseCase) UpdatePriceList(ctx context.Context, id uuid.UUID, req dtos.UpdatePriceListRequest, userID uuid.UUID) (*dtos.PriceListResponse, error) {
+	if err := u.validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	err := u.repo.WithTransaction(func(repo repositorys.PriceListRepository) error {
+		list, err := repo.GetPriceListForUpdate(id)
+		if err != nil {
+			return err
+		}
+		wasDefault := list.IsDefault
+		if req.Code != "" {
+			list.Code = req.Code
+		}
+		if req.Name != "" {
+			list.Name = req.Name
+		}
+		if req.CustomerGroup != "" {
+			list.CustomerGroup = req.CustomerGroup
+		}
+		if req.Currency != "" {
+			list.Currency = strings.ToUpper(req.Currency)
+		}
+		if req.Description != "" {
+			list.Description = req.Description
+		}
+		if req.IsActive != nil {
+			list.IsActive = *req.IsActive
+		}
+		if req.IsDefault != nil {
+			if wasDefault && !*req.IsDefault {
+				// Default hanya berpindah dengan menjadikan daftar lain default
+				return ErrPriceListIsDefault
+			}
+			list.IsDefault = *req.IsDefault
+		}
+		if err := checkPriceList(list); err != nil {
+			return err
+		}
+		list.UpdatedAt = time.Now()
+		if err := repo.UpdatePriceList(list); err != nil {
+			return err
+		}
+		if list.IsDefault && !wasDefault {
+			return repo.ClearDefaultPriceLists(list.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return u.GetPriceListByID(ctx, id)
+}
+
+// DeletePriceList menghapus daftar harga selain daftar default; harga dan riwayatnya tetap tersimpan
+func (u *priceListUseCase) DeletePriceList(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
+	list, err := u.repo.GetPriceListByID(id)
+	if err != nil {
+		return err
+	}
+	if list.IsDefault {
+		return ErrPriceListIsDefault
+	}
+	return u.repo.DeletePriceList(id)
+}
+
+// parseEffectiveTime menerima RFC3339 atau YYYY-MM-DD (awal hari)
+func parseEffectiveTime(value string) (time.Time, error) {
+	t, ok := parseLedgerTime(value, false)
+	if !ok {
+		return time.Time{}, ErrInvalidEffectiveDate
+	}
+	return t, nil
+}
+
+// parseEffectiveTo; nilai kosong berarti harga berlaku tanpa batas
+func parseEffectiveTo(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := parseEffectiveTime(value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func checkEffectivePeriod(from time.Time, to *time.Time) error {
+	if to != nil && !to.After(from) {
+		return ErrInvalidEffectivePeriod
+	}
+	return nil
+}
+
+func overlapError(existing []models.ProductPrice) error {
+	periods := make([]string, 0, len(existing))
+	for _, p := range existing {
+		period := p.EffectiveFrom.Format(time.RFC3339) + " - "
+		if p.EffectiveTo != nil {
+			period += p.EffectiveTo.Format(time.RFC3339)
+		}
+		periods = append(periods, period)
+	}
+	return fmt.Errorf("%w: %s", ErrPriceOverlap, strings.Join(periods, ", "))
+}
+
+// CreateProductPrice menambah harga produk pada daftar harga. Harga berjalan tanpa batas yang dimulai
+// sebelum harga baru otomatis ditutup pada effective_from harga baru; irisan lainnya ditolak.
+func (u *priceListUseCase) CreateProductPrice(ctx context.Context, priceListID uuid.UUID, req dtos.ProductPriceRequest, userID uuid.UUID) (*dtos.ProductPriceResponse, error) {
+	if err := u.validate.Struct(req); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	from := now
+	if req.EffectiveFrom != "" {
+		t, err := parseEffectiveTime(req.EffectiveFrom)
+		if err != nil {
+			return nil, err
+		}
+		from = t
+	}
+	to, err := parseEffectiveTo(req.EffectiveTo)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkEffectivePeriod(from, to); err != nil {
+		return nil, err
+	}
+	if _, err := u.productRepo.GetProductByID(req.ProductID); err != nil {
+		return nil, err
+	}
+
+	price := &models.ProductPrice{
+		ID:              uuid.New(),
+		PriceListID:     priceListID,
+		SourceProductID: req.ProductID,
+		Price:           roundCost(req.Price),
+		EffectiveFrom:   from,
+		EffectiveTo:     to,
+		CreatedBy:       userID,
+		UpdatedBy:       userID,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	err = u.repo.WithTransaction(func(repo repositorys.PriceListRepository) error {
+		if _, err := repo.GetPriceListForUpdate(priceListID); err != nil {
+			return err
+		}
+		existing, err := repo.GetOverlappingProductPrices(priceListID, req.ProductID, from, to, uuid.Nil)
+		if err != nil {
+			return err
+		}
+		var conflicts []models.ProductPrice
+		for i := range existing {
+			current := existing[i]
+			if current.EffectiveTo != nil || !current.EffectiveFrom.Before(from) {
+				conflicts = append(conflicts, current)
+				continue
+			}
+			old := current
+			current.EffectiveTo = &from
+			current.UpdatedBy = userID
+			current.UpdatedAt = now
+			if err := repo.UpdateProductPrice(&current); err != nil {
+				return err
+			}
+			note := "closed by new price from " + from.Format(time.RFC3339)
+			if err := repo.CreateProductPriceHistory(priceHistoryEntry("updated", &old, &current, note, userID)); err != nil {
+				return err
+			}
+		}
+		if len(conflicts) > 0 {
+			return overlapError(conflicts)
+		}
+		if err := repo.CreateProductPrice(price); err != nil {
+			return err
+		}
+		return repo.CreateProductPriceHistory(priceHistoryEntry("created", nil, price, req.Note, userID))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	saved, err := u.repo.GetProductPriceByID(priceListID, price.ID)
+	if err != nil {
+		return nil, err
+	}
+	response := toProductPriceResponse(saved)
+	return &response, nil
+}
+
+func (u *priceListUseCase) GetProductPricesList(ctx context.Context, priceListID uuid.UUID, req dtos.ProductPriceListRequest) ([]dtos.ProductPriceResponse, dtos.Pagination, error) {
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.Limit == 0 {
+		req.Limit = 10
+	}
+	if err := u.validate.Struct(req); err != nil {
+		return nil, dtos.Pagination{}, err
+	}
+	var at *time.Time
+	if req.At != "" {
+		t, ok := parseLedgerTime(req.At, true)
+		if !ok {
+			return nil, dtos.Pagination{}, ErrInvalidAsOfTime
+		}
+		at = &t
+	}
+	if _, err := u.repo.GetPriceListByID(priceListID); err != nil {
+		return nil, dtos.Pagination{}, err
+	}
+
+	prices, total, err := u.repo.GetProductPricesList(priceListID, req, at)
+	if err != nil {
+		return nil, dtos.Pagination{}, err
+	}
+
+	list := make([]dtos.ProductPriceResponse, 0, len(prices))
+	for i := range prices {
+		list = append(list, toProductPriceResponse(&prices[i]))
+	}
+	return list, buildPagination(req.Page, req.Limit, total), nil
+}
+
+// UpdateProductPrice mengubah harga atau periodenya; periode baru tidak boleh beririsan dengan harga lain
+func (u *priceListUseCase) UpdateProductPrice(ctx context.Context, priceListID, id uuid.UUID, req dtos.UpdateProductPriceRequest, userID uuid.UUID) (*dtos.ProductPriceResponse, error) {
+	if err := u.validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	err := u.repo.WithTransaction(func(repo repositorys.PriceListRepository) error {
+		if _, err := repo.GetPriceListForUpdate(priceListID); err != nil {
+			return err
+		}
+		price, err := repo.GetProductPriceByID(priceListID, id)
+		if err != nil {
+			return err
+		}
+		old := *price
+		if req.Price != nil {
+			price.Price = roundCost(*req.Price)
+		}
+		if req.EffectiveFrom != nil {
+			if price.EffectiveFrom, err = parseEffectiveTime(*req.EffectiveFrom); err != nil {
+				return err
+			}
+		}
+		if req.EffectiveTo != nil {
+			if price.EffectiveTo, err = parseEffectiveTo(*req.EffectiveTo); err != nil {
+				return err
+			}
+		}
+		if err := checkEffectivePeriod(price.EffectiveFrom, price.EffectiveTo); err != nil {
+			return err
+		}
+
+		existing, err := repo.GetOverlappingProductPrices(priceListID, price.SourceProductID, price.EffectiveFrom, price.EffectiveTo, price.ID)
+		if err != nil {
+			return err
+		}
+		if len(existing) > 0 {
+			return overlapError(existing)
+		}
+		price.UpdatedBy = userID
+		price.UpdatedAt = time.Now()
+		if err := repo.UpdateProductPrice(price); err != nil {
+			return err
+		}
+		return repo.CreateProductPriceHistory(priceHistoryEntry("updated", &old, price, req.Note, userID))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	saved, err := u.repo.GetProductPriceByID(priceListID, id)
+	if err != nil {
+		return nil, err
+	}
+	response := toProductPriceResponse(saved)
+	return &response, nil
+}
+
+func (u *priceListUseCase) DeleteProductPrice(ctx context.Context, priceListID, id uuid.UUID, userID uuid.UUID) error {
+	return u.repo.WithTransaction(func(repo repositorys.PriceListRepository) error {
+		if _, err := repo.GetPriceListForUpdate(priceListID); err != nil {
+			return err
+		}
+		price, err := repo.GetProductPriceByID(priceListID, id)
+		if err != nil {
+			return err
+		}
+		if err := repo.DeleteProductPrice(price.ID); err != nil {
+			return err
+		}
+		return repo.CreateProductPriceHistory(priceHistoryEntry("deleted", price, nil, "", userID))
+	})
+}
+
+func (u *priceListUseCase) GetProductPriceHistory(ctx context.Context, priceListID uuid.UUID, req dtos.ProductPriceHistoryRequest) ([]dtos.ProductPriceHistoryResponse, dtos.Pagination, error) {
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.Limit == 0 {
+		req.Limit = 10
+	}
+	if err := u.validate.Struct(req); err != nil {
+		return nil, dtos.Pagination{}, err
+	}
+	from, to, err := parseLedgerRange(req.From, req.To)
+	if err != nil {
+		return nil, dtos.Pagination{}, err
+	}
+	if _, err := u.repo.GetPriceListByID(priceListID); err != nil {
+		return nil, dtos.Pagination{}, err
+	}
+
+	entries, total, err := u.repo.GetProductPriceHistory(priceListID, req, from, to)
+	if err != nil {
+		return nil, dtos.Pagination{}, err
+	}
+
+	list := make([]dtos.ProductPriceHistoryResponse, 0, len(entries))
+	for _, e := range entries {
+		list = append(list, dtos.ProductPriceHistoryResponse{
+			ID:               e.ID,
+			ProductPriceID:   e.ProductPriceID,
+			PriceListID:      e.PriceListID,
+			ProductID:        e.SourceProductID,
+			ProductName:      e.Product.Name,
+			SKU:              e.Product.SKU,
+			Action:           e.Action,
+			OldPrice:         e.OldPrice,
+			NewPrice:         e.NewPrice,
+			OldEffectiveFrom: e.OldEffectiveFrom,
+			OldEffectiveTo:   e.OldEffectiveTo,
+			NewEffectiveFrom: e.NewEffectiveFrom,
+			NewEffectiveTo:   e.NewEffectiveTo,
+			Note:             e.Note,
+			ChangedBy:        e.ChangedBy,
+			ChangedAt:        e.ChangedAt,
+		})
+	}
+	return list, buildPagination(req.Page, req.Limit, total), nil
+}
+
+// GetCurrentPrice mengembalikan harga produk yang berlaku pada waktu at dalam daftar harga
+func (u *priceListUseCase) GetCurrentPrice(ctx context.Context, priceListID, productID uuid.UUID, req dtos.PriceLookupRequest) (*dtos.PriceLookupResponse, error) {
+	at := time.Now()
+	if req.At != "" {
+		t, ok := parseLedgerTime(req.At, true)
+		if !ok {
+			return nil, ErrInvalidAsOfTime
+		}
+		at = t
+	}
+	list, err := priceListFor(u.productRepo, priceListID)
+	if err != nil {
+		return nil, err
+	}
+	product, err := u.productRepo.GetProductByID(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	prices, err := currentProductPrices(u.productRepo, list.ID, []models.Product{*product}, at)
+	if err != nil {
+		return nil, err
+	}
+	price, ok := prices[product.ID]
+	if !ok {
+		return nil, fmt.Errorf("no price for product %s in price list %s at %s: %w", product.SKU, list.Code, at.Format(time.RFC3339), gorm.ErrRecordNotFound)
+	}
+	return &dtos.PriceLookupResponse{
+		PriceListID:     list.ID,
+		PriceListCode:   list.Code,
+		Currency:        list.Currency,
+		ProductID:       product.ID,
+		PricedProductID: price.SourceProductID,
+		ProductPriceID:  price.ID,
+		Price:           price.Price,
+		EffectiveFrom:   price.EffectiveFrom,
+		EffectiveTo:     price.EffectiveTo,
+		At:              at,
+	}, nil
+}
+
+// priceListFor mengembalikan daftar harga aktif dengan id tersebut, atau daftar default bila id kosong
+func priceListFor(repo repositorys.ProductRepository, id uuid.UUID) (*models.PriceList, error) {
+	var list *models.PriceList
+	var err error
+	if id == uuid.Nil {
+		list, err = repo.GetDefaultPriceList()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNoDefaultPriceList
+		}
+	} else {
+		list, err = repo.GetPriceListByID(id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !list.IsActive {
+		return nil, ErrPriceListInactive
+	}
+	return list, nil
+}
+
+// currentProductPrices mengembalikan harga yang berlaku pada waktu at per produk; varian tanpa
+// harga sendiri memakai harga parent-nya
+func currentProductPrices(repo repositorys.ProductRepository, priceListID uuid.UUID, products []models.Product, at time.Time) (map[uuid.UUID]models.ProductPrice, error) {
+	result := make(map[uuid.UUID]models.ProductPrice, len(products))
+	if len(products) == 0 {
+		return result, nil
+	}
+	ids := make([]uuid.UUID, 0, len(products))
+	for _, p := range products {
+		ids = append(ids, p.ID)
+		if p.ParentID != nil {
+			ids = append(ids, *p.ParentID)
+		}
+	}
+	prices, err := repo.GetCurrentProductPrices(priceListID, ids, at)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range products {
+		if price, ok := prices[p.ID]; ok {
+			result[p.ID] = price
+		} else if p.ParentID != nil {
+			if price, ok := prices[*p.ParentID]; ok {
+				result[p.ID] = price
+			}
+		}
+	}
+	return result, nil
+}
+
+// toProductListPrice menyusun harga untuk ProductListResponse; Price nil bila produk tidak punya harga
+func toProductListPrice(list *models.PriceList, price models.ProductPrice, ok bool) *dtos.ProductListPrice {
+	response := &dtos.ProductListPrice{
+		PriceListID:   list.ID,
+		PriceListCode: list.Code,
+		Currency:      list.Currency,
+	}
+	if ok {
+		response.Price = &price.Price
+		response.EffectiveFrom = &price.EffectiveFrom
+		response.EffectiveTo = price.EffectiveTo
+	}
+	return response
+}
+
+// priceHistoryEntry mencatat perubahan harga; before nil untuk created, after nil untuk deleted
+func priceHistoryEntry(action string, before, after *models.ProductPrice, note string, userID uuid.UUID) *models.ProductPriceHistory {
+	entry := &models.ProductPriceHistory{
+		ID:        uuid.New(),
+		Action:    action,
+		Note:      note,
+		ChangedBy: userID,
+		ChangedAt: time.Now(),
+	}
+	for _, p := range []*models.ProductPrice{before, after} {
+		if p != nil {
+			entry.ProductPriceID = p.ID
+			entry.PriceListID = p.PriceListID
+			entry.SourceProductID = p.SourceProductID
+		}
+	}
+	if before != nil {
+		price, from := before.Price, before.EffectiveFrom
+		entry.OldPrice = &price
+		entry.OldEffectiveFrom = &from
+		entry.OldEffectiveTo = before.EffectiveTo
+	}
+	if after != nil {
+		price, from := after.Price, after.EffectiveFrom
+		entry.NewPrice = &price
+		entry.NewEffectiveFrom = &from
+		entry.NewEffectiveTo = after.EffectiveTo
+	}
+	return entry
+}
+
+func toPriceListResponse(l *models.PriceList) dtos.PriceListResponse {
+	return dtos.PriceListResponse{
+		ID:            l.ID,
+		Code:          l.Code,
+		Name:          l.Name,
+		Type:          l.Type,
+		CustomerGroup: l.CustomerGroup,
+		Currency:      l.Currency,
+		IsDefault:     l.IsDefault,
+		IsActive:      l.IsActive,
+		Description:   l.Description,
+		CreatedBy:     l.CreatedBy,
+		CreatedAt:     l.CreatedAt,
+		UpdatedAt:     l.UpdatedAt,
+	}
+}
+
+func toProductPriceResponse(p *models.ProductPrice) dtos.ProductPriceResponse {
+	return dtos.ProductPriceResponse{
+		ID:            p.ID,
+		PriceListID:   p.PriceListID,
+		ProductID:     p.SourceProductID,
+		ProductName:   p.Product.Name,
+		SKU:           p.Product.SKU,
+		Price:         p.Price,
+		EffectiveFrom: p.EffectiveFrom,
+		EffectiveTo:   p.EffectiveTo,
+		CreatedBy:     p.CreatedBy,
+		UpdatedBy:     p.UpdatedBy,
+		CreatedAt:     p.CreatedAt,
+		UpdatedAt:     p.UpdatedAt,
+	}
+}
@@ -0,0 +1,119 @@
+package usecases
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"auth-service/internal/dtos"
+	"auth-service/internal/models"
+)
+
+var (
+	ErrInvalidAttributeSchema  = errors.New("invalid attribute schema")
+	ErrInvalidProductAttribute = errors.New("invalid product attribute")
+)
+
+// toAttributeSchema mengubah skema dari request; allowed_values hanya boleh untuk string dan number
+func toAttributeSchema(definitions []dtos.AttributeDefinition) (models.AttributeSchema, error) {
+	schema := make(models.AttributeSchema, 0, len(definitions))
+	for _, d := range definitions {
+		if len(d.AllowedValues) > 0 {
+			switch d.Type {
+			case "string":
+			case "number":
+				for _, v := range d.AllowedValues {
+					if _, err := strconv.ParseFloat(v, 64); err != nil {
+						return nil, fmt.Errorf("%w: allowed value %q of %s is not a number", ErrInvalidAttributeSchema, v, d.Name)
+					}
+				}
+			default:
+				return nil, fmt.Errorf("%w: allowed_values are not supported for %s attribute %s", ErrInvalidAttributeSchema, d.Type, d.Name)
+			}
+		}
+		schema = append(schema, models.AttributeDefinition{
+			Name:          d.Name,
+			Type:          d.Type,
+			Required:      d.Required,
+			AllowedValues: d.AllowedValues,
+		})
+	}
+	return schema, nil
+}
+
+func toAttributeDefinitions(schema models.AttributeSchema) []dtos.AttributeDefinition {
+	list := make([]dtos.AttributeDefinition, 0, len(schema))
+	for _, d := range schema {
+		list = append(list, dtos.AttributeDefinition{
+			Name:          d.Name,
+			Type:          d.Type,
+			Required:      d.Required,
+			AllowedValues: d.AllowedValues,
+		})
+	}
+	return list
+}
+
+// checkCustomAttributes memvalidasi nilai atribut produk terhadap skema category.
+// Nilai null dianggap tidak diisi dan dibuang dari hasil.
+func checkCustomAttributes(schema models.AttributeSchema, values map[string]interface{}) (models.JSONMap, error) {
+	result := make(models.JSONMap, len(values))
+	for name, value := range values {
+		if value == nil {
+			continue
+		}
+		i := slices.IndexFunc(schema, func(d models.AttributeDefinition) bool { return d.Name == name })
+		if i < 0 {
+			return nil, fmt.Errorf("%w: %s is not defined for this category", ErrInvalidProductAttribute, name)
+		}
+		if err := checkAttributeValue(schema[i], value); err != nil {
+			return nil, err
+		}
+		result[name] = value
+	}
+	for _, d := range schema {
+		if _, ok := result[d.Name]; d.Required && !ok {
+			return nil, fmt.Errorf("%w: %s is required", ErrInvalidProductAttribute, d.Name)
+		}
+	}
+	return result, nil
+}
+
+func checkAttributeValue(d models.AttributeDefinition, value interface{}) error {
+	switch d.Type {
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%w: %s must be a string", ErrInvalidProductAttribute, d.Name)
+		}
+		if len(d.AllowedValues) > 0 && !slices.Contains(d.AllowedValues, s) {
+			return fmt.Errorf("%w: %s must be one of %v", ErrInvalidProductAttribute, d.Name, d.AllowedValues)
+		}
+	case "number":
+		n, ok := value.(float64)
+		if !ok {
+			return fmt.Errorf("%w: %s must be a number", ErrInvalidProductAttribute, d.Name)
+		}
+		if len(d.AllowedValues) > 0 && !slices.ContainsFunc(d.AllowedValues, func(v string) bool {
+			allowed, _ := strconv.ParseFloat(v, 64)
+			return allowed == n
+		}) {
+			return fmt.Errorf("%w: %s must be one of %v", ErrInvalidProductAttribute, d.Name, d.AllowedValues)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("%w: %s must be a boolean", ErrInvalidProductAttribute, d.Name)
+		}
+	case "date":
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%w: %s must be a date (YYYY-MM-DD)", ErrInvalidProductAttribute, d.Name)
+		}
+		if _, err := time.Parse("2006-01-02", s); err != nil {
+			return fmt.Errorf("%w: %s must be a date (YYYY-MM-DD)", ErrInvalidProductAttribute, d.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package usecases
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"auth-service/internal/dtos"
+	"auth-service/internal/models"
+	"auth-service/internal/repositorys"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrUnknownUnit    = errors.New("unit is not defined for this product")
+	ErrInvalidUnit    = errors.New("an alternate unit cannot be the same as the base unit")
+	ErrBaseUnitLocked = errors.New("base unit cannot be changed while the product has stock on hand")
+)
+
+// defaultBaseUnit dipakai bila produk dibuat tanpa base_unit
+const defaultBaseUnit = "each"
+
+// toProductUnits menyusun satuan alternatif produk dari request
+func toProductUnits(productID uuid.UUID, baseUnit string, units []dtos.ProductUnit) ([]models.ProductUnit, error) {
+	list := make([]models.ProductUnit, 0, len(units))
+	for _, unit := range units {
+		if unit.Unit == baseUnit {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidUnit, unit.Unit)
+		}
+		list = append(list, models.ProductUnit{
+			ID:        uuid.New(),
+			ProductID: productID,
+			Unit:      unit.Unit,
+			Factor:    unit.Factor,
+		})
+	}
+	return list, nil
+}
+
+func toProductUnitResponses(units []models.ProductUnit) []dtos.ProductUnit {
+	list := make([]dtos.ProductUnit, 0, len(units))
+	for _, unit := range units {
+		list = append(list, dtos.ProductUnit{Unit: unit.Unit, Factor: unit.Factor})
+	}
+	return list
+}
+
+// baseQuantity mengonversi quantity yang diinput dalam unit ke satuan dasar produk; unit kosong berarti satuan dasar
+func baseQuantity(repo repositorys.ProductRepository, productID uuid.UUID, unit string, quantity int) (int, error) {
+	if unit == "" {
+		return quantity, nil
+	}
+	factor, err := unitFactor(repo, productID, unit)
+	if err != nil {
+		return 0, err
+	}
+	return quantity * factor, nil
+}
+
+// baseUnitCost mengonversi biaya per unit input menjadi biaya per satuan dasar
+func baseUnitCost(cost *float64, quantity, base int) *float64 {
+	if cost == nil || quantity == base {
+		return cost
+	}
+	converted := *cost * float64(quantity) / float64(base)
+	return &converted
+}
+
+// unitFactor mengembalikan faktor konversi unit ke satuan dasar satu produk
+func unitFactor(repo repositorys.ProductRepository, productID uuid.UUID, unit string) (int, error) {
+	factors, err := repo.GetProductUnitFactors([]uuid.UUID{productID}, unit)
+	if err != nil {
+		return 0, err
+	}
+	factor, ok := factors[productID]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrUnknownUnit, unit)
+	}
+	return factor, nil
+}
+
+// inUnit mengubah quantity satuan dasar ke satuan lain, dibulatkan tiga desimal
+func inUnit(quantity, factor int) float64 {
+	return math.Round(float64(quantity)/float64(factor)*1000) / 1000
+}
+
+func stockInUnit(unit string, factor, onHand, reserved int) *dtos.StockQuantityInUnit {
+	return &dtos.StockQuantityInUnit{
+		Unit:      unit,
+		Factor:    factor,
+		OnHand:    inUnit(onHand, factor),
+		Reserved:  inUnit(reserved, factor),
+		Available: inUnit(onHand-reserved, factor),
+	}
+}
+
+// applyMovementUnits mengisi in_unit pada riwayat ledger untuk produk yang mengenal unit tersebut
+func applyMovementUnits(repo repositorys.ProductRepository, list []dtos.StockMovementHistoryResponse, unit string) error {
+	if unit == "" || len(list) == 0 {
+		return nil
+	}
+	ids := make([]uuid.UUID, 0, len(list))
+	for _, m := range list {
+		ids = append(ids, m.ProductID)
+	}
+	factors, err := repo.GetProductUnitFactors(ids, unit)
+	if err != nil {
+		return err
+	}
+	for i, m := range list {
+		factor, ok := factors[m.ProductID]
+		if !ok {
+			continue
+		}
+		list[i].InUnit = &dtos.MovementQuantityInUnit{
+			Unit:           unit,
+			Factor:         factor,
+			Quantity:       inUnit(m.Quantity, factor),
+			Delta:          inUnit(m.Delta, factor),
+			BalanceAfter:   inUnit(m.BalanceAfter, factor),
+			RunningBalance: inUnit(m.RunningBalance, factor),
+		}
+	}
+	return nil
+}
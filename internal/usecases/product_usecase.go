@@ -2,7 +2,10 @@ package usecases
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"auth-service/internal/dtos"
@@ -12,8 +15,31 @@ import (
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
 )
 
+var (
+	ErrStockAlreadyExists = errors.New("stock for this product and location already exists")
+	ErrStockNotEmpty      = errors.New("stock quantity must be zero before it can be deleted")
+	ErrLotTrackingLocked  = errors.New("lot tracking cannot be changed while the product has stock on hand")
+	ErrSerializedLocked   = errors.New("serialized flag cannot be changed while the product has stock on hand")
+	ErrSerializedLotMix   = errors.New("a product cannot be both lot tracked and serialized")
+
+	ErrInvalidLocationLevel = errors.New("invalid location level")
+	ErrLocationCodeRequired = errors.New("code is required for child locations and their parent must have a code")
+	ErrLocationPathTaken    = errors.New("location code path already exists")
+	ErrLocationHasChildren  = errors.New("location still has child locations")
+)
+
+// locationLevels menentukan urutan level pada pohon lokasi
+var locationLevels = map[string]int{
+	"site":  1,
+	"zone":  2,
+	"aisle": 3,
+	"rack":  4,
+	"bin":   5,
+}
+
 type ProductUseCase interface {
 	CreateProduct(ctx context.Context, req dtos.CreateProductRequest, userID uuid.UUID) (*dtos.ProductResponse, error)
 	GetProductByID(ctx context.Context, id uuid.UUID) (*dtos.ProductResponse, error)
@@ -24,59 +50,119 @@ type ProductUseCase interface {
 	UpdateProductCategory(ctx context.Context, id uuid.UUID, req dtos.UpdateProductCategoryRequest, userID uuid.UUID) (*dtos.ProductCategoryResponse, error)
 	DeleteProductCategory(ctx context.Context, id uuid.UUID, userID uuid.UUID) error
 	CreateProductStock(ctx context.Context, req dtos.CreateProductStockRequest, userID uuid.UUID) (*dtos.ProductStockResponse, error)
-	GetProductStockByID(ctx context.Context, id uuid.UUID) (*dtos.ProductStockResponse, error)
+	GetProductStockByID(ctx context.Context, id uuid.UUID, unit string) (*dtos.ProductStockResponse, error)
 	UpdateProductStock(ctx context.Context, id uuid.UUID, req dtos.UpdateProductStockRequest, userID uuid.UUID) (*dtos.ProductStockResponse, error)
 	DeleteProductStock(ctx context.Context, id uuid.UUID, userID uuid.UUID) error
-	TrackStockMovement(ctx context.Context, productID uuid.UUID, movementType string, quantity int, userID uuid.UUID) error
+	TrackStockMovement(ctx context.Context, req dtos.CreateStockMovementRequest, userID uuid.UUID, role string) (*dtos.TrackStockMovementResponse, error)
+	GetMovementReasonCodes(ctx context.Context) []dtos.MovementReasonCodesResponse
+	GetStockMovementApprovalsList(ctx context.Context, req dtos.StockMovementApprovalListRequest) ([]dtos.StockMovementApprovalResponse, dtos.Pagination, error)
+	ApproveStockMovement(ctx context.Context, id uuid.UUID, req dtos.DecideStockMovementApprovalRequest, userID uuid.UUID) (*dtos.StockMovementApprovalResponse, error)
+	RejectStockMovement(ctx context.Context, id uuid.UUID, req dtos.DecideStockMovementApprovalRequest, userID uuid.UUID) (*dtos.StockMovementApprovalResponse, error)
+	GetStockLotsByStockID(ctx context.Context, stockID uuid.UUID) ([]dtos.StockLotResponse, error)
+	GetSerialNumbersList(ctx context.Context, productID uuid.UUID, req dtos.SerialNumberListRequest) ([]dtos.SerialNumberResponse, dtos.Pagination, error)
+	GetSerialNumber(ctx context.Context, productID uuid.UUID, serialNumber string) (*dtos.SerialNumberResponse, error)
+	RebuildProductStocks(ctx context.Context, dryRun bool) ([]dtos.StockRebuildResult, error)
 
 	CreateWarehouseLocation(ctx context.Context, req dtos.CreateWarehouseLocationRequest, userID uuid.UUID) (*dtos.WarehouseLocationResponse, error)
 	GetWarehouseLocationByID(ctx context.Context, id uuid.UUID) (*dtos.WarehouseLocationResponse, error)
 	UpdateWarehouseLocation(ctx context.Context, id uuid.UUID, req dtos.UpdateWarehouseLocationRequest, userID uuid.UUID) (*dtos.WarehouseLocationResponse, error)
 	DeleteWarehouseLocation(ctx context.Context, id uuid.UUID, userID uuid.UUID) error
+	GetWarehouseLocationTree(ctx context.Context, rootID *uuid.UUID) ([]*dtos.WarehouseLocationTreeNode, error)
+	GetLocationStockRollup(ctx context.Context, id uuid.UUID) (*dtos.LocationStockRollupResponse, error)
 
 	GetProductsList(ctx context.Context, req dtos.PaginationRequest) ([]dtos.ProductListResponse, dtos.Pagination, error)
 	GetWarehouseLocationsList(ctx context.Context, req dtos.PaginationRequest) ([]dtos.WarehouseLocationListResponse, dtos.Pagination, error)
 	GetProductStocksList(ctx context.Context, req dtos.PaginationRequest) ([]dtos.ProductStockListResponse, dtos.Pagination, error)
+	GetProductStocksAsOf(ctx context.Context, req dtos.StockAsOfRequest) ([]dtos.ProductStockListResponse, dtos.Pagination, error)
+	GetStockMovementHistory(ctx context.Context, req dtos.StockMovementHistoryRequest) ([]dtos.StockMovementHistoryResponse, dtos.Pagination, error)
+	GetStockCard(ctx context.Context, productID uuid.UUID, req dtos.StockCardRequest) (*dtos.StockCardResponse, dtos.Pagination, error)
 	GetProductCategoriesList(ctx context.Context, req dtos.PaginationRequest) ([]dtos.ProductCategoryListResponse, dtos.Pagination, error)
-	GetDashboardSummary(ctx context.Context) (*dtos.DashboardResponse, error)
+	GetDashboardSummary(ctx context.Context, expiringWithinDays int) (*dtos.DashboardResponse, error)
 }
 
 type productUseCase struct {
-	repo     repositorys.ProductRepository
-	validate *validator.Validate
-	log      *logrus.Logger
+	repo           repositorys.ProductRepository
+	approvalPolicy MovementApprovalPolicy
+	// costingMethod adalah metode valuasi default untuk produk tanpa costing_method (valuation.default_method)
+	costingMethod string
+	validate      *validator.Validate
+	log           *logrus.Logger
 }
 
-func NewProductUseCase(repo repositorys.ProductRepository, log *logrus.Logger, validate *validator.Validate) ProductUseCase {
-	return &productUseCase{repo: repo, log: log, validate: validate}
+func NewProductUseCase(repo repositorys.ProductRepository, approvalPolicy MovementApprovalPolicy, costingMethod string, log *logrus.Logger, validate *validator.Validate) ProductUseCase {
+	return &productUseCase{repo: repo, approvalPolicy: approvalPolicy, costingMethod: costingMethod, log: log, validate: validate}
 }
 
 func (u *productUseCase) CreateProduct(ctx context.Context, req dtos.CreateProductRequest, userID uuid.UUID) (*dtos.ProductResponse, error) {
 	if err := u.validate.Struct(req); err != nil {
 		return nil, err
 	}
+	if req.LotTracked && req.Serialized {
+		return nil, ErrSerializedLotMix
+	}
 
 	product := &models.Product{
-		ID:          uuid.New(),
-		Name:        req.Name,
-		SKU:         req.SKU,
-		CategoryID:  req.CategoryID,
-		Description: req.Description,
-		CreatedBy:   userID,
+		ID:                uuid.New(),
+		Name:              req.Name,
+		SKU:               req.SKU,
+		CategoryID:        req.CategoryID,
+		Description:       req.Description,
+		LotTracked:        req.LotTracked,
+		Serialized:        req.Serialized,
+		VariantAttributes: req.VariantAttributes,
+		BaseUnit:          req.BaseUnit,
+		CostingMethod:     req.CostingMethod,
+		CreatedBy:         userID,
+	}
+	if product.BaseUnit == "" {
+		product.BaseUnit = defaultBaseUnit
+	}
+	units, err := toProductUnits(product.ID, product.BaseUnit, req.Units)
+	if err != nil {
+		return nil, err
 	}
+	product.Units = units
+	if req.ParentID != nil {
+		// Varian: atribut harus sesuai matriks parent dan category mengikuti parent
+		if len(req.VariantAttributes) > 0 {
+			return nil, ErrNestedVariant
+		}
+		parent, err := u.repo.GetProductByID(*req.ParentID)
+		if err != nil {
+			return nil, fmt.Errorf("parent product not found: %w", err)
+		}
+		if parent.ParentID != nil {
+			return nil, ErrNestedVariant
+		}
+		if !isVariantParent(parent) {
+			return nil, fmt.Errorf("%w: %s", ErrNotVariantParent, parent.SKU)
+		}
+		if err := checkVariantAttributes(u.repo, parent, product.ID, req.Attributes); err != nil {
+			return nil, err
+		}
+		product.ParentID = &parent.ID
+		product.CategoryID = parent.CategoryID
+		product.Attributes = req.Attributes
+	} else if len(req.Attributes) > 0 {
+		return nil, fmt.Errorf("%w: attributes are only allowed on variants", ErrVariantAttributesMismatch)
+	}
+
+	category, err := u.repo.GetProductCategoryByID(product.CategoryID)
+	if err != nil {
+		return nil, fmt.Errorf("category not found: %w", err)
+	}
+	if product.CustomAttributes, err = checkCustomAttributes(category.AttributeSchema, req.CustomAttributes); err != nil {
+		return nil, err
+	}
+
+	// Satuan alternatif ikut tersimpan lewat asosiasi Units
 	if err := u.repo.CreateProduct(product); err != nil {
 		return nil, err
 	}
 	u.log.Info(fmt.Sprintf("Product %s created", product.Name))
-	return &dtos.ProductResponse{
-		ID:          product.ID,
-		Name:        product.Name,
-		SKU:         product.SKU,
-		CategoryID:  product.CategoryID,
-		Description: product.Description,
-		CreatedAt:   product.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:   product.UpdatedAt.Format(time.RFC3339),
-	}, nil
+	response := toProductResponse(product, u.costingMethod)
+	response.Units = toProductUnitResponses(product.Units)
+	return response, nil
 }
 
 func (u *productUseCase) GetProductByID(ctx context.Context, id uuid.UUID) (*dtos.ProductResponse, error) {
@@ -84,15 +170,34 @@ func (u *productUseCase) GetProductByID(ctx context.Context, id uuid.UUID) (*dto
 	if err != nil {
 		return nil, err
 	}
-	return &dtos.ProductResponse{
-		ID:          product.ID,
-		Name:        product.Name,
-		SKU:         product.SKU,
-		CategoryID:  product.CategoryID,
-		Description: product.Description,
-		CreatedAt:   product.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:   product.UpdatedAt.Format(time.RFC3339),
-	}, nil
+	response := toProductResponse(product, u.costingMethod)
+	units, err := u.repo.GetProductUnits(product.ID)
+	if err != nil {
+		return nil, err
+	}
+	response.Units = toProductUnitResponses(units)
+	barcodes, err := u.repo.GetProductBarcodes(product.ID)
+	if err != nil {
+		return nil, err
+	}
+	response.Barcodes = toProductBarcodeResponses(barcodes)
+	if isVariantParent(product) {
+		if response.Variants, err = productVariantsFor(u.repo, product); err != nil {
+			return nil, err
+		}
+	}
+
+	// Untuk kit, tampilkan BOM dan jumlah kit yang bisa dirakit dari stok komponen saat ini
+	bom, err := billOfMaterialsFor(u.repo, product)
+	if err != nil {
+		return nil, err
+	}
+	if len(bom.Components) > 0 {
+		response.Components = bom.Components
+		response.BuildableQuantity = &bom.BuildableQuantity
+		response.BuildableByLocation = bom.BuildableByLocation
+	}
+	return response, nil
 }
 
 func (u *productUseCase) UpdateProduct(ctx context.Context, id uuid.UUID, req dtos.UpdateProductRequest, userID uuid.UUID) (*dtos.ProductResponse, error) {
@@ -105,31 +210,130 @@ func (u *productUseCase) UpdateProduct(ctx context.Context, id uuid.UUID, req dt
 		return nil, err
 	}
 
+	categoryChanged := product.CategoryID != req.CategoryID
 	product.Name = req.Name
 	product.SKU = req.SKU
 	product.CategoryID = req.CategoryID
 	product.Description = req.Description
+	// Atribut khusus divalidasi ulang terhadap skema category bila diubah atau category berganti
+	if req.CustomAttributes != nil || categoryChanged {
+		values := req.CustomAttributes
+		if values == nil {
+			values = product.CustomAttributes
+		}
+		category, err := u.repo.GetProductCategoryByID(product.CategoryID)
+		if err != nil {
+			return nil, fmt.Errorf("category not found: %w", err)
+		}
+		if product.CustomAttributes, err = checkCustomAttributes(category.AttributeSchema, values); err != nil {
+			return nil, err
+		}
+	}
+	if req.LotTracked != nil && *req.LotTracked != product.LotTracked {
+		// Mengubah mode lot hanya aman bila belum ada stok on hand
+		onHand, err := u.repo.GetProductOnHandQuantity(product.ID)
+		if err != nil {
+			return nil, err
+		}
+		if onHand != 0 {
+			return nil, ErrLotTrackingLocked
+		}
+		product.LotTracked = *req.LotTracked
+	}
+	if req.Serialized != nil && *req.Serialized != product.Serialized {
+		// Quantity produk serialized harus selalu sama dengan jumlah serial on hand
+		onHand, err := u.repo.GetProductOnHandQuantity(product.ID)
+		if err != nil {
+			return nil, err
+		}
+		if onHand != 0 {
+			return nil, ErrSerializedLocked
+		}
+		product.Serialized = *req.Serialized
+	}
+	if product.LotTracked && product.Serialized {
+		return nil, ErrSerializedLotMix
+	}
+	if req.BaseUnit != "" && req.BaseUnit != product.BaseUnit {
+		// Quantity tersimpan dalam satuan dasar sehingga satuan dasar terkunci selama ada stok
+		onHand, err := u.repo.GetProductOnHandQuantity(product.ID)
+		if err != nil {
+			return nil, err
+		}
+		if onHand != 0 {
+			return nil, ErrBaseUnitLocked
+		}
+		product.BaseUnit = req.BaseUnit
+	}
+	if req.CostingMethod != "" && req.CostingMethod != product.CostingMethod {
+		// Nilai stok yang ada dihitung dengan metode lama sehingga metode hanya boleh berganti saat stok kosong
+		if req.CostingMethod != costingMethodFor(product, u.costingMethod) {
+			onHand, err := u.repo.GetProductOnHandQuantity(product.ID)
+			if err != nil {
+				return nil, err
+			}
+			if onHand != 0 {
+				return nil, ErrCostingMethodLocked
+			}
+		}
+		product.CostingMethod = req.CostingMethod
+	}
+	units, err := u.repo.GetProductUnits(product.ID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Units != nil {
+		if units, err = toProductUnits(product.ID, product.BaseUnit, req.Units); err != nil {
+			return nil, err
+		}
+	} else if slices.ContainsFunc(units, func(unit models.ProductUnit) bool { return unit.Unit == product.BaseUnit }) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidUnit, product.BaseUnit)
+	}
+	if req.Attributes != nil {
+		if product.ParentID == nil {
+			return nil, fmt.Errorf("%w: attributes are only allowed on variants", ErrVariantAttributesMismatch)
+		}
+		parent, err := u.repo.GetProductByID(*product.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkVariantAttributes(u.repo, parent, product.ID, req.Attributes); err != nil {
+			return nil, err
+		}
+		product.Attributes = req.Attributes
+	}
 	product.UpdatedAt = time.Now()
-	if err := u.repo.UpdateProduct(product); err != nil {
+	err = u.repo.WithTransaction(func(repo repositorys.ProductRepository) error {
+		if req.Units != nil {
+			if err := repo.ReplaceProductUnits(product.ID, units); err != nil {
+				return err
+			}
+		}
+		return repo.UpdateProduct(product)
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	return &dtos.ProductResponse{
-		ID:          product.ID,
-		Name:        product.Name,
-		SKU:         product.SKU,
-		CategoryID:  product.CategoryID,
-		Description: product.Description,
-		CreatedAt:   product.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:   product.UpdatedAt.Format(time.RFC3339),
-	}, nil
+	response := toProductResponse(product, u.costingMethod)
+	response.Units = toProductUnitResponses(units)
+	return response, nil
 }
 
 func (u *productUseCase) DeleteProduct(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
-	_, err := u.repo.GetProductByID(id)
+	product, err := u.repo.GetProductByID(id)
 	if err != nil {
 		return err
 	}
+	if isVariantParent(product) {
+		variants, err := u.repo.GetProductVariants(product.ID)
+		if err != nil {
+			return err
+		}
+		if len(variants) > 0 {
+			return ErrProductHasVariants
+		}
+	}
 	return u.repo.DeleteProduct(id)
 }
 
@@ -139,21 +343,32 @@ func (u *productUseCase) CreateProductCategory(ctx context.Context, req dtos.Cre
 		return nil, err
 	}
 
+	schema, err := toAttributeSchema(req.AttributeSchema)
+	if err != nil {
+		return nil, err
+	}
+
 	category := &models.ProductCategory{
-		ID:          uuid.New(),
-		Name:        req.Name,
-		Description: req.Description,
+		ID:              uuid.New(),
+		Name:            req.Name,
+		Description:     req.Description,
+		AttributeSchema: schema,
 	}
 	if err := u.repo.CreateProductCategory(category); err != nil {
 		return nil, err
 	}
 
+	return toProductCategoryResponse(category), nil
+}
+
+func toProductCategoryResponse(c *models.ProductCategory) *dtos.ProductCategoryResponse {
 	return &dtos.ProductCategoryResponse{
-		ID:          category.ID,
-		Name:        category.Name,
-		Description: category.Description,
-		CreatedAt:   category.CreatedAt.Format(time.RFC3339),
-	}, nil
+		ID:              c.ID,
+		Name:            c.Name,
+		Description:     c.Description,
+		AttributeSchema: toAttributeDefinitions(c.AttributeSchema),
+		CreatedAt:       c.CreatedAt.Format(time.RFC3339),
+	}
 }
 
 func (u *productUseCase) GetProductCategoryByID(ctx context.Context, id uuid.UUID) (*dtos.ProductCategoryResponse, error) {
@@ -161,12 +376,7 @@ func (u *productUseCase) GetProductCategoryByID(ctx context.Context, id uuid.UUI
 	if err != nil {
 		return nil, err
 	}
-	return &dtos.ProductCategoryResponse{
-		ID:          category.ID,
-		Name:        category.Name,
-		Description: category.Description,
-		CreatedAt:   category.CreatedAt.Format(time.RFC3339),
-	}, nil
+	return toProductCategoryResponse(category), nil
 }
 
 func (u *productUseCase) UpdateProductCategory(ctx context.Context, id uuid.UUID, req dtos.UpdateProductCategoryRequest, userID uuid.UUID) (*dtos.ProductCategoryResponse, error) {
@@ -181,17 +391,18 @@ func (u *productUseCase) UpdateProductCategory(ctx context.Context, id uuid.UUID
 
 	category.Name = req.Name
 	category.Description = req.Description
+	// Skema baru berlaku untuk penulisan produk berikutnya; nilai produk yang sudah ada tidak diubah
+	if req.AttributeSchema != nil {
+		if category.AttributeSchema, err = toAttributeSchema(req.AttributeSchema); err != nil {
+			return nil, err
+		}
+	}
 	category.UpdatedAt = time.Now()
 	if err := u.repo.UpdateProductCategory(category); err != nil {
 		return nil, err
 	}
 
-	return &dtos.ProductCategoryResponse{
-		ID:          category.ID,
-		Name:        category.Name,
-		Description: category.Description,
-		CreatedAt:   category.CreatedAt.Format(time.RFC3339),
-	}, nil
+	return toProductCategoryResponse(category), nil
 }
 
 func (u *productUseCase) DeleteProductCategory(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
@@ -204,19 +415,32 @@ func (u *productUseCase) DeleteProductCategory(ctx context.Context, id uuid.UUID
 
 // Implementasi untuk ProductStock
 
-func (u *productUseCase) GetProductStockByID(ctx context.Context, id uuid.UUID) (*dtos.ProductStockResponse, error) {
+// GetProductStockByID; unit diisi untuk menampilkan quantity juga dalam satuan tersebut
+func (u *productUseCase) GetProductStockByID(ctx context.Context, id uuid.UUID, unit string) (*dtos.ProductStockResponse, error) {
 	stock, err := u.repo.GetProductStockByID(id)
 	if err != nil {
 		return nil, err
 	}
-	return &dtos.ProductStockResponse{
+	response := &dtos.ProductStockResponse{
 		ID:                  stock.ID,
 		ProductID:           stock.SourceProductID,
 		WarehouseLocationID: stock.WarehouseLocationID,
 		Quantity:            stock.Quantity,
+		OnHand:              stock.Quantity,
+		Reserved:            stock.ReservedQuantity,
+		Available:           stock.Quantity - stock.ReservedQuantity,
+		StockValue:          stock.StockValue,
 		Status:              stock.Status,
 		UpdatedAt:           stock.UpdatedAt.Format(time.RFC3339),
-	}, nil
+	}
+	if unit != "" {
+		factor, err := unitFactor(u.repo, stock.SourceProductID, unit)
+		if err != nil {
+			return nil, err
+		}
+		response.InUnit = stockInUnit(unit, factor, stock.Quantity, stock.ReservedQuantity)
+	}
+	return response, nil
 }
 
 func (u *productUseCase) CreateProductStock(ctx context.Context, req dtos.CreateProductStockRequest, userID uuid.UUID) (*dtos.ProductStockResponse, error) {
@@ -224,156 +448,560 @@ func (u *productUseCase) CreateProductStock(ctx context.Context, req dtos.Create
 		return nil, err
 	}
 
-	stock := &models.ProductStock{
-		ID:                  uuid.New(),
-		SourceProductID:     req.ProductID,
-		WarehouseLocationID: req.WarehouseLocationID,
-		Quantity:            req.Quantity,
-		Status:              u.determineStockStatus(req.Quantity),
-		UpdatedBy:           userID,
-		UpdatedAt:           time.Now(),
+	expiryDate, err := parseExpiryDate(req.ExpiryDate)
+	if err != nil {
+		return nil, err
 	}
-	if err := u.repo.CreateProductStock(stock); err != nil {
+
+	product, err := u.repo.GetProductByID(req.ProductID)
+	if err != nil {
 		return nil, err
 	}
+	if isVariantParent(product) {
+		return nil, ErrVariantParentStock
+	}
+	quantity, err := baseQuantity(u.repo, product.ID, req.Unit, req.Quantity)
+	if err != nil {
+		return nil, err
+	}
+
+	var stock *models.ProductStock
+	err = u.repo.WithTransaction(func(repo repositorys.ProductRepository) error {
+		existing, err := repo.GetProductStockForUpdate(req.ProductID, req.WarehouseLocationID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if existing != nil {
+			return ErrStockAlreadyExists
+		}
+
+		stock = &models.ProductStock{
+			ID:                  uuid.New(),
+			SourceProductID:     req.ProductID,
+			WarehouseLocationID: req.WarehouseLocationID,
+			Quantity:            0,
+			Status:              determineStockStatus(0, nil),
+			UpdatedBy:           userID,
+			UpdatedAt:           time.Now(),
+		}
+		created, err := repo.CreateProductStockIfMissing(stock)
+		if err != nil {
+			return err
+		}
+		if !created {
+			return ErrStockAlreadyExists
+		}
 
-	// Catat initial movement sebagai 'inbound'
-	if req.Quantity > 0 {
-		if err := u.TrackStockMovement(ctx, req.ProductID, "inbound", req.Quantity, userID); err != nil {
-			u.log.Errorf("Failed to track initial stock movement: %v", err)
-			// Optional: Rollback create jika critical, tapi di sini log saja untuk simplicity
+		// Catat initial stock sebagai entri ledger 'adjustment' masuk
+		if quantity > 0 {
+			if _, err := applyStockMovement(repo, u.costingMethod, stockMovementInput{
+				ProductID:           req.ProductID,
+				WarehouseLocationID: req.WarehouseLocationID,
+				MovementType:        "adjustment",
+				Direction:           1,
+				Quantity:            quantity,
+				LotNumber:           req.LotNumber,
+				ExpiryDate:          expiryDate,
+				SerialNumbers:       req.SerialNumbers,
+				Reason:              "initial_stock",
+				ReferenceType:       "product_stock",
+				ReferenceID:         &stock.ID,
+				ReferenceNote:       "Initial stock",
+				UserID:              userID,
+				UnitCost:            baseUnitCost(req.UnitCost, req.Quantity, quantity),
+			}); err != nil {
+				return err
+			}
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return &dtos.ProductStockResponse{
-		ID:                  stock.ID,
-		ProductID:           stock.SourceProductID,
-		WarehouseLocationID: stock.WarehouseLocationID,
-		Quantity:            stock.Quantity,
-		Status:              stock.Status,
-		UpdatedAt:           stock.UpdatedAt.Format(time.RFC3339),
-	}, nil
+	return u.GetProductStockByID(ctx, stock.ID, req.Unit)
 }
 
+// UpdateProductStock menyetel quantity ke nilai baru dengan mencatat selisihnya sebagai entri ledger
 func (u *productUseCase) UpdateProductStock(ctx context.Context, id uuid.UUID, req dtos.UpdateProductStockRequest, userID uuid.UUID) (*dtos.ProductStockResponse, error) {
 	if err := u.validate.Struct(req); err != nil {
 		return nil, err
 	}
 
+	expiryDate, err := parseExpiryDate(req.ExpiryDate)
+	if err != nil {
+		return nil, err
+	}
+
 	stock, err := u.repo.GetProductStockByID(id)
 	if err != nil {
 		return nil, err
 	}
+	quantity, err := baseQuantity(u.repo, stock.SourceProductID, req.Unit, req.Quantity)
+	if err != nil {
+		return nil, err
+	}
 
-	oldQuantity := stock.Quantity
-	newQuantity := req.Quantity
+	err = u.repo.WithTransaction(func(repo repositorys.ProductRepository) error {
+		locked, err := repo.GetProductStockForUpdate(stock.SourceProductID, stock.WarehouseLocationID)
+		if err != nil {
+			return err
+		}
 
-	// Hitung delta
-	delta := newQuantity - oldQuantity
-	if delta != 0 {
-		movementType := "inbound"
-		movementQuantity := delta
+		// Hitung delta terhadap saldo terkini
+		delta := quantity - locked.Quantity
+		if delta == 0 {
+			return nil
+		}
+		direction := 1
 		if delta < 0 {
-			movementType = "outbound"
-			movementQuantity = -delta
-			if newQuantity < 0 {
-				return nil, fmt.Errorf("new quantity cannot be negative")
-			}
+			direction = -1
+			delta = -delta
 		}
 
-		// Catat movement
-		if err := u.TrackStockMovement(ctx, stock.SourceProductID, movementType, movementQuantity, userID); err != nil {
+		_, err = applyStockMovement(repo, u.costingMethod, stockMovementInput{
+			ProductID:           locked.SourceProductID,
+			WarehouseLocationID: locked.WarehouseLocationID,
+			MovementType:        "adjustment",
+			Direction:           direction,
+			Quantity:            delta,
+			LotNumber:           req.LotNumber,
+			ExpiryDate:          expiryDate,
+			SerialNumbers:       req.SerialNumbers,
+			Reason:              "manual_adjustment",
+			ReferenceType:       "product_stock",
+			ReferenceID:         &locked.ID,
+			ReferenceNote:       fmt.Sprintf("Stock set to %d", quantity),
+			UserID:              userID,
+			UnitCost:            baseUnitCost(req.UnitCost, req.Quantity, quantity),
+			// Menyetel quantity stok karantina tetap menghasilkan stok karantina
+			Quarantine: locked.Status == "quarantined",
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return u.GetProductStockByID(ctx, id, req.Unit)
+}
+
+func (u *productUseCase) DeleteProductStock(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
+	stock, err := u.repo.GetProductStockByID(id)
+	if err != nil {
+		return err
+	}
+	// Quantity adalah proyeksi ledger, kosongkan dulu lewat movement sebelum dihapus
+	if stock.Quantity != 0 {
+		return ErrStockNotEmpty
+	}
+	return u.repo.DeleteProductStock(id)
+}
+
+// TrackStockMovement mencatat pergerakan stok pada satu produk dan lokasi ke ledger.
+// Outbound tanpa lot_number pada produk lot-tracked dialokasikan FEFO dan dapat menghasilkan beberapa entri.
+// Jika quantity melebihi kebijakan approval tipe tersebut dan role bukan super_admin,
+// movement disimpan sebagai approval pending dan belum dibukukan.
+func (u *productUseCase) TrackStockMovement(ctx context.Context, req dtos.CreateStockMovementRequest, userID uuid.UUID, role string) (*dtos.TrackStockMovementResponse, error) {
+	if err := u.validate.Struct(req); err != nil {
+		return nil, err
+	}
+	in, err := manualMovementInput(req, userID)
+	if err != nil {
+		return nil, err
+	}
+	// Ledger dan approval policy selalu bekerja dalam satuan dasar
+	if in.Quantity, err = baseQuantity(u.repo, in.ProductID, req.Unit, req.Quantity); err != nil {
+		return nil, err
+	}
+	in.UnitCost = baseUnitCost(req.UnitCost, req.Quantity, in.Quantity)
+	// Validasi tipe, arah, reason, dan reference sebelum menunggu approval
+	direction, err := movementDirection(in.MovementType, in.Direction)
+	if err != nil {
+		return nil, err
+	}
+	if in.UnitCost != nil && direction < 0 {
+		return nil, ErrUnitCostNotAllowed
+	}
+	if !slices.Contains(MovementReasonCodes[in.MovementType], in.Reason) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidReasonCode, in.Reason)
+	}
+
+	if u.approvalPolicy.requiresSignOff(in.MovementType, in.Quantity, role) {
+		approval, err := u.requestStockMovementApproval(in)
+		if err != nil {
 			return nil, err
 		}
+		u.log.Info(fmt.Sprintf("Stock movement %s of %d awaiting super_admin approval", in.MovementType, in.Quantity))
+		return &dtos.TrackStockMovementResponse{Approval: approval}, nil
 	}
 
-	// Perbarui stok
-	stock.Quantity = newQuantity
-	stock.Status = u.determineStockStatus(newQuantity)
-	stock.UpdatedAt = time.Now()
-	stock.UpdatedBy = userID
-	if err := u.repo.UpdateProductStock(stock); err != nil {
+	var movements []*models.StockMovement
+	err = u.repo.WithTransaction(func(repo repositorys.ProductRepository) error {
+		var err error
+		movements, err = applyStockMovement(repo, u.costingMethod, in)
+		return err
+	})
+	if err != nil {
 		return nil, err
 	}
+	return &dtos.TrackStockMovementResponse{Movements: toStockMovementResponses(movements, in.SerialNumbers)}, nil
+}
 
-	return &dtos.ProductStockResponse{
-		ID:                  stock.ID,
-		ProductID:           stock.SourceProductID,
-		WarehouseLocationID: stock.WarehouseLocationID,
-		Quantity:            stock.Quantity,
-		Status:              stock.Status,
-		UpdatedAt:           stock.UpdatedAt.Format(time.RFC3339),
+// manualMovementInput menyusun input ledger dari request movement manual
+func manualMovementInput(req dtos.CreateStockMovementRequest, userID uuid.UUID) (stockMovementInput, error) {
+	expiryDate, err := parseExpiryDate(req.ExpiryDate)
+	if err != nil {
+		return stockMovementInput{}, err
+	}
+
+	direction := 0
+	switch req.Direction {
+	case "in":
+		direction = 1
+	case "out":
+		direction = -1
+	}
+
+	return stockMovementInput{
+		ProductID:           req.ProductID,
+		WarehouseLocationID: req.WarehouseLocationID,
+		MovementType:        req.MovementType,
+		Direction:           direction,
+		Quantity:            req.Quantity,
+		LotNumber:           req.LotNumber,
+		ExpiryDate:          expiryDate,
+		SerialNumbers:       req.SerialNumbers,
+		Reason:              req.Reason,
+		ReferenceType:       "manual",
+		ReferenceNote:       req.ReferenceNote,
+		UserID:              userID,
 	}, nil
 }
 
-func (u *productUseCase) DeleteProductStock(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
-	_, err := u.repo.GetProductStockByID(id)
+func toStockMovementResponses(movements []*models.StockMovement, serials []string) []dtos.StockMovementResponse {
+	responses := make([]dtos.StockMovementResponse, 0, len(movements))
+	for _, m := range movements {
+		responses = append(responses, *toStockMovementResponse(m))
+	}
+	// Produk serialized selalu menghasilkan satu entri
+	if len(serials) > 0 && len(responses) == 1 {
+		responses[0].SerialNumbers = serials
+	}
+	return responses
+}
+
+// GetSerialNumbersList mengambil registry serial dari satu produk
+func (u *productUseCase) GetSerialNumbersList(ctx context.Context, productID uuid.UUID, req dtos.SerialNumberListRequest) ([]dtos.SerialNumberResponse, dtos.Pagination, error) {
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.Limit == 0 {
+		req.Limit = 10
+	}
+	if err := u.validate.Struct(req); err != nil {
+		return nil, dtos.Pagination{}, err
+	}
+	if _, err := u.repo.GetProductByID(productID); err != nil {
+		return nil, dtos.Pagination{}, err
+	}
+
+	serials, total, err := u.repo.GetSerialNumbersList(productID, req)
 	if err != nil {
-		return err
+		return nil, dtos.Pagination{}, err
 	}
-	return u.repo.DeleteProductStock(id)
+
+	list := make([]dtos.SerialNumberResponse, 0, len(serials))
+	for i := range serials {
+		list = append(list, *toSerialNumberResponse(&serials[i]))
+	}
+	return list, buildPagination(req.Page, req.Limit, total), nil
 }
 
-func (u *productUseCase) TrackStockMovement(ctx context.Context, productID uuid.UUID, movementType string, quantity int, userID uuid.UUID) error {
-	// Validasi movementType
-	validMovements := map[string]bool{"inbound": true, "outbound": true}
-	if !validMovements[movementType] {
-		return fmt.Errorf("invalid movement type: %s", movementType)
+// GetSerialNumber mengambil satu serial beserta riwayat lengkap pergerakannya
+func (u *productUseCase) GetSerialNumber(ctx context.Context, productID uuid.UUID, serialNumber string) (*dtos.SerialNumberResponse, error) {
+	serial, err := u.repo.GetSerialNumber(productID, serialNumber)
+	if err != nil {
+		return nil, err
 	}
-	if quantity <= 0 {
-		return fmt.Errorf("quantity must be positive")
+	movements, err := u.repo.GetSerialNumberHistory(serial.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := toSerialNumberResponse(serial)
+	for i := range movements {
+		resp.History = append(resp.History, *toStockMovementResponse(&movements[i]))
+	}
+	return resp, nil
+}
+
+func toSerialNumberResponse(s *models.SerialNumber) *dtos.SerialNumberResponse {
+	resp := &dtos.SerialNumberResponse{
+		ID:                  s.ID,
+		ProductID:           s.SourceProductID,
+		SerialNumber:        s.SerialNumber,
+		Status:              s.Status,
+		WarehouseLocationID: s.WarehouseLocationID,
+		CreatedAt:           s.CreatedAt,
+		UpdatedAt:           s.UpdatedAt,
+	}
+	if s.WarehouseLocation != nil {
+		resp.WarehouseName = s.WarehouseLocation.Name
 	}
+	return resp
+}
 
-	// Ambil stok terkait
-	stock, err := u.repo.GetProductStockByID(productID) // Asumsi ada logika untuk mencari stok berdasarkan productID
+// GetStockLotsByStockID mengambil rincian lot dari satu ProductStock (urut FEFO)
+func (u *productUseCase) GetStockLotsByStockID(ctx context.Context, stockID uuid.UUID) ([]dtos.StockLotResponse, error) {
+	if _, err := u.repo.GetProductStockByID(stockID); err != nil {
+		return nil, err
+	}
+	lots, err := u.repo.GetStockLotsByStockID(stockID)
 	if err != nil {
-		return fmt.Errorf("stock not found for product ID: %v", err)
+		return nil, err
 	}
 
-	// Hitung stok baru
-	newQuantity := stock.Quantity
-	if movementType == "inbound" {
-		newQuantity += quantity
-	} else if movementType == "outbound" {
-		newQuantity -= quantity
-		if newQuantity < 0 {
-			return fmt.Errorf("insufficient stock for outbound movement")
-		}
+	responses := make([]dtos.StockLotResponse, 0, len(lots))
+	for _, lot := range lots {
+		responses = append(responses, dtos.StockLotResponse{
+			ID:             lot.ID,
+			ProductStockID: lot.ProductStockID,
+			LotNumber:      lot.LotNumber,
+			ExpiryDate:     lot.ExpiryDate,
+			Quantity:       lot.Quantity,
+			CreatedAt:      lot.CreatedAt,
+			UpdatedAt:      lot.UpdatedAt,
+		})
 	}
+	return responses, nil
+}
 
-	// Perbarui stok
-	stock.Quantity = newQuantity
-	stock.Status = u.determineStockStatus(newQuantity)
-	stock.UpdatedAt = time.Now()
-	stock.UpdatedBy = userID
-	if err := u.repo.UpdateProductStock(stock); err != nil {
-		return err
+// parseExpiryDate mengubah tanggal YYYY-MM-DD menjadi *time.Time (nil jika kosong)
+func parseExpiryDate(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
 	}
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, err
+	}
+	return &date, nil
+}
 
-	// Catat pergerakan stok
-	movement := &models.StockMovement{
-		ID:              uuid.New(),
-		SourceProductID: productID,
-		MovementType:    movementType,
-		Quantity:        quantity,
-		ReferenceNote:   "Automatic stock update",
-		CreatedBy:       userID,
-		CreatedAt:       time.Now(),
+// RebuildProductStocks menghitung ulang seluruh ProductStock.Quantity dan StockValue dari ledger.
+// Jika dryRun bernilai true, hanya selisihnya yang dikembalikan tanpa menyimpan perubahan.
+func (u *productUseCase) RebuildProductStocks(ctx context.Context, dryRun bool) ([]dtos.StockRebuildResult, error) {
+	var results []dtos.StockRebuildResult
+	err := u.repo.WithTransaction(func(repo repositorys.ProductRepository) error {
+		balances, err := repo.GetStockLedgerBalances()
+		if err != nil {
+			return err
+		}
+		stocks, err := repo.GetAllProductStocks()
+		if err != nil {
+			return err
+		}
+
+		type stockKey struct{ productID, locationID uuid.UUID }
+		ledger := make(map[stockKey]repositorys.StockLedgerBalance, len(balances))
+		for _, b := range balances {
+			b.Value = roundCost(b.Value)
+			ledger[stockKey{b.ProductID, b.WarehouseLocationID}] = b
+		}
+
+		// Kategori per produk untuk mencari threshold efektif; stok di lokasi karantina selalu quarantined
+		categories := make(map[uuid.UUID]uuid.UUID)
+		quarantineLocations := make(map[uuid.UUID]bool)
+		statusFor := func(productID, locationID uuid.UUID, quantity int) (string, error) {
+			quarantine, ok := quarantineLocations[locationID]
+			if !ok {
+				location, err := repo.GetWarehouseLocationByID(locationID)
+				if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+					return "", err
+				}
+				quarantine = location != nil && location.Quarantine
+				quarantineLocations[locationID] = quarantine
+			}
+			if quarantine && quantity > 0 {
+				return "quarantined", nil
+			}
+			categoryID, ok := categories[productID]
+			if !ok {
+				product, err := repo.GetProductByID(productID)
+				if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+					return "", err
+				}
+				if product != nil {
+					categoryID = product.CategoryID
+				}
+				categories[productID] = categoryID
+			}
+			return stockStatusFor(repo, productID, locationID, categoryID, quantity)
+		}
+
+		now := time.Now()
+		seen := make(map[stockKey]bool, len(stocks))
+		unledgered := make(map[uuid.UUID]bool)
+		for i := range stocks {
+			stock := &stocks[i]
+			key := stockKey{stock.SourceProductID, stock.WarehouseLocationID}
+			seen[key] = true
+			balance, ok := ledger[key]
+			if !ok && stock.Quantity != 0 {
+				// Stok lama tanpa entri ledger (belum di-backfill saldo awalnya) tidak di-reset ke nol
+				u.log.Warn(fmt.Sprintf("Stock rebuild skipped product %s at location %s: stock has no ledger entries", stock.SourceProductID, stock.WarehouseLocationID))
+				unledgered[stock.ID] = true
+				continue
+			}
+			quantity, value := balance.Quantity, balance.Value
+			if stock.Quantity == quantity && stock.StockValue == value {
+				continue
+			}
+			results = append(results, dtos.StockRebuildResult{
+				ProductID:           stock.SourceProductID,
+				WarehouseLocationID: stock.WarehouseLocationID,
+				PreviousQuantity:    stock.Quantity,
+				LedgerQuantity:      quantity,
+				PreviousValue:       stock.StockValue,
+				LedgerValue:         value,
+			})
+			if dryRun {
+				continue
+			}
+			stock.Quantity = quantity
+			stock.StockValue = value
+			// Status karantina bertahan selama stoknya masih ada
+			if stock.Status != "quarantined" || quantity <= 0 {
+				if stock.Status, err = statusFor(stock.SourceProductID, stock.WarehouseLocationID, quantity); err != nil {
+					return err
+				}
+			}
+			stock.UpdatedAt = now
+			if err := repo.UpdateProductStock(stock); err != nil {
+				return err
+			}
+		}
+
+		// Saldo ledger yang belum punya baris ProductStock
+		for _, b := range balances {
+			key := stockKey{b.ProductID, b.WarehouseLocationID}
+			if seen[key] || b.Quantity == 0 {
+				continue
+			}
+			b.Value = roundCost(b.Value)
+			results = append(results, dtos.StockRebuildResult{
+				ProductID:           b.ProductID,
+				WarehouseLocationID: b.WarehouseLocationID,
+				PreviousQuantity:    0,
+				LedgerQuantity:      b.Quantity,
+				LedgerValue:         b.Value,
+			})
+			if dryRun {
+				continue
+			}
+			status, err := statusFor(b.ProductID, b.WarehouseLocationID, b.Quantity)
+			if err != nil {
+				return err
+			}
+			if err := repo.CreateProductStock(&models.ProductStock{
+				ID:                  uuid.New(),
+				SourceProductID:     b.ProductID,
+				WarehouseLocationID: b.WarehouseLocationID,
+				Quantity:            b.Quantity,
+				StockValue:          b.Value,
+				Status:              status,
+				UpdatedAt:           now,
+			}); err != nil {
+				return err
+			}
+		}
+
+		// Saldo per lot juga merupakan proyeksi ledger
+		if dryRun {
+			return nil
+		}
+		lotBalances, err := repo.GetLotLedgerBalances()
+		if err != nil {
+			return err
+		}
+		lots, err := repo.GetAllStockLots()
+		if err != nil {
+			return err
+		}
+		for i := range lots {
+			if unledgered[lots[i].ProductStockID] || lots[i].Quantity == lotBalances[lots[i].ID] {
+				continue
+			}
+			lots[i].Quantity = lotBalances[lots[i].ID]
+			lots[i].UpdatedAt = now
+			if err := repo.UpdateStockLot(&lots[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
-	return u.repo.CreateStockMovement(movement)
+
+	u.log.Info(fmt.Sprintf("Stock rebuild finished: %d balance(s) differ from ledger (dry run: %t)", len(results), dryRun))
+	return results, nil
 }
 
-func (u *productUseCase) determineStockStatus(quantity int) string {
+func toStockMovementResponse(m *models.StockMovement) *dtos.StockMovementResponse {
+	resp := &dtos.StockMovementResponse{
+		ID:                  m.ID,
+		ProductID:           m.SourceProductID,
+		WarehouseLocationID: m.WarehouseLocationID,
+		MovementType:        m.MovementType,
+		Quantity:            m.Quantity,
+		Delta:               m.Delta,
+		BalanceAfter:        m.BalanceAfter,
+		UnitCost:            m.UnitCost,
+		TotalCost:           m.TotalCost,
+		LotID:               m.LotID,
+		Reason:              m.Reason,
+		ReferenceType:       m.ReferenceType,
+		ReferenceID:         m.ReferenceID,
+		ReferenceNote:       m.ReferenceNote,
+		CreatedBy:           m.CreatedBy,
+		CreatedAt:           m.CreatedAt,
+	}
+	if m.Lot != nil {
+		resp.LotNumber = m.Lot.LotNumber
+	}
+	return resp
+}
+
+// determineStockStatus menghitung status dari quantity dan threshold efektif (nil = threshold bawaan)
+func determineStockStatus(quantity int, threshold *models.StockThreshold) string {
+	minQuantity, reorderPoint := models.DefaultMinQuantity, models.DefaultReorderPoint
+	if threshold != nil {
+		minQuantity, reorderPoint = threshold.MinQuantity, threshold.ReorderPoint
+	}
 	switch {
 	case quantity <= 0:
 		return "out-of-stock"
-	case quantity < 10:
+	case quantity <= reorderPoint || quantity < minQuantity:
 		return "low-stock"
 	default:
 		return "available"
 	}
 }
 
+// stockStatusFor mencari threshold efektif produk di lokasi tersebut lalu menghitung statusnya
+func stockStatusFor(repo repositorys.ProductRepository, productID, locationID, categoryID uuid.UUID, quantity int) (string, error) {
+	if quantity <= 0 {
+		return "out-of-stock", nil
+	}
+	threshold, err := repo.GetEffectiveStockThreshold(productID, locationID, categoryID)
+	if err != nil {
+		return "", err
+	}
+	return determineStockStatus(quantity, threshold), nil
+}
+
 func (u *productUseCase) GetProductsList(ctx context.Context, req dtos.PaginationRequest) ([]dtos.ProductListResponse, dtos.Pagination, error) {
 	if err := u.validate.Struct(req); err != nil {
 		return nil, dtos.Pagination{}, err
@@ -396,18 +1024,60 @@ func (u *productUseCase) GetProductsList(ctx context.Context, req dtos.Paginatio
 		return nil, dtos.Pagination{}, err
 	}
 
+	// Stok varian digabung ke parent
+	ids := make([]uuid.UUID, len(products))
+	for i, p := range products {
+		ids[i] = p.ID
+	}
+	rollups := map[uuid.UUID]repositorys.ProductStockRollup{}
+	if len(ids) > 0 {
+		if rollups, err = u.repo.GetProductStockRollups(ids, req.Attribute); err != nil {
+			return nil, dtos.Pagination{}, err
+		}
+	}
+
+	// Harga yang berlaku saat ini dari daftar harga yang diminta atau daftar default
+	var priceList *models.PriceList
+	prices := map[uuid.UUID]models.ProductPrice{}
+	if req.WithPrice || req.PriceListID != uuid.Nil {
+		if priceList, err = priceListFor(u.repo, req.PriceListID); err != nil {
+			return nil, dtos.Pagination{}, err
+		}
+		if prices, err = currentProductPrices(u.repo, priceList.ID, products, time.Now()); err != nil {
+			return nil, dtos.Pagination{}, err
+		}
+	}
+
 	var list []dtos.ProductListResponse
 	for _, p := range products {
-		list = append(list, dtos.ProductListResponse{
-			ID:           p.ID,
-			Name:         p.Name,
-			SKU:          p.SKU,
-			CategoryID:   p.CategoryID,
-			CategoryName: p.Category.Name, // Dari preload
-			Description:  p.Description,
-			CreatedAt:    p.CreatedAt,
-			UpdatedAt:    p.UpdatedAt,
-		})
+		stock := rollups[p.ID]
+		item := dtos.ProductListResponse{
+			ID:                p.ID,
+			Name:              p.Name,
+			SKU:               p.SKU,
+			CategoryID:        p.CategoryID,
+			CategoryName:      p.Category.Name, // Dari preload
+			Description:       p.Description,
+			LotTracked:        p.LotTracked,
+			Serialized:        p.Serialized,
+			ParentID:          p.ParentID,
+			VariantAttributes: p.VariantAttributes,
+			Attributes:        p.Attributes,
+			CustomAttributes:  p.CustomAttributes,
+			BaseUnit:          p.BaseUnit,
+			VariantCount:      stock.VariantCount,
+			OnHand:            stock.OnHand,
+			Reserved:          stock.Reserved,
+			Quarantined:       stock.Quarantined,
+			Available:         stock.Available,
+			CreatedAt:         p.CreatedAt,
+			UpdatedAt:         p.UpdatedAt,
+		}
+		if priceList != nil {
+			price, ok := prices[p.ID]
+			item.Price = toProductListPrice(priceList, price, ok)
+		}
+		list = append(list, item)
 	}
 
 	totalPages := int((total + int64(req.Limit) - 1) / int64(req.Limit))
@@ -436,22 +1106,51 @@ func (u *productUseCase) CreateWarehouseLocation(ctx context.Context, req dtos.C
 
 	location := &models.WarehouseLocation{
 		ID:          uuid.New(),
+		ParentID:    req.ParentID,
+		Type:        req.Type,
+		Code:        strings.ToUpper(req.Code),
 		Name:        req.Name,
 		Description: req.Description,
+		Quarantine:  req.Quarantine,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
+	if location.Type == "" {
+		location.Type = "site"
+	}
+
+	if req.ParentID != nil {
+		parent, err := u.repo.GetWarehouseLocationByID(*req.ParentID)
+		if err != nil {
+			return nil, fmt.Errorf("parent location not found: %w", err)
+		}
+		// Level anak harus lebih dalam dari parent, dan parent harus sudah punya path
+		if locationLevels[location.Type] <= locationLevels[parent.Type] {
+			return nil, fmt.Errorf("%w: a %s cannot be placed under a %s", ErrInvalidLocationLevel, location.Type, parent.Type)
+		}
+		if parent.Path == "" || location.Code == "" {
+			return nil, ErrLocationCodeRequired
+		}
+		location.Path = parent.Path + "-" + location.Code
+		// Seluruh lokasi di bawah lokasi karantina juga lokasi karantina
+		location.Quarantine = location.Quarantine || parent.Quarantine
+	} else {
+		if location.Type != "site" {
+			return nil, fmt.Errorf("%w: only a site can be a root location", ErrInvalidLocationLevel)
+		}
+		location.Path = location.Code
+	}
+
+	if location.Path != "" {
+		if err := u.ensureLocationPathFree(location.Path); err != nil {
+			return nil, err
+		}
+	}
 	if err := u.repo.CreateWarehouseLocation(location); err != nil {
 		return nil, err
 	}
 
-	return &dtos.WarehouseLocationResponse{
-		ID:          location.ID,
-		Name:        location.Name,
-		Description: location.Description,
-		CreatedAt:   location.CreatedAt,
-		UpdatedAt:   location.UpdatedAt,
-	}, nil
+	return toWarehouseLocationResponse(location), nil
 }
 
 // GetWarehouseLocationByID
@@ -460,13 +1159,7 @@ func (u *productUseCase) GetWarehouseLocationByID(ctx context.Context, id uuid.U
 	if err != nil {
 		return nil, err
 	}
-	return &dtos.WarehouseLocationResponse{
-		ID:          location.ID,
-		Name:        location.Name,
-		Description: location.Description,
-		CreatedAt:   location.CreatedAt,
-		UpdatedAt:   location.UpdatedAt,
-	}, nil
+	return toWarehouseLocationResponse(location), nil
 }
 
 // UpdateWarehouseLocation
@@ -483,17 +1176,44 @@ func (u *productUseCase) UpdateWarehouseLocation(ctx context.Context, id uuid.UU
 	location.Name = req.Name
 	location.Description = req.Description
 	location.UpdatedAt = time.Now()
-	if err := u.repo.UpdateWarehouseLocation(location); err != nil {
+
+	code := strings.ToUpper(req.Code)
+	if code == "" || code == location.Code {
+		if err := u.repo.UpdateWarehouseLocation(location); err != nil {
+			return nil, err
+		}
+		return toWarehouseLocationResponse(location), nil
+	}
+
+	// Code berubah: hitung ulang path node ini dan seluruh turunannya dalam satu transaksi
+	newPath := code
+	if location.ParentID != nil {
+		parent, err := u.repo.GetWarehouseLocationByID(*location.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		newPath = parent.Path + "-" + code
+	}
+	if err := u.ensureLocationPathFree(newPath); err != nil {
 		return nil, err
 	}
 
-	return &dtos.WarehouseLocationResponse{
-		ID:          location.ID,
-		Name:        location.Name,
-		Description: location.Description,
-		CreatedAt:   location.CreatedAt,
-		UpdatedAt:   location.UpdatedAt,
-	}, nil
+	oldPath := location.Path
+	location.Code = code
+	location.Path = newPath
+	err = u.repo.WithTransaction(func(repo repositorys.ProductRepository) error {
+		if err := repo.UpdateWarehouseLocation(location); err != nil {
+			return err
+		}
+		if oldPath == "" {
+			return nil
+		}
+		return repo.UpdateLocationSubtreePath(oldPath, newPath)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toWarehouseLocationResponse(location), nil
 }
 
 // DeleteWarehouseLocation
@@ -502,8 +1222,122 @@ func (u *productUseCase) DeleteWarehouseLocation(ctx context.Context, id uuid.UU
 	if err != nil {
 		return err
 	}
+	children, err := u.repo.CountChildLocations(id)
+	if err != nil {
+		return err
+	}
+	if children > 0 {
+		return ErrLocationHasChildren
+	}
 	return u.repo.DeleteWarehouseLocation(id)
 }
+
+// GetWarehouseLocationTree mengembalikan pohon lokasi; jika rootID diisi hanya subtree dari lokasi tersebut
+func (u *productUseCase) GetWarehouseLocationTree(ctx context.Context, rootID *uuid.UUID) ([]*dtos.WarehouseLocationTreeNode, error) {
+	var root *models.WarehouseLocation
+	if rootID != nil {
+		var err error
+		if root, err = u.repo.GetWarehouseLocationByID(*rootID); err != nil {
+			return nil, err
+		}
+	}
+
+	locations, err := u.repo.GetWarehouseLocationSubtree(root)
+	if err != nil {
+		return nil, err
+	}
+
+	// Lokasi diurutkan berdasarkan path sehingga parent selalu muncul sebelum anaknya
+	nodes := make(map[uuid.UUID]*dtos.WarehouseLocationTreeNode, len(locations))
+	var roots []*dtos.WarehouseLocationTreeNode
+	for _, l := range locations {
+		node := &dtos.WarehouseLocationTreeNode{
+			ID:         l.ID,
+			Type:       l.Type,
+			Code:       l.Code,
+			Path:       l.Path,
+			Name:       l.Name,
+			Quarantine: l.Quarantine,
+			Children:   []*dtos.WarehouseLocationTreeNode{},
+		}
+		nodes[l.ID] = node
+		if l.ParentID != nil && (root == nil || l.ID != root.ID) {
+			if parent, ok := nodes[*l.ParentID]; ok {
+				parent.Children = append(parent.Children, node)
+				continue
+			}
+		}
+		roots = append(roots, node)
+	}
+	return roots, nil
+}
+
+// GetLocationStockRollup menjumlahkan stok per produk pada lokasi beserta seluruh turunannya
+func (u *productUseCase) GetLocationStockRollup(ctx context.Context, id uuid.UUID) (*dtos.LocationStockRollupResponse, error) {
+	root, err := u.repo.GetWarehouseLocationByID(id)
+	if err != nil {
+		return nil, err
+	}
+	locations, err := u.repo.GetWarehouseLocationSubtree(root)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := u.repo.GetLocationStockRollup(root)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dtos.LocationStockRollupResponse{
+		LocationID:    root.ID,
+		Path:          root.Path,
+		LocationCount: len(locations),
+		Products:      make([]dtos.LocationStockRollupItem, 0, len(rows)),
+	}
+	for _, row := range rows {
+		resp.OnHand += row.OnHand
+		resp.Reserved += row.Reserved
+		resp.Quarantined += row.Quarantined
+		resp.Available += row.Available
+		resp.Products = append(resp.Products, dtos.LocationStockRollupItem{
+			ProductID:   row.ProductID,
+			ProductName: row.ProductName,
+			SKU:         row.SKU,
+			OnHand:      row.OnHand,
+			Reserved:    row.Reserved,
+			Quarantined: row.Quarantined,
+			Available:   row.Available,
+		})
+	}
+	return resp, nil
+}
+
+// ensureLocationPathFree memastikan path belum dipakai lokasi lain
+func (u *productUseCase) ensureLocationPathFree(path string) error {
+	_, err := u.repo.GetWarehouseLocationByPath(path)
+	if err == nil {
+		return fmt.Errorf("%w: %s", ErrLocationPathTaken, path)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return nil
+}
+
+func toWarehouseLocationResponse(l *models.WarehouseLocation) *dtos.WarehouseLocationResponse {
+	return &dtos.WarehouseLocationResponse{
+		ID:          l.ID,
+		ParentID:    l.ParentID,
+		Type:        l.Type,
+		Code:        l.Code,
+		Path:        l.Path,
+		Name:        l.Name,
+		Description: l.Description,
+		Quarantine:  l.Quarantine,
+		CreatedAt:   l.CreatedAt,
+		UpdatedAt:   l.UpdatedAt,
+	}
+}
+
 func (u *productUseCase) GetWarehouseLocationsList(ctx context.Context, req dtos.PaginationRequest) ([]dtos.WarehouseLocationListResponse, dtos.Pagination, error) {
 	// Logika serupa dengan GetProductsList, adaptasi untuk WarehouseLocation
 	locations, total, err := u.repo.GetWarehouseLocationsList(req)
@@ -515,8 +1349,13 @@ func (u *productUseCase) GetWarehouseLocationsList(ctx context.Context, req dtos
 	for _, l := range locations {
 		list = append(list, dtos.WarehouseLocationListResponse{
 			ID:          l.ID,
+			ParentID:    l.ParentID,
+			Type:        l.Type,
+			Code:        l.Code,
+			Path:        l.Path,
 			Name:        l.Name,
 			Description: l.Description,
+			Quarantine:  l.Quarantine,
 			CreatedAt:   l.CreatedAt,
 		})
 	}
@@ -547,18 +1386,37 @@ func (u *productUseCase) GetProductStocksList(ctx context.Context, req dtos.Pagi
 		return nil, dtos.Pagination{}, err
 	}
 
+	var factors map[uuid.UUID]int
+	if req.Unit != "" && len(stocks) > 0 {
+		ids := make([]uuid.UUID, len(stocks))
+		for i, s := range stocks {
+			ids[i] = s.SourceProductID
+		}
+		if factors, err = u.repo.GetProductUnitFactors(ids, req.Unit); err != nil {
+			return nil, dtos.Pagination{}, err
+		}
+	}
+
 	var list []dtos.ProductStockListResponse
 	for _, s := range stocks {
-		list = append(list, dtos.ProductStockListResponse{
+		item := dtos.ProductStockListResponse{
 			ID:                  s.ID,
 			ProductID:           s.SourceProductID,
 			ProductName:         s.Product.Name, // Dari preload
 			WarehouseLocationID: s.WarehouseLocationID,
 			WarehouseName:       s.WarehouseLocation.Name, // Dari preload
 			Quantity:            s.Quantity,
+			OnHand:              s.Quantity,
+			Reserved:            s.ReservedQuantity,
+			Available:           s.Quantity - s.ReservedQuantity,
+			StockValue:          s.StockValue,
 			Status:              s.Status,
 			UpdatedAt:           s.UpdatedAt,
-		})
+		}
+		if factor, ok := factors[s.SourceProductID]; ok {
+			item.InUnit = stockInUnit(req.Unit, factor, s.Quantity, s.ReservedQuantity)
+		}
+		list = append(list, item)
 	}
 
 	// Hitung pagination seperti di atas
@@ -580,8 +1438,66 @@ func (u *productUseCase) GetProductStocksList(ctx context.Context, req dtos.Pagi
 	return list, pagination, nil
 }
 
-func (u *productUseCase) GetDashboardSummary(ctx context.Context) (*dtos.DashboardResponse, error) {
-	summary, err := u.repo.GetDashboardSummary()
+var ErrInvalidAsOfTime = errors.New("invalid 'at' value, use RFC3339 or YYYY-MM-DD")
+
+// GetProductStocksAsOf merekonstruksi posisi stok per produk dan lokasi pada waktu req.At dari ledger
+func (u *productUseCase) GetProductStocksAsOf(ctx context.Context, req dtos.StockAsOfRequest) ([]dtos.ProductStockListResponse, dtos.Pagination, error) {
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.Limit == 0 {
+		req.Limit = 10
+	}
+	if err := u.validate.Struct(req); err != nil {
+		return nil, dtos.Pagination{}, err
+	}
+
+	// Tanggal saja berarti posisi di akhir hari tersebut
+	at, ok := parseLedgerTime(req.At, true)
+	if !ok {
+		return nil, dtos.Pagination{}, ErrInvalidAsOfTime
+	}
+
+	rows, total, err := u.repo.GetProductStocksAsOf(req, at)
+	if err != nil {
+		return nil, dtos.Pagination{}, err
+	}
+
+	var list []dtos.ProductStockListResponse
+	for _, r := range rows {
+		// Status dihitung dengan threshold yang berlaku saat ini; stok di lokasi karantina tidak pernah tersedia.
+		// Reservasi tidak punya riwayat sehingga reserved selalu 0.
+		var threshold *models.StockThreshold
+		if r.MinQuantity != nil && r.ReorderPoint != nil {
+			threshold = &models.StockThreshold{MinQuantity: *r.MinQuantity, ReorderPoint: *r.ReorderPoint}
+		}
+		status, available := determineStockStatus(r.Quantity, threshold), r.Quantity
+		if r.Quarantine {
+			available = 0
+			if r.Quantity > 0 {
+				status = "quarantined"
+			}
+		}
+		list = append(list, dtos.ProductStockListResponse{
+			ID:                  r.StockID,
+			ProductID:           r.ProductID,
+			ProductName:         r.ProductName,
+			WarehouseLocationID: r.WarehouseLocationID,
+			WarehouseName:       r.WarehouseName,
+			Quantity:            r.Quantity,
+			OnHand:              r.Quantity,
+			Available:           available,
+			StockValue:          roundCost(r.StockValue),
+			Status:              status,
+			UpdatedAt:           r.LastMovementAt,
+		})
+	}
+
+	return list, buildPagination(req.Page, req.Limit, total), nil
+}
+
+func (u *productUseCase) GetDashboardSummary(ctx context.Context, expiringWithinDays int) (*dtos.DashboardResponse, error) {
+	summary, err := u.repo.GetDashboardSummary(expiringWithinDays)
 	if err != nil {
 		return nil, err
 	}
@@ -612,11 +1528,12 @@ func (u *productUseCase) GetProductCategoriesList(ctx context.Context, req dtos.
 	var list []dtos.ProductCategoryListResponse
 	for _, c := range categories {
 		list = append(list, dtos.ProductCategoryListResponse{
-			ID:          c.ID,
-			Name:        c.Name,
-			Description: c.Description,
-			CreatedAt:   c.CreatedAt,
-			UpdatedAt:   c.UpdatedAt,
+			ID:              c.ID,
+			Name:            c.Name,
+			Description:     c.Description,
+			AttributeSchema: toAttributeDefinitions(c.AttributeSchema),
+			CreatedAt:       c.CreatedAt,
+			UpdatedAt:       c.UpdatedAt,
 		})
 	}
 
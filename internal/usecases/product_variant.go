@@ -0,0 +1,105 @@
+package usecases
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+	"time"
+
+	"auth-service/internal/dtos"
+	"auth-service/internal/models"
+	"auth-service/internal/repositorys"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrNotVariantParent          = errors.New("parent product has no variant attributes")
+	ErrNestedVariant             = errors.New("variants cannot be nested: a variant cannot have variants of its own")
+	ErrVariantAttributesMismatch = errors.New("variant attributes must give a value for exactly the attributes of the parent")
+	ErrDuplicateVariant          = errors.New("a variant with the same attribute values already exists")
+	ErrVariantParentStock        = errors.New("stock is kept at variant level: a variant parent cannot hold stock")
+	ErrProductHasVariants        = errors.New("product still has variants")
+)
+
+// isVariantParent; parent varian tidak pernah memegang stok, stoknya adalah gabungan varian
+func isVariantParent(p *models.Product) bool {
+	return len(p.VariantAttributes) > 0
+}
+
+// checkVariantAttributes memastikan varian mengisi tepat atribut matriks parent dan kombinasinya
+// belum dipakai varian lain dari parent yang sama
+func checkVariantAttributes(repo repositorys.ProductRepository, parent *models.Product, variantID uuid.UUID, attributes map[string]string) error {
+	if len(attributes) != len(parent.VariantAttributes) {
+		return fmt.Errorf("%w: expected %s", ErrVariantAttributesMismatch, strings.Join(parent.VariantAttributes, ", "))
+	}
+	for _, name := range parent.VariantAttributes {
+		if strings.TrimSpace(attributes[name]) == "" {
+			return fmt.Errorf("%w: missing %s", ErrVariantAttributesMismatch, name)
+		}
+	}
+
+	siblings, err := repo.GetProductVariants(parent.ID)
+	if err != nil {
+		return err
+	}
+	for _, sibling := range siblings {
+		if sibling.ID != variantID && maps.Equal(map[string]string(sibling.Attributes), attributes) {
+			return fmt.Errorf("%w: %s", ErrDuplicateVariant, sibling.SKU)
+		}
+	}
+	return nil
+}
+
+// productVariantsFor menyusun daftar varian sebuah parent beserta stok masing-masing
+func productVariantsFor(repo repositorys.ProductRepository, parent *models.Product) ([]dtos.ProductVariantResponse, error) {
+	variants, err := repo.GetProductVariants(parent.ID)
+	if err != nil || len(variants) == 0 {
+		return nil, err
+	}
+	ids := make([]uuid.UUID, len(variants))
+	for i, v := range variants {
+		ids[i] = v.ID
+	}
+	rollups, err := repo.GetProductStockRollups(ids, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]dtos.ProductVariantResponse, len(variants))
+	for i, v := range variants {
+		stock := rollups[v.ID]
+		list[i] = dtos.ProductVariantResponse{
+			ID:          v.ID,
+			Name:        v.Name,
+			SKU:         v.SKU,
+			Attributes:  v.Attributes,
+			OnHand:      stock.OnHand,
+			Reserved:    stock.Reserved,
+			Quarantined: stock.Quarantined,
+			Available:   stock.Available,
+		}
+	}
+	return list, nil
+}
+
+func toProductResponse(p *models.Product, costingMethod string) *dtos.ProductResponse {
+	return &dtos.ProductResponse{
+		ID:                p.ID,
+		Name:              p.Name,
+		SKU:               p.SKU,
+		CategoryID:        p.CategoryID,
+		Description:       p.Description,
+		LotTracked:        p.LotTracked,
+		Serialized:        p.Serialized,
+		ParentID:          p.ParentID,
+		VariantAttributes: []string(p.VariantAttributes),
+		Attributes:        p.Attributes,
+		CustomAttributes:  p.CustomAttributes,
+		BaseUnit:          p.BaseUnit,
+		CostingMethod:     costingMethodFor(p, costingMethod),
+		CreatedAt:         p.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:         p.UpdatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,362 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"auth-service/internal/dtos"
+	"auth-service/internal/models"
+	"auth-service/internal/repositorys"
+	"auth-service/internal/utils"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidPurchaseOrderState = errors.New("invalid purchase order state for this action")
+	ErrProductNotSupplied        = errors.New("product is not mapped to this supplier")
+	ErrBelowMinOrderQuantity     = errors.New("quantity is below the supplier minimum order quantity")
+	ErrNoSupplierForProduct      = errors.New("no active supplier is mapped to this product")
+)
+
+type PurchaseOrderUseCase interface {
+	CreatePurchaseOrder(ctx context.Context, req dtos.PurchaseOrderRequest, userID uuid.UUID) (*dtos.PurchaseOrderResponse, error)
+	UpdatePurchaseOrder(ctx context.Context, id uuid.UUID, req dtos.PurchaseOrderRequest, userID uuid.UUID) (*dtos.PurchaseOrderResponse, error)
+	GetPurchaseOrderByID(ctx context.Context, id uuid.UUID) (*dtos.PurchaseOrderResponse, error)
+	GetPurchaseOrdersList(ctx context.Context, req dtos.PurchaseOrderListRequest) ([]dtos.PurchaseOrderResponse, dtos.Pagination, error)
+	ApprovePurchaseOrder(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*dtos.PurchaseOrderResponse, error)
+	ClosePurchaseOrder(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*dtos.PurchaseOrderResponse, error)
+	CancelPurchaseOrder(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*dtos.PurchaseOrderResponse, error)
+	ReceivePurchaseOrder(ctx context.Context, id uuid.UUID, req dtos.CreateGoodsReceiptRequest, userID uuid.UUID) (*dtos.GoodsReceiptResponse, error)
+	GetGoodsReceipts(ctx context.Context, id uuid.UUID) ([]dtos.GoodsReceiptResponse, error)
+}
+
+type purchaseOrderUseCase struct {
+	repo         repositorys.PurchaseOrderRepository
+	supplierRepo repositorys.SupplierRepository
+	productRepo  repositorys.ProductRepository
+	// overReceiptTolerance adalah persen kelebihan kirim yang masih boleh diterima per baris PO
+	overReceiptTolerance int
+	// costingMethod adalah metode valuasi default untuk produk tanpa costing_method (valuation.default_method)
+	costingMethod string
+	validate      *validator.Validate
+	log           *logrus.Logger
+}
+
+func NewPurchaseOrderUseCase(repo repositorys.PurchaseOrderRepository, supplierRepo repositorys.SupplierRepository, productRepo repositorys.ProductRepository, overReceiptTolerance int, costingMethod string, log *logrus.Logger, validate *validator.Validate) PurchaseOrderUseCase {
+	return &purchaseOrderUseCase{repo: repo, supplierRepo: supplierRepo, productRepo: productRepo, overReceiptTolerance: overReceiptTolerance, costingMethod: costingMethod, log: log, validate: validate}
+}
+
+func (u *purchaseOrderUseCase) CreatePurchaseOrder(ctx context.Context, req dtos.PurchaseOrderRequest, userID uuid.UUID) (*dtos.PurchaseOrderResponse, error) {
+	if err := u.validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	order, err := buildPurchaseOrder(u.supplierRepo, u.productRepo, req, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.repo.CreatePurchaseOrder(order); err != nil {
+		return nil, err
+	}
+	u.log.Info(fmt.Sprintf("Purchase order %s created", order.PONumber))
+	return u.GetPurchaseOrderByID(ctx, order.ID)
+}
+
+// buildPurchaseOrder memvalidasi supplier, lokasi, dan mapping produk lalu menyusun PO draft (belum disimpan).
+// Harga baris tanpa unit_cost diambil dari mapping supplier.
+func buildPurchaseOrder(supplierRepo repositorys.SupplierRepository, productRepo repositorys.ProductRepository, req dtos.PurchaseOrderRequest, userID uuid.UUID) (*models.PurchaseOrder, error) {
+	supplier, err := supplierRepo.GetSupplierByID(req.SupplierID)
+	if err != nil {
+		return nil, fmt.Errorf("supplier not found: %w", err)
+	}
+	if !supplier.IsActive {
+		return nil, ErrSupplierInactive
+	}
+	if _, err := productRepo.GetWarehouseLocationByID(req.WarehouseLocationID); err != nil {
+		return nil, fmt.Errorf("warehouse location not found: %w", err)
+	}
+	expectedDate, err := parseExpiryDate(req.ExpectedDate)
+	if err != nil {
+		return nil, err
+	}
+
+	order := &models.PurchaseOrder{
+		ID:                  uuid.New(),
+		PONumber:            utils.GenerateDocumentNumber("PO"),
+		SupplierID:          supplier.ID,
+		WarehouseLocationID: req.WarehouseLocationID,
+		Status:              "draft",
+		ExpectedDate:        expectedDate,
+		Note:                req.Note,
+		CreatedBy:           userID,
+	}
+
+	// Gabungkan baris dengan produk yang sama; unit_cost pertama yang diisi dipakai
+	quantities := make(map[uuid.UUID]int)
+	costs := make(map[uuid.UUID]*float64)
+	var productOrder []uuid.UUID
+	for _, line := range req.Lines {
+		quantity, err := baseQuantity(productRepo, line.ProductID, line.Unit, line.Quantity)
+		if err != nil {
+			return nil, fmt.Errorf("product %s: %w", line.ProductID, err)
+		}
+		if _, ok := quantities[line.ProductID]; !ok {
+			productOrder = append(productOrder, line.ProductID)
+		}
+		quantities[line.ProductID] += quantity
+		if costs[line.ProductID] == nil {
+			costs[line.ProductID] = baseUnitCost(line.UnitCost, line.Quantity, quantity)
+		}
+	}
+
+	leadTime := 0
+	for _, productID := range productOrder {
+		mapping, err := supplierRepo.GetSupplierProduct(supplier.ID, productID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("product %s: %w", productID, ErrProductNotSupplied)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if quantities[productID] < mapping.MinOrderQuantity {
+			return nil, fmt.Errorf("product %s needs at least %d: %w", productID, mapping.MinOrderQuantity, ErrBelowMinOrderQuantity)
+		}
+		if days := supplierLeadTime(mapping, supplier); days > leadTime {
+			leadTime = days
+		}
+
+		unitCost := mapping.UnitCost
+		if costs[productID] != nil {
+			unitCost = *costs[productID]
+		}
+		order.Lines = append(order.Lines, models.PurchaseOrderLine{
+			ID:              uuid.New(),
+			PurchaseOrderID: order.ID,
+			SourceProductID: productID,
+			SupplierSKU:     mapping.SupplierSKU,
+			Quantity:        quantities[productID],
+			UnitCost:        unitCost,
+		})
+	}
+
+	if order.ExpectedDate == nil {
+		// Default kedatangan: hari ini ditambah lead time terpanjang di antara baris PO
+		today, _ := time.Parse("2006-01-02", time.Now().Format("2006-01-02"))
+		expected := today.AddDate(0, 0, leadTime)
+		order.ExpectedDate = &expected
+	}
+	return order, nil
+}
+
+// UpdatePurchaseOrder mengganti supplier, lokasi, tanggal, catatan, dan seluruh baris PO yang masih draft
+func (u *purchaseOrderUseCase) UpdatePurchaseOrder(ctx context.Context, id uuid.UUID, req dtos.PurchaseOrderRequest, userID uuid.UUID) (*dtos.PurchaseOrderResponse, error) {
+	if err := u.validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	err := u.repo.WithTransaction(func(repo repositorys.PurchaseOrderRepository, stockRepo repositorys.ProductRepository) error {
+		order, err := repo.GetPurchaseOrderForUpdate(id)
+		if err != nil {
+			return err
+		}
+		if order.Status != "draft" {
+			return fmt.Errorf("%w: cannot edit a %s purchase order", ErrInvalidPurchaseOrderState, order.Status)
+		}
+
+		built, err := buildPurchaseOrder(u.supplierRepo, stockRepo, req, userID)
+		if err != nil {
+			return err
+		}
+		for i := range built.Lines {
+			built.Lines[i].PurchaseOrderID = order.ID
+		}
+		if err := repo.ReplacePurchaseOrderLines(order.ID, built.Lines); err != nil {
+			return err
+		}
+
+		order.SupplierID = built.SupplierID
+		order.WarehouseLocationID = built.WarehouseLocationID
+		order.ExpectedDate = built.ExpectedDate
+		order.Note = built.Note
+		order.UpdatedAt = time.Now()
+		return repo.UpdatePurchaseOrder(order)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return u.GetPurchaseOrderByID(ctx, id)
+}
+
+func (u *purchaseOrderUseCase) GetPurchaseOrderByID(ctx context.Context, id uuid.UUID) (*dtos.PurchaseOrderResponse, error) {
+	order, err := u.repo.GetPurchaseOrderByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return toPurchaseOrderResponse(order), nil
+}
+
+func (u *purchaseOrderUseCase) GetPurchaseOrdersList(ctx context.Context, req dtos.PurchaseOrderListRequest) ([]dtos.PurchaseOrderResponse, dtos.Pagination, error) {
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.Limit == 0 {
+		req.Limit = 10
+	}
+	if err := u.validate.Struct(req); err != nil {
+		return nil, dtos.Pagination{}, err
+	}
+
+	orders, total, err := u.repo.GetPurchaseOrdersList(req)
+	if err != nil {
+		return nil, dtos.Pagination{}, err
+	}
+
+	list := make([]dtos.PurchaseOrderResponse, 0, len(orders))
+	for i := range orders {
+		list = append(list, *toPurchaseOrderResponse(&orders[i]))
+	}
+	return list, buildPagination(req.Page, req.Limit, total), nil
+}
+
+// ApprovePurchaseOrder mengunci isi PO draft sehingga siap dikirim ke supplier dan diterima
+func (u *purchaseOrderUseCase) ApprovePurchaseOrder(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*dtos.PurchaseOrderResponse, error) {
+	err := u.repo.WithTransaction(func(repo repositorys.PurchaseOrderRepository, _ repositorys.ProductRepository) error {
+		order, err := repo.GetPurchaseOrderForUpdate(id)
+		if err != nil {
+			return err
+		}
+		if order.Status != "draft" {
+			return fmt.Errorf("%w: cannot approve a %s purchase order", ErrInvalidPurchaseOrderState, order.Status)
+		}
+
+		now := time.Now()
+		order.Status = "approved"
+		order.ApprovedBy = &userID
+		order.ApprovedAt = &now
+		order.UpdatedAt = now
+		return repo.UpdatePurchaseOrder(order)
+	})
+	if err != nil {
+		return nil, err
+	}
+	u.log.Info(fmt.Sprintf("Purchase order %s approved", id))
+	return u.GetPurchaseOrderByID(ctx, id)
+}
+
+// ClosePurchaseOrder menutup PO approved/partially_received; sisa yang belum diterima tidak lagi ditunggu
+func (u *purchaseOrderUseCase) ClosePurchaseOrder(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*dtos.PurchaseOrderResponse, error) {
+	err := u.repo.WithTransaction(func(repo repositorys.PurchaseOrderRepository, _ repositorys.ProductRepository) error {
+		order, err := repo.GetPurchaseOrderForUpdate(id)
+		if err != nil {
+			return err
+		}
+		if order.Status != "approved" && order.Status != "partially_received" {
+			return fmt.Errorf("%w: cannot close a %s purchase order", ErrInvalidPurchaseOrderState, order.Status)
+		}
+		return closePurchaseOrder(repo, order, userID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return u.GetPurchaseOrderByID(ctx, id)
+}
+
+func closePurchaseOrder(repo repositorys.PurchaseOrderRepository, order *models.PurchaseOrder, userID uuid.UUID) error {
+	now := time.Now()
+	order.Status = "closed"
+	order.ClosedBy = &userID
+	order.ClosedAt = &now
+	order.UpdatedAt = now
+	return repo.UpdatePurchaseOrder(order)
+}
+
+// CancelPurchaseOrder membatalkan PO yang belum menerima barang apa pun
+func (u *purchaseOrderUseCase) CancelPurchaseOrder(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*dtos.PurchaseOrderResponse, error) {
+	err := u.repo.WithTransaction(func(repo repositorys.PurchaseOrderRepository, _ repositorys.ProductRepository) error {
+		order, err := repo.GetPurchaseOrderForUpdate(id)
+		if err != nil {
+			return err
+		}
+		if order.Status != "draft" && order.Status != "approved" {
+			return fmt.Errorf("%w: cannot cancel a %s purchase order", ErrInvalidPurchaseOrderState, order.Status)
+		}
+
+		now := time.Now()
+		order.Status = "cancelled"
+		order.CancelledBy = &userID
+		order.CancelledAt = &now
+		order.UpdatedAt = now
+		return repo.UpdatePurchaseOrder(order)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return u.GetPurchaseOrderByID(ctx, id)
+}
+
+func lineReceiptStatus(line models.PurchaseOrderLine) string {
+	switch {
+	case line.ReceivedQuantity == 0:
+		return "pending"
+	case line.ReceivedQuantity < line.Quantity:
+		return "partial"
+	case line.ReceivedQuantity > line.Quantity:
+		return "over"
+	}
+	return "complete"
+}
+
+func toPurchaseOrderResponse(o *models.PurchaseOrder) *dtos.PurchaseOrderResponse {
+	response := &dtos.PurchaseOrderResponse{
+		ID:                    o.ID,
+		PONumber:              o.PONumber,
+		SupplierID:            o.SupplierID,
+		SupplierName:          o.Supplier.Name,
+		WarehouseLocationID:   o.WarehouseLocationID,
+		WarehouseLocationName: o.WarehouseLocation.Name,
+		Status:                o.Status,
+		ExpectedDate:          o.ExpectedDate,
+		Note:                  o.Note,
+		Lines:                 make([]dtos.PurchaseOrderLineResponse, 0, len(o.Lines)),
+		CreatedBy:             o.CreatedBy,
+		ApprovedBy:            o.ApprovedBy,
+		ApprovedAt:            o.ApprovedAt,
+		ClosedBy:              o.ClosedBy,
+		ClosedAt:              o.ClosedAt,
+		CancelledBy:           o.CancelledBy,
+		CancelledAt:           o.CancelledAt,
+		CreatedAt:             o.CreatedAt,
+		UpdatedAt:             o.UpdatedAt,
+	}
+	for _, line := range o.Lines {
+		outstanding := line.Quantity - line.ReceivedQuantity
+		if outstanding < 0 {
+			outstanding = 0
+		}
+		lineTotal := float64(line.Quantity) * line.UnitCost
+		response.Lines = append(response.Lines, dtos.PurchaseOrderLineResponse{
+			ID:                  line.ID,
+			ProductID:           line.SourceProductID,
+			ProductName:         line.Product.Name,
+			SKU:                 line.Product.SKU,
+			SupplierSKU:         line.SupplierSKU,
+			Quantity:            line.Quantity,
+			ReceivedQuantity:    line.ReceivedQuantity,
+			OutstandingQuantity: outstanding,
+			Variance:            line.ReceivedQuantity - line.Quantity,
+			ReceiptStatus:       lineReceiptStatus(line),
+			UnitCost:            line.UnitCost,
+			LineTotal:           lineTotal,
+		})
+		response.TotalQuantity += line.Quantity
+		response.ReceivedQuantity += line.ReceivedQuantity
+		response.TotalCost += lineTotal
+	}
+	return response
+}
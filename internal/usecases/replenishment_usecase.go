@@ -0,0 +1,315 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"auth-service/internal/dtos"
+	"auth-service/internal/models"
+	"auth-service/internal/repositorys"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var ErrProposalNotOpen = errors.New("replenishment proposal is no longer open")
+
+type ReplenishmentUseCase interface {
+	GenerateReplenishmentProposals(ctx context.Context) (*dtos.ReplenishmentRunResponse, error)
+	GetReplenishmentProposalsList(ctx context.Context, req dtos.ReplenishmentProposalListRequest) ([]dtos.ReplenishmentProposalResponse, dtos.Pagination, error)
+	ConvertReplenishmentProposal(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*dtos.ReplenishmentProposalResponse, error)
+	DismissReplenishmentProposal(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*dtos.ReplenishmentProposalResponse, error)
+}
+
+type replenishmentUseCase struct {
+	repo         repositorys.ReplenishmentRepository
+	supplierRepo repositorys.SupplierRepository
+	validate     *validator.Validate
+	log          *logrus.Logger
+}
+
+func NewReplenishmentUseCase(repo repositorys.ReplenishmentRepository, supplierRepo repositorys.SupplierRepository, log *logrus.Logger, validate *validator.Validate) ReplenishmentUseCase {
+	return &replenishmentUseCase{repo: repo, supplierRepo: supplierRepo, log: log, validate: validate}
+}
+
+// GenerateReplenishmentProposals membuat atau memperbarui proposal untuk setiap stok di bawah reorder point.
+// Sumber diusulkan dari lokasi lain yang surplusnya cukup untuk seluruh jumlah, selain itu dari pembelian.
+// Proposal open yang stoknya sudah tidak kekurangan ditandai obsolete.
+func (u *replenishmentUseCase) GenerateReplenishmentProposals(ctx context.Context) (*dtos.ReplenishmentRunResponse, error) {
+	result := &dtos.ReplenishmentRunResponse{}
+	err := u.repo.WithTransaction(func(repo repositorys.ReplenishmentRepository, _ repositorys.StockTransferRepository, _ repositorys.PurchaseOrderRepository, _ repositorys.ProductRepository) error {
+		candidates, err := repo.GetReplenishmentCandidates()
+		if err != nil {
+			return err
+		}
+
+		var productIDs []uuid.UUID
+		seen := make(map[uuid.UUID]bool)
+		for _, c := range candidates {
+			if !seen[c.ProductID] {
+				seen[c.ProductID] = true
+				productIDs = append(productIDs, c.ProductID)
+			}
+		}
+		surpluses, err := repo.GetSurplusStocks(productIDs)
+		if err != nil {
+			return err
+		}
+
+		open, err := repo.GetOpenReplenishmentProposals()
+		if err != nil {
+			return err
+		}
+		openByStock := make(map[uuid.UUID]*models.ReplenishmentProposal, len(open))
+		for i := range open {
+			openByStock[open[i].ProductStockID] = &open[i]
+		}
+
+		now := time.Now()
+		for _, c := range candidates {
+			suggested := c.Target - (c.Available + c.Incoming)
+			if suggested <= 0 {
+				continue
+			}
+
+			sourceType := "transfer"
+			sourceLocationID := pickSurplusSource(surpluses, c, suggested)
+			if sourceLocationID == nil {
+				sourceType = "purchase"
+			}
+
+			proposal, exists := openByStock[c.ProductStockID]
+			if exists {
+				delete(openByStock, c.ProductStockID)
+			} else {
+				proposal = &models.ReplenishmentProposal{
+					ID:                  uuid.New(),
+					ProductStockID:      c.ProductStockID,
+					SourceProductID:     c.ProductID,
+					WarehouseLocationID: c.WarehouseLocationID,
+					Status:              "open",
+					CreatedAt:           now,
+				}
+			}
+			proposal.AvailableQuantity = c.Available
+			proposal.IncomingQuantity = c.Incoming
+			proposal.ReorderPoint = c.ReorderPoint
+			proposal.TargetQuantity = c.Target
+			proposal.SuggestedQuantity = suggested
+			proposal.SourceType = sourceType
+			proposal.SourceLocationID = sourceLocationID
+			proposal.UpdatedAt = now
+
+			if exists {
+				err = repo.UpdateReplenishmentProposal(proposal)
+				result.Updated++
+			} else {
+				err = repo.CreateReplenishmentProposal(proposal)
+				result.Opened++
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		// Sisa proposal open tidak lagi di bawah reorder point
+		for _, proposal := range openByStock {
+			proposal.Status = "obsolete"
+			proposal.ResolvedAt = &now
+			proposal.UpdatedAt = now
+			if err := repo.UpdateReplenishmentProposal(proposal); err != nil {
+				return err
+			}
+			result.Obsolete++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	u.log.Info(fmt.Sprintf("Replenishment planner: %d opened, %d updated, %d obsolete", result.Opened, result.Updated, result.Obsolete))
+	return result, nil
+}
+
+// pickSurplusSource memilih lokasi lain dengan surplus terbesar yang cukup untuk seluruh jumlah,
+// lalu mengurangi surplusnya agar tidak dijanjikan ke beberapa proposal sekaligus
+func pickSurplusSource(surpluses []repositorys.SurplusStockRow, c repositorys.ReplenishmentCandidateRow, quantity int) *uuid.UUID {
+	for i := range surpluses {
+		s := &surpluses[i]
+		if s.ProductID != c.ProductID || s.WarehouseLocationID == c.WarehouseLocationID || s.Surplus < quantity {
+			continue
+		}
+		s.Surplus -= quantity
+		locationID := s.WarehouseLocationID
+		return &locationID
+	}
+	return nil
+}
+
+func (u *replenishmentUseCase) GetReplenishmentProposalsList(ctx context.Context, req dtos.ReplenishmentProposalListRequest) ([]dtos.ReplenishmentProposalResponse, dtos.Pagination, error) {
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.Limit == 0 {
+		req.Limit = 10
+	}
+	if err := u.validate.Struct(req); err != nil {
+		return nil, dtos.Pagination{}, err
+	}
+
+	proposals, total, err := u.repo.GetReplenishmentProposalsList(req)
+	if err != nil {
+		return nil, dtos.Pagination{}, err
+	}
+
+	list := make([]dtos.ReplenishmentProposalResponse, 0, len(proposals))
+	for i := range proposals {
+		list = append(list, toReplenishmentProposalResponse(&proposals[i]))
+	}
+	return list, buildPagination(req.Page, req.Limit, total), nil
+}
+
+// ConvertReplenishmentProposal mengubah proposal open menjadi transfer draft dari lokasi surplus,
+// atau PO draft ke supplier preferred produk untuk proposal purchase
+func (u *replenishmentUseCase) ConvertReplenishmentProposal(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*dtos.ReplenishmentProposalResponse, error) {
+	err := u.repo.WithTransaction(func(repo repositorys.ReplenishmentRepository, transferRepo repositorys.StockTransferRepository, purchaseRepo repositorys.PurchaseOrderRepository, stockRepo repositorys.ProductRepository) error {
+		proposal, err := repo.GetReplenishmentProposalForUpdate(id)
+		if err != nil {
+			return err
+		}
+		if proposal.Status != "open" {
+			return ErrProposalNotOpen
+		}
+
+		note := fmt.Sprintf("Replenishment proposal %s", proposal.ID)
+		if proposal.SourceType == "transfer" && proposal.SourceLocationID != nil {
+			transfer, err := buildStockTransfer(stockRepo, dtos.CreateStockTransferRequest{
+				SourceLocationID:      *proposal.SourceLocationID,
+				DestinationLocationID: proposal.WarehouseLocationID,
+				Note:                  note,
+				Items: []dtos.StockTransferItemRequest{
+					{ProductID: proposal.SourceProductID, Quantity: proposal.SuggestedQuantity},
+				},
+			}, userID)
+			if err != nil {
+				return err
+			}
+			if err := transferRepo.CreateStockTransfer(transfer); err != nil {
+				return err
+			}
+			proposal.ReferenceType = "stock_transfer"
+			proposal.ReferenceID = &transfer.ID
+		} else {
+			mapping, err := u.supplierRepo.GetPreferredSupplierProduct(proposal.SourceProductID)
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrNoSupplierForProduct
+			}
+			if err != nil {
+				return err
+			}
+			// Jumlah dibulatkan ke minimum order supplier
+			quantity := proposal.SuggestedQuantity
+			if quantity < mapping.MinOrderQuantity {
+				quantity = mapping.MinOrderQuantity
+			}
+			order, err := buildPurchaseOrder(u.supplierRepo, stockRepo, dtos.PurchaseOrderRequest{
+				SupplierID:          mapping.SupplierID,
+				WarehouseLocationID: proposal.WarehouseLocationID,
+				Note:                note,
+				Lines: []dtos.PurchaseOrderLineRequest{
+					{ProductID: proposal.SourceProductID, Quantity: quantity},
+				},
+			}, userID)
+			if err != nil {
+				return err
+			}
+			if err := purchaseRepo.CreatePurchaseOrder(order); err != nil {
+				return err
+			}
+			proposal.ReferenceType = "purchase_order"
+			proposal.ReferenceID = &order.ID
+		}
+
+		now := time.Now()
+		proposal.Status = "converted"
+		proposal.ResolvedBy = &userID
+		proposal.ResolvedAt = &now
+		proposal.UpdatedAt = now
+		return repo.UpdateReplenishmentProposal(proposal)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	u.log.Info(fmt.Sprintf("Replenishment proposal %s converted", id))
+	return u.getReplenishmentProposal(id)
+}
+
+// DismissReplenishmentProposal menutup proposal open tanpa tindakan. Jika stok masih kekurangan,
+// planner berikutnya akan membuka proposal baru.
+func (u *replenishmentUseCase) DismissReplenishmentProposal(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*dtos.ReplenishmentProposalResponse, error) {
+	err := u.repo.WithTransaction(func(repo repositorys.ReplenishmentRepository, _ repositorys.StockTransferRepository, _ repositorys.PurchaseOrderRepository, _ repositorys.ProductRepository) error {
+		proposal, err := repo.GetReplenishmentProposalForUpdate(id)
+		if err != nil {
+			return err
+		}
+		if proposal.Status != "open" {
+			return ErrProposalNotOpen
+		}
+
+		now := time.Now()
+		proposal.Status = "dismissed"
+		proposal.ResolvedBy = &userID
+		proposal.ResolvedAt = &now
+		proposal.UpdatedAt = now
+		return repo.UpdateReplenishmentProposal(proposal)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return u.getReplenishmentProposal(id)
+}
+
+func (u *replenishmentUseCase) getReplenishmentProposal(id uuid.UUID) (*dtos.ReplenishmentProposalResponse, error) {
+	proposal, err := u.repo.GetReplenishmentProposalByID(id)
+	if err != nil {
+		return nil, err
+	}
+	response := toReplenishmentProposalResponse(proposal)
+	return &response, nil
+}
+
+func toReplenishmentProposalResponse(p *models.ReplenishmentProposal) dtos.ReplenishmentProposalResponse {
+	response := dtos.ReplenishmentProposalResponse{
+		ID:                    p.ID,
+		ProductStockID:        p.ProductStockID,
+		ProductID:             p.SourceProductID,
+		ProductName:           p.Product.Name,
+		SKU:                   p.Product.SKU,
+		WarehouseLocationID:   p.WarehouseLocationID,
+		WarehouseLocationName: p.WarehouseLocation.Name,
+		AvailableQuantity:     p.AvailableQuantity,
+		IncomingQuantity:      p.IncomingQuantity,
+		ReorderPoint:          p.ReorderPoint,
+		TargetQuantity:        p.TargetQuantity,
+		SuggestedQuantity:     p.SuggestedQuantity,
+		SourceType:            p.SourceType,
+		SourceLocationID:      p.SourceLocationID,
+		Status:                p.Status,
+		ReferenceType:         p.ReferenceType,
+		ReferenceID:           p.ReferenceID,
+		ResolvedBy:            p.ResolvedBy,
+		ResolvedAt:            p.ResolvedAt,
+		CreatedAt:             p.CreatedAt,
+		UpdatedAt:             p.UpdatedAt,
+	}
+	if p.SourceLocation != nil {
+		response.SourceLocationName = p.SourceLocation.Name
+	}
+	return response
+}
@@ -0,0 +1,489 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"auth-service/internal/dtos"
+	"auth-service/internal/models"
+	"auth-service/internal/repositorys"
+	"auth-service/internal/utils"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidReturnState         = errors.New("invalid return authorization state for this action")
+	ErrReturnProductNotOnOrder    = errors.New("product was not shipped on the referenced outbound order")
+	ErrReturnExceedsShipped       = errors.New("return quantity exceeds the shipped quantity not yet returned")
+	ErrReturnLineNotOnRMA         = errors.New("line is not on this return authorization")
+	ErrReceiveExceedsAuthorized   = errors.New("received quantity exceeds the authorized quantity")
+	ErrDispositionExceedsReceived = errors.New("disposition quantity exceeds the received quantity still awaiting inspection")
+	ErrRestockIntoQuarantine      = errors.New("restocked returns cannot go to a quarantine location")
+)
+
+type ReturnAuthorizationUseCase interface {
+	CreateReturnAuthorization(ctx context.Context, req dtos.CreateReturnAuthorizationRequest, userID uuid.UUID) (*dtos.ReturnAuthorizationResponse, error)
+	GetReturnAuthorizationByID(ctx context.Context, id uuid.UUID) (*dtos.ReturnAuthorizationResponse, error)
+	GetReturnAuthorizationsList(ctx context.Context, req dtos.ReturnAuthorizationListRequest) ([]dtos.ReturnAuthorizationResponse, dtos.Pagination, error)
+	ReceiveReturn(ctx context.Context, id uuid.UUID, req dtos.ReceiveReturnRequest, userID uuid.UUID) (*dtos.ReturnAuthorizationResponse, error)
+	InspectReturn(ctx context.Context, id uuid.UUID, req dtos.InspectReturnRequest, userID uuid.UUID) (*dtos.ReturnAuthorizationResponse, error)
+	CancelReturnAuthorization(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*dtos.ReturnAuthorizationResponse, error)
+}
+
+type returnAuthorizationUseCase struct {
+	repo repositorys.ReturnAuthorizationRepository
+	// costingMethod adalah metode valuasi default untuk produk tanpa costing_method (valuation.default_method)
+	costingMethod string
+	validate      *validator.Validate
+	log           *logrus.Logger
+}
+
+func NewReturnAuthorizationUseCase(repo repositorys.ReturnAuthorizationRepository, costingMethod string, log *logrus.Logger, validate *validator.Validate) ReturnAuthorizationUseCase {
+	return &returnAuthorizationUseCase{repo: repo, costingMethod: costingMethod, log: log, validate: validate}
+}
+
+// CreateReturnAuthorization menerbitkan RMA. Bila merujuk pesanan outbound, setiap produk harus
+// pernah dikirim di pesanan itu dan quantity-nya tidak melebihi sisa yang belum diretur.
+func (u *returnAuthorizationUseCase) CreateReturnAuthorization(ctx context.Context, req dtos.CreateReturnAuthorizationRequest, userID uuid.UUID) (*dtos.ReturnAuthorizationResponse, error) {
+	if err := u.validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	rma := &models.ReturnAuthorization{
+		ID:                uuid.New(),
+		RMANumber:         utils.GenerateDocumentNumber("RMA"),
+		OutboundOrderID:   req.OutboundOrderID,
+		CustomerName:      req.CustomerName,
+		CustomerReference: req.CustomerReference,
+		Reason:            req.Reason,
+		Status:            "authorized",
+		Note:              req.Note,
+		CreatedBy:         userID,
+	}
+
+	err := u.repo.WithTransaction(func(repo repositorys.ReturnAuthorizationRepository, stockRepo repositorys.ProductRepository) error {
+		// Baris dengan produk yang sama digabung dalam satuan dasar
+		quantities := make(map[uuid.UUID]int)
+		var productOrder []uuid.UUID
+		for _, line := range req.Lines {
+			quantity, err := baseQuantity(stockRepo, line.ProductID, line.Unit, line.Quantity)
+			if err != nil {
+				return fmt.Errorf("product %s: %w", line.ProductID, err)
+			}
+			if _, ok := quantities[line.ProductID]; !ok {
+				productOrder = append(productOrder, line.ProductID)
+			}
+			quantities[line.ProductID] += quantity
+		}
+
+		var order *models.OutboundOrder
+		var returned map[uuid.UUID]int
+		if req.OutboundOrderID != nil {
+			var err error
+			if order, err = repo.GetOutboundOrderForReturnUpdate(*req.OutboundOrderID); err != nil {
+				return fmt.Errorf("outbound order not found: %w", err)
+			}
+			if order.Status != "shipped" {
+				return fmt.Errorf("%w: cannot return goods from a %s order", ErrInvalidReturnState, order.Status)
+			}
+			if returned, err = repo.GetReturnedQuantitiesByOrderLine(order.ID); err != nil {
+				return err
+			}
+			if rma.CustomerName == "" {
+				rma.CustomerName = order.CustomerName
+			}
+			if rma.CustomerReference == "" {
+				rma.CustomerReference = order.CustomerReference
+			}
+		}
+
+		for _, productID := range productOrder {
+			product, err := stockRepo.GetProductByID(productID)
+			if err != nil {
+				return fmt.Errorf("product %s not found: %w", productID, err)
+			}
+			line := models.ReturnAuthorizationLine{
+				ID:                    uuid.New(),
+				ReturnAuthorizationID: rma.ID,
+				SourceProductID:       productID,
+				Quantity:              quantities[productID],
+			}
+			if order != nil {
+				orderLine := findShippedOrderLine(order, productID)
+				if orderLine == nil {
+					return fmt.Errorf("%w: %s", ErrReturnProductNotOnOrder, product.SKU)
+				}
+				if left := orderLine.ShippedQuantity - returned[orderLine.ID]; line.Quantity > left {
+					return fmt.Errorf("%s: %d requested, %d returnable: %w", product.SKU, line.Quantity, left, ErrReturnExceedsShipped)
+				}
+				line.OutboundOrderLineID = &orderLine.ID
+			}
+			rma.Lines = append(rma.Lines, line)
+		}
+		return repo.CreateReturnAuthorization(rma)
+	})
+	if err != nil {
+		return nil, err
+	}
+	u.log.Info(fmt.Sprintf("Return authorization %s created", rma.RMANumber))
+	return u.GetReturnAuthorizationByID(ctx, rma.ID)
+}
+
+// findShippedOrderLine mencari baris pesanan untuk produk yang quantity terkirimnya > 0
+func findShippedOrderLine(order *models.OutboundOrder, productID uuid.UUID) *models.OutboundOrderLine {
+	for i := range order.Lines {
+		if order.Lines[i].SourceProductID == productID && order.Lines[i].ShippedQuantity > 0 {
+			return &order.Lines[i]
+		}
+	}
+	return nil
+}
+
+func (u *returnAuthorizationUseCase) GetReturnAuthorizationByID(ctx context.Context, id uuid.UUID) (*dtos.ReturnAuthorizationResponse, error) {
+	rma, err := u.repo.GetReturnAuthorizationByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return toReturnAuthorizationResponse(rma), nil
+}
+
+func (u *returnAuthorizationUseCase) GetReturnAuthorizationsList(ctx context.Context, req dtos.ReturnAuthorizationListRequest) ([]dtos.ReturnAuthorizationResponse, dtos.Pagination, error) {
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.Limit == 0 {
+		req.Limit = 10
+	}
+	if err := u.validate.Struct(req); err != nil {
+		return nil, dtos.Pagination{}, err
+	}
+
+	rmas, total, err := u.repo.GetReturnAuthorizationsList(req)
+	if err != nil {
+		return nil, dtos.Pagination{}, err
+	}
+
+	list := make([]dtos.ReturnAuthorizationResponse, 0, len(rmas))
+	for i := range rmas {
+		list = append(list, *toReturnAuthorizationResponse(&rmas[i]))
+	}
+	return list, buildPagination(req.Page, req.Limit, total), nil
+}
+
+// ReceiveReturn mencatat kedatangan barang retur (authorized -> received). Barang belum masuk
+// stok; stok baru bertambah setelah inspeksi memberi disposition restock atau quarantine.
+func (u *returnAuthorizationUseCase) ReceiveReturn(ctx context.Context, id uuid.UUID, req dtos.ReceiveReturnRequest, userID uuid.UUID) (*dtos.ReturnAuthorizationResponse, error) {
+	if err := u.validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	err := u.repo.WithTransaction(func(repo repositorys.ReturnAuthorizationRepository, stockRepo repositorys.ProductRepository) error {
+		rma, err := repo.GetReturnAuthorizationForUpdate(id)
+		if err != nil {
+			return err
+		}
+		if rma.Status != "authorized" {
+			return fmt.Errorf("%w: cannot receive a %s return", ErrInvalidReturnState, rma.Status)
+		}
+
+		received := make(map[uuid.UUID]int, len(rma.Lines))
+		if len(req.Lines) == 0 {
+			for _, line := range rma.Lines {
+				received[line.ID] = line.Quantity
+			}
+		}
+		for _, item := range req.Lines {
+			line := findReturnLine(rma, item.ReturnLineID)
+			if line == nil {
+				return fmt.Errorf("%w: line %s", ErrReturnLineNotOnRMA, item.ReturnLineID)
+			}
+			quantity, err := baseQuantity(stockRepo, line.SourceProductID, item.Unit, item.Quantity)
+			if err != nil {
+				return fmt.Errorf("%s: %w", line.Product.SKU, err)
+			}
+			received[line.ID] += quantity
+			if received[line.ID] > line.Quantity {
+				return fmt.Errorf("%s: %w", line.Product.SKU, ErrReceiveExceedsAuthorized)
+			}
+		}
+
+		now := time.Now()
+		for i := range rma.Lines {
+			line := &rma.Lines[i]
+			line.ReceivedQuantity = received[line.ID]
+			line.UpdatedAt = now
+			if err := repo.UpdateReturnAuthorizationLine(line); err != nil {
+				return err
+			}
+		}
+
+		rma.Status = "received"
+		rma.ReceivedBy = &userID
+		rma.ReceivedAt = &now
+		rma.UpdatedAt = now
+		completeIfInspected(rma, userID, now)
+		return repo.UpdateReturnAuthorization(rma)
+	})
+	if err != nil {
+		return nil, err
+	}
+	u.log.Info(fmt.Sprintf("Return authorization %s received", id))
+	return u.GetReturnAuthorizationByID(ctx, id)
+}
+
+// InspectReturn membukukan hasil inspeksi barang retur yang sudah diterima:
+//   - restock: movement return (customer_return) ke lokasi stok sellable
+//   - quarantine: movement return ke lokasi karantina (stok non-sellable)
+//   - scrap: hanya dicatat, barang tidak pernah masuk stok
+//
+// RMA menjadi completed setelah seluruh quantity yang diterima mendapat disposition.
+func (u *returnAuthorizationUseCase) InspectReturn(ctx context.Context, id uuid.UUID, req dtos.InspectReturnRequest, userID uuid.UUID) (*dtos.ReturnAuthorizationResponse, error) {
+	if err := u.validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	err := u.repo.WithTransaction(func(repo repositorys.ReturnAuthorizationRepository, stockRepo repositorys.ProductRepository) error {
+		rma, err := repo.GetReturnAuthorizationForUpdate(id)
+		if err != nil {
+			return err
+		}
+		if rma.Status != "received" {
+			return fmt.Errorf("%w: cannot inspect a %s return", ErrInvalidReturnState, rma.Status)
+		}
+
+		referenceNote := rma.RMANumber
+		if rma.OutboundOrderID != nil {
+			order, err := repo.GetOutboundOrderForReturnUpdate(*rma.OutboundOrderID)
+			if err != nil {
+				return err
+			}
+			referenceNote += " / " + order.OrderNumber
+		}
+
+		now := time.Now()
+		touched := make(map[uuid.UUID]*models.ReturnAuthorizationLine)
+		for _, item := range req.Lines {
+			line := findReturnLine(rma, item.ReturnLineID)
+			if line == nil {
+				return fmt.Errorf("%w: line %s", ErrReturnLineNotOnRMA, item.ReturnLineID)
+			}
+			quantity, err := baseQuantity(stockRepo, line.SourceProductID, item.Unit, item.Quantity)
+			if err != nil {
+				return fmt.Errorf("%s: %w", line.Product.SKU, err)
+			}
+			if pending := line.ReceivedQuantity - dispositionedQuantity(line); quantity > pending {
+				return fmt.Errorf("%s: %d pending inspection: %w", line.Product.SKU, pending, ErrDispositionExceedsReceived)
+			}
+			expiryDate, err := parseExpiryDate(item.ExpiryDate)
+			if err != nil {
+				return err
+			}
+
+			inspection := &models.ReturnInspection{
+				ID:                        uuid.New(),
+				ReturnAuthorizationID:     rma.ID,
+				ReturnAuthorizationLineID: line.ID,
+				SourceProductID:           line.SourceProductID,
+				Disposition:               item.Disposition,
+				Quantity:                  quantity,
+				LotNumber:                 item.LotNumber,
+				ExpiryDate:                expiryDate,
+				SerialNumbers:             item.SerialNumbers,
+				Note:                      item.Note,
+				InspectedBy:               userID,
+				CreatedAt:                 now,
+			}
+
+			switch item.Disposition {
+			case "restock", "quarantine":
+				location, err := stockRepo.GetWarehouseLocationByID(*item.WarehouseLocationID)
+				if err != nil {
+					return fmt.Errorf("warehouse location not found: %w", err)
+				}
+				quarantine := item.Disposition == "quarantine"
+				if quarantine && !location.Quarantine {
+					return fmt.Errorf("%s: %w", location.Path, ErrQuarantineLocation)
+				}
+				if !quarantine {
+					// Restock tidak boleh masuk ke lokasi atau stok karantina
+					if location.Quarantine {
+						return fmt.Errorf("%s: %w", location.Path, ErrRestockIntoQuarantine)
+					}
+					stock, err := stockRepo.GetProductStockForUpdate(line.SourceProductID, *item.WarehouseLocationID)
+					if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+						return err
+					}
+					if stock != nil && stock.Status == "quarantined" {
+						return fmt.Errorf("%s: %w", line.Product.SKU, ErrStockQuarantined)
+					}
+				}
+				if _, err := applyStockMovement(stockRepo, u.costingMethod, stockMovementInput{
+					ProductID:           line.SourceProductID,
+					WarehouseLocationID: *item.WarehouseLocationID,
+					MovementType:        "return",
+					Quantity:            quantity,
+					LotNumber:           item.LotNumber,
+					ExpiryDate:          expiryDate,
+					SerialNumbers:       item.SerialNumbers,
+					Reason:              "customer_return",
+					ReferenceType:       "return_authorization",
+					ReferenceID:         &rma.ID,
+					ReferenceNote:       referenceNote,
+					UserID:              userID,
+					Quarantine:          quarantine,
+				}); err != nil {
+					return fmt.Errorf("%s: %w", line.Product.SKU, err)
+				}
+				inspection.WarehouseLocationID = item.WarehouseLocationID
+				if quarantine {
+					line.QuarantinedQuantity += quantity
+				} else {
+					line.RestockedQuantity += quantity
+				}
+			case "scrap":
+				line.ScrappedQuantity += quantity
+			}
+
+			if err := repo.CreateReturnInspection(inspection); err != nil {
+				return err
+			}
+			touched[line.ID] = line
+		}
+
+		for _, line := range touched {
+			line.UpdatedAt = now
+			if err := repo.UpdateReturnAuthorizationLine(line); err != nil {
+				return err
+			}
+		}
+		rma.UpdatedAt = now
+		completeIfInspected(rma, userID, now)
+		return repo.UpdateReturnAuthorization(rma)
+	})
+	if err != nil {
+		return nil, err
+	}
+	u.log.Info(fmt.Sprintf("Return authorization %s inspected", id))
+	return u.GetReturnAuthorizationByID(ctx, id)
+}
+
+// CancelReturnAuthorization membatalkan RMA yang barangnya belum diterima
+func (u *returnAuthorizationUseCase) CancelReturnAuthorization(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*dtos.ReturnAuthorizationResponse, error) {
+	err := u.repo.WithTransaction(func(repo repositorys.ReturnAuthorizationRepository, _ repositorys.ProductRepository) error {
+		rma, err := repo.GetReturnAuthorizationForUpdate(id)
+		if err != nil {
+			return err
+		}
+		if rma.Status != "authorized" {
+			return fmt.Errorf("%w: cannot cancel a %s return", ErrInvalidReturnState, rma.Status)
+		}
+
+		now := time.Now()
+		rma.Status = "cancelled"
+		rma.CancelledBy = &userID
+		rma.CancelledAt = &now
+		rma.UpdatedAt = now
+		return repo.UpdateReturnAuthorization(rma)
+	})
+	if err != nil {
+		return nil, err
+	}
+	u.log.Info(fmt.Sprintf("Return authorization %s cancelled", id))
+	return u.GetReturnAuthorizationByID(ctx, id)
+}
+
+func findReturnLine(rma *models.ReturnAuthorization, lineID uuid.UUID) *models.ReturnAuthorizationLine {
+	for i := range rma.Lines {
+		if rma.Lines[i].ID == lineID {
+			return &rma.Lines[i]
+		}
+	}
+	return nil
+}
+
+func dispositionedQuantity(line *models.ReturnAuthorizationLine) int {
+	return line.RestockedQuantity + line.QuarantinedQuantity + line.ScrappedQuantity
+}
+
+// completeIfInspected menandai RMA received sebagai completed bila tidak ada lagi quantity yang menunggu inspeksi
+func completeIfInspected(rma *models.ReturnAuthorization, userID uuid.UUID, now time.Time) {
+	for i := range rma.Lines {
+		if dispositionedQuantity(&rma.Lines[i]) < rma.Lines[i].ReceivedQuantity {
+			return
+		}
+	}
+	rma.Status = "completed"
+	rma.CompletedBy = &userID
+	rma.CompletedAt = &now
+}
+
+func toReturnAuthorizationResponse(r *models.ReturnAuthorization) *dtos.ReturnAuthorizationResponse {
+	response := &dtos.ReturnAuthorizationResponse{
+		ID:                r.ID,
+		RMANumber:         r.RMANumber,
+		OutboundOrderID:   r.OutboundOrderID,
+		CustomerName:      r.CustomerName,
+		CustomerReference: r.CustomerReference,
+		Reason:            r.Reason,
+		Status:            r.Status,
+		Note:              r.Note,
+		Lines:             make([]dtos.ReturnAuthorizationLineResponse, 0, len(r.Lines)),
+		Inspections:       make([]dtos.ReturnInspectionResponse, 0, len(r.Inspections)),
+		CreatedBy:         r.CreatedBy,
+		ReceivedBy:        r.ReceivedBy,
+		ReceivedAt:        r.ReceivedAt,
+		CompletedBy:       r.CompletedBy,
+		CompletedAt:       r.CompletedAt,
+		CancelledBy:       r.CancelledBy,
+		CancelledAt:       r.CancelledAt,
+		CreatedAt:         r.CreatedAt,
+		UpdatedAt:         r.UpdatedAt,
+	}
+	if r.OutboundOrder != nil {
+		response.OrderNumber = r.OutboundOrder.OrderNumber
+	}
+	for i := range r.Lines {
+		line := &r.Lines[i]
+		response.Lines = append(response.Lines, dtos.ReturnAuthorizationLineResponse{
+			ID:                  line.ID,
+			OutboundOrderLineID: line.OutboundOrderLineID,
+			ProductID:           line.SourceProductID,
+			ProductName:         line.Product.Name,
+			SKU:                 line.Product.SKU,
+			Quantity:            line.Quantity,
+			ReceivedQuantity:    line.ReceivedQuantity,
+			RestockedQuantity:   line.RestockedQuantity,
+			QuarantinedQuantity: line.QuarantinedQuantity,
+			ScrappedQuantity:    line.ScrappedQuantity,
+			PendingQuantity:     max(line.ReceivedQuantity-dispositionedQuantity(line), 0),
+		})
+	}
+	for _, inspection := range r.Inspections {
+		item := dtos.ReturnInspectionResponse{
+			ID:                  inspection.ID,
+			ReturnLineID:        inspection.ReturnAuthorizationLineID,
+			ProductID:           inspection.SourceProductID,
+			SKU:                 inspection.Product.SKU,
+			Disposition:         inspection.Disposition,
+			Quantity:            inspection.Quantity,
+			WarehouseLocationID: inspection.WarehouseLocationID,
+			LotNumber:           inspection.LotNumber,
+			ExpiryDate:          inspection.ExpiryDate,
+			SerialNumbers:       inspection.SerialNumbers,
+			Note:                inspection.Note,
+			InspectedBy:         inspection.InspectedBy,
+			CreatedAt:           inspection.CreatedAt,
+		}
+		if inspection.WarehouseLocation != nil {
+			item.WarehouseLocationName = inspection.WarehouseLocation.Name
+		}
+		response.Inspections = append(response.Inspections, item)
+	}
+	return response
+}
@@ -0,0 +1,486 @@
+package usecases
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"auth-service/internal/models"
+	"auth-service/internal/repositorys"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInsufficientStock        = errors.New("insufficient stock for outbound movement")
+	ErrStockReserved            = errors.New("outbound movement would consume reserved stock")
+	ErrInvalidMovementType      = errors.New("invalid movement type")
+	ErrInvalidQuantity          = errors.New("quantity must be positive")
+	ErrLotNumberRequired        = errors.New("lot number is required for lot-tracked products")
+	ErrProductNotLotTracked     = errors.New("product is not lot tracked")
+	ErrLotNotFound              = errors.New("lot not found at this location")
+	ErrInsufficientLotStock     = errors.New("insufficient stock in lot")
+	ErrLotExpiryMismatch        = errors.New("expiry date does not match the existing lot")
+	ErrSerialCountMismatch      = errors.New("number of serial numbers must equal the quantity for serialized products")
+	ErrProductNotSerialized     = errors.New("product is not serialized")
+	ErrDuplicateSerial          = errors.New("duplicate serial number in request")
+	ErrSerialAlreadyInStock     = errors.New("serial number is already in stock")
+	ErrSerialNotAvailable       = errors.New("serial number is not in stock at this location")
+	ErrInvalidReasonCode        = errors.New("reason code is not allowed for this movement type")
+	ErrReferenceRequired        = errors.New("a reference is required for every stock movement")
+	ErrInvalidMovementDirection = errors.New("direction (in/out) is required for adjustments and not allowed for other movement types")
+	ErrStockQuarantined         = errors.New("stock is quarantined and cannot be sold or reserved")
+	ErrQuarantineMixesStock     = errors.New("quarantined and sellable stock of a product cannot be mixed at one location")
+	ErrQuarantineLocation       = errors.New("quarantined stock can only be stored at a quarantine location")
+)
+
+// movementDirections adalah arah delta setiap tipe movement. Nilai 0 berarti dua arah
+// (adjustment, count_correction) sehingga arah diambil dari input.
+var movementDirections = map[string]int{
+	"receipt":          1,
+	"return":           1,
+	"transfer_in":      1,
+	"shipment":         -1,
+	"transfer_out":     -1,
+	"damage":           -1,
+	"assembly_in":      1,
+	"assembly_out":     -1,
+	"adjustment":       0,
+	"count_correction": 0,
+}
+
+// MovementReasonCodes adalah daftar reason code yang diizinkan per tipe movement
+var MovementReasonCodes = map[string][]string{
+	"receipt":          {"purchase_receipt", "production", "other"},
+	"shipment":         {"sales_order", "sample", "other"},
+	"transfer_in":      {"transfer", "transfer_cancelled"},
+	"transfer_out":     {"transfer"},
+	"adjustment":       {"initial_stock", "manual_adjustment", "data_correction", "found", "other"},
+	"damage":           {"damaged", "expired", "lost"},
+	"return":           {"customer_return", "other"},
+	"count_correction": {"cycle_count"},
+	"assembly_in":      {"kit_assembly", "kit_disassembly"},
+	"assembly_out":     {"kit_assembly", "kit_disassembly"},
+}
+
+// stockMovementInput berisi data satu entri ledger pada satu produk dan lokasi
+type stockMovementInput struct {
+	ProductID           uuid.UUID
+	WarehouseLocationID uuid.UUID
+	MovementType        string
+	Direction           int // hanya untuk tipe dua arah (adjustment, count_correction): +1 atau -1
+	Quantity            int
+	LotNumber           string
+	ExpiryDate          *time.Time
+	SerialNumbers       []string
+	Reason              string
+	ReferenceType       string
+	ReferenceID         *uuid.UUID
+	ReferenceNote       string
+	TransferID          *uuid.UUID
+	UserID              uuid.UUID
+	Quarantine          bool     // inbound menjadi stok karantina (non-sellable); hanya ke lokasi karantina
+	UnitCost            *float64 // biaya per satuan dasar untuk inbound; nil berarti memakai biaya rata-rata saat ini
+}
+
+// lotPortion adalah bagian quantity yang diambil dari / dimasukkan ke satu lot
+type lotPortion struct {
+	lot      *models.StockLot
+	quantity int
+}
+
+// movementDirection mengembalikan tanda delta (+1 / -1) untuk tipe movement.
+// Tipe dua arah memakai direction dari input.
+func movementDirection(movementType string, direction int) (int, error) {
+	sign, ok := movementDirections[movementType]
+	if !ok {
+		return 0, ErrInvalidMovementType
+	}
+	if sign != 0 {
+		if direction != 0 {
+			return 0, ErrInvalidMovementDirection
+		}
+		return sign, nil
+	}
+	if direction == 1 || direction == -1 {
+		return direction, nil
+	}
+	return 0, ErrInvalidMovementDirection
+}
+
+// applyStockMovement menambahkan entri ke ledger (stock_movements) lalu memperbarui
+// proyeksi ProductStock (dan StockLot) untuk produk dan lokasi tersebut.
+// Untuk produk lot-tracked, outbound tanpa nomor lot dialokasikan secara FEFO
+// sehingga satu input bisa menghasilkan beberapa entri ledger (satu per lot).
+// repo harus sudah terikat ke transaksi (lihat ProductRepository.WithTransaction).
+// costingMethod adalah metode valuasi default untuk produk tanpa costing_method.
+func applyStockMovement(repo repositorys.ProductRepository, costingMethod string, in stockMovementInput) ([]*models.StockMovement, error) {
+	direction, err := movementDirection(in.MovementType, in.Direction)
+	if err != nil {
+		return nil, err
+	}
+	if in.Quantity <= 0 {
+		return nil, ErrInvalidQuantity
+	}
+	if !slices.Contains(MovementReasonCodes[in.MovementType], in.Reason) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidReasonCode, in.Reason)
+	}
+	if strings.TrimSpace(in.ReferenceNote) == "" {
+		return nil, ErrReferenceRequired
+	}
+
+	product, err := repo.GetProductByID(in.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	if isVariantParent(product) {
+		return nil, fmt.Errorf("%w: %s", ErrVariantParentStock, product.SKU)
+	}
+	if !product.LotTracked && (in.LotNumber != "" || in.ExpiryDate != nil) {
+		return nil, ErrProductNotLotTracked
+	}
+	if product.LotTracked && direction > 0 && in.LotNumber == "" {
+		return nil, ErrLotNumberRequired
+	}
+	if !product.Serialized && len(in.SerialNumbers) > 0 {
+		return nil, ErrProductNotSerialized
+	}
+	if in.UnitCost != nil && (direction < 0 || *in.UnitCost < 0) {
+		return nil, ErrUnitCostNotAllowed
+	}
+	if product.Serialized {
+		if err := checkSerialList(in.SerialNumbers, in.Quantity); err != nil {
+			return nil, err
+		}
+	}
+
+	location, err := repo.GetWarehouseLocationByID(in.WarehouseLocationID)
+	if err != nil {
+		return nil, fmt.Errorf("warehouse location not found: %w", err)
+	}
+
+	stock, err := repo.GetProductStockForUpdate(in.ProductID, in.WarehouseLocationID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	now := time.Now()
+	if stock == nil {
+		if direction < 0 {
+			return nil, ErrInsufficientStock
+		}
+		// Belum ada stok di lokasi ini, buat baris proyeksi baru. Bila transaksi lain membuatnya
+		// lebih dulu, insert dilewati dan baris milik transaksi itu yang dikunci.
+		if _, err := repo.CreateProductStockIfMissing(&models.ProductStock{
+			ID:                  uuid.New(),
+			SourceProductID:     in.ProductID,
+			WarehouseLocationID: in.WarehouseLocationID,
+			Quantity:            0,
+			Status:              determineStockStatus(0, nil),
+			UpdatedBy:           in.UserID,
+			UpdatedAt:           now,
+		}); err != nil {
+			return nil, err
+		}
+		if stock, err = repo.GetProductStockForUpdate(in.ProductID, in.WarehouseLocationID); err != nil {
+			return nil, err
+		}
+	}
+
+	newQuantity := stock.Quantity + direction*in.Quantity
+	if newQuantity < 0 {
+		return nil, ErrInsufficientStock
+	}
+	// Stok yang sudah di-reserve tidak boleh ikut keluar. Koreksi hitung mengikuti stok fisik sehingga
+	// boleh turun di bawah reserved; kekurangannya dicatat pada sesi hitung.
+	if direction < 0 && newQuantity < stock.ReservedQuantity && in.MovementType != "count_correction" {
+		return nil, ErrStockReserved
+	}
+	// Seluruh stok di lokasi karantina berstatus karantina dan tidak boleh dijual atau dirakit
+	quarantined := location.Quarantine || stock.Status == "quarantined"
+	if in.Quarantine && !quarantined {
+		return nil, ErrQuarantineLocation
+	}
+	if quarantined && (in.MovementType == "shipment" || in.MovementType == "assembly_out") {
+		return nil, ErrStockQuarantined
+	}
+	// Baris karantina lama di lokasi biasa (sebelum ada lokasi karantina) tidak boleh menerima stok sellable.
+	// count_correction hanya mengoreksi baris yang dihitung sehingga statusnya dipertahankan.
+	if direction > 0 && in.MovementType != "count_correction" && quarantined && !location.Quarantine && !in.Quarantine {
+		return nil, ErrQuarantineMixesStock
+	}
+
+	portions := []lotPortion{{quantity: in.Quantity}}
+	if product.LotTracked {
+		if direction > 0 {
+			portions, err = receiveIntoLot(repo, stock, in, now)
+		} else {
+			portions, err = allocateFromLots(repo, stock, in, now)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Valuasi: inbound menambah nilai stok sebesar biayanya, outbound mengeluarkan harga pokok
+	// sesuai metode costing produk (lihat valuation.go)
+	method := costingMethodFor(product, costingMethod)
+	var unitCost float64
+	if direction > 0 {
+		if unitCost, err = inboundUnitCost(repo, stock, in); err != nil {
+			return nil, err
+		}
+	}
+
+	balance := stock.Quantity
+	movements := make([]*models.StockMovement, 0, len(portions))
+	for _, portion := range portions {
+		onHand := balance
+		delta := direction * portion.quantity
+		balance += delta
+
+		movement := &models.StockMovement{
+			ID:                  uuid.New(),
+			SourceProductID:     in.ProductID,
+			WarehouseLocationID: in.WarehouseLocationID,
+			MovementType:        in.MovementType,
+			Quantity:            portion.quantity,
+			Delta:               delta,
+			BalanceAfter:        balance,
+			Reason:              in.Reason,
+			ReferenceType:       in.ReferenceType,
+			ReferenceID:         in.ReferenceID,
+			ReferenceNote:       in.ReferenceNote,
+			TransferID:          in.TransferID,
+			CreatedBy:           in.UserID,
+			CreatedAt:           now,
+		}
+		if portion.lot != nil {
+			portion.lot.Quantity += delta
+			portion.lot.UpdatedAt = now
+			if err := repo.UpdateStockLot(portion.lot); err != nil {
+				return nil, err
+			}
+			movement.LotID = &portion.lot.ID
+			movement.Lot = portion.lot
+		}
+		if direction > 0 {
+			movement.UnitCost = unitCost
+			movement.TotalCost = roundCost(unitCost * float64(portion.quantity))
+		} else {
+			total, err := issueCost(repo, stock, method, onHand, portion.quantity)
+			if err != nil {
+				return nil, err
+			}
+			movement.UnitCost = roundCost(total / float64(portion.quantity))
+			movement.TotalCost = total
+		}
+		if err := repo.CreateStockMovement(movement); err != nil {
+			return nil, err
+		}
+		if direction > 0 {
+			if err := receiveCost(repo, stock, movement, method); err != nil {
+				return nil, err
+			}
+		}
+		movements = append(movements, movement)
+	}
+
+	// Produk serialized tidak lot-tracked, sehingga selalu hanya ada satu entri ledger
+	if product.Serialized {
+		if err := moveSerials(repo, in, movements[0], direction, now); err != nil {
+			return nil, err
+		}
+	}
+
+	stock.Quantity = newQuantity
+	if quarantined && newQuantity > 0 {
+		// Status karantina bertahan sampai stoknya habis (dipindah, dimusnahkan, atau dikoreksi)
+		stock.Status = "quarantined"
+	} else if stock.Status, err = stockStatusFor(repo, in.ProductID, in.WarehouseLocationID, product.CategoryID, newQuantity); err != nil {
+		return nil, err
+	}
+	stock.UpdatedAt = now
+	stock.UpdatedBy = in.UserID
+	if err := repo.UpdateProductStock(stock); err != nil {
+		return nil, err
+	}
+	return movements, nil
+}
+
+// receiveIntoLot mencari (atau membuat) lot tujuan untuk movement inbound
+func receiveIntoLot(repo repositorys.ProductRepository, stock *models.ProductStock, in stockMovementInput, now time.Time) ([]lotPortion, error) {
+	lot, err := repo.GetStockLotForUpdate(stock.ID, in.LotNumber)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if lot == nil {
+		lot = &models.StockLot{
+			ID:                  uuid.New(),
+			ProductStockID:      stock.ID,
+			SourceProductID:     stock.SourceProductID,
+			WarehouseLocationID: stock.WarehouseLocationID,
+			LotNumber:           in.LotNumber,
+			ExpiryDate:          in.ExpiryDate,
+			CreatedAt:           now,
+			UpdatedAt:           now,
+		}
+		if err := repo.CreateStockLot(lot); err != nil {
+			return nil, err
+		}
+	} else if in.ExpiryDate != nil {
+		if lot.ExpiryDate == nil {
+			lot.ExpiryDate = in.ExpiryDate
+		} else if !sameDate(*lot.ExpiryDate, *in.ExpiryDate) {
+			return nil, ErrLotExpiryMismatch
+		}
+	}
+	return []lotPortion{{lot: lot, quantity: in.Quantity}}, nil
+}
+
+// allocateFromLots memilih lot untuk movement outbound: lot yang diminta, atau FEFO
+// (expiry paling awal lebih dulu, lot tanpa expiry paling akhir). FEFO melewati lot yang sudah
+// kedaluwarsa; lot seperti itu hanya bisa dikeluarkan dengan menyebut nomor lotnya.
+func allocateFromLots(repo repositorys.ProductRepository, stock *models.ProductStock, in stockMovementInput, now time.Time) ([]lotPortion, error) {
+	if in.LotNumber != "" {
+		lot, err := repo.GetStockLotForUpdate(stock.ID, in.LotNumber)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrLotNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+		if lot.Quantity < in.Quantity {
+			return nil, ErrInsufficientLotStock
+		}
+		return []lotPortion{{lot: lot, quantity: in.Quantity}}, nil
+	}
+
+	lots, err := repo.GetAvailableStockLotsForUpdate(stock.ID)
+	if err != nil {
+		return nil, err
+	}
+	sortLotsFEFO(lots)
+
+	remaining := in.Quantity
+	var portions []lotPortion
+	for i := range lots {
+		if remaining == 0 {
+			break
+		}
+		if lotExpired(&lots[i], now) {
+			continue
+		}
+		take := min(lots[i].Quantity, remaining)
+		portions = append(portions, lotPortion{lot: &lots[i], quantity: take})
+		remaining -= take
+	}
+	if remaining > 0 {
+		return nil, ErrInsufficientLotStock
+	}
+	return portions, nil
+}
+
+// sortLotsFEFO mengurutkan lot berdasarkan expiry paling awal, lot tanpa expiry paling akhir,
+// lalu lot yang lebih dulu dibuat
+func sortLotsFEFO(lots []models.StockLot) {
+	sort.SliceStable(lots, func(i, j int) bool {
+		a, b := lots[i].ExpiryDate, lots[j].ExpiryDate
+		switch {
+		case a != nil && b != nil && !a.Equal(*b):
+			return a.Before(*b)
+		case (a == nil) != (b == nil):
+			return a != nil
+		}
+		return lots[i].CreatedAt.Before(lots[j].CreatedAt)
+	})
+}
+
+// lotExpired bernilai true bila tanggal expiry lot sudah lewat; lot masih boleh dipakai pada tanggal expiry-nya
+func lotExpired(lot *models.StockLot, now time.Time) bool {
+	if lot.ExpiryDate == nil {
+		return false
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, lot.ExpiryDate.Location())
+	return lot.ExpiryDate.Before(today)
+}
+
+// checkSerialList memastikan jumlah serial sama dengan quantity dan tidak ada duplikat
+func checkSerialList(serials []string, quantity int) error {
+	if len(serials) != quantity {
+		return ErrSerialCountMismatch
+	}
+	seen := make(map[string]bool, len(serials))
+	for _, sn := range serials {
+		if seen[sn] {
+			return fmt.Errorf("%w: %s", ErrDuplicateSerial, sn)
+		}
+		seen[sn] = true
+	}
+	return nil
+}
+
+// moveSerials memperbarui registry serial sesuai arah movement dan menautkannya ke entri ledger
+func moveSerials(repo repositorys.ProductRepository, in stockMovementInput, movement *models.StockMovement, direction int, now time.Time) error {
+	for _, sn := range in.SerialNumbers {
+		serial, err := repo.GetSerialNumberForUpdate(in.ProductID, sn)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		if direction > 0 {
+			if serial != nil && serial.Status == "in_stock" {
+				return fmt.Errorf("%w: %s", ErrSerialAlreadyInStock, sn)
+			}
+			locationID := in.WarehouseLocationID
+			if serial == nil {
+				serial = &models.SerialNumber{
+					ID:                  uuid.New(),
+					SourceProductID:     in.ProductID,
+					SerialNumber:        sn,
+					WarehouseLocationID: &locationID,
+					Status:              "in_stock",
+					CreatedAt:           now,
+					UpdatedAt:           now,
+				}
+				if err := repo.CreateSerialNumber(serial); err != nil {
+					return err
+				}
+			} else {
+				serial.WarehouseLocationID = &locationID
+				serial.Status = "in_stock"
+				serial.UpdatedAt = now
+				if err := repo.UpdateSerialNumber(serial); err != nil {
+					return err
+				}
+			}
+		} else {
+			if serial == nil || serial.Status != "in_stock" || serial.WarehouseLocationID == nil || *serial.WarehouseLocationID != in.WarehouseLocationID {
+				return fmt.Errorf("%w: %s", ErrSerialNotAvailable, sn)
+			}
+			serial.WarehouseLocationID = nil
+			serial.Status = "issued"
+			serial.UpdatedAt = now
+			if err := repo.UpdateSerialNumber(serial); err != nil {
+				return err
+			}
+		}
+
+		if err := repo.CreateStockMovementSerial(&models.StockMovementSerial{
+			StockMovementID: movement.ID,
+			SerialNumberID:  serial.ID,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sameDate(a, b time.Time) bool {
+	return a.Format("2006-01-02") == b.Format("2006-01-02")
+}
@@ -0,0 +1,233 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"auth-service/internal/dtos"
+	"auth-service/internal/models"
+	"auth-service/internal/repositorys"
+
+	"github.com/google/uuid"
+)
+
+var ErrApprovalNotPending = errors.New("stock movement approval is no longer pending")
+
+// MovementApprovalPolicy memetakan tipe movement ke quantity maksimum yang boleh dibukukan
+// tanpa sign-off super_admin. Tipe yang tidak terdaftar tidak membutuhkan approval.
+type MovementApprovalPolicy map[string]int
+
+// requiresSignOff menentukan apakah movement harus menunggu approval super_admin
+func (p MovementApprovalPolicy) requiresSignOff(movementType string, quantity int, role string) bool {
+	limit, ok := p[movementType]
+	return ok && quantity > limit && role != "super_admin"
+}
+
+// GetMovementReasonCodes mengembalikan tipe movement, arahnya, reason code yang diizinkan, dan batas approval
+func (u *productUseCase) GetMovementReasonCodes(ctx context.Context) []dtos.MovementReasonCodesResponse {
+	types := make([]string, 0, len(MovementReasonCodes))
+	for movementType := range MovementReasonCodes {
+		types = append(types, movementType)
+	}
+	sort.Strings(types)
+
+	list := make([]dtos.MovementReasonCodesResponse, 0, len(types))
+	for _, movementType := range types {
+		item := dtos.MovementReasonCodesResponse{
+			MovementType: movementType,
+			Direction:    directionName(movementDirections[movementType]),
+			ReasonCodes:  MovementReasonCodes[movementType],
+		}
+		if limit, ok := u.approvalPolicy[movementType]; ok {
+			item.ApprovalAbove = &limit
+		}
+		list = append(list, item)
+	}
+	return list
+}
+
+// requestStockMovementApproval menyimpan movement manual sebagai approval pending
+func (u *productUseCase) requestStockMovementApproval(in stockMovementInput) (*dtos.StockMovementApprovalResponse, error) {
+	if _, err := u.repo.GetProductByID(in.ProductID); err != nil {
+		return nil, fmt.Errorf("product not found: %w", err)
+	}
+	if _, err := u.repo.GetWarehouseLocationByID(in.WarehouseLocationID); err != nil {
+		return nil, fmt.Errorf("warehouse location not found: %w", err)
+	}
+
+	now := time.Now()
+	approval := &models.StockMovementApproval{
+		ID:                  uuid.New(),
+		SourceProductID:     in.ProductID,
+		WarehouseLocationID: in.WarehouseLocationID,
+		MovementType:        in.MovementType,
+		Direction:           in.Direction,
+		Quantity:            in.Quantity,
+		LotNumber:           in.LotNumber,
+		ExpiryDate:          in.ExpiryDate,
+		SerialNumbers:       in.SerialNumbers,
+		UnitCost:            in.UnitCost,
+		Reason:              in.Reason,
+		ReferenceNote:       in.ReferenceNote,
+		Status:              "pending",
+		RequestedBy:         in.UserID,
+		CreatedAt:           now,
+		UpdatedAt:           now,
+	}
+	if err := u.repo.CreateStockMovementApproval(approval); err != nil {
+		return nil, err
+	}
+	return u.getStockMovementApproval(approval.ID, nil)
+}
+
+func (u *productUseCase) GetStockMovementApprovalsList(ctx context.Context, req dtos.StockMovementApprovalListRequest) ([]dtos.StockMovementApprovalResponse, dtos.Pagination, error) {
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.Limit == 0 {
+		req.Limit = 10
+	}
+	if err := u.validate.Struct(req); err != nil {
+		return nil, dtos.Pagination{}, err
+	}
+
+	approvals, total, err := u.repo.GetStockMovementApprovalsList(req)
+	if err != nil {
+		return nil, dtos.Pagination{}, err
+	}
+
+	list := make([]dtos.StockMovementApprovalResponse, 0, len(approvals))
+	for i := range approvals {
+		list = append(list, *toStockMovementApprovalResponse(&approvals[i]))
+	}
+	return list, buildPagination(req.Page, req.Limit, total), nil
+}
+
+// ApproveStockMovement membukukan movement yang menunggu sign-off. Entri ledger merujuk ke approval ini.
+func (u *productUseCase) ApproveStockMovement(ctx context.Context, id uuid.UUID, req dtos.DecideStockMovementApprovalRequest, userID uuid.UUID) (*dtos.StockMovementApprovalResponse, error) {
+	if err := u.validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	var movements []*models.StockMovement
+	var serials []string
+	err := u.repo.WithTransaction(func(repo repositorys.ProductRepository) error {
+		approval, err := repo.GetStockMovementApprovalForUpdate(id)
+		if err != nil {
+			return err
+		}
+		if approval.Status != "pending" {
+			return ErrApprovalNotPending
+		}
+
+		serials = approval.SerialNumbers
+		movements, err = applyStockMovement(repo, u.costingMethod, stockMovementInput{
+			ProductID:           approval.SourceProductID,
+			WarehouseLocationID: approval.WarehouseLocationID,
+			MovementType:        approval.MovementType,
+			Direction:           approval.Direction,
+			Quantity:            approval.Quantity,
+			LotNumber:           approval.LotNumber,
+			ExpiryDate:          approval.ExpiryDate,
+			SerialNumbers:       approval.SerialNumbers,
+			UnitCost:            approval.UnitCost,
+			Reason:              approval.Reason,
+			ReferenceType:       "stock_movement_approval",
+			ReferenceID:         &approval.ID,
+			ReferenceNote:       approval.ReferenceNote,
+			UserID:              approval.RequestedBy,
+		})
+		if err != nil {
+			return err
+		}
+		return decideStockMovementApproval(repo, approval, "approved", req.Note, userID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	u.log.Info(fmt.Sprintf("Stock movement approval %s approved", id))
+	return u.getStockMovementApproval(id, toStockMovementResponses(movements, serials))
+}
+
+func (u *productUseCase) RejectStockMovement(ctx context.Context, id uuid.UUID, req dtos.DecideStockMovementApprovalRequest, userID uuid.UUID) (*dtos.StockMovementApprovalResponse, error) {
+	if err := u.validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	err := u.repo.WithTransaction(func(repo repositorys.ProductRepository) error {
+		approval, err := repo.GetStockMovementApprovalForUpdate(id)
+		if err != nil {
+			return err
+		}
+		if approval.Status != "pending" {
+			return ErrApprovalNotPending
+		}
+		return decideStockMovementApproval(repo, approval, "rejected", req.Note, userID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return u.getStockMovementApproval(id, nil)
+}
+
+func decideStockMovementApproval(repo repositorys.ProductRepository, approval *models.StockMovementApproval, status, note string, userID uuid.UUID) error {
+	now := time.Now()
+	approval.Status = status
+	approval.DecidedBy = &userID
+	approval.DecidedAt = &now
+	approval.DecisionNote = note
+	approval.UpdatedAt = now
+	return repo.UpdateStockMovementApproval(approval)
+}
+
+func (u *productUseCase) getStockMovementApproval(id uuid.UUID, movements []dtos.StockMovementResponse) (*dtos.StockMovementApprovalResponse, error) {
+	approval, err := u.repo.GetStockMovementApprovalByID(id)
+	if err != nil {
+		return nil, err
+	}
+	response := toStockMovementApprovalResponse(approval)
+	response.Movements = movements
+	return response, nil
+}
+
+func directionName(direction int) string {
+	switch direction {
+	case 1:
+		return "in"
+	case -1:
+		return "out"
+	}
+	return "both"
+}
+
+func toStockMovementApprovalResponse(a *models.StockMovementApproval) *dtos.StockMovementApprovalResponse {
+	response := &dtos.StockMovementApprovalResponse{
+		ID:                    a.ID,
+		ProductID:             a.SourceProductID,
+		ProductName:           a.Product.Name,
+		WarehouseLocationID:   a.WarehouseLocationID,
+		WarehouseLocationName: a.WarehouseLocation.Name,
+		MovementType:          a.MovementType,
+		Quantity:              a.Quantity,
+		LotNumber:             a.LotNumber,
+		ExpiryDate:            a.ExpiryDate,
+		SerialNumbers:         a.SerialNumbers,
+		UnitCost:              a.UnitCost,
+		Reason:                a.Reason,
+		ReferenceNote:         a.ReferenceNote,
+		Status:                a.Status,
+		RequestedBy:           a.RequestedBy,
+		DecidedBy:             a.DecidedBy,
+		DecidedAt:             a.DecidedAt,
+		DecisionNote:          a.DecisionNote,
+		CreatedAt:             a.CreatedAt,
+	}
+	if a.Direction != 0 {
+		response.Direction = directionName(a.Direction)
+	}
+	return response
+}
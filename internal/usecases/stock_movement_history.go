@@ -0,0 +1,190 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"auth-service/internal/dtos"
+	"auth-service/internal/models"
+	"auth-service/internal/repositorys"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidDateFilter = errors.New("invalid 'from'/'to' value, use RFC3339 or YYYY-MM-DD")
+	ErrInvalidDateRange  = errors.New("'from' must not be after 'to'")
+)
+
+// parseLedgerTime menerima RFC3339 atau YYYY-MM-DD. Tanggal saja berarti awal hari,
+// atau akhir hari bila endOfDay.
+func parseLedgerTime(value string, endOfDay bool) (time.Time, bool) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, true
+	}
+	day, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return time.Time{}, false
+	}
+	if endOfDay {
+		return day.Add(24*time.Hour - time.Nanosecond), true
+	}
+	return day, true
+}
+
+// parseLedgerRange mengubah query from/to menjadi batas waktu; nilai kosong berarti tanpa batas
+func parseLedgerRange(fromValue, toValue string) (*time.Time, *time.Time, error) {
+	var from, to *time.Time
+	if fromValue != "" {
+		t, ok := parseLedgerTime(fromValue, false)
+		if !ok {
+			return nil, nil, ErrInvalidDateFilter
+		}
+		from = &t
+	}
+	if toValue != "" {
+		t, ok := parseLedgerTime(toValue, true)
+		if !ok {
+			return nil, nil, ErrInvalidDateFilter
+		}
+		to = &t
+	}
+	if from != nil && to != nil && from.After(*to) {
+		return nil, nil, ErrInvalidDateRange
+	}
+	return from, to, nil
+}
+
+// GetStockMovementHistory mengembalikan riwayat ledger terfilter beserta saldo berjalan per produk
+func (u *productUseCase) GetStockMovementHistory(ctx context.Context, req dtos.StockMovementHistoryRequest) ([]dtos.StockMovementHistoryResponse, dtos.Pagination, error) {
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.Limit == 0 {
+		req.Limit = 10
+	}
+	if err := u.validate.Struct(req); err != nil {
+		return nil, dtos.Pagination{}, err
+	}
+	from, to, err := parseLedgerRange(req.From, req.To)
+	if err != nil {
+		return nil, dtos.Pagination{}, err
+	}
+
+	rows, total, err := u.repo.GetStockMovementHistory(req, from, to)
+	if err != nil {
+		return nil, dtos.Pagination{}, err
+	}
+	list := toStockMovementHistoryResponses(rows)
+	if err := applyMovementUnits(u.repo, list, req.Unit); err != nil {
+		return nil, dtos.Pagination{}, err
+	}
+	return list, buildPagination(req.Page, req.Limit, total), nil
+}
+
+// GetStockCard menyusun kartu stok produk: saldo awal, entri kronologis dengan saldo berjalan, dan saldo akhir.
+// Bila warehouse_location_id diisi, kartu hanya mencakup lokasi tersebut beserta turunannya.
+func (u *productUseCase) GetStockCard(ctx context.Context, productID uuid.UUID, req dtos.StockCardRequest) (*dtos.StockCardResponse, dtos.Pagination, error) {
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.Limit == 0 {
+		req.Limit = 10
+	}
+	if err := u.validate.Struct(req); err != nil {
+		return nil, dtos.Pagination{}, err
+	}
+	from, to, err := parseLedgerRange(req.From, req.To)
+	if err != nil {
+		return nil, dtos.Pagination{}, err
+	}
+
+	product, err := u.repo.GetProductByID(productID)
+	if err != nil {
+		return nil, dtos.Pagination{}, err
+	}
+	factor := 1
+	if req.Unit != "" {
+		if factor, err = unitFactor(u.repo, product.ID, req.Unit); err != nil {
+			return nil, dtos.Pagination{}, err
+		}
+	}
+	card := &dtos.StockCardResponse{
+		ProductID:   product.ID,
+		ProductName: product.Name,
+		SKU:         product.SKU,
+		From:        from,
+		To:          to,
+	}
+
+	var root *models.WarehouseLocation
+	if req.WarehouseLocationID != uuid.Nil {
+		root, err = u.repo.GetWarehouseLocationByID(req.WarehouseLocationID)
+		if err != nil {
+			return nil, dtos.Pagination{}, err
+		}
+		card.WarehouseLocationID = &root.ID
+		card.WarehouseLocationName = root.Name
+	}
+
+	totals, err := u.repo.GetStockCardTotals(productID, root, from, to)
+	if err != nil {
+		return nil, dtos.Pagination{}, err
+	}
+	rows, total, err := u.repo.GetStockCardEntries(productID, root, from, to, req.Page, req.Limit)
+	if err != nil {
+		return nil, dtos.Pagination{}, err
+	}
+
+	card.OpeningBalance = totals.OpeningBalance
+	card.TotalIn = totals.TotalIn
+	card.TotalOut = totals.TotalOut
+	card.ClosingBalance = totals.OpeningBalance + totals.TotalIn - totals.TotalOut
+	card.Entries = toStockMovementHistoryResponses(rows)
+	if req.Unit != "" {
+		card.InUnit = &dtos.StockCardInUnit{
+			Unit:           req.Unit,
+			Factor:         factor,
+			OpeningBalance: inUnit(card.OpeningBalance, factor),
+			TotalIn:        inUnit(card.TotalIn, factor),
+			TotalOut:       inUnit(card.TotalOut, factor),
+			ClosingBalance: inUnit(card.ClosingBalance, factor),
+		}
+		if err := applyMovementUnits(u.repo, card.Entries, req.Unit); err != nil {
+			return nil, dtos.Pagination{}, err
+		}
+	}
+	return card, buildPagination(req.Page, req.Limit, total), nil
+}
+
+func toStockMovementHistoryResponses(rows []repositorys.StockMovementHistoryRow) []dtos.StockMovementHistoryResponse {
+	list := make([]dtos.StockMovementHistoryResponse, 0, len(rows))
+	for _, r := range rows {
+		list = append(list, dtos.StockMovementHistoryResponse{
+			ID:                    r.ID,
+			ProductID:             r.ProductID,
+			ProductName:           r.ProductName,
+			SKU:                   r.SKU,
+			WarehouseLocationID:   r.WarehouseLocationID,
+			WarehouseLocationName: r.WarehouseName,
+			WarehouseLocationPath: r.WarehouseLocationPath,
+			MovementType:          r.MovementType,
+			Quantity:              r.Quantity,
+			Delta:                 r.Delta,
+			BalanceAfter:          r.BalanceAfter,
+			RunningBalance:        r.RunningBalance,
+			UnitCost:              r.UnitCost,
+			TotalCost:             r.TotalCost,
+			LotID:                 r.LotID,
+			LotNumber:             r.LotNumber,
+			Reason:                r.Reason,
+			ReferenceType:         r.ReferenceType,
+			ReferenceID:           r.ReferenceID,
+			ReferenceNote:         r.ReferenceNote,
+			CreatedBy:             r.CreatedBy,
+			CreatedAt:             r.CreatedAt,
+		})
+	}
+	return list
+}
@@ -0,0 +1,294 @@
+package usecases
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"auth-service/internal/models"
+	"auth-service/internal/repositorys"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// fakeLotRepository menyimpan lot di memori; GetAvailableStockLotsForUpdate sengaja tidak mengurutkan
+// agar urutan FEFO diuji dari allocateFromLots sendiri
+type fakeLotRepository struct {
+	repositorys.ProductRepository
+	lots    []models.StockLot
+	created []models.StockLot
+}
+
+func (r *fakeLotRepository) GetStockLotForUpdate(stockID uuid.UUID, lotNumber string) (*models.StockLot, error) {
+	for i := range r.lots {
+		if r.lots[i].ProductStockID == stockID && r.lots[i].LotNumber == lotNumber {
+			lot := r.lots[i]
+			return &lot, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeLotRepository) GetAvailableStockLotsForUpdate(stockID uuid.UUID) ([]models.StockLot, error) {
+	var lots []models.StockLot
+	for _, lot := range r.lots {
+		if lot.ProductStockID == stockID && lot.Quantity > 0 {
+			lots = append(lots, lot)
+		}
+	}
+	return lots, nil
+}
+
+func (r *fakeLotRepository) CreateStockLot(lot *models.StockLot) error {
+	r.created = append(r.created, *lot)
+	return nil
+}
+
+var lotTestNow = time.Date(2026, 3, 15, 14, 30, 0, 0, time.UTC)
+
+func lotDate(s string) *time.Time {
+	d, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		panic(err)
+	}
+	return &d
+}
+
+type testLot struct {
+	number  string
+	expiry  string // kosong berarti lot tanpa expiry
+	created int    // urutan pembuatan
+	qty     int
+}
+
+func stockLots(stockID uuid.UUID, lots []testLot) []models.StockLot {
+	list := make([]models.StockLot, len(lots))
+	for i, l := range lots {
+		list[i] = models.StockLot{
+			ID:             uuid.New(),
+			ProductStockID: stockID,
+			LotNumber:      l.number,
+			Quantity:       l.qty,
+			CreatedAt:      lotTestNow.AddDate(0, -1, l.created),
+		}
+		if l.expiry != "" {
+			list[i].ExpiryDate = lotDate(l.expiry)
+		}
+	}
+	return list
+}
+
+// portionSummary menuliskan porsi sebagai "lot:quantity" agar mudah dibandingkan
+func portionSummary(portions []lotPortion) []string {
+	list := make([]string, len(portions))
+	for i, p := range portions {
+		list[i] = fmt.Sprintf("%s:%d", p.lot.LotNumber, p.quantity)
+	}
+	return list
+}
+
+func TestAllocateFromLots(t *testing.T) {
+	tests := []struct {
+		name      string
+		lots      []testLot
+		lotNumber string
+		quantity  int
+		want      []string
+		wantErr   error
+	}{
+		{
+			name: "fefo takes the earliest expiry first",
+			lots: []testLot{
+				{number: "L-LATE", expiry: "2026-09-01", created: 1, qty: 10},
+				{number: "L-EARLY", expiry: "2026-04-01", created: 2, qty: 10},
+				{number: "L-MID", expiry: "2026-06-01", created: 3, qty: 10},
+			},
+			quantity: 5,
+			want:     []string{"L-EARLY:5"},
+		},
+		{
+			name: "lots without expiry come last",
+			lots: []testLot{
+				{number: "L-NONE", created: 1, qty: 10},
+				{number: "L-DATED", expiry: "2027-01-01", created: 2, qty: 3},
+			},
+			quantity: 5,
+			want:     []string{"L-DATED:3", "L-NONE:2"},
+		},
+		{
+			name: "same expiry falls back to creation order",
+			lots: []testLot{
+				{number: "L-B", expiry: "2026-05-01", created: 2, qty: 4},
+				{number: "L-A", expiry: "2026-05-01", created: 1, qty: 4},
+			},
+			quantity: 6,
+			want:     []string{"L-A:4", "L-B:2"},
+		},
+		{
+			name: "partial issue spans several lots",
+			lots: []testLot{
+				{number: "L-1", expiry: "2026-04-01", created: 1, qty: 3},
+				{number: "L-2", expiry: "2026-05-01", created: 2, qty: 4},
+				{number: "L-3", expiry: "2026-06-01", created: 3, qty: 5},
+			},
+			quantity: 9,
+			want:     []string{"L-1:3", "L-2:4", "L-3:2"},
+		},
+		{
+			name: "expired lots are skipped",
+			lots: []testLot{
+				{number: "L-EXPIRED", expiry: "2026-03-14", created: 1, qty: 10},
+				{number: "L-TODAY", expiry: "2026-03-15", created: 2, qty: 2},
+				{number: "L-FRESH", expiry: "2026-08-01", created: 3, qty: 10},
+			},
+			quantity: 4,
+			want:     []string{"L-TODAY:2", "L-FRESH:2"},
+		},
+		{
+			name: "expired stock does not count as available",
+			lots: []testLot{
+				{number: "L-EXPIRED", expiry: "2026-01-01", created: 1, qty: 10},
+				{number: "L-FRESH", expiry: "2026-08-01", created: 2, qty: 3},
+			},
+			quantity: 5,
+			wantErr:  ErrInsufficientLotStock,
+		},
+		{
+			name: "empty lots are ignored",
+			lots: []testLot{
+				{number: "L-EMPTY", expiry: "2026-04-01", created: 1, qty: 0},
+				{number: "L-FULL", expiry: "2026-05-01", created: 2, qty: 5},
+			},
+			quantity: 5,
+			want:     []string{"L-FULL:5"},
+		},
+		{
+			name: "insufficient stock across all lots",
+			lots: []testLot{
+				{number: "L-1", expiry: "2026-04-01", created: 1, qty: 2},
+				{number: "L-2", created: 2, qty: 2},
+			},
+			quantity: 5,
+			wantErr:  ErrInsufficientLotStock,
+		},
+		{
+			name: "requested lot is used even when expired",
+			lots: []testLot{
+				{number: "L-EXPIRED", expiry: "2026-01-01", created: 1, qty: 10},
+				{number: "L-FRESH", expiry: "2026-02-01", created: 2, qty: 10},
+			},
+			lotNumber: "L-EXPIRED",
+			quantity:  4,
+			want:      []string{"L-EXPIRED:4"},
+		},
+		{
+			name:      "requested lot with too little stock",
+			lots:      []testLot{{number: "L-1", expiry: "2026-04-01", created: 1, qty: 2}},
+			lotNumber: "L-1",
+			quantity:  3,
+			wantErr:   ErrInsufficientLotStock,
+		},
+		{
+			name:      "requested lot not at this location",
+			lots:      []testLot{{number: "L-1", expiry: "2026-04-01", created: 1, qty: 2}},
+			lotNumber: "L-OTHER",
+			quantity:  1,
+			wantErr:   ErrLotNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stock := &models.ProductStock{ID: uuid.New()}
+			repo := &fakeLotRepository{lots: stockLots(stock.ID, tt.lots)}
+
+			portions, err := allocateFromLots(repo, stock, stockMovementInput{LotNumber: tt.lotNumber, Quantity: tt.quantity}, lotTestNow)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, portionSummary(portions))
+		})
+	}
+}
+
+func TestReceiveIntoLot(t *testing.T) {
+	tests := []struct {
+		name        string
+		lots        []testLot
+		lotNumber   string
+		expiry      string
+		wantCreated bool
+		wantExpiry  string
+		wantErr     error
+	}{
+		{name: "new lot is created with its expiry", lotNumber: "L-NEW", expiry: "2026-12-01", wantCreated: true, wantExpiry: "2026-12-01"},
+		{name: "new lot without expiry", lotNumber: "L-NEW", wantCreated: true},
+		{
+			name:       "existing lot keeps its expiry",
+			lots:       []testLot{{number: "L-1", expiry: "2026-06-01", qty: 5}},
+			lotNumber:  "L-1",
+			wantExpiry: "2026-06-01",
+		},
+		{
+			name:       "existing lot with the same expiry",
+			lots:       []testLot{{number: "L-1", expiry: "2026-06-01", qty: 5}},
+			lotNumber:  "L-1",
+			expiry:     "2026-06-01",
+			wantExpiry: "2026-06-01",
+		},
+		{
+			name:       "existing lot without expiry takes the received one",
+			lots:       []testLot{{number: "L-1", qty: 5}},
+			lotNumber:  "L-1",
+			expiry:     "2026-06-01",
+			wantExpiry: "2026-06-01",
+		},
+		{
+			name:      "existing lot with a different expiry is rejected",
+			lots:      []testLot{{number: "L-1", expiry: "2026-06-01", qty: 5}},
+			lotNumber: "L-1",
+			expiry:    "2026-07-01",
+			wantErr:   ErrLotExpiryMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stock := &models.ProductStock{ID: uuid.New(), SourceProductID: uuid.New(), WarehouseLocationID: uuid.New()}
+			repo := &fakeLotRepository{lots: stockLots(stock.ID, tt.lots)}
+			in := stockMovementInput{LotNumber: tt.lotNumber, Quantity: 3}
+			if tt.expiry != "" {
+				in.ExpiryDate = lotDate(tt.expiry)
+			}
+
+			portions, err := receiveIntoLot(repo, stock, in, lotTestNow)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, portions, 1)
+			lot := portions[0].lot
+			assert.Equal(t, 3, portions[0].quantity)
+			assert.Equal(t, tt.lotNumber, lot.LotNumber)
+			assert.Equal(t, stock.ID, lot.ProductStockID)
+			if tt.wantExpiry == "" {
+				assert.Nil(t, lot.ExpiryDate)
+			} else {
+				require.NotNil(t, lot.ExpiryDate)
+				assert.Equal(t, tt.wantExpiry, lot.ExpiryDate.Format(time.DateOnly))
+			}
+			if tt.wantCreated {
+				require.Len(t, repo.created, 1)
+				assert.Equal(t, lot.ID, repo.created[0].ID)
+			} else {
+				assert.Empty(t, repo.created)
+			}
+		})
+	}
+}
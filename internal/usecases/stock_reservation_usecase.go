@@ -0,0 +1,228 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"auth-service/internal/dtos"
+	"auth-service/internal/models"
+	"auth-service/internal/repositorys"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrInsufficientAvailableStock = errors.New("not enough available stock to reserve")
+	ErrReservationNotActive       = errors.New("reservation is not active")
+	ErrReservationExpiryInPast    = errors.New("reservation expiry must be in the future")
+)
+
+// expiredReservationBatchSize membatasi jumlah reservasi yang dilepas per siklus sweeper
+const expiredReservationBatchSize = 500
+
+type StockReservationUseCase interface {
+	CreateStockReservation(ctx context.Context, stockID uuid.UUID, req dtos.CreateStockReservationRequest, userID uuid.UUID) (*dtos.StockReservationResponse, error)
+	GetStockReservationsByStockID(ctx context.Context, stockID uuid.UUID, status string) ([]dtos.StockReservationResponse, error)
+	ReleaseStockReservation(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*dtos.StockReservationResponse, error)
+	ReleaseExpiredReservations(ctx context.Context) (int, error)
+}
+
+type stockReservationUseCase struct {
+	repo     repositorys.StockReservationRepository
+	validate *validator.Validate
+	log      *logrus.Logger
+}
+
+func NewStockReservationUseCase(repo repositorys.StockReservationRepository, log *logrus.Logger, validate *validator.Validate) StockReservationUseCase {
+	return &stockReservationUseCase{repo: repo, log: log, validate: validate}
+}
+
+func (u *stockReservationUseCase) CreateStockReservation(ctx context.Context, stockID uuid.UUID, req dtos.CreateStockReservationRequest, userID uuid.UUID) (*dtos.StockReservationResponse, error) {
+	if err := u.validate.Struct(req); err != nil {
+		return nil, err
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, ErrReservationExpiryInPast
+	}
+
+	var reservation *models.StockReservation
+	err := u.repo.WithTransaction(func(repo repositorys.StockReservationRepository, stockRepo repositorys.ProductRepository) error {
+		stock, err := stockRepo.GetProductStockByID(stockID)
+		if err != nil {
+			return err
+		}
+		quantity, err := baseQuantity(stockRepo, stock.SourceProductID, req.Unit, req.Quantity)
+		if err != nil {
+			return err
+		}
+		reservation, err = reserveStock(repo, stockRepo, stockID, quantity, req.OwnerReference, req.ExpiresAt, userID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	u.log.Info(fmt.Sprintf("Reserved %d unit(s) of stock %s for %s", reservation.Quantity, stockID, reservation.OwnerReference))
+	return toStockReservationResponse(reservation), nil
+}
+
+func (u *stockReservationUseCase) GetStockReservationsByStockID(ctx context.Context, stockID uuid.UUID, status string) ([]dtos.StockReservationResponse, error) {
+	reservations, err := u.repo.GetStockReservationsByStockID(stockID, status)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]dtos.StockReservationResponse, 0, len(reservations))
+	for i := range reservations {
+		list = append(list, *toStockReservationResponse(&reservations[i]))
+	}
+	return list, nil
+}
+
+func (u *stockReservationUseCase) ReleaseStockReservation(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*dtos.StockReservationResponse, error) {
+	var reservation *models.StockReservation
+	err := u.repo.WithTransaction(func(repo repositorys.StockReservationRepository, stockRepo repositorys.ProductRepository) error {
+		var err error
+		reservation, err = closeReservation(repo, stockRepo, id, "released")
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toStockReservationResponse(reservation), nil
+}
+
+// ReleaseExpiredReservations melepas reservasi aktif yang sudah kedaluwarsa, dipanggil oleh background sweeper
+func (u *stockReservationUseCase) ReleaseExpiredReservations(ctx context.Context) (int, error) {
+	ids, err := u.repo.GetExpiredReservationIDs(time.Now(), expiredReservationBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	released := 0
+	for _, id := range ids {
+		err := u.repo.WithTransaction(func(repo repositorys.StockReservationRepository, stockRepo repositorys.ProductRepository) error {
+			_, err := closeReservation(repo, stockRepo, id, "expired")
+			return err
+		})
+		if errors.Is(err, ErrReservationNotActive) {
+			// Sudah dilepas oleh proses lain
+			continue
+		}
+		if err != nil {
+			return released, err
+		}
+		released++
+	}
+
+	if released > 0 {
+		u.log.Info(fmt.Sprintf("Released %d expired stock reservation(s)", released))
+	}
+	return released, nil
+}
+
+// reserveStock menahan quantity dari stok tersedia (on hand - reserved).
+// repo dan stockRepo harus terikat ke transaksi yang sama.
+func reserveStock(repo repositorys.StockReservationRepository, stockRepo repositorys.ProductRepository, stockID uuid.UUID, quantity int, ownerReference string, expiresAt *time.Time, userID uuid.UUID) (*models.StockReservation, error) {
+	if quantity <= 0 {
+		return nil, ErrInvalidQuantity
+	}
+
+	stock, err := stockRepo.GetProductStockByIDForUpdate(stockID)
+	if err != nil {
+		return nil, err
+	}
+	if stock.Status == "quarantined" {
+		return nil, ErrStockQuarantined
+	}
+	if stock.Quantity-stock.ReservedQuantity < quantity {
+		return nil, ErrInsufficientAvailableStock
+	}
+
+	now := time.Now()
+	reservation := &models.StockReservation{
+		ID:                  uuid.New(),
+		ProductStockID:      stock.ID,
+		SourceProductID:     stock.SourceProductID,
+		WarehouseLocationID: stock.WarehouseLocationID,
+		Quantity:            quantity,
+		OwnerReference:      ownerReference,
+		Status:              "active",
+		ExpiresAt:           expiresAt,
+		CreatedBy:           userID,
+		CreatedAt:           now,
+		UpdatedAt:           now,
+	}
+	if err := repo.CreateStockReservation(reservation); err != nil {
+		return nil, err
+	}
+
+	stock.ReservedQuantity += quantity
+	stock.UpdatedAt = now
+	stock.UpdatedBy = userID
+	if err := stockRepo.UpdateProductStock(stock); err != nil {
+		return nil, err
+	}
+	return reservation, nil
+}
+
+// closeReservation menutup reservasi aktif dengan status released/expired/consumed
+// dan mengembalikan quantity-nya dari ReservedQuantity stok.
+// repo dan stockRepo harus terikat ke transaksi yang sama.
+func closeReservation(repo repositorys.StockReservationRepository, stockRepo repositorys.ProductRepository, id uuid.UUID, status string) (*models.StockReservation, error) {
+	current, err := repo.GetStockReservationByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Urutan lock: stok dulu, lalu reservasi (sama seperti reserveStock)
+	stock, err := stockRepo.GetProductStockByIDForUpdate(current.ProductStockID)
+	if err != nil {
+		return nil, err
+	}
+	reservation, err := repo.GetStockReservationForUpdate(id)
+	if err != nil {
+		return nil, err
+	}
+	if reservation.Status != "active" {
+		return nil, ErrReservationNotActive
+	}
+
+	now := time.Now()
+	reservation.Status = status
+	reservation.ReleasedAt = &now
+	reservation.UpdatedAt = now
+	if err := repo.UpdateStockReservation(reservation); err != nil {
+		return nil, err
+	}
+
+	stock.ReservedQuantity -= reservation.Quantity
+	if stock.ReservedQuantity < 0 {
+		stock.ReservedQuantity = 0
+	}
+	stock.UpdatedAt = now
+	if err := stockRepo.UpdateProductStock(stock); err != nil {
+		return nil, err
+	}
+	return reservation, nil
+}
+
+func toStockReservationResponse(r *models.StockReservation) *dtos.StockReservationResponse {
+	return &dtos.StockReservationResponse{
+		ID:                  r.ID,
+		ProductStockID:      r.ProductStockID,
+		ProductID:           r.SourceProductID,
+		WarehouseLocationID: r.WarehouseLocationID,
+		Quantity:            r.Quantity,
+		OwnerReference:      r.OwnerReference,
+		Status:              r.Status,
+		ExpiresAt:           r.ExpiresAt,
+		ReleasedAt:          r.ReleasedAt,
+		CreatedBy:           r.CreatedBy,
+		CreatedAt:           r.CreatedAt,
+	}
+}
@@ -0,0 +1,137 @@
+package usecases
+
+import (
+	"testing"
+
+	"auth-service/internal/models"
+	"auth-service/internal/repositorys"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetermineStockStatus(t *testing.T) {
+	levels := func(minQuantity, reorderPoint, maxQuantity int) *models.StockThreshold {
+		return &models.StockThreshold{MinQuantity: minQuantity, ReorderPoint: reorderPoint, MaxQuantity: maxQuantity}
+	}
+	tests := []struct {
+		name      string
+		quantity  int
+		threshold *models.StockThreshold
+		want      string
+	}{
+		{name: "default empty", quantity: 0, want: "out-of-stock"},
+		{name: "default negative", quantity: -3, want: "out-of-stock"},
+		{name: "default at reorder point", quantity: models.DefaultReorderPoint, want: "low-stock"},
+		{name: "default above reorder point", quantity: models.DefaultReorderPoint + 1, want: "available"},
+		{name: "at reorder point", quantity: 5, threshold: levels(2, 5, 20), want: "low-stock"},
+		{name: "above reorder point", quantity: 6, threshold: levels(2, 5, 20), want: "available"},
+		{name: "below min above reorder point", quantity: 7, threshold: levels(8, 5, 20), want: "low-stock"},
+		{name: "at min above reorder point", quantity: 8, threshold: levels(8, 5, 20), want: "available"},
+		{name: "at max", quantity: 20, threshold: levels(2, 5, 20), want: "available"},
+		{name: "above max", quantity: 25, threshold: levels(2, 5, 20), want: "available"},
+		{name: "zero levels", quantity: 1, threshold: levels(0, 0, 0), want: "available"},
+		{name: "empty with zero levels", quantity: 0, threshold: levels(0, 0, 0), want: "out-of-stock"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, determineStockStatus(tt.quantity, tt.threshold))
+		})
+	}
+}
+
+// fakeThresholdRepository mengembalikan threshold efektif dari kandidat yang sudah lolos filter cakupan
+type fakeThresholdRepository struct {
+	repositorys.ProductRepository
+	candidates []models.StockThreshold
+	calls      int
+}
+
+func (r *fakeThresholdRepository) GetEffectiveStockThreshold(productID, locationID, categoryID uuid.UUID) (*models.StockThreshold, error) {
+	r.calls++
+	return models.EffectiveStockThreshold(r.candidates), nil
+}
+
+func TestStockStatusForThresholdFallback(t *testing.T) {
+	productID, locationID, categoryID := uuid.New(), uuid.New(), uuid.New()
+	// Setiap level memakai reorder point berbeda sehingga status menunjukkan level yang terpilih
+	productLocation := models.StockThreshold{SourceProductID: &productID, WarehouseLocationID: &locationID, ReorderPoint: 40}
+	product := models.StockThreshold{SourceProductID: &productID, ReorderPoint: 30}
+	category := models.StockThreshold{CategoryID: &categoryID, ReorderPoint: 20}
+	location := models.StockThreshold{WarehouseLocationID: &locationID, ReorderPoint: 15}
+	global := models.StockThreshold{ReorderPoint: 12}
+
+	tests := []struct {
+		name       string
+		candidates []models.StockThreshold
+		wantScope  string
+		quantity   int
+		want       string
+	}{
+		{
+			name:       "product and location wins over everything",
+			candidates: []models.StockThreshold{global, location, category, product, productLocation},
+			wantScope:  "product_location",
+			quantity:   35,
+			want:       "low-stock",
+		},
+		{
+			name:       "product wins over category",
+			candidates: []models.StockThreshold{category, global, product},
+			wantScope:  "product",
+			quantity:   25,
+			want:       "low-stock",
+		},
+		{
+			name:       "category wins over location and global",
+			candidates: []models.StockThreshold{global, location, category},
+			wantScope:  "category",
+			quantity:   18,
+			want:       "low-stock",
+		},
+		{
+			name:       "location wins over global",
+			candidates: []models.StockThreshold{global, location},
+			wantScope:  "location",
+			quantity:   13,
+			want:       "low-stock",
+		},
+		{
+			name:       "global only",
+			candidates: []models.StockThreshold{global},
+			wantScope:  "global",
+			quantity:   13,
+			want:       "available",
+		},
+		{
+			name:     "defaults without any threshold",
+			quantity: models.DefaultReorderPoint,
+			want:     "low-stock",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			effective := models.EffectiveStockThreshold(tt.candidates)
+			if tt.wantScope == "" {
+				assert.Nil(t, effective)
+			} else {
+				require.NotNil(t, effective)
+				assert.Equal(t, tt.wantScope, effective.Scope())
+			}
+
+			repo := &fakeThresholdRepository{candidates: tt.candidates}
+			status, err := stockStatusFor(repo, productID, locationID, categoryID, tt.quantity)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, status)
+		})
+	}
+
+	t.Run("empty stock skips the threshold lookup", func(t *testing.T) {
+		repo := &fakeThresholdRepository{candidates: []models.StockThreshold{productLocation}}
+		status, err := stockStatusFor(repo, productID, locationID, categoryID, 0)
+		require.NoError(t, err)
+		assert.Equal(t, "out-of-stock", status)
+		assert.Zero(t, repo.calls)
+	})
+}
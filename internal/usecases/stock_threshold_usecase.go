@@ -0,0 +1,185 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"auth-service/internal/dtos"
+	"auth-service/internal/models"
+	"auth-service/internal/repositorys"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var ErrInvalidThresholdLevels = errors.New("max quantity must be zero (unbounded) or at least the reorder point")
+
+type StockThresholdUseCase interface {
+	UpsertStockThreshold(ctx context.Context, req dtos.UpsertStockThresholdRequest, userID uuid.UUID) (*dtos.StockThresholdResponse, error)
+	GetStockThresholdsList(ctx context.Context, req dtos.StockThresholdListRequest) ([]dtos.StockThresholdResponse, dtos.Pagination, error)
+	DeleteStockThreshold(ctx context.Context, id uuid.UUID) error
+	GetEffectiveStockThreshold(ctx context.Context, stockID uuid.UUID) (*dtos.StockThresholdResponse, error)
+}
+
+type stockThresholdUseCase struct {
+	repo        repositorys.StockThresholdRepository
+	productRepo repositorys.ProductRepository
+	validate    *validator.Validate
+	log         *logrus.Logger
+}
+
+func NewStockThresholdUseCase(repo repositorys.StockThresholdRepository, productRepo repositorys.ProductRepository, log *logrus.Logger, validate *validator.Validate) StockThresholdUseCase {
+	return &stockThresholdUseCase{repo: repo, productRepo: productRepo, log: log, validate: validate}
+}
+
+// UpsertStockThreshold membuat atau memperbarui threshold untuk satu cakupan, lalu menghitung ulang status stok
+func (u *stockThresholdUseCase) UpsertStockThreshold(ctx context.Context, req dtos.UpsertStockThresholdRequest, userID uuid.UUID) (*dtos.StockThresholdResponse, error) {
+	if err := u.validate.Struct(req); err != nil {
+		return nil, err
+	}
+	if req.MaxQuantity != 0 && req.MaxQuantity < req.ReorderPoint {
+		return nil, ErrInvalidThresholdLevels
+	}
+
+	if req.ProductID != nil {
+		if _, err := u.productRepo.GetProductByID(*req.ProductID); err != nil {
+			return nil, fmt.Errorf("product not found: %w", err)
+		}
+	}
+	if req.WarehouseLocationID != nil {
+		if _, err := u.productRepo.GetWarehouseLocationByID(*req.WarehouseLocationID); err != nil {
+			return nil, fmt.Errorf("warehouse location not found: %w", err)
+		}
+	}
+	if req.CategoryID != nil {
+		if _, err := u.productRepo.GetProductCategoryByID(*req.CategoryID); err != nil {
+			return nil, fmt.Errorf("product category not found: %w", err)
+		}
+	}
+
+	threshold, err := u.repo.GetStockThresholdByScope(req.ProductID, req.WarehouseLocationID, req.CategoryID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	now := time.Now()
+	isNew := threshold == nil
+	if isNew {
+		threshold = &models.StockThreshold{
+			ID:                  uuid.New(),
+			SourceProductID:     req.ProductID,
+			WarehouseLocationID: req.WarehouseLocationID,
+			CategoryID:          req.CategoryID,
+			CreatedAt:           now,
+		}
+	}
+	threshold.MinQuantity = req.MinQuantity
+	threshold.ReorderPoint = req.ReorderPoint
+	threshold.MaxQuantity = req.MaxQuantity
+	threshold.UpdatedBy = userID
+	threshold.UpdatedAt = now
+	err = u.repo.WithTransaction(func(repo repositorys.StockThresholdRepository) error {
+		if isNew {
+			if err := repo.CreateStockThreshold(threshold); err != nil {
+				return err
+			}
+		} else if err := repo.UpdateStockThreshold(threshold); err != nil {
+			return err
+		}
+		return u.refreshStatuses(repo)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toStockThresholdResponse(threshold), nil
+}
+
+func (u *stockThresholdUseCase) GetStockThresholdsList(ctx context.Context, req dtos.StockThresholdListRequest) ([]dtos.StockThresholdResponse, dtos.Pagination, error) {
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.Limit == 0 {
+		req.Limit = 10
+	}
+	if err := u.validate.Struct(req); err != nil {
+		return nil, dtos.Pagination{}, err
+	}
+
+	thresholds, total, err := u.repo.GetStockThresholdsList(req)
+	if err != nil {
+		return nil, dtos.Pagination{}, err
+	}
+
+	list := make([]dtos.StockThresholdResponse, 0, len(thresholds))
+	for i := range thresholds {
+		list = append(list, *toStockThresholdResponse(&thresholds[i]))
+	}
+	return list, buildPagination(req.Page, req.Limit, total), nil
+}
+
+func (u *stockThresholdUseCase) DeleteStockThreshold(ctx context.Context, id uuid.UUID) error {
+	if _, err := u.repo.GetStockThresholdByID(id); err != nil {
+		return err
+	}
+	return u.repo.WithTransaction(func(repo repositorys.StockThresholdRepository) error {
+		if err := repo.DeleteStockThreshold(id); err != nil {
+			return err
+		}
+		return u.refreshStatuses(repo)
+	})
+}
+
+// GetEffectiveStockThreshold mengembalikan threshold yang berlaku untuk satu ProductStock beserta asal cakupannya
+func (u *stockThresholdUseCase) GetEffectiveStockThreshold(ctx context.Context, stockID uuid.UUID) (*dtos.StockThresholdResponse, error) {
+	stock, err := u.productRepo.GetProductStockByID(stockID)
+	if err != nil {
+		return nil, err
+	}
+	product, err := u.productRepo.GetProductByID(stock.SourceProductID)
+	if err != nil {
+		return nil, err
+	}
+
+	threshold, err := u.repo.GetEffectiveStockThreshold(stock.SourceProductID, stock.WarehouseLocationID, product.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+	if threshold == nil {
+		return &dtos.StockThresholdResponse{
+			Scope:        "default",
+			MinQuantity:  models.DefaultMinQuantity,
+			ReorderPoint: models.DefaultReorderPoint,
+			MaxQuantity:  models.DefaultMaxQuantity,
+		}, nil
+	}
+	return toStockThresholdResponse(threshold), nil
+}
+
+// refreshStatuses menyelaraskan kolom status ProductStock setelah threshold berubah, di transaksi yang sama
+// dengan perubahan threshold sehingga kegagalan refresh ikut membatalkan perubahan tersebut
+func (u *stockThresholdUseCase) refreshStatuses(repo repositorys.StockThresholdRepository) error {
+	updated, err := repo.RefreshStockStatuses()
+	if err != nil {
+		return fmt.Errorf("failed to refresh stock statuses: %w", err)
+	}
+	u.log.Info(fmt.Sprintf("Stock thresholds changed, %d stock status(es) refreshed", updated))
+	return nil
+}
+
+func toStockThresholdResponse(t *models.StockThreshold) *dtos.StockThresholdResponse {
+	return &dtos.StockThresholdResponse{
+		ID:                  &t.ID,
+		Scope:               t.Scope(),
+		ProductID:           t.SourceProductID,
+		WarehouseLocationID: t.WarehouseLocationID,
+		CategoryID:          t.CategoryID,
+		MinQuantity:         t.MinQuantity,
+		ReorderPoint:        t.ReorderPoint,
+		MaxQuantity:         t.MaxQuantity,
+		UpdatedAt:           &t.UpdatedAt,
+	}
+}
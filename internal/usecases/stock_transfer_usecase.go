@@ -0,0 +1,354 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"auth-service/internal/dtos"
+	"auth-service/internal/models"
+	"auth-service/internal/repositorys"
+	"auth-service/internal/utils"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var ErrInvalidTransferState = errors.New("invalid transfer state for this action")
+
+type StockTransferUseCase interface {
+	CreateStockTransfer(ctx context.Context, req dtos.CreateStockTransferRequest, userID uuid.UUID) (*dtos.StockTransferResponse, error)
+	GetStockTransferByID(ctx context.Context, id uuid.UUID) (*dtos.StockTransferResponse, error)
+	GetStockTransfersList(ctx context.Context, req dtos.StockTransferListRequest) ([]dtos.StockTransferResponse, dtos.Pagination, error)
+	DispatchStockTransfer(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*dtos.StockTransferResponse, error)
+	ReceiveStockTransfer(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*dtos.StockTransferResponse, error)
+	CancelStockTransfer(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*dtos.StockTransferResponse, error)
+}
+
+type stockTransferUseCase struct {
+	repo        repositorys.StockTransferRepository
+	productRepo repositorys.ProductRepository
+	// costingMethod adalah metode valuasi default untuk produk tanpa costing_method (valuation.default_method)
+	costingMethod string
+	validate      *validator.Validate
+	log           *logrus.Logger
+}
+
+func NewStockTransferUseCase(repo repositorys.StockTransferRepository, productRepo repositorys.ProductRepository, costingMethod string, log *logrus.Logger, validate *validator.Validate) StockTransferUseCase {
+	return &stockTransferUseCase{repo: repo, productRepo: productRepo, costingMethod: costingMethod, log: log, validate: validate}
+}
+
+func (u *stockTransferUseCase) CreateStockTransfer(ctx context.Context, req dtos.CreateStockTransferRequest, userID uuid.UUID) (*dtos.StockTransferResponse, error) {
+	if err := u.validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	transfer, err := buildStockTransfer(u.productRepo, req, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.repo.CreateStockTransfer(transfer); err != nil {
+		return nil, err
+	}
+	u.log.Info(fmt.Sprintf("Stock transfer %s created", transfer.TransferNumber))
+	return u.GetStockTransferByID(ctx, transfer.ID)
+}
+
+// buildStockTransfer memvalidasi lokasi dan produk lalu menyusun transfer draft (belum disimpan)
+func buildStockTransfer(productRepo repositorys.ProductRepository, req dtos.CreateStockTransferRequest, userID uuid.UUID) (*models.StockTransfer, error) {
+	source, err := productRepo.GetWarehouseLocationByID(req.SourceLocationID)
+	if err != nil {
+		return nil, fmt.Errorf("source location not found: %w", err)
+	}
+	destination, err := productRepo.GetWarehouseLocationByID(req.DestinationLocationID)
+	if err != nil {
+		return nil, fmt.Errorf("destination location not found: %w", err)
+	}
+	// Stok karantina tetap karantina sehingga hanya boleh pindah ke lokasi karantina lain
+	if source.Quarantine && !destination.Quarantine {
+		return nil, fmt.Errorf("%w: %s is a quarantine location", ErrQuarantineLocation, source.Path)
+	}
+
+	transfer := &models.StockTransfer{
+		ID:                    uuid.New(),
+		TransferNumber:        utils.GenerateDocumentNumber("TRF"),
+		SourceLocationID:      req.SourceLocationID,
+		DestinationLocationID: req.DestinationLocationID,
+		Status:                "draft",
+		Note:                  req.Note,
+		CreatedBy:             userID,
+	}
+
+	// Gabungkan item dengan produk yang sama
+	quantities := make(map[uuid.UUID]int)
+	serials := make(map[uuid.UUID][]string)
+	products := make(map[uuid.UUID]*models.Product)
+	var order []uuid.UUID
+	for _, item := range req.Items {
+		if _, ok := products[item.ProductID]; !ok {
+			product, err := productRepo.GetProductByID(item.ProductID)
+			if err != nil {
+				return nil, fmt.Errorf("product %s not found: %w", item.ProductID, err)
+			}
+			products[item.ProductID] = product
+			order = append(order, item.ProductID)
+		}
+		quantity, err := baseQuantity(productRepo, item.ProductID, item.Unit, item.Quantity)
+		if err != nil {
+			return nil, fmt.Errorf("product %s: %w", item.ProductID, err)
+		}
+		quantities[item.ProductID] += quantity
+		serials[item.ProductID] = append(serials[item.ProductID], item.SerialNumbers...)
+	}
+	for _, productID := range order {
+		// Produk serialized wajib menyebutkan serial yang dikirim
+		if products[productID].Serialized {
+			if err := checkSerialList(serials[productID], quantities[productID]); err != nil {
+				return nil, fmt.Errorf("product %s: %w", productID, err)
+			}
+		} else if len(serials[productID]) > 0 {
+			return nil, fmt.Errorf("product %s: %w", productID, ErrProductNotSerialized)
+		}
+		transfer.Items = append(transfer.Items, models.StockTransferItem{
+			ID:              uuid.New(),
+			TransferID:      transfer.ID,
+			SourceProductID: productID,
+			Quantity:        quantities[productID],
+			SerialNumbers:   serials[productID],
+		})
+	}
+	return transfer, nil
+}
+
+func (u *stockTransferUseCase) GetStockTransferByID(ctx context.Context, id uuid.UUID) (*dtos.StockTransferResponse, error) {
+	transfer, err := u.repo.GetStockTransferByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return toStockTransferResponse(transfer), nil
+}
+
+func (u *stockTransferUseCase) GetStockTransfersList(ctx context.Context, req dtos.StockTransferListRequest) ([]dtos.StockTransferResponse, dtos.Pagination, error) {
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.Limit == 0 {
+		req.Limit = 10
+	}
+	if err := u.validate.Struct(req); err != nil {
+		return nil, dtos.Pagination{}, err
+	}
+
+	transfers, total, err := u.repo.GetStockTransfersList(req)
+	if err != nil {
+		return nil, dtos.Pagination{}, err
+	}
+
+	var list []dtos.StockTransferResponse
+	for i := range transfers {
+		list = append(list, *toStockTransferResponse(&transfers[i]))
+	}
+	return list, buildPagination(req.Page, req.Limit, total), nil
+}
+
+// DispatchStockTransfer mengurangi stok di lokasi asal dan mengubah status menjadi in_transit
+func (u *stockTransferUseCase) DispatchStockTransfer(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*dtos.StockTransferResponse, error) {
+	err := u.repo.WithTransaction(func(repo repositorys.StockTransferRepository, stockRepo repositorys.ProductRepository) error {
+		transfer, err := repo.GetStockTransferForUpdate(id)
+		if err != nil {
+			return err
+		}
+		if transfer.Status != "draft" {
+			return fmt.Errorf("%w: cannot dispatch a %s transfer", ErrInvalidTransferState, transfer.Status)
+		}
+		destination, err := stockRepo.GetWarehouseLocationByID(transfer.DestinationLocationID)
+		if err != nil {
+			return fmt.Errorf("destination location not found: %w", err)
+		}
+
+		for i := range transfer.Items {
+			item := &transfer.Items[i]
+			// Status karantina stok asal dibawa ke lokasi tujuan agar stok karantina tidak menjadi sellable lewat transfer
+			source, err := stockRepo.GetProductStockForUpdate(item.SourceProductID, transfer.SourceLocationID)
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+			item.Quarantined = source != nil && source.Status == "quarantined"
+			if item.Quarantined && !destination.Quarantine {
+				return fmt.Errorf("product %s: %w", item.SourceProductID, ErrQuarantineLocation)
+			}
+			if err := repo.UpdateStockTransferItem(item); err != nil {
+				return err
+			}
+
+			if _, err := applyStockMovement(stockRepo, u.costingMethod, stockMovementInput{
+				ProductID:           item.SourceProductID,
+				WarehouseLocationID: transfer.SourceLocationID,
+				MovementType:        "transfer_out",
+				Quantity:            item.Quantity,
+				SerialNumbers:       item.SerialNumbers,
+				Reason:              "transfer",
+				ReferenceType:       "stock_transfer",
+				ReferenceID:         &transfer.ID,
+				ReferenceNote:       fmt.Sprintf("Transfer %s dispatched", transfer.TransferNumber),
+				TransferID:          &transfer.ID,
+				UserID:              userID,
+			}); err != nil {
+				return fmt.Errorf("product %s: %w", item.SourceProductID, err)
+			}
+		}
+
+		now := time.Now()
+		transfer.Status = "in_transit"
+		transfer.DispatchedBy = &userID
+		transfer.DispatchedAt = &now
+		transfer.UpdatedAt = now
+		return repo.UpdateStockTransfer(transfer)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return u.GetStockTransferByID(ctx, id)
+}
+
+// ReceiveStockTransfer menambah stok di lokasi tujuan untuk transfer yang sedang in_transit
+func (u *stockTransferUseCase) ReceiveStockTransfer(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*dtos.StockTransferResponse, error) {
+	err := u.repo.WithTransaction(func(repo repositorys.StockTransferRepository, stockRepo repositorys.ProductRepository) error {
+		transfer, err := repo.GetStockTransferForUpdate(id)
+		if err != nil {
+			return err
+		}
+		if transfer.Status != "in_transit" {
+			return fmt.Errorf("%w: cannot receive a %s transfer", ErrInvalidTransferState, transfer.Status)
+		}
+
+		note := fmt.Sprintf("Transfer %s received", transfer.TransferNumber)
+		if err := restockDispatchedItems(stockRepo, u.costingMethod, transfer, transfer.DestinationLocationID, "transfer", note, userID); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		transfer.Status = "received"
+		transfer.ReceivedBy = &userID
+		transfer.ReceivedAt = &now
+		transfer.UpdatedAt = now
+		return repo.UpdateStockTransfer(transfer)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return u.GetStockTransferByID(ctx, id)
+}
+
+// CancelStockTransfer membatalkan transfer. Transfer yang sudah in_transit dikembalikan ke lokasi asal.
+func (u *stockTransferUseCase) CancelStockTransfer(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*dtos.StockTransferResponse, error) {
+	err := u.repo.WithTransaction(func(repo repositorys.StockTransferRepository, stockRepo repositorys.ProductRepository) error {
+		transfer, err := repo.GetStockTransferForUpdate(id)
+		if err != nil {
+			return err
+		}
+
+		switch transfer.Status {
+		case "draft":
+			// Belum ada stok yang berpindah
+		case "in_transit":
+			note := fmt.Sprintf("Transfer %s cancelled, returned to source", transfer.TransferNumber)
+			if err := restockDispatchedItems(stockRepo, u.costingMethod, transfer, transfer.SourceLocationID, "transfer_cancelled", note, userID); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("%w: cannot cancel a %s transfer", ErrInvalidTransferState, transfer.Status)
+		}
+
+		now := time.Now()
+		transfer.Status = "cancelled"
+		transfer.CancelledBy = &userID
+		transfer.CancelledAt = &now
+		transfer.UpdatedAt = now
+		return repo.UpdateStockTransfer(transfer)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return u.GetStockTransferByID(ctx, id)
+}
+
+// restockDispatchedItems membukukan transfer_in di locationID dengan mencerminkan entri transfer_out
+// saat dispatch, sehingga lot (dan expiry) serta serial yang terkirim ikut berpindah apa adanya.
+func restockDispatchedItems(stockRepo repositorys.ProductRepository, costingMethod string, transfer *models.StockTransfer, locationID uuid.UUID, reason, note string, userID uuid.UUID) error {
+	dispatched, err := stockRepo.GetDispatchedStockMovements(transfer.ID)
+	if err != nil {
+		return err
+	}
+	quarantined := make(map[uuid.UUID]bool, len(transfer.Items))
+	for _, item := range transfer.Items {
+		quarantined[item.SourceProductID] = quarantined[item.SourceProductID] || item.Quarantined
+	}
+	for _, out := range dispatched {
+		// Stok masuk membawa harga pokok yang keluar dari lokasi asal
+		unitCost := out.TotalCost / float64(out.Quantity)
+		in := stockMovementInput{
+			ProductID:           out.SourceProductID,
+			WarehouseLocationID: locationID,
+			MovementType:        "transfer_in",
+			Quantity:            out.Quantity,
+			Reason:              reason,
+			ReferenceType:       "stock_transfer",
+			ReferenceID:         &transfer.ID,
+			ReferenceNote:       note,
+			TransferID:          &transfer.ID,
+			UserID:              userID,
+			UnitCost:            &unitCost,
+			Quarantine:          quarantined[out.SourceProductID],
+		}
+		if out.Lot != nil {
+			in.LotNumber = out.Lot.LotNumber
+			in.ExpiryDate = out.Lot.ExpiryDate
+		}
+		if in.SerialNumbers, err = stockRepo.GetSerialNumbersByMovementID(out.ID); err != nil {
+			return err
+		}
+		if len(in.SerialNumbers) == 0 {
+			in.SerialNumbers = nil
+		}
+		if _, err := applyStockMovement(stockRepo, costingMethod, in); err != nil {
+			return fmt.Errorf("product %s: %w", out.SourceProductID, err)
+		}
+	}
+	return nil
+}
+
+func toStockTransferResponse(t *models.StockTransfer) *dtos.StockTransferResponse {
+	items := make([]dtos.StockTransferItemResponse, 0, len(t.Items))
+	for _, item := range t.Items {
+		items = append(items, dtos.StockTransferItemResponse{
+			ID:            item.ID,
+			ProductID:     item.SourceProductID,
+			ProductName:   item.Product.Name,
+			SKU:           item.Product.SKU,
+			Quantity:      item.Quantity,
+			SerialNumbers: item.SerialNumbers,
+			Quarantined:   item.Quarantined,
+		})
+	}
+	return &dtos.StockTransferResponse{
+		ID:                      t.ID,
+		TransferNumber:          t.TransferNumber,
+		SourceLocationID:        t.SourceLocationID,
+		SourceLocationName:      t.SourceLocation.Name,
+		DestinationLocationID:   t.DestinationLocationID,
+		DestinationLocationName: t.DestinationLocation.Name,
+		Status:                  t.Status,
+		Note:                    t.Note,
+		Items:                   items,
+		CreatedBy:               t.CreatedBy,
+		DispatchedAt:            t.DispatchedAt,
+		ReceivedAt:              t.ReceivedAt,
+		CancelledAt:             t.CancelledAt,
+		CreatedAt:               t.CreatedAt,
+		UpdatedAt:               t.UpdatedAt,
+	}
+}
@@ -0,0 +1,311 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"auth-service/internal/dtos"
+	"auth-service/internal/models"
+	"auth-service/internal/repositorys"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrSupplierInactive        = errors.New("supplier is inactive")
+	ErrSupplierHasOpenOrders   = errors.New("supplier still has open purchase orders")
+	ErrMultiplePrimaryContacts = errors.New("only one contact can be primary")
+)
+
+type SupplierUseCase interface {
+	CreateSupplier(ctx context.Context, req dtos.CreateSupplierRequest, userID uuid.UUID) (*dtos.SupplierResponse, error)
+	GetSupplierByID(ctx context.Context, id uuid.UUID) (*dtos.SupplierResponse, error)
+	GetSuppliersList(ctx context.Context, req dtos.SupplierListRequest) ([]dtos.SupplierResponse, dtos.Pagination, error)
+	UpdateSupplier(ctx context.Context, id uuid.UUID, req dtos.UpdateSupplierRequest, userID uuid.UUID) (*dtos.SupplierResponse, error)
+	DeleteSupplier(ctx context.Context, id uuid.UUID, userID uuid.UUID) error
+	SaveSupplierProduct(ctx context.Context, supplierID uuid.UUID, req dtos.SupplierProductRequest, userID uuid.UUID) (*dtos.SupplierProductResponse, error)
+	DeleteSupplierProduct(ctx context.Context, supplierID, productID uuid.UUID, userID uuid.UUID) error
+}
+
+type supplierUseCase struct {
+	repo        repositorys.SupplierRepository
+	productRepo repositorys.ProductRepository
+	validate    *validator.Validate
+	log         *logrus.Logger
+}
+
+func NewSupplierUseCase(repo repositorys.SupplierRepository, productRepo repositorys.ProductRepository, log *logrus.Logger, validate *validator.Validate) SupplierUseCase {
+	return &supplierUseCase{repo: repo, productRepo: productRepo, log: log, validate: validate}
+}
+
+func (u *supplierUseCase) CreateSupplier(ctx context.Context, req dtos.CreateSupplierRequest, userID uuid.UUID) (*dtos.SupplierResponse, error) {
+	if err := u.validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	supplier := &models.Supplier{
+		ID:           uuid.New(),
+		Code:         req.Code,
+		Name:         req.Name,
+		Email:        req.Email,
+		Phone:        req.Phone,
+		Address:      req.Address,
+		LeadTimeDays: req.LeadTimeDays,
+		IsActive:     true,
+		Note:         req.Note,
+		CreatedBy:    userID,
+	}
+	contacts, err := buildSupplierContacts(supplier.ID, req.Contacts)
+	if err != nil {
+		return nil, err
+	}
+	supplier.Contacts = contacts
+
+	if err := u.repo.CreateSupplier(supplier); err != nil {
+		return nil, err
+	}
+	u.log.Info(fmt.Sprintf("Supplier %s created", supplier.Code))
+	return u.GetSupplierByID(ctx, supplier.ID)
+}
+
+func buildSupplierContacts(supplierID uuid.UUID, req []dtos.SupplierContactRequest) ([]models.SupplierContact, error) {
+	contacts := make([]models.SupplierContact, 0, len(req))
+	primary := 0
+	for _, c := range req {
+		if c.IsPrimary {
+			primary++
+		}
+		contacts = append(contacts, models.SupplierContact{
+			ID:         uuid.New(),
+			SupplierID: supplierID,
+			Name:       c.Name,
+			Title:      c.Title,
+			Email:      c.Email,
+			Phone:      c.Phone,
+			IsPrimary:  c.IsPrimary,
+		})
+	}
+	if primary > 1 {
+		return nil, ErrMultiplePrimaryContacts
+	}
+	return contacts, nil
+}
+
+func (u *supplierUseCase) GetSupplierByID(ctx context.Context, id uuid.UUID) (*dtos.SupplierResponse, error) {
+	supplier, err := u.repo.GetSupplierByID(id)
+	if err != nil {
+		return nil, err
+	}
+	response := toSupplierResponse(supplier)
+	for _, c := range supplier.Contacts {
+		response.Contacts = append(response.Contacts, dtos.SupplierContactResponse{
+			ID:        c.ID,
+			Name:      c.Name,
+			Title:     c.Title,
+			Email:     c.Email,
+			Phone:     c.Phone,
+			IsPrimary: c.IsPrimary,
+		})
+	}
+	for i := range supplier.Products {
+		response.Products = append(response.Products, toSupplierProductResponse(&supplier.Products[i], supplier))
+	}
+	return &response, nil
+}
+
+func (u *supplierUseCase) GetSuppliersList(ctx context.Context, req dtos.SupplierListRequest) ([]dtos.SupplierResponse, dtos.Pagination, error) {
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.Limit == 0 {
+		req.Limit = 10
+	}
+	if err := u.validate.Struct(req); err != nil {
+		return nil, dtos.Pagination{}, err
+	}
+
+	suppliers, total, err := u.repo.GetSuppliersList(req)
+	if err != nil {
+		return nil, dtos.Pagination{}, err
+	}
+
+	list := make([]dtos.SupplierResponse, 0, len(suppliers))
+	for i := range suppliers {
+		list = append(list, toSupplierResponse(&suppliers[i]))
+	}
+	return list, buildPagination(req.Page, req.Limit, total), nil
+}
+
+func (u *supplierUseCase) UpdateSupplier(ctx context.Context, id uuid.UUID, req dtos.UpdateSupplierRequest, userID uuid.UUID) (*dtos.SupplierResponse, error) {
+	if err := u.validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	err := u.repo.WithTransaction(func(repo repositorys.SupplierRepository) error {
+		supplier, err := repo.GetSupplierByID(id)
+		if err != nil {
+			return err
+		}
+		if req.Code != "" {
+			supplier.Code = req.Code
+		}
+		if req.Name != "" {
+			supplier.Name = req.Name
+		}
+		if req.Email != "" {
+			supplier.Email = req.Email
+		}
+		if req.Phone != "" {
+			supplier.Phone = req.Phone
+		}
+		if req.Address != "" {
+			supplier.Address = req.Address
+		}
+		if req.Note != "" {
+			supplier.Note = req.Note
+		}
+		if req.LeadTimeDays != nil {
+			supplier.LeadTimeDays = *req.LeadTimeDays
+		}
+		if req.IsActive != nil {
+			supplier.IsActive = *req.IsActive
+		}
+		supplier.UpdatedAt = time.Now()
+		if err := repo.UpdateSupplier(supplier); err != nil {
+			return err
+		}
+
+		// nil berarti kontak tidak diubah, slice kosong menghapus semua kontak
+		if req.Contacts != nil {
+			contacts, err := buildSupplierContacts(supplier.ID, req.Contacts)
+			if err != nil {
+				return err
+			}
+			return repo.ReplaceSupplierContacts(supplier.ID, contacts)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return u.GetSupplierByID(ctx, id)
+}
+
+// DeleteSupplier menghapus supplier yang tidak lagi punya PO terbuka
+func (u *supplierUseCase) DeleteSupplier(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
+	if _, err := u.repo.GetSupplierByID(id); err != nil {
+		return err
+	}
+	open, err := u.repo.HasOpenPurchaseOrders(id)
+	if err != nil {
+		return err
+	}
+	if open {
+		return ErrSupplierHasOpenOrders
+	}
+	return u.repo.DeleteSupplier(id)
+}
+
+// SaveSupplierProduct memetakan SKU supplier ke produk berdasarkan SKU kita. Menandai preferred
+// melepas tanda preferred supplier lain untuk produk yang sama.
+func (u *supplierUseCase) SaveSupplierProduct(ctx context.Context, supplierID uuid.UUID, req dtos.SupplierProductRequest, userID uuid.UUID) (*dtos.SupplierProductResponse, error) {
+	if err := u.validate.Struct(req); err != nil {
+		return nil, err
+	}
+	product, err := u.productRepo.GetProductBySKU(req.SKU)
+	if err != nil {
+		return nil, fmt.Errorf("product with sku %s not found: %w", req.SKU, err)
+	}
+
+	err = u.repo.WithTransaction(func(repo repositorys.SupplierRepository) error {
+		if _, err := repo.GetSupplierByID(supplierID); err != nil {
+			return err
+		}
+		now := time.Now()
+		mapping := &models.SupplierProduct{
+			ID:               uuid.New(),
+			SupplierID:       supplierID,
+			SourceProductID:  product.ID,
+			SupplierSKU:      req.SupplierSKU,
+			UnitCost:         req.UnitCost,
+			LeadTimeDays:     req.LeadTimeDays,
+			MinOrderQuantity: req.MinOrderQuantity,
+			Preferred:        req.Preferred,
+			CreatedAt:        now,
+			UpdatedAt:        now,
+		}
+		if mapping.MinOrderQuantity == 0 {
+			mapping.MinOrderQuantity = 1
+		}
+		if err := repo.SaveSupplierProduct(mapping); err != nil {
+			return err
+		}
+		if !req.Preferred {
+			return nil
+		}
+		saved, err := repo.GetSupplierProduct(supplierID, product.ID)
+		if err != nil {
+			return err
+		}
+		return repo.ClearPreferredSupplierProducts(product.ID, saved.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	mapping, err := u.repo.GetSupplierProduct(supplierID, product.ID)
+	if err != nil {
+		return nil, err
+	}
+	response := toSupplierProductResponse(mapping, &mapping.Supplier)
+	return &response, nil
+}
+
+func (u *supplierUseCase) DeleteSupplierProduct(ctx context.Context, supplierID, productID uuid.UUID, userID uuid.UUID) error {
+	return u.repo.DeleteSupplierProduct(supplierID, productID)
+}
+
+// supplierLeadTime mengembalikan lead time efektif: override produk atau lead time supplier
+func supplierLeadTime(mapping *models.SupplierProduct, supplier *models.Supplier) int {
+	if mapping.LeadTimeDays != nil {
+		return *mapping.LeadTimeDays
+	}
+	return supplier.LeadTimeDays
+}
+
+func toSupplierResponse(s *models.Supplier) dtos.SupplierResponse {
+	return dtos.SupplierResponse{
+		ID:           s.ID,
+		Code:         s.Code,
+		Name:         s.Name,
+		Email:        s.Email,
+		Phone:        s.Phone,
+		Address:      s.Address,
+		LeadTimeDays: s.LeadTimeDays,
+		IsActive:     s.IsActive,
+		Note:         s.Note,
+		CreatedBy:    s.CreatedBy,
+		CreatedAt:    s.CreatedAt,
+		UpdatedAt:    s.UpdatedAt,
+	}
+}
+
+func toSupplierProductResponse(m *models.SupplierProduct, supplier *models.Supplier) dtos.SupplierProductResponse {
+	return dtos.SupplierProductResponse{
+		ID:               m.ID,
+		SupplierID:       m.SupplierID,
+		ProductID:        m.SourceProductID,
+		ProductName:      m.Product.Name,
+		SKU:              m.Product.SKU,
+		SupplierSKU:      m.SupplierSKU,
+		UnitCost:         m.UnitCost,
+		LeadTimeDays:     supplierLeadTime(m, supplier),
+		MinOrderQuantity: m.MinOrderQuantity,
+		Preferred:        m.Preferred,
+		UpdatedAt:        m.UpdatedAt,
+	}
+}
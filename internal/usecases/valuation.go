@@ -0,0 +1,159 @@
+package usecases
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+
+	"auth-service/internal/models"
+	"auth-service/internal/repositorys"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidCostingMethod = errors.New("costing method must be fifo or moving_average")
+	ErrCostingMethodLocked  = errors.New("costing method cannot be changed while the product has stock on hand")
+	ErrUnitCostNotAllowed   = errors.New("unit cost can only be given for inbound movements")
+)
+
+const (
+	CostingFIFO          = "fifo"
+	CostingMovingAverage = "moving_average"
+)
+
+// CostingMethods adalah metode valuasi yang didukung
+var CostingMethods = []string{CostingFIFO, CostingMovingAverage}
+
+// ParseCostingMethod memvalidasi metode valuasi default dari konfigurasi (valuation.default_method);
+// kosong berarti moving_average
+func ParseCostingMethod(method string) (string, error) {
+	if method == "" {
+		return CostingMovingAverage, nil
+	}
+	if !slices.Contains(CostingMethods, method) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidCostingMethod, method)
+	}
+	return method, nil
+}
+
+// costingMethodFor mengembalikan metode valuasi efektif sebuah produk; defaultMethod dipakai
+// bila produk tidak menentukan costing_method sendiri
+func costingMethodFor(p *models.Product, defaultMethod string) string {
+	if p.CostingMethod != "" {
+		return p.CostingMethod
+	}
+	if defaultMethod != "" {
+		return defaultMethod
+	}
+	return CostingMovingAverage
+}
+
+// roundCost membulatkan nilai uang ke presisi kolom numeric(18,4)
+func roundCost(v float64) float64 {
+	return math.Round(v*10000) / 10000
+}
+
+// inboundUnitCost menentukan biaya per satuan dasar untuk movement masuk. Tanpa biaya eksplisit
+// (adjustment, return, hasil count) dipakai biaya rata-rata stok di lokasi ini, lalu rata-rata
+// produk di seluruh lokasi, lalu biaya masuk terakhir, sehingga nilai rata-rata tidak terdistorsi.
+func inboundUnitCost(repo repositorys.ProductRepository, stock *models.ProductStock, in stockMovementInput) (float64, error) {
+	if in.UnitCost != nil {
+		return roundCost(*in.UnitCost), nil
+	}
+	if stock.Quantity > 0 {
+		return roundCost(stock.StockValue / float64(stock.Quantity)), nil
+	}
+	cost, err := repo.GetProductUnitCost(in.ProductID)
+	if err != nil {
+		return 0, err
+	}
+	return roundCost(cost), nil
+}
+
+// receiveCost membukukan nilai entri ledger masuk ke stok; produk fifo mendapat satu cost layer per entri
+func receiveCost(repo repositorys.ProductRepository, stock *models.ProductStock, movement *models.StockMovement, method string) error {
+	stock.StockValue = roundCost(stock.StockValue + movement.TotalCost)
+	if method != CostingFIFO {
+		return nil
+	}
+	return repo.CreateCostLayer(&models.CostLayer{
+		ID:                  uuid.New(),
+		ProductStockID:      stock.ID,
+		SourceProductID:     stock.SourceProductID,
+		WarehouseLocationID: stock.WarehouseLocationID,
+		StockMovementID:     movement.ID,
+		Quantity:            movement.Quantity,
+		RemainingQuantity:   movement.Quantity,
+		UnitCost:            movement.UnitCost,
+		CreatedAt:           movement.CreatedAt,
+	})
+}
+
+// issueCost menghitung harga pokok movement keluar sebanyak quantity dari stok yang saldonya onHand
+// sebelum movement ini, lalu mengurangkannya dari nilai stok. Moving average memakai nilai rata-rata
+// saat ini; fifo mengonsumsi cost layer tertua. Bila stok habis, seluruh sisa nilai ikut keluar
+// agar tidak ada selisih pembulatan yang tertinggal.
+func issueCost(repo repositorys.ProductRepository, stock *models.ProductStock, method string, onHand, quantity int) (float64, error) {
+	if quantity >= onHand {
+		if method == CostingFIFO {
+			// Tutup seluruh layer yang tersisa, termasuk yang basi karena metode sempat berganti
+			if _, err := consumeCostLayers(repo, stock, onHand, math.MaxInt); err != nil {
+				return 0, err
+			}
+		}
+		total := roundCost(stock.StockValue)
+		stock.StockValue = 0
+		return total, nil
+	}
+
+	var total float64
+	if method == CostingFIFO {
+		var err error
+		if total, err = consumeCostLayers(repo, stock, onHand, quantity); err != nil {
+			return 0, err
+		}
+	} else {
+		total = stock.StockValue * float64(quantity) / float64(onHand)
+	}
+	total = roundCost(max(total, 0))
+	stock.StockValue = roundCost(max(stock.StockValue-total, 0))
+	return total, nil
+}
+
+// consumeCostLayers mengambil quantity dari cost layer fifo. Stok tanpa layer (sudah ada sebelum
+// produk memakai fifo) dianggap paling tua dan dinilai dari sisa nilai yang tidak ter-cover layer.
+func consumeCostLayers(repo repositorys.ProductRepository, stock *models.ProductStock, onHand, quantity int) (float64, error) {
+	layers, err := repo.GetOpenCostLayersForUpdate(stock.ID)
+	if err != nil {
+		return 0, err
+	}
+	layeredQuantity := 0
+	layeredValue := 0.0
+	for _, layer := range layers {
+		layeredQuantity += layer.RemainingQuantity
+		layeredValue += layer.UnitCost * float64(layer.RemainingQuantity)
+	}
+
+	total := 0.0
+	remaining := quantity
+	if untracked := onHand - layeredQuantity; untracked > 0 {
+		take := min(untracked, remaining)
+		total += max(stock.StockValue-layeredValue, 0) * float64(take) / float64(untracked)
+		remaining -= take
+	}
+	for i := range layers {
+		if remaining == 0 {
+			break
+		}
+		take := min(layers[i].RemainingQuantity, remaining)
+		layers[i].RemainingQuantity -= take
+		total += layers[i].UnitCost * float64(take)
+		remaining -= take
+		if err := repo.UpdateCostLayer(&layers[i]); err != nil {
+			return 0, err
+		}
+	}
+	return total, nil
+}
@@ -0,0 +1,254 @@
+package usecases
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"auth-service/internal/models"
+	"auth-service/internal/repositorys"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCostRepository hanya mengimplementasikan method ProductRepository yang dipakai valuasi;
+// method lain akan panic karena interface yang di-embed bernilai nil
+type fakeCostRepository struct {
+	repositorys.ProductRepository
+	layers         []models.CostLayer
+	updated        []uuid.UUID
+	productCost    float64
+	productCostErr error
+}
+
+func (r *fakeCostRepository) GetOpenCostLayersForUpdate(stockID uuid.UUID) ([]models.CostLayer, error) {
+	var open []models.CostLayer
+	for _, layer := range r.layers {
+		if layer.ProductStockID == stockID && layer.RemainingQuantity > 0 {
+			open = append(open, layer)
+		}
+	}
+	return open, nil
+}
+
+func (r *fakeCostRepository) UpdateCostLayer(layer *models.CostLayer) error {
+	for i := range r.layers {
+		if r.layers[i].ID == layer.ID {
+			r.layers[i].RemainingQuantity = layer.RemainingQuantity
+			r.updated = append(r.updated, layer.ID)
+			return nil
+		}
+	}
+	return errors.New("cost layer not found")
+}
+
+func (r *fakeCostRepository) GetProductUnitCost(productID uuid.UUID) (float64, error) {
+	return r.productCost, r.productCostErr
+}
+
+// costLayers membuat layer fifo berurutan (tertua lebih dulu) untuk satu stok
+func costLayers(stockID uuid.UUID, remaining []int, unitCosts []float64) []models.CostLayer {
+	layers := make([]models.CostLayer, len(remaining))
+	for i := range remaining {
+		layers[i] = models.CostLayer{
+			ID:                uuid.New(),
+			ProductStockID:    stockID,
+			Quantity:          remaining[i],
+			RemainingQuantity: remaining[i],
+			UnitCost:          unitCosts[i],
+		}
+	}
+	return layers
+}
+
+func remainingQuantities(layers []models.CostLayer) []int {
+	remaining := make([]int, len(layers))
+	for i, layer := range layers {
+		remaining[i] = layer.RemainingQuantity
+	}
+	return remaining
+}
+
+func TestIssueCost(t *testing.T) {
+	tests := []struct {
+		name          string
+		method        string
+		stockValue    float64
+		onHand        int
+		quantity      int
+		remaining     []int
+		unitCosts     []float64
+		wantTotal     float64
+		wantValue     float64
+		wantRemaining []int
+		wantUpdated   int
+	}{
+		{
+			name:          "fifo partial issue spans several layers",
+			method:        CostingFIFO,
+			stockValue:    29,
+			onHand:        10,
+			quantity:      6,
+			remaining:     []int{3, 5, 2},
+			unitCosts:     []float64{2, 3, 4},
+			wantTotal:     15,
+			wantValue:     14,
+			wantRemaining: []int{0, 2, 2},
+			wantUpdated:   2,
+		},
+		{
+			name:          "fifo issue within the oldest layer",
+			method:        CostingFIFO,
+			stockValue:    29,
+			onHand:        10,
+			quantity:      2,
+			remaining:     []int{3, 5, 2},
+			unitCosts:     []float64{2, 3, 4},
+			wantTotal:     4,
+			wantValue:     25,
+			wantRemaining: []int{1, 5, 2},
+			wantUpdated:   1,
+		},
+		{
+			name:          "fifo full issue takes the whole value and closes stale layers",
+			method:        CostingFIFO,
+			stockValue:    10,
+			onHand:        4,
+			quantity:      4,
+			remaining:     []int{3, 3},
+			unitCosts:     []float64{2, 2.5},
+			wantTotal:     10,
+			wantValue:     0,
+			wantRemaining: []int{0, 0},
+			wantUpdated:   2,
+		},
+		{
+			name:          "fifo untracked legacy stock is issued before the layers",
+			method:        CostingFIFO,
+			stockValue:    25,
+			onHand:        10,
+			quantity:      8,
+			remaining:     []int{4},
+			unitCosts:     []float64{3},
+			wantTotal:     19, // 6 unit lama senilai 13, lalu 2 x 3
+			wantValue:     6,
+			wantRemaining: []int{2},
+			wantUpdated:   1,
+		},
+		{
+			name:          "fifo issue covered by untracked legacy stock leaves layers untouched",
+			method:        CostingFIFO,
+			stockValue:    25,
+			onHand:        10,
+			quantity:      3,
+			remaining:     []int{4},
+			unitCosts:     []float64{3},
+			wantTotal:     6.5,
+			wantValue:     18.5,
+			wantRemaining: []int{4},
+			wantUpdated:   0,
+		},
+		{
+			name:       "moving average rounds the issued cost",
+			method:     CostingMovingAverage,
+			stockValue: 10,
+			onHand:     3,
+			quantity:   1,
+			wantTotal:  3.3333,
+			wantValue:  6.6667,
+		},
+		{
+			name:       "moving average full issue leaves no rounding residue",
+			method:     CostingMovingAverage,
+			stockValue: 6.6667,
+			onHand:     2,
+			quantity:   2,
+			wantTotal:  6.6667,
+			wantValue:  0,
+		},
+		{
+			name:       "moving average issue beyond on hand empties the value",
+			method:     CostingMovingAverage,
+			stockValue: 9,
+			onHand:     3,
+			quantity:   5,
+			wantTotal:  9,
+			wantValue:  0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stock := &models.ProductStock{ID: uuid.New(), Quantity: tt.onHand, StockValue: tt.stockValue}
+			repo := &fakeCostRepository{layers: costLayers(stock.ID, tt.remaining, tt.unitCosts)}
+
+			total, err := issueCost(repo, stock, tt.method, tt.onHand, tt.quantity)
+			require.NoError(t, err)
+			assert.InDelta(t, tt.wantTotal, total, 1e-9)
+			assert.InDelta(t, tt.wantValue, stock.StockValue, 1e-9)
+			if tt.wantRemaining != nil {
+				assert.Equal(t, tt.wantRemaining, remainingQuantities(repo.layers))
+			}
+			assert.Len(t, repo.updated, tt.wantUpdated)
+		})
+	}
+}
+
+func TestConsumeCostLayersUnbounded(t *testing.T) {
+	// Quantity tak terbatas dipakai issueCost untuk menutup seluruh layer saat stok habis
+	stock := &models.ProductStock{ID: uuid.New(), Quantity: 2, StockValue: 5}
+	repo := &fakeCostRepository{layers: costLayers(stock.ID, []int{1, 4}, []float64{1, 2})}
+
+	total, err := consumeCostLayers(repo, stock, 2, math.MaxInt)
+	require.NoError(t, err)
+	assert.InDelta(t, 9, total, 1e-9)
+	assert.Equal(t, []int{0, 0}, remainingQuantities(repo.layers))
+}
+
+func TestInboundUnitCost(t *testing.T) {
+	explicit := 1.23456
+	tests := []struct {
+		name        string
+		unitCost    *float64
+		quantity    int
+		stockValue  float64
+		productCost float64
+		want        float64
+	}{
+		{name: "explicit cost is rounded", unitCost: &explicit, quantity: 3, stockValue: 10, productCost: 7, want: 1.2346},
+		{name: "falls back to the location average", quantity: 3, stockValue: 10, productCost: 7, want: 3.3333},
+		{name: "empty location falls back to the product average", quantity: 0, productCost: 2.71828, want: 2.7183},
+		{name: "negative location falls back to the product average", quantity: -2, stockValue: -4, productCost: 2.5, want: 2.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stock := &models.ProductStock{ID: uuid.New(), Quantity: tt.quantity, StockValue: tt.stockValue}
+			repo := &fakeCostRepository{productCost: tt.productCost}
+
+			cost, err := inboundUnitCost(repo, stock, stockMovementInput{ProductID: uuid.New(), UnitCost: tt.unitCost})
+			require.NoError(t, err)
+			assert.InDelta(t, tt.want, cost, 1e-9)
+		})
+	}
+
+	t.Run("product average error is returned", func(t *testing.T) {
+		repo := &fakeCostRepository{productCostErr: errors.New("db down")}
+		_, err := inboundUnitCost(repo, &models.ProductStock{}, stockMovementInput{ProductID: uuid.New()})
+		assert.EqualError(t, err, "db down")
+	})
+}
+
+func TestCostingMethodFor(t *testing.T) {
+	assert.Equal(t, CostingFIFO, costingMethodFor(&models.Product{CostingMethod: CostingFIFO}, CostingMovingAverage))
+	assert.Equal(t, CostingFIFO, costingMethodFor(&models.Product{}, CostingFIFO))
+	assert.Equal(t, CostingMovingAverage, costingMethodFor(&models.Product{}, ""))
+
+	method, err := ParseCostingMethod("")
+	require.NoError(t, err)
+	assert.Equal(t, CostingMovingAverage, method)
+	_, err = ParseCostingMethod("lifo")
+	assert.ErrorIs(t, err, ErrInvalidCostingMethod)
+}
@@ -0,0 +1,164 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+
+	"auth-service/internal/dtos"
+	"auth-service/internal/models"
+	"auth-service/internal/repositorys"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type ValuationUseCase interface {
+	GetInventoryValuation(ctx context.Context, req dtos.InventoryValuationRequest) (*dtos.InventoryValuationResponse, error)
+	GetCostOfGoods(ctx context.Context, req dtos.CostOfGoodsRequest) (*dtos.CostOfGoodsResponse, error)
+}
+
+type valuationUseCase struct {
+	repo        repositorys.ValuationRepository
+	productRepo repositorys.ProductRepository
+	// costingMethod adalah metode valuasi default untuk produk tanpa costing_method (valuation.default_method)
+	costingMethod string
+	validate      *validator.Validate
+	log           *logrus.Logger
+}
+
+func NewValuationUseCase(repo repositorys.ValuationRepository, productRepo repositorys.ProductRepository, costingMethod string, log *logrus.Logger, validate *validator.Validate) ValuationUseCase {
+	return &valuationUseCase{repo: repo, productRepo: productRepo, costingMethod: costingMethod, log: log, validate: validate}
+}
+
+// GetInventoryValuation menjumlahkan nilai persediaan saat ini (ProductStock.StockValue) per lokasi dan category
+func (u *valuationUseCase) GetInventoryValuation(ctx context.Context, req dtos.InventoryValuationRequest) (*dtos.InventoryValuationResponse, error) {
+	root, err := u.locationFilter(req.WarehouseLocationID)
+	if err != nil {
+		return nil, err
+	}
+
+	locations, err := u.repo.GetInventoryValueByLocation(root, req.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+	categories, err := u.repo.GetInventoryValueByCategory(root, req.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dtos.InventoryValuationResponse{
+		DefaultCostingMethod: costingMethodFor(&models.Product{}, u.costingMethod),
+		ByLocation:           make([]dtos.InventoryValuationLocationResponse, 0, len(locations)),
+		ByCategory:           make([]dtos.InventoryValuationCategoryResponse, 0, len(categories)),
+	}
+	for _, row := range locations {
+		response.TotalQuantity += row.Quantity
+		response.TotalValue += row.Value
+		response.ByLocation = append(response.ByLocation, dtos.InventoryValuationLocationResponse{
+			WarehouseLocationID:   row.WarehouseLocationID,
+			WarehouseLocationName: row.Name,
+			WarehouseLocationPath: row.Path,
+			Quantity:              row.Quantity,
+			Value:                 roundCost(row.Value),
+		})
+	}
+	for _, row := range categories {
+		response.ByCategory = append(response.ByCategory, dtos.InventoryValuationCategoryResponse{
+			CategoryID:   row.CategoryID,
+			CategoryName: row.Name,
+			Quantity:     row.Quantity,
+			Value:        roundCost(row.Value),
+		})
+	}
+	response.TotalValue = roundCost(response.TotalValue)
+	return response, nil
+}
+
+// GetCostOfGoods menjumlahkan harga pokok movement keluar per produk dan per tipe movement
+func (u *valuationUseCase) GetCostOfGoods(ctx context.Context, req dtos.CostOfGoodsRequest) (*dtos.CostOfGoodsResponse, error) {
+	if err := u.validate.Struct(req); err != nil {
+		return nil, err
+	}
+	from, to, err := parseLedgerRange(req.From, req.To)
+	if err != nil {
+		return nil, err
+	}
+	root, err := u.locationFilter(req.WarehouseLocationID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := u.repo.GetCostOfGoods(repositorys.CostOfGoodsFilter{
+		From:         from,
+		To:           to,
+		ProductID:    req.ProductID,
+		CategoryID:   req.CategoryID,
+		Location:     root,
+		MovementType: req.MovementType,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dtos.CostOfGoodsResponse{
+		From:           from,
+		To:             to,
+		ByMovementType: []dtos.CostOfGoodsMovementTypeResponse{},
+		Products:       []dtos.CostOfGoodsProductResponse{},
+	}
+	types := make(map[string]int)
+	products := make(map[uuid.UUID]int)
+	for _, row := range rows {
+		response.TotalQuantity += row.Quantity
+		response.TotalCost += row.TotalCost
+
+		i, ok := types[row.MovementType]
+		if !ok {
+			i = len(response.ByMovementType)
+			types[row.MovementType] = i
+			response.ByMovementType = append(response.ByMovementType, dtos.CostOfGoodsMovementTypeResponse{MovementType: row.MovementType})
+		}
+		response.ByMovementType[i].Quantity += row.Quantity
+		response.ByMovementType[i].TotalCost += row.TotalCost
+
+		j, ok := products[row.ProductID]
+		if !ok {
+			j = len(response.Products)
+			products[row.ProductID] = j
+			response.Products = append(response.Products, dtos.CostOfGoodsProductResponse{
+				ProductID:     row.ProductID,
+				ProductName:   row.ProductName,
+				SKU:           row.SKU,
+				CostingMethod: costingMethodFor(&models.Product{CostingMethod: row.CostingMethod}, u.costingMethod),
+			})
+		}
+		response.Products[j].Quantity += row.Quantity
+		response.Products[j].TotalCost += row.TotalCost
+	}
+
+	response.TotalCost = roundCost(response.TotalCost)
+	for i := range response.ByMovementType {
+		response.ByMovementType[i].TotalCost = roundCost(response.ByMovementType[i].TotalCost)
+	}
+	for i := range response.Products {
+		p := &response.Products[i]
+		p.TotalCost = roundCost(p.TotalCost)
+		if p.Quantity > 0 {
+			p.AverageUnitCost = roundCost(p.TotalCost / float64(p.Quantity))
+		}
+	}
+	return response, nil
+}
+
+// locationFilter memuat lokasi root filter; uuid.Nil berarti seluruh lokasi
+func (u *valuationUseCase) locationFilter(id uuid.UUID) (*models.WarehouseLocation, error) {
+	if id == uuid.Nil {
+		return nil, nil
+	}
+	location, err := u.productRepo.GetWarehouseLocationByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("warehouse location not found: %w", err)
+	}
+	return location, nil
+}
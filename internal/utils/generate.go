@@ -3,6 +3,7 @@ package utils
 import (
 	"crypto/sha256"
 	"encoding/hex"
+	"fmt"
 	"math/rand"
 	"time"
 )
@@ -22,3 +23,8 @@ func GenerateOTP(length int) string {
 	}
 	return string(result)
 }
+
+// GenerateDocumentNumber membuat nomor dokumen, contoh: TRF-20250101-483920
+func GenerateDocumentNumber(prefix string) string {
+	return fmt.Sprintf("%s-%s-%s", prefix, time.Now().Format("20060102"), GenerateOTP(6))
+}